@@ -0,0 +1,245 @@
+// Marketplace de delivery fake para testar a integração localmente.
+//
+// Envia pedidos e cancelamentos assinados para o webhook do LEP e recebe de volta
+// as mudanças de status (POST /orders/{id}/status), como faria um iFood.
+//
+//	go run ./cmd/fakemarketplace -target http://localhost:8080/webhook/marketplace/fake -secret <webhook_secret> -merchant loja-teste
+//
+// Endpoints:
+//
+//	POST /simulate/order            cria pedido (corpo opcional no formato MarketplaceOrder; ?code=PDV para o item padrão, ?duplicate=1 reenvia o webhook)
+//	POST /simulate/cancel/{id}      cancela o pedido ({"reason": "..."} opcional)
+//	POST /simulate/outage?on=1      faz a API de status responder 503 (testa as novas tentativas)
+//	POST /orders/{id}/status        API de status chamada pelo LEP
+//	GET  /orders/{id}/status        status recebidos para o pedido
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"lep/repositories/models"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const signatureHeader = "X-Marketplace-Signature"
+
+type statusUpdate struct {
+	Status     string    `json:"status"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+type fakeMarketplace struct {
+	target   string
+	secret   string
+	merchant string
+	token    string
+	client   *http.Client
+
+	mu      sync.Mutex
+	outage  bool
+	updates map[string][]statusUpdate
+}
+
+func main() {
+	addr := flag.String("addr", ":9090", "endereço do marketplace fake")
+	target := flag.String("target", "http://localhost:8080/webhook/marketplace/fake", "URL do webhook do LEP")
+	secret := flag.String("secret", "", "webhook_secret da integração")
+	merchant := flag.String("merchant", "loja-teste", "merchant_id da integração")
+	token := flag.String("token", "", "api_token esperado nas chamadas de status (vazio = não valida)")
+	flag.Parse()
+
+	if *secret == "" {
+		log.Fatal("-secret é obrigatório (webhook_secret da integração cadastrada no LEP)")
+	}
+
+	m := &fakeMarketplace{
+		target:   *target,
+		secret:   *secret,
+		merchant: *merchant,
+		token:    *token,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		updates:  make(map[string][]statusUpdate),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /simulate/order", m.simulateOrder)
+	mux.HandleFunc("POST /simulate/cancel/{id}", m.simulateCancel)
+	mux.HandleFunc("POST /simulate/outage", m.simulateOutage)
+	mux.HandleFunc("POST /orders/{id}/status", m.receiveStatus)
+	mux.HandleFunc("GET /orders/{id}/status", m.listStatus)
+
+	log.Printf("🛵 Marketplace fake em %s (webhook: %s, merchant: %s)", *addr, *target, *merchant)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+// simulateOrder envia um order.placed assinado para o LEP
+func (m *fakeMarketplace) simulateOrder(w http.ResponseWriter, r *http.Request) {
+	order := models.MarketplaceOrder{}
+	body, _ := io.ReadAll(r.Body)
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &order); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+	}
+	if len(order.Items) == 0 {
+		code := r.URL.Query().Get("code")
+		if code == "" {
+			code = "001"
+		}
+		order.Items = []models.MarketplaceOrderItem{{Code: code, Name: "Item " + code, Quantity: 1}}
+	}
+	if order.Customer.Name == "" {
+		order.Customer = models.PublicOrderCustomer{Name: "Cliente Marketplace", Phone: "+5511999990000"}
+	}
+	if order.Type == "" {
+		order.Type = models.OrderTypeDelivery
+	}
+	if order.Type == models.OrderTypeDelivery && order.Address == nil {
+		order.Address = &models.Address{Street: "Rua de Teste", Number: "100", City: "São Paulo", State: "SP"}
+	}
+
+	event := models.MarketplaceOrderEvent{
+		EventId:         "evt-" + randomId(),
+		Type:            models.MarketplaceEventOrderPlaced,
+		MerchantId:      m.merchant,
+		ExternalOrderId: "ord-" + randomId(),
+		Order:           &order,
+	}
+
+	sends := 1
+	if r.URL.Query().Get("duplicate") == "1" {
+		sends = 2
+	}
+	m.deliver(w, event, sends)
+}
+
+// simulateCancel envia um order.cancelled assinado para o LEP
+func (m *fakeMarketplace) simulateCancel(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	json.NewDecoder(r.Body).Decode(&request)
+
+	event := models.MarketplaceOrderEvent{
+		EventId:         "evt-" + randomId(),
+		Type:            models.MarketplaceEventOrderCancelled,
+		MerchantId:      m.merchant,
+		ExternalOrderId: r.PathValue("id"),
+		Reason:          request.Reason,
+	}
+	m.deliver(w, event, 1)
+}
+
+// simulateOutage liga/desliga a indisponibilidade da API de status
+func (m *fakeMarketplace) simulateOutage(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	m.outage = r.URL.Query().Get("on") == "1"
+	outage := m.outage
+	m.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]bool{"outage": outage})
+}
+
+// deliver assina e envia o evento ao webhook, repetindo o mesmo corpo quando sends > 1
+func (m *fakeMarketplace) deliver(w http.ResponseWriter, event models.MarketplaceOrderEvent, sends int) {
+	body, err := json.Marshal(map[string]interface{}{"events": []models.MarketplaceOrderEvent{event}})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	responses := make([]json.RawMessage, 0, sends)
+	for i := 0; i < sends; i++ {
+		req, err := http.NewRequest(http.MethodPost, m.target, bytes.NewReader(body))
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(signatureHeader, sign(body, m.secret))
+
+		resp, err := m.client.Do(req)
+		if err != nil {
+			writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+			return
+		}
+		result, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		log.Printf("→ %s %s (%s): %d", event.Type, event.ExternalOrderId, event.EventId, resp.StatusCode)
+		if !json.Valid(result) {
+			result, _ = json.Marshal(string(result))
+		}
+		responses = append(responses, result)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"event": event, "responses": responses})
+}
+
+// receiveStatus API de status chamada pelo LEP
+func (m *fakeMarketplace) receiveStatus(w http.ResponseWriter, r *http.Request) {
+	if m.token != "" && r.Header.Get("Authorization") != "Bearer "+m.token {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.outage {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "marketplace unavailable"})
+		return
+	}
+
+	var request struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Status == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "status is required"})
+		return
+	}
+
+	id := r.PathValue("id")
+	m.updates[id] = append(m.updates[id], statusUpdate{Status: request.Status, ReceivedAt: time.Now()})
+	log.Printf("← status %s: %s", id, request.Status)
+	writeJSON(w, http.StatusOK, map[string]string{"status": request.Status})
+}
+
+// listStatus status recebidos para o pedido
+func (m *fakeMarketplace) listStatus(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	updates := append([]statusUpdate{}, m.updates[r.PathValue("id")]...)
+	m.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, updates)
+}
+
+func sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomId() string {
+	bytes := make([]byte, 6)
+	if _, err := rand.Read(bytes); err != nil {
+		return fmt.Sprint(time.Now().UnixNano())
+	}
+	return strings.ToLower(hex.EncodeToString(bytes))
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,118 @@
+// Agente de impressão para a rede local do restaurante.
+//
+// A API (Cloud Run) não alcança impressoras na LAN: o agente roda num computador da loja,
+// busca os trabalhos das impressoras com delivery "agent", envia os bytes ESC/POS por TCP
+// (porta 9100) e devolve o resultado. Só conecta em IPs privados, como a API.
+//
+//	go run ./cmd/printagent -api https://api.lepgo.com.br -token <token de POST /printer/agent/token>
+//
+// O token também pode vir de PRINT_AGENT_TOKEN.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"lep/repositories/models"
+	"lep/utils"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const tokenHeader = "X-Print-Agent-Token"
+
+type printAgent struct {
+	api       string
+	token     string
+	client    *http.Client
+	transport utils.PrinterTransport
+}
+
+func main() {
+	api := flag.String("api", "http://localhost:8080", "URL da API do LEP")
+	token := flag.String("token", os.Getenv("PRINT_AGENT_TOKEN"), "token do agente do projeto")
+	interval := flag.Duration("interval", 3*time.Second, "intervalo entre consultas à fila")
+	flag.Parse()
+
+	if *token == "" {
+		log.Fatal("-token é obrigatório (gerado em POST /printer/agent/token)")
+	}
+
+	a := &printAgent{
+		api:       strings.TrimRight(*api, "/"),
+		token:     *token,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		transport: &utils.TCPPrinterTransport{Timeout: 5 * time.Second},
+	}
+
+	log.Printf("🖨️ Agente de impressão consultando %s a cada %s", a.api, *interval)
+	for {
+		if err := a.poll(); err != nil {
+			log.Printf("Erro ao consultar a fila: %v", err)
+		}
+		time.Sleep(*interval)
+	}
+}
+
+// poll pega os trabalhos prontos, imprime e devolve o resultado de cada um
+func (a *printAgent) poll() error {
+	var jobs []models.PrintAgentJob
+	if err := a.call(http.MethodGet, "/print/agent/jobs", nil, &jobs); err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		result := models.PrintAgentResult{Success: true}
+		if err := a.transport.Send(job.Host, job.Port, job.Payload); err != nil {
+			result = models.PrintAgentResult{Success: false, Error: err.Error()}
+			log.Printf("Falha ao imprimir %s em %s:%d: %v", job.Id, job.Host, job.Port, err)
+		}
+
+		// Sem resposta, o trabalho volta para a fila quando ficar preso em "printing"
+		if err := a.call(http.MethodPost, "/print/agent/jobs/"+job.Id.String()+"/result", result, nil); err != nil {
+			log.Printf("Erro ao enviar resultado de %s: %v", job.Id, err)
+		}
+	}
+	return nil
+}
+
+// call faz a requisição autenticada e decodifica a resposta em out (opcional)
+func (a *printAgent) call(method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, a.api+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(tokenHeader, a.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: %d %s", method, path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -29,6 +29,9 @@ var (
 	// For GCP Cloud SQL
 	INSTANCE_UNIX_SOCKET = os.Getenv("INSTANCE_UNIX_SOCKET")
 
+	// Proxies whose X-Forwarded-For is trusted to resolve the client IP (CIDRs, comma-separated)
+	TRUSTED_PROXIES = getTrustedProxies()
+
 	// Application configuration
 	ENABLE_CRON_JOBS = getEnableCronJobs()
 	GIN_MODE         = getGinMode()
@@ -43,6 +46,12 @@ var (
 	BUCKET_NAME          = os.Getenv("BUCKET_NAME")
 	BUCKET_CACHE_CONTROL = os.Getenv("BUCKET_CACHE_CONTROL")
 	BUCKET_TIMEOUT, _    = strconv.Atoi(os.Getenv("BUCKET_TIMEOUT"))
+
+	// Fiscal configuration: nfe_v4.00.xsd do pacote de schemas oficial (obrigatório fora de dev)
+	NFCE_SCHEMA_PATH = os.Getenv("NFCE_SCHEMA_PATH")
+
+	// Pix configuration: segredo HMAC dos webhooks do provedor (X-Pix-Signature)
+	PIX_WEBHOOK_SECRET = os.Getenv("PIX_WEBHOOK_SECRET")
 )
 
 // getEnvironment returns the current environment or defaults to "dev"
@@ -86,6 +95,26 @@ func getDBSSLMode() string {
 	return sslMode
 }
 
+// getTrustedProxies returns the trusted proxy CIDRs. On Cloud Run requests arrive from an internal
+// link-local proxy that appends the real client IP to X-Forwarded-For; add load balancer ranges via TRUSTED_PROXIES.
+func getTrustedProxies() []string {
+	raw := os.Getenv("TRUSTED_PROXIES")
+	if raw == "" {
+		if ENV == "stage" || ENV == "prod" {
+			return []string{"169.254.0.0/16"}
+		}
+		return nil
+	}
+
+	var proxies []string
+	for _, proxy := range strings.Split(raw, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
 // getEnableCronJobs returns whether cron jobs should be enabled
 func getEnableCronJobs() bool {
 	cronJobs := os.Getenv("ENABLE_CRON_JOBS")
@@ -148,6 +177,38 @@ func IsGCP() bool {
 	return ENV == "stage" || ENV == "prod"
 }
 
+// allowedOrigins fixed front-end origins allowed outside development
+var allowedOrigins = map[string]bool{
+	"http://localhost:5173":                                        true,
+	"http://localhost:5174":                                        true,
+	"https://lep-front.vercel.app":                                 true,
+	"https://lep-front-git-main-leps-projects-a55eafc4.vercel.app": true,
+	"https://lep-front-nw6k.vercel.app":                            true,
+	"https://lep-front-stage.vercel.app":                           true,
+}
+
+// IsAllowedOrigin returns true if the origin is in the CORS allow-list (any origin in development)
+func IsAllowedOrigin(origin string) bool {
+	if IsDev() {
+		return true
+	}
+
+	// Remove trailing slash for comparison
+	origin = strings.TrimSuffix(origin, "/")
+
+	// Check fixed origins
+	if allowedOrigins[origin] {
+		return true
+	}
+
+	// Allow any subdomain of lepgo.com.br (e.g., admin.lepgo.com.br, www.lepgo.com.br)
+	if origin == "https://lepgo.com.br" || strings.HasSuffix(origin, ".lepgo.com.br") {
+		return strings.HasPrefix(origin, "https://")
+	}
+
+	return false
+}
+
 // getStorageType returns the storage type based on environment
 func getStorageType() string {
 	storageType := os.Getenv("STORAGE_TYPE")
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resourceBlockedPeriod struct {
+	repo *repositories.DBconn
+}
+
+type IHandlerBlockedPeriod interface {
+	GetBlockedPeriod(id string) (*models.BlockedPeriod, error)
+	ListBlockedPeriods(orgId, projectId string) ([]models.BlockedPeriod, error)
+	CreateBlockedPeriod(period *models.BlockedPeriod) error
+	UpdateBlockedPeriod(period *models.BlockedPeriod) error
+	DeleteBlockedPeriod(id string) error
+	ListActiveInRange(orgId, projectId string, start, end time.Time) ([]models.BlockedPeriod, error)
+}
+
+func NewSourceHandlerBlockedPeriod(repo *repositories.DBconn) IHandlerBlockedPeriod {
+	return &resourceBlockedPeriod{repo: repo}
+}
+
+func (r *resourceBlockedPeriod) GetBlockedPeriod(id string) (*models.BlockedPeriod, error) {
+	periodId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.BlockedPeriods.GetBlockedPeriodById(periodId)
+}
+
+// ListBlockedPeriods lista períodos bloqueados do projeto (inclusive inativos)
+func (r *resourceBlockedPeriod) ListBlockedPeriods(orgId, projectId string) ([]models.BlockedPeriod, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.BlockedPeriods.GetBlockedPeriodsByProject(orgUUID, projectUUID)
+}
+
+// CreateBlockedPeriod cria período bloqueado (ambiente e mesas precisam ser do projeto)
+func (r *resourceBlockedPeriod) CreateBlockedPeriod(period *models.BlockedPeriod) error {
+	if err := r.checkScope(period); err != nil {
+		return err
+	}
+	period.Id = uuid.New()
+	period.CreatedAt = time.Now()
+	period.UpdatedAt = time.Now()
+	return r.repo.BlockedPeriods.CreateBlockedPeriod(period)
+}
+
+// UpdateBlockedPeriod atualiza período bloqueado
+func (r *resourceBlockedPeriod) UpdateBlockedPeriod(period *models.BlockedPeriod) error {
+	if err := r.checkScope(period); err != nil {
+		return err
+	}
+	return r.repo.BlockedPeriods.UpdateBlockedPeriod(period)
+}
+
+func (r *resourceBlockedPeriod) DeleteBlockedPeriod(id string) error {
+	periodId, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	return r.repo.BlockedPeriods.SoftDeleteBlockedPeriod(periodId)
+}
+
+// ListActiveInRange períodos ativos que podem ter ocorrência no intervalo (avaliar com FindBlockingPeriod)
+func (r *resourceBlockedPeriod) ListActiveInRange(orgId, projectId string, start, end time.Time) ([]models.BlockedPeriod, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.BlockedPeriods.GetActiveBlockedPeriodsInRange(orgUUID, projectUUID, start, end)
+}
+
+// checkScope confere se o ambiente e as mesas do bloqueio pertencem ao projeto
+func (r *resourceBlockedPeriod) checkScope(period *models.BlockedPeriod) error {
+	if period.EnvironmentId != nil {
+		environment, err := r.repo.Environments.GetEnvironmentById(*period.EnvironmentId)
+		if err != nil || environment.OrganizationId != period.OrganizationId || environment.ProjectId != period.ProjectId {
+			return errors.New("invalid_blocked_period: environment not found in this project")
+		}
+	}
+	for _, tableId := range period.TableIds {
+		id, err := uuid.Parse(tableId)
+		if err != nil {
+			return fmt.Errorf("invalid_blocked_period: invalid table id %s", tableId)
+		}
+		table, err := r.repo.Tables.GetTableById(id)
+		if err != nil || table.OrganizationId != period.OrganizationId || table.ProjectId != period.ProjectId {
+			return fmt.Errorf("invalid_blocked_period: table %s not found in this project", tableId)
+		}
+	}
+	return nil
+}
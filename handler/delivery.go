@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resourceDelivery struct {
+	repo *repositories.DBconn
+}
+
+type IHandlerDelivery interface {
+	GetZone(id string) (*models.DeliveryZone, error)
+	ListZones(orgId, projectId string) ([]models.DeliveryZone, error)
+	CreateZone(zone *models.DeliveryZone) error
+	UpdateZone(zone *models.DeliveryZone) error
+	DeleteZone(id string) error
+	QuoteDelivery(orgId, projectId uuid.UUID, address *models.Address) (*models.DeliveryQuote, error)
+	ListOrderSlots(orgId, projectId uuid.UUID, orderType string, date time.Time) ([]models.OrderSlot, error)
+	PrepareOrder(order *models.Order) error
+}
+
+func NewSourceHandlerDelivery(repo *repositories.DBconn) IHandlerDelivery {
+	return &resourceDelivery{repo: repo}
+}
+
+// GetZone busca zona de entrega por ID
+func (r *resourceDelivery) GetZone(id string) (*models.DeliveryZone, error) {
+	zoneId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.DeliveryZones.GetZoneById(zoneId)
+}
+
+// ListZones lista zonas de entrega do projeto
+func (r *resourceDelivery) ListZones(orgId, projectId string) ([]models.DeliveryZone, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.DeliveryZones.ListZones(orgUUID, projectUUID)
+}
+
+// CreateZone cadastra zona de entrega
+func (r *resourceDelivery) CreateZone(zone *models.DeliveryZone) error {
+	zone.Id = uuid.New()
+	zone.Active = true
+	zone.CreatedAt = time.Now()
+	zone.UpdatedAt = time.Now()
+	return r.repo.DeliveryZones.CreateZone(zone)
+}
+
+// UpdateZone atualiza zona de entrega
+func (r *resourceDelivery) UpdateZone(zone *models.DeliveryZone) error {
+	zone.UpdatedAt = time.Now()
+	return r.repo.DeliveryZones.UpdateZone(zone)
+}
+
+// DeleteZone exclui zona de entrega
+func (r *resourceDelivery) DeleteZone(id string) error {
+	zoneId, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	return r.repo.DeliveryZones.SoftDeleteZone(zoneId)
+}
+
+// QuoteDelivery localiza a zona que atende o endereço e retorna taxa, pedido mínimo e tempo de deslocamento
+func (r *resourceDelivery) QuoteDelivery(orgId, projectId uuid.UUID, address *models.Address) (*models.DeliveryQuote, error) {
+	if !address.HasLocation() {
+		return nil, fmt.Errorf("invalid_delivery: address must have latitude and longitude")
+	}
+
+	project, err := r.repo.Projects.GetProjectById(projectId)
+	if err != nil {
+		return nil, err
+	}
+	zones, err := r.repo.DeliveryZones.ListZones(orgId, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	zone := utils.FindDeliveryZone(zones, *address.Latitude, *address.Longitude, project.Latitude, project.Longitude)
+	if zone == nil {
+		return nil, fmt.Errorf("invalid_delivery: address is outside the delivery area")
+	}
+
+	quote := &models.DeliveryQuote{
+		ZoneId:           zone.Id,
+		ZoneName:         zone.Name,
+		Fee:              zone.Fee,
+		MinOrderValue:    zone.MinOrderValue,
+		EstimatedMinutes: zone.EstimatedMinutes,
+	}
+	if project.Latitude != nil && project.Longitude != nil {
+		quote.DistanceKm = utils.HaversineKm(*project.Latitude, *project.Longitude, *address.Latitude, *address.Longitude)
+	}
+	return quote, nil
+}
+
+// ListOrderSlots horários de retirada/entrega do dia com a ocupação da cozinha
+func (r *resourceDelivery) ListOrderSlots(orgId, projectId uuid.UUID, orderType string, date time.Time) ([]models.OrderSlot, error) {
+	settings, err := r.repo.Settings.GetOrCreateSettings(orgId, projectId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOrderTypeEnabled(settings, orderType); err != nil {
+		return nil, err
+	}
+	return r.orderSlots(settings, date, 0, time.Now())
+}
+
+// PrepareOrder valida o tipo do pedido e aplica as regras de retirada/entrega:
+// zona, taxa e pedido mínimo do delivery e o horário agendado dentro da capacidade da cozinha.
+// Deve ser chamado depois do cálculo do total e do tempo estimado de preparo.
+func (r *resourceDelivery) PrepareOrder(order *models.Order) error {
+	if order.Type == "" {
+		order.Type = models.OrderTypeDineIn
+	}
+
+	switch order.Type {
+	case models.OrderTypeDineIn:
+		order.DeliveryAddress = nil
+		order.DeliveryZoneId = nil
+		order.DeliveryFee = 0
+		order.ScheduledFor = nil
+		return nil
+	case models.OrderTypeTakeout, models.OrderTypeDelivery:
+	default:
+		return fmt.Errorf("invalid_order_type: unknown type %q (allowed: dine_in, takeout, delivery)", order.Type)
+	}
+
+	// Pedido de marketplace já vem com endereço, taxa e horário definidos pela plataforma
+	if order.ExternalOrderId != "" {
+		order.TableId = nil
+		order.TableNumber = nil
+		order.TabId = nil
+		order.DeliveryZoneId = nil
+		if order.Type == models.OrderTypeTakeout {
+			order.DeliveryAddress = nil
+			order.DeliveryFee = 0
+		}
+		if order.ScheduledFor != nil && (order.EstimatedDeliveryTime == nil || order.ScheduledFor.After(*order.EstimatedDeliveryTime)) {
+			scheduled := *order.ScheduledFor
+			order.EstimatedDeliveryTime = &scheduled
+		}
+		return nil
+	}
+
+	settings, err := r.repo.Settings.GetOrCreateSettings(order.OrganizationId, order.ProjectId)
+	if err != nil {
+		return err
+	}
+	if err := checkOrderTypeEnabled(settings, order.Type); err != nil {
+		return err
+	}
+
+	// Retirada e entrega não ocupam mesa
+	order.TableId = nil
+	order.TableNumber = nil
+	order.TabId = nil
+	order.DeliveryZoneId = nil
+	order.DeliveryFee = 0
+
+	travelMinutes := 0
+	if order.Type == models.OrderTypeDelivery {
+		if order.DeliveryAddress == nil {
+			return fmt.Errorf("invalid_delivery: delivery address is required")
+		}
+		quote, err := r.QuoteDelivery(order.OrganizationId, order.ProjectId, order.DeliveryAddress)
+		if err != nil {
+			return err
+		}
+		if order.TotalAmount < quote.MinOrderValue {
+			return fmt.Errorf("invalid_delivery: minimum order for zone %s is %.2f", quote.ZoneName, quote.MinOrderValue)
+		}
+		order.DeliveryZoneId = &quote.ZoneId
+		order.DeliveryFee = quote.Fee
+		travelMinutes = quote.EstimatedMinutes
+	} else {
+		order.DeliveryAddress = nil
+	}
+
+	now := time.Now()
+	ready := now
+	if order.EstimatedDeliveryTime != nil {
+		ready = *order.EstimatedDeliveryTime
+	}
+	arrival := ready.Add(time.Duration(travelMinutes) * time.Minute)
+
+	if order.ScheduledFor == nil {
+		order.EstimatedDeliveryTime = &arrival
+		return nil
+	}
+
+	// Horário agendado precisa cair em um slot disponível do funcionamento (dia local do projeto)
+	scheduled := order.ScheduledFor.In(utils.ProjectClock(r.repo.Projects, order.ProjectId).Location())
+	slots, err := r.orderSlots(settings, scheduled, travelMinutes, now)
+	if err != nil {
+		return err
+	}
+	slot := utils.FindOrderSlot(slots, scheduled, orderSlotInterval(settings))
+	if slot == nil {
+		return fmt.Errorf("invalid_slot: %s is outside service hours", scheduled.Format("02/01 15:04"))
+	}
+	if !slot.Available {
+		return fmt.Errorf("invalid_slot: slot %s is not available", slot.Time)
+	}
+
+	order.ScheduledFor = &scheduled
+	if arrival.Before(scheduled) {
+		arrival = scheduled
+	}
+	order.EstimatedDeliveryTime = &arrival
+	return nil
+}
+
+// orderSlots monta os slots da data civil de date no fuso do projeto; o deslocamento do delivery entra na antecedência mínima
+func (r *resourceDelivery) orderSlots(settings *models.Settings, date time.Time, travelMinutes int, now time.Time) ([]models.OrderSlot, error) {
+	interval := orderSlotInterval(settings)
+	dayStart := utils.ProjectClock(r.repo.Projects, settings.ProjectId).Date(date)
+
+	scheduled, err := r.repo.DeliveryZones.ListScheduledOrderTimes(settings.OrganizationId, settings.ProjectId, dayStart, dayStart.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	earliest := now.Add(time.Duration(settings.OrderLeadMinutes+travelMinutes) * time.Minute)
+	times := utils.ServiceTimeSlots(dayStart, settings, interval)
+	return utils.BuildOrderSlots(dayStart, times, interval, scheduled, settings.OrderSlotCapacity, earliest), nil
+}
+
+// orderSlotInterval intervalo dos slots de retirada/entrega (padrão 15 minutos)
+func orderSlotInterval(settings *models.Settings) int {
+	if settings.OrderSlotIntervalMinutes > 0 {
+		return settings.OrderSlotIntervalMinutes
+	}
+	return 15
+}
+
+// checkOrderTypeEnabled verifica se o projeto aceita retirada/entrega
+func checkOrderTypeEnabled(settings *models.Settings, orderType string) error {
+	switch orderType {
+	case models.OrderTypeTakeout:
+		if !settings.EnableTakeout {
+			return fmt.Errorf("invalid_order_type: takeout is disabled for this project")
+		}
+	case models.OrderTypeDelivery:
+		if !settings.EnableDelivery {
+			return fmt.Errorf("invalid_order_type: delivery is disabled for this project")
+		}
+	default:
+		return fmt.Errorf("invalid_order_type: slots are only available for takeout and delivery")
+	}
+	return nil
+}
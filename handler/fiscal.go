@@ -0,0 +1,419 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"lep/config"
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// nfceNumberAttempts tentativas de numeração quando outra emissão simultânea usa o mesmo número
+const nfceNumberAttempts = 3
+
+type resourceFiscal struct {
+	repo *repositories.DBconn
+}
+
+type IHandlerFiscal interface {
+	IssueNfce(orgId, projectId string, request models.NfceIssueRequest, createdBy string) (*models.FiscalDocument, error)
+	TransmitNfce(id string) (*models.FiscalDocument, error)
+	CancelNfce(id string, request models.NfceCancelRequest) (*models.FiscalDocument, error)
+	GetDocument(id string) (*models.FiscalDocument, error)
+	ListDocuments(orgId, projectId, status, orderId string, limit int) ([]models.FiscalDocument, error)
+	GetDocumentQRCode(id string, size int) ([]byte, error)
+}
+
+func NewSourceHandlerFiscal(repo *repositories.DBconn) IHandlerFiscal {
+	return &resourceFiscal{repo: repo}
+}
+
+// IssueNfce emite a NFC-e dos pedidos entregues: monta, valida, assina, grava vinculada aos pedidos e transmite.
+// Falha de comunicação com a SEFAZ não desfaz a emissão: a nota fica "signed" para nova transmissão.
+func (r *resourceFiscal) IssueNfce(orgId, projectId string, request models.NfceIssueRequest, createdBy string) (*models.FiscalDocument, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := r.repo.Settings.GetOrCreateSettings(orgUUID, projectUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.NfceEnabled {
+		return nil, errors.New("nfce_not_configured: NFC-e is disabled in project settings")
+	}
+	if err := utils.ValidateNfceCsc(settings.NfceCscId, settings.NfceCsc); err != nil {
+		return nil, err
+	}
+	if _, err := utils.GetNfceProvider(settings.NfceProvider); err != nil {
+		return nil, fmt.Errorf("nfce_not_configured: %w", err)
+	}
+	organization, err := r.repo.Organizations.GetOrganizationById(orgUUID)
+	if err != nil {
+		return nil, fmt.Errorf("organization not found: %w", err)
+	}
+	certificate, err := r.loadCertificate(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := r.loadOrders(orgUUID, projectUUID, request.OrderIds)
+	if err != nil {
+		return nil, err
+	}
+	items, otherCharges, delivery, err := r.buildItems(orders)
+	if err != nil {
+		return nil, err
+	}
+
+	environment, series := nfceEnvironment(settings), nfceSeries(settings)
+	orderIds := make(pq.StringArray, 0, len(orders))
+	for _, order := range orders {
+		orderIds = append(orderIds, order.Id.String())
+	}
+
+	for attempt := 1; ; attempt++ {
+		number, err := r.repo.Fiscal.NextNumber(orgUUID, environment, series, settings.NfceStartNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		result, err := utils.BuildNfce(utils.NfceInput{
+			Organization:     organization,
+			Environment:      environment,
+			Series:           series,
+			Number:           number,
+			IssuedAt:         now,
+			Items:            items,
+			OtherCharges:     otherCharges,
+			Delivery:         delivery,
+			Payments:         request.Payments,
+			CustomerDocument: request.CustomerDocument,
+			CustomerName:     request.CustomerName,
+			AdditionalInfo:   request.AdditionalInfo,
+			CscId:            settings.NfceCscId,
+			Csc:              settings.NfceCsc,
+			QrCodeUrl:        settings.NfceQrCodeUrl,
+			ConsultUrl:       settings.NfceConsultUrl,
+		}, certificate)
+		if err != nil {
+			return nil, err
+		}
+
+		payments := models.FiscalPayments(request.Payments)
+		if len(payments) == 0 {
+			payments = models.FiscalPayments{{Method: models.FiscalPaymentCash, Amount: result.TotalAmount}}
+		}
+		document := &models.FiscalDocument{
+			Id:               uuid.New(),
+			OrganizationId:   orgUUID,
+			ProjectId:        projectUUID,
+			Model:            models.FiscalModelNfce,
+			Environment:      environment,
+			Series:           series,
+			Number:           number,
+			AccessKey:        result.AccessKey,
+			Status:           models.FiscalStatusSigned,
+			Provider:         settings.NfceProvider,
+			OrderIds:         orderIds,
+			CustomerDocument: strings.TrimSpace(request.CustomerDocument),
+			TotalAmount:      result.TotalAmount,
+			Payments:         payments,
+			Change:           result.Change,
+			QRCodeUrl:        result.QRCodeUrl,
+			ConsultUrl:       result.ConsultUrl,
+			XML:              string(result.XML),
+			IssuedAt:         now,
+			CreatedBy:        parseActor(createdBy),
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+
+		err = r.repo.Fiscal.CreateDocument(document)
+		if err != nil && utils.IsDuplicateKeyError(err) && attempt < nfceNumberAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return r.transmit(document, certificate)
+	}
+}
+
+// TransmitNfce reenvia à SEFAZ a nota que ficou sem resposta
+func (r *resourceFiscal) TransmitNfce(id string) (*models.FiscalDocument, error) {
+	document, err := r.GetDocument(id)
+	if err != nil {
+		return nil, err
+	}
+	if document.Status != models.FiscalStatusSigned {
+		return nil, fmt.Errorf("invalid_nfce: document is %s; only signed documents can be transmitted", document.Status)
+	}
+	settings, err := r.repo.Settings.GetOrCreateSettings(document.OrganizationId, document.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+	certificate, err := r.loadCertificate(settings)
+	if err != nil {
+		return nil, err
+	}
+	return r.transmit(document, certificate)
+}
+
+// CancelNfce registra o evento de cancelamento da nota autorizada e libera os pedidos para nova emissão
+func (r *resourceFiscal) CancelNfce(id string, request models.NfceCancelRequest) (*models.FiscalDocument, error) {
+	document, err := r.GetDocument(id)
+	if err != nil {
+		return nil, err
+	}
+	if document.Status != models.FiscalStatusAuthorized {
+		return nil, fmt.Errorf("invalid_nfce: document is %s; only authorized documents can be cancelled", document.Status)
+	}
+	reason := strings.TrimSpace(request.Reason)
+	if len([]rune(reason)) < 15 || len([]rune(reason)) > 255 {
+		return nil, errors.New("invalid_nfce: reason must have 15 to 255 characters")
+	}
+
+	settings, err := r.repo.Settings.GetOrCreateSettings(document.OrganizationId, document.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+	certificate, err := r.loadCertificate(settings)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := utils.GetNfceProvider(document.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("nfce_not_configured: %w", err)
+	}
+
+	event, err := utils.BuildNfceCancelEvent(document.AccessKey, document.Environment, document.Protocol, reason, time.Now(), certificate)
+	if err != nil {
+		return nil, err
+	}
+	response, err := provider.Cancel(utils.NfceTransmission{
+		AccessKey:   document.AccessKey,
+		Environment: document.Environment,
+		XML:         event,
+		Certificate: certificate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("nfce_unavailable: %w", err)
+	}
+	if !response.Authorized() {
+		return nil, fmt.Errorf("nfce_rejected: %s - %s", response.StatusCode, response.StatusMessage)
+	}
+
+	document.Status = models.FiscalStatusCancelled
+	document.CancelProtocol = response.Protocol
+	document.CancelReason = reason
+	document.CancelledAt = &response.ReceivedAt
+	if err := r.repo.Fiscal.ReleaseOrders(document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// GetDocument busca nota fiscal por ID
+func (r *resourceFiscal) GetDocument(id string) (*models.FiscalDocument, error) {
+	documentId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Fiscal.GetDocumentById(documentId)
+}
+
+// ListDocuments lista notas do projeto (limite padrão 100, máximo 500)
+func (r *resourceFiscal) ListDocuments(orgId, projectId, status, orderId string, limit int) ([]models.FiscalDocument, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	return r.repo.Fiscal.ListDocuments(orgUUID, projectUUID, status, orderId, limit)
+}
+
+// GetDocumentQRCode gera o PNG do QR Code impresso no DANFE NFC-e
+func (r *resourceFiscal) GetDocumentQRCode(id string, size int) ([]byte, error) {
+	document, err := r.GetDocument(id)
+	if err != nil {
+		return nil, err
+	}
+	return utils.GeneratePixQRCode(document.QRCodeUrl, size)
+}
+
+// transmit envia a nota ao provedor e grava o retorno: autorizada recebe o protocolo (nfeProc),
+// rejeitada libera os pedidos e falha de comunicação mantém a nota assinada
+func (r *resourceFiscal) transmit(document *models.FiscalDocument, certificate *utils.NfceCertificate) (*models.FiscalDocument, error) {
+	provider, err := utils.GetNfceProvider(document.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("nfce_not_configured: %w", err)
+	}
+
+	response, err := provider.Authorize(utils.NfceTransmission{
+		AccessKey:   document.AccessKey,
+		Environment: document.Environment,
+		XML:         []byte(document.XML),
+		Certificate: certificate,
+	})
+	if err != nil {
+		document.StatusCode = ""
+		document.StatusMessage = "transmission failed: " + err.Error()
+		if err := r.repo.Fiscal.UpdateDocument(document); err != nil {
+			return nil, err
+		}
+		return document, nil
+	}
+
+	document.StatusCode = response.StatusCode
+	document.StatusMessage = response.StatusMessage
+	if !response.Authorized() {
+		document.Status = models.FiscalStatusRejected
+		if err := r.repo.Fiscal.ReleaseOrders(document); err != nil {
+			return nil, err
+		}
+		return document, nil
+	}
+
+	document.Status = models.FiscalStatusAuthorized
+	document.Protocol = response.Protocol
+	document.AuthorizedAt = &response.ReceivedAt
+	document.XML = string(utils.BuildNfeProc([]byte(document.XML), response.ProtocolXML))
+	if err := r.repo.Fiscal.UpdateDocument(document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// loadCertificate certificado A1 das configurações; fora de produção, sem arquivo, usa o certificado de teste
+func (r *resourceFiscal) loadCertificate(settings *models.Settings) (*utils.NfceCertificate, error) {
+	if settings.NfceCertificatePath != "" {
+		return utils.LoadNfceCertificate(settings.NfceCertificatePath, settings.NfceCertificatePassword)
+	}
+	if config.ENV != "prod" {
+		return utils.NfceTestCertificate()
+	}
+	return nil, errors.New("nfce_not_configured: certificate path is required in project settings")
+}
+
+// loadOrders pedidos entregues do projeto ainda sem nota
+func (r *resourceFiscal) loadOrders(orgId, projectId uuid.UUID, orderIds []uuid.UUID) ([]models.Order, error) {
+	if len(orderIds) == 0 {
+		return nil, errors.New("invalid_nfce: order_ids is required")
+	}
+	seen := make(map[uuid.UUID]bool)
+	orders := make([]models.Order, 0, len(orderIds))
+	for _, id := range orderIds {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		order, err := r.repo.Orders.GetOrderById(id.String())
+		if err != nil || order.OrganizationId != orgId || order.ProjectId != projectId {
+			return nil, fmt.Errorf("order %s not found", id)
+		}
+		if order.Status != models.OrderStatusDelivered {
+			return nil, fmt.Errorf("invalid_nfce: order %s is %s; only delivered orders can be invoiced", id, order.Status)
+		}
+		if order.FiscalDocumentId != nil {
+			return nil, fmt.Errorf("fiscal_conflict: order %s already has a fiscal document", id)
+		}
+		orders = append(orders, *order)
+	}
+	return orders, nil
+}
+
+// buildItems itens da nota com os dados fiscais do cadastro; taxas de entrega somadas como outras despesas
+func (r *resourceFiscal) buildItems(orders []models.Order) ([]utils.NfceItem, float64, bool, error) {
+	var productIds []uuid.UUID
+	for _, order := range orders {
+		for _, item := range order.Items {
+			productIds = append(productIds, item.ProductId)
+		}
+	}
+	products := make(map[uuid.UUID]models.Product)
+	if len(productIds) > 0 {
+		list, err := r.repo.Products.GetProductsByIds(productIds)
+		if err != nil {
+			return nil, 0, false, err
+		}
+		for _, product := range list {
+			products[product.Id] = product
+		}
+	}
+
+	var items []utils.NfceItem
+	otherCharges := 0.0
+	delivery := false
+	for _, order := range orders {
+		otherCharges += order.DeliveryFee
+		if order.Type == models.OrderTypeDelivery {
+			delivery = true
+		}
+		for _, item := range order.Items {
+			product, ok := products[item.ProductId]
+			if !ok {
+				return nil, 0, false, fmt.Errorf("invalid_nfce: product %s of order %s not found", item.ProductId, order.Id)
+			}
+			unitPrice := item.Price + utils.ModifiersDelta(item)
+			if item.Quantity <= 0 || unitPrice <= 0 {
+				continue
+			}
+			name := item.ProductName
+			if name == "" {
+				name = product.Name
+			}
+			if item.Variant != "" {
+				name += " (" + item.Variant + ")"
+			}
+			items = append(items, utils.NfceItem{
+				Product:   product,
+				Name:      name,
+				Quantity:  float64(item.Quantity),
+				UnitPrice: unitPrice,
+			})
+		}
+	}
+	if len(items) == 0 {
+		return nil, 0, false, errors.New("invalid_nfce: orders have no billable items")
+	}
+	return items, otherCharges, delivery, nil
+}
+
+// nfceEnvironment ambiente configurado (homologação quando não informado)
+func nfceEnvironment(settings *models.Settings) int {
+	if settings.NfceEnvironment == models.FiscalEnvironmentProduction {
+		return models.FiscalEnvironmentProduction
+	}
+	return models.FiscalEnvironmentHomologation
+}
+
+// nfceSeries série configurada (1 quando não informada)
+func nfceSeries(settings *models.Settings) int {
+	if settings.NfceSeries <= 0 {
+		return 1
+	}
+	return settings.NfceSeries
+}
@@ -0,0 +1,421 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resourceIngredient struct {
+	repo *repositories.DBconn
+}
+
+type IHandlerIngredient interface {
+	GetIngredient(id string) (*models.Ingredient, error)
+	ListIngredients(orgId, projectId string) ([]models.Ingredient, error)
+	CreateIngredient(ingredient *models.Ingredient) error
+	UpdateIngredient(ingredient *models.Ingredient) error
+	DeleteIngredient(id string) error
+	GetRecipe(product *models.Product) (*models.ProductFoodCost, error)
+	SetRecipe(product *models.Product, items []models.RecipeItem) (*models.ProductFoodCost, error)
+	ListFoodCost(orgId, projectId string) ([]models.ProductFoodCost, error)
+	GetConsumption(orgId, projectId string, from, to time.Time) (*models.ConsumptionReport, error)
+	CreateCount(orgId, projectId string, request models.InventoryCountRequest, countedBy string) (*models.InventoryCount, error)
+	GetCount(id string) (*models.InventoryCount, error)
+	ListCounts(orgId, projectId string, limit int) ([]models.InventoryCount, error)
+}
+
+func NewSourceHandlerIngredient(repo *repositories.DBconn) IHandlerIngredient {
+	return &resourceIngredient{repo: repo}
+}
+
+// GetIngredient busca ingrediente por ID
+func (r *resourceIngredient) GetIngredient(id string) (*models.Ingredient, error) {
+	ingredientId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Ingredients.GetIngredientById(ingredientId)
+}
+
+// ListIngredients lista ingredientes com o estoque teórico (descontado o consumo desde a última contagem)
+func (r *resourceIngredient) ListIngredients(orgId, projectId string) ([]models.Ingredient, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	ingredients, err := r.repo.Ingredients.ListIngredients(orgUUID, projectUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	consumption, err := r.consumptionSinceLastCount(orgUUID, projectUUID, ingredients)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ingredients {
+		ingredients[i].TheoreticalStock = ingredients[i].Stock - consumption[ingredients[i].Id]
+	}
+	return ingredients, nil
+}
+
+// CreateIngredient cria ingrediente com estoque e custo iniciais
+func (r *resourceIngredient) CreateIngredient(ingredient *models.Ingredient) error {
+	if ingredient.Stock < 0 || ingredient.UnitCost < 0 {
+		return errors.New("invalid_ingredient: stock and unit_cost must not be negative")
+	}
+
+	now := time.Now()
+	ingredient.Id = uuid.New()
+	ingredient.Active = true
+	// O estoque inicial vale como primeira contagem
+	ingredient.LastCountedAt = &now
+	ingredient.CreatedAt = now
+	ingredient.UpdatedAt = now
+	return r.repo.Ingredients.CreateIngredient(ingredient)
+}
+
+// UpdateIngredient atualiza cadastro do ingrediente
+func (r *resourceIngredient) UpdateIngredient(ingredient *models.Ingredient) error {
+	return r.repo.Ingredients.UpdateIngredient(ingredient)
+}
+
+// DeleteIngredient exclui ingrediente e o retira das fichas técnicas
+func (r *resourceIngredient) DeleteIngredient(id string) error {
+	ingredientId, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	return r.repo.Ingredients.SoftDeleteIngredient(ingredientId)
+}
+
+// GetRecipe retorna a ficha técnica do produto com custo e food cost
+func (r *resourceIngredient) GetRecipe(product *models.Product) (*models.ProductFoodCost, error) {
+	recipe, err := r.repo.Ingredients.GetRecipe(product.Id)
+	if err != nil {
+		return nil, err
+	}
+	return r.foodCost(product, recipe)
+}
+
+// SetRecipe substitui a ficha técnica do produto
+func (r *resourceIngredient) SetRecipe(product *models.Product, items []models.RecipeItem) (*models.ProductFoodCost, error) {
+	ingredientIds := make([]uuid.UUID, 0, len(items))
+	seen := make(map[uuid.UUID]bool, len(items))
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, errors.New("invalid_recipe: quantity must be greater than zero")
+		}
+		if seen[item.IngredientId] {
+			return nil, fmt.Errorf("invalid_recipe: ingredient %s is listed more than once", item.IngredientId)
+		}
+		seen[item.IngredientId] = true
+		ingredientIds = append(ingredientIds, item.IngredientId)
+	}
+
+	ingredients, err := r.projectIngredients(product.OrganizationId, product.ProjectId, ingredientIds)
+	if err != nil {
+		return nil, err
+	}
+	if len(ingredients) != len(ingredientIds) {
+		return nil, errors.New("invalid_recipe: ingredient not found in this project")
+	}
+
+	now := time.Now()
+	recipe := make([]models.RecipeItem, 0, len(items))
+	for _, item := range items {
+		recipe = append(recipe, models.RecipeItem{
+			Id:             uuid.New(),
+			OrganizationId: product.OrganizationId,
+			ProjectId:      product.ProjectId,
+			ProductId:      product.Id,
+			IngredientId:   item.IngredientId,
+			Quantity:       item.Quantity,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+	}
+
+	if err := r.repo.Ingredients.ReplaceRecipe(product.Id, recipe); err != nil {
+		return nil, err
+	}
+
+	result := utils.CalculateFoodCost(*product, recipe, ingredients)
+	return &result, nil
+}
+
+// ListFoodCost retorna o food cost de todos os produtos com ficha técnica
+func (r *resourceIngredient) ListFoodCost(orgId, projectId string) ([]models.ProductFoodCost, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	recipeItems, err := r.repo.Ingredients.ListRecipes(orgUUID, projectUUID)
+	if err != nil {
+		return nil, err
+	}
+	recipes := utils.GroupRecipes(recipeItems)
+	if len(recipes) == 0 {
+		return []models.ProductFoodCost{}, nil
+	}
+
+	productIds := make([]uuid.UUID, 0, len(recipes))
+	for productId := range recipes {
+		productIds = append(productIds, productId)
+	}
+	products, err := r.repo.Products.GetProductsByIds(productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	ingredients, err := r.projectIngredients(orgUUID, projectUUID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.ProductFoodCost, 0, len(products))
+	for _, product := range products {
+		result = append(result, utils.CalculateFoodCost(product, recipes[product.Id], ingredients))
+	}
+	return result, nil
+}
+
+// GetConsumption calcula o consumo teórico de ingredientes dos pedidos iniciados no período
+func (r *resourceIngredient) GetConsumption(orgId, projectId string, from, to time.Time) (*models.ConsumptionReport, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := r.repo.Ingredients.ListStartedOrders(orgUUID, projectUUID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	recipeItems, err := r.repo.Ingredients.ListRecipes(orgUUID, projectUUID)
+	if err != nil {
+		return nil, err
+	}
+	ingredients, err := r.projectIngredients(orgUUID, projectUUID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	consumption := utils.TheoreticalConsumption(orders, utils.GroupRecipes(recipeItems), nil)
+
+	report := &models.ConsumptionReport{
+		From:        from,
+		To:          to,
+		OrdersCount: len(orders),
+		Items:       make([]models.IngredientConsumption, 0, len(consumption)),
+	}
+	for ingredientId, quantity := range consumption {
+		ingredient := ingredients[ingredientId]
+		cost := utils.RoundMoney(quantity * ingredient.UnitCost)
+		report.Items = append(report.Items, models.IngredientConsumption{
+			IngredientId:   ingredientId,
+			IngredientName: ingredient.Name,
+			Unit:           ingredient.Unit,
+			Quantity:       quantity,
+			Cost:           cost,
+		})
+		report.TotalCost += cost
+	}
+	report.TotalCost = utils.RoundMoney(report.TotalCost)
+	sort.Slice(report.Items, func(i, j int) bool { return report.Items[i].IngredientName < report.Items[j].IngredientName })
+	return report, nil
+}
+
+// CreateCount registra contagem física, calcula a divergência contra o estoque teórico
+// e redefine o estoque dos ingredientes contados
+func (r *resourceIngredient) CreateCount(orgId, projectId string, request models.InventoryCountRequest, countedBy string) (*models.InventoryCount, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(request.Items) == 0 {
+		return nil, errors.New("invalid_count: count must have at least one ingredient")
+	}
+
+	ingredientIds := make([]uuid.UUID, 0, len(request.Items))
+	seen := make(map[uuid.UUID]bool, len(request.Items))
+	for _, item := range request.Items {
+		if item.Counted < 0 {
+			return nil, errors.New("invalid_count: counted quantity must not be negative")
+		}
+		if seen[item.IngredientId] {
+			return nil, fmt.Errorf("invalid_count: ingredient %s is listed more than once", item.IngredientId)
+		}
+		seen[item.IngredientId] = true
+		ingredientIds = append(ingredientIds, item.IngredientId)
+	}
+
+	ingredients, err := r.projectIngredients(orgUUID, projectUUID, ingredientIds)
+	if err != nil {
+		return nil, err
+	}
+	if len(ingredients) != len(ingredientIds) {
+		return nil, errors.New("invalid_count: ingredient not found in this project")
+	}
+
+	counted := make([]models.Ingredient, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		counted = append(counted, ingredient)
+	}
+	consumption, err := r.consumptionSinceLastCount(orgUUID, projectUUID, counted)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	count := &models.InventoryCount{
+		Id:             uuid.New(),
+		OrganizationId: orgUUID,
+		ProjectId:      projectUUID,
+		Notes:          request.Notes,
+		CountedBy:      parseActor(countedBy),
+		CountedAt:      now,
+		CreatedAt:      now,
+		Items:          make([]models.InventoryCountItem, 0, len(request.Items)),
+	}
+	for _, item := range request.Items {
+		ingredient := ingredients[item.IngredientId]
+		expected := ingredient.Stock - consumption[ingredient.Id]
+		variance := item.Counted - expected
+		varianceCost := utils.RoundMoney(variance * ingredient.UnitCost)
+
+		count.Items = append(count.Items, models.InventoryCountItem{
+			Id:             uuid.New(),
+			CountId:        count.Id,
+			IngredientId:   ingredient.Id,
+			IngredientName: ingredient.Name,
+			Unit:           ingredient.Unit,
+			Expected:       expected,
+			Counted:        item.Counted,
+			Variance:       variance,
+			UnitCost:       ingredient.UnitCost,
+			VarianceCost:   varianceCost,
+		})
+		count.TotalVarianceCost += varianceCost
+	}
+	count.TotalVarianceCost = utils.RoundMoney(count.TotalVarianceCost)
+
+	if err := r.repo.Ingredients.CreateCount(count); err != nil {
+		return nil, err
+	}
+	return count, nil
+}
+
+// GetCount busca contagem (relatório de divergência) por ID
+func (r *resourceIngredient) GetCount(id string) (*models.InventoryCount, error) {
+	countId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Ingredients.GetCountById(countId)
+}
+
+// ListCounts lista contagens do projeto
+func (r *resourceIngredient) ListCounts(orgId, projectId string, limit int) ([]models.InventoryCount, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Ingredients.ListCounts(orgUUID, projectUUID, limit)
+}
+
+// foodCost calcula o custo da ficha técnica com os custos atuais dos ingredientes
+func (r *resourceIngredient) foodCost(product *models.Product, recipe []models.RecipeItem) (*models.ProductFoodCost, error) {
+	ingredientIds := make([]uuid.UUID, 0, len(recipe))
+	for _, item := range recipe {
+		ingredientIds = append(ingredientIds, item.IngredientId)
+	}
+	ingredients, err := r.projectIngredients(product.OrganizationId, product.ProjectId, ingredientIds)
+	if err != nil {
+		return nil, err
+	}
+
+	result := utils.CalculateFoodCost(*product, recipe, ingredients)
+	return &result, nil
+}
+
+// projectIngredients busca ingredientes do projeto indexados por ID (ids nil = todos)
+func (r *resourceIngredient) projectIngredients(orgId, projectId uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]models.Ingredient, error) {
+	var ingredients []models.Ingredient
+	var err error
+	if ids == nil {
+		ingredients, err = r.repo.Ingredients.ListIngredients(orgId, projectId)
+	} else {
+		ingredients, err = r.repo.Ingredients.GetIngredientsByIds(ids)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uuid.UUID]models.Ingredient, len(ingredients))
+	for _, ingredient := range ingredients {
+		if ingredient.OrganizationId == orgId && ingredient.ProjectId == projectId {
+			result[ingredient.Id] = ingredient
+		}
+	}
+	return result, nil
+}
+
+// consumptionSinceLastCount consumo teórico de cada ingrediente desde a sua última contagem
+func (r *resourceIngredient) consumptionSinceLastCount(orgId, projectId uuid.UUID, ingredients []models.Ingredient) (map[uuid.UUID]float64, error) {
+	if len(ingredients) == 0 {
+		return map[uuid.UUID]float64{}, nil
+	}
+
+	since := make(map[uuid.UUID]time.Time, len(ingredients))
+	from := time.Now()
+	for _, ingredient := range ingredients {
+		start := ingredient.CreatedAt
+		if ingredient.LastCountedAt != nil {
+			start = *ingredient.LastCountedAt
+		}
+		since[ingredient.Id] = start
+		if start.Before(from) {
+			from = start
+		}
+	}
+
+	orders, err := r.repo.Ingredients.ListStartedOrders(orgId, projectId, from, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	recipeItems, err := r.repo.Ingredients.ListRecipes(orgId, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.TheoreticalConsumption(orders, utils.GroupRecipes(recipeItems), since), nil
+}
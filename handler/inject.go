@@ -12,10 +12,28 @@ type Handlers struct {
 	HandlerProducts           IHandlerProducts
 	HandlerAuth               IHandlerAuth
 	HandlerOrder              IOrderHandler
+	HandlerInventory          IHandlerInventory
+	HandlerIngredient         IHandlerIngredient
+	HandlerSupplier           IHandlerSupplier
+	HandlerPublicOrder        IHandlerPublicOrder
+	HandlerKitchenStation     IKitchenStationHandler
+	HandlerTab                IHandlerTab
+	HandlerPix                IHandlerPix
+	HandlerPrint              IHandlerPrint
+	HandlerPrepTime           IHandlerPrepTime
+	HandlerDelivery           IHandlerDelivery
+	HandlerMarketplace        IHandlerMarketplace
+	HandlerPos                IHandlerPos
+	HandlerFiscal             IHandlerFiscal
+	HandlerServiceRequest     IHandlerServiceRequest
+	HandlerSLA                IHandlerSLA
 	HandlerOrganization       IHandlerOrganization
 	HandlerTables             IHandlerTables
 	HandlerWaitlist           IHandlerWaitlist
 	HandlerReservation        IHandlerReservation
+	HandlerBlockedPeriod      IHandlerBlockedPeriod
+	HandlerTableAllocation    IHandlerTableAllocation
+	HandlerPacing             IHandlerPacing
 	HandlerCustomer           IHandlerCustomer
 	HandlerProject            IProjectHandler
 	HandlerSettings           ISettingsHandler
@@ -60,11 +78,30 @@ func (h *Handlers) Inject(repo *repositories.DBconn, db interface{}) {
 
 	h.HandlerProducts = NewSourceHandlerProducts(repo)
 	h.HandlerAuth = NewAuthHandler(repo)
-	h.HandlerOrder = NewOrderHandler(repo.Orders, repo.Products, repo.KitchenQueue)
+	h.HandlerInventory = NewSourceHandlerInventory(repo)
+	h.HandlerIngredient = NewSourceHandlerIngredient(repo)
+	h.HandlerSupplier = NewSourceHandlerSupplier(repo)
+	h.HandlerTab = NewSourceHandlerTab(repo)
+	h.HandlerPrint = NewSourceHandlerPrint(repo, h.HandlerTab)
+	h.HandlerPrepTime = NewSourceHandlerPrepTime(repo)
+	h.HandlerDelivery = NewSourceHandlerDelivery(repo)
+	marketplaceSync := utils.NewMarketplaceSyncService(repo.Marketplace)
+	h.HandlerOrder = NewOrderHandler(repo.Orders, repo.Products, repo.KitchenQueue, repo.KitchenStations, repo.OrderStatusHistory, repo.Stock, h.HandlerInventory, h.HandlerPrint, h.HandlerPrepTime, h.HandlerDelivery, marketplaceSync)
+	h.HandlerPublicOrder = NewSourceHandlerPublicOrder(repo, h.HandlerOrder)
+	h.HandlerMarketplace = NewSourceHandlerMarketplace(repo, h.HandlerOrder, marketplaceSync)
+	h.HandlerPos = NewSourceHandlerPos(repo)
+	h.HandlerFiscal = NewSourceHandlerFiscal(repo)
+	h.HandlerServiceRequest = NewSourceHandlerServiceRequest(repo)
+	h.HandlerKitchenStation = NewKitchenStationHandler(repo.KitchenStations)
+	h.HandlerSLA = NewSourceHandlerSLA(repo)
+	h.HandlerPix = NewSourceHandlerPix(repo, h.HandlerTab)
 	h.HandlerOrganization = NewSourceHandlerOrganization(repo, repo.DB)
 	h.HandlerTables = NewSourceHandlerTables(repo)
 	h.HandlerWaitlist = NewSourceHandlerWaitlist(repo)
 	h.HandlerReservation = NewSourceHandlerReservation(repo)
+	h.HandlerBlockedPeriod = NewSourceHandlerBlockedPeriod(repo)
+	h.HandlerTableAllocation = NewSourceHandlerTableAllocation(repo)
+	h.HandlerPacing = NewSourceHandlerPacing(repo)
 	h.HandlerCustomer = NewSourceHandlerCustomer(repo)
 	h.HandlerProject = NewProjectHandler(repo.Projects, repo.Settings, repo.Notifications, repo.CascadeDelete)
 	h.HandlerSettings = NewSettingsHandler(repo.Settings)
@@ -106,6 +143,7 @@ func (h *Handlers) Inject(repo *repositories.DBconn, db interface{}) {
 		repo.Products,
 		repo.Reservations,
 		repo.Modules,
+		repo.Projects,
 	)
 
 	// Sidebar Config Handler
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+
+	"github.com/google/uuid"
+)
+
+type resourceInventory struct {
+	repo         *repositories.DBconn
+	eventService *utils.EventService
+}
+
+type IHandlerInventory interface {
+	AdjustStock(productId string, request models.StockAdjustmentRequest, changedBy string) (*models.StockMovement, error)
+	ListMovements(orgId, projectId, productId string, limit int) ([]models.StockMovement, error)
+	ListLowStock(orgId, projectId string) ([]models.Product, error)
+	ProcessMovements(movements []models.StockMovement)
+}
+
+func NewSourceHandlerInventory(repo *repositories.DBconn) IHandlerInventory {
+	eventService := utils.NewEventService(repo.Notifications, repo.Projects, repo.Settings)
+	return &resourceInventory{repo: repo, eventService: eventService}
+}
+
+// AdjustStock registra entrada, perda ou correção manual de estoque
+func (r *resourceInventory) AdjustStock(productId string, request models.StockAdjustmentRequest, changedBy string) (*models.StockMovement, error) {
+	productUUID, err := uuid.Parse(productId)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Quantity == 0 {
+		return nil, errors.New("invalid_stock: quantity must not be zero")
+	}
+	if request.Reason == "" {
+		return nil, errors.New("invalid_stock: reason is required")
+	}
+
+	switch request.Type {
+	case "":
+		request.Type = models.StockMovementManualAdjustment
+	case models.StockMovementManualAdjustment, models.StockMovementRestock, models.StockMovementLoss:
+	default:
+		return nil, errors.New("invalid_stock: type must be one of manual_adjustment, restock, loss")
+	}
+	if request.Type == models.StockMovementRestock && request.Quantity < 0 {
+		return nil, errors.New("invalid_stock: restock quantity must be positive")
+	}
+	if request.Type == models.StockMovementLoss && request.Quantity > 0 {
+		return nil, errors.New("invalid_stock: loss quantity must be negative")
+	}
+
+	movement, err := r.repo.Stock.AdjustStock(productUUID, request.Quantity, request.Type, request.Reason, parseActor(changedBy))
+	if err != nil {
+		return nil, err
+	}
+
+	r.ProcessMovements([]models.StockMovement{*movement})
+	return movement, nil
+}
+
+// ListMovements lista o livro de movimentações do projeto (opcionalmente de um produto)
+func (r *resourceInventory) ListMovements(orgId, projectId, productId string, limit int) ([]models.StockMovement, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	var productUUID *uuid.UUID
+	if productId != "" {
+		parsed, err := uuid.Parse(productId)
+		if err != nil {
+			return nil, err
+		}
+		productUUID = &parsed
+	}
+
+	return r.repo.Stock.ListMovements(orgUUID, projectUUID, productUUID, limit)
+}
+
+// ListLowStock lista produtos no limite de estoque baixo
+func (r *resourceInventory) ListLowStock(orgId, projectId string) ([]models.Product, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := r.repo.Settings.GetOrCreateSettings(orgUUID, projectUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.repo.Stock.ListLowStock(orgUUID, projectUUID, settings.LowStockThreshold)
+}
+
+// ProcessMovements dispara alertas quando uma saída cruza o limite de estoque baixo ou zera o produto
+func (r *resourceInventory) ProcessMovements(movements []models.StockMovement) {
+	for _, movement := range movements {
+		if movement.StockAfter >= movement.StockBefore {
+			continue
+		}
+
+		product, err := r.repo.Products.GetProductById(movement.ProductId)
+		if err != nil {
+			continue
+		}
+
+		threshold := 0
+		if product.LowStockThreshold != nil {
+			threshold = *product.LowStockThreshold
+		} else if settings, err := r.repo.Settings.GetOrCreateSettings(movement.OrganizationId, movement.ProjectId); err == nil {
+			threshold = settings.LowStockThreshold
+		}
+
+		// Alerta apenas na passagem pelo limite, não a cada venda abaixo dele
+		crossedLow := movement.StockBefore > threshold && movement.StockAfter <= threshold
+		soldOut := movement.StockAfter == 0
+		if !crossedLow && !soldOut {
+			continue
+		}
+
+		eventType := "stock.low"
+		if soldOut {
+			eventType = "stock.out"
+		}
+		utils.GetRealtimeBus().Publish(movement.OrganizationId, movement.ProjectId, utils.RealtimeTopicKitchen, eventType, product)
+
+		if err := r.eventService.TriggerLowStock(movement.OrganizationId, movement.ProjectId, product, threshold); err != nil {
+			fmt.Printf("⚠️ Erro ao notificar estoque baixo de %s: %v\n", product.Name, err)
+		}
+	}
+}
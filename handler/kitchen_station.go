@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type KitchenStationHandler struct {
+	stationRepo repositories.IKitchenStationRepository
+}
+
+type IKitchenStationHandler interface {
+	GetStationById(id string) (*models.KitchenStation, error)
+	ListStations(orgId, projectId string) ([]models.KitchenStation, error)
+	CreateStation(station *models.KitchenStation) error
+	UpdateStation(station *models.KitchenStation) error
+	SoftDeleteStation(id string) error
+	CreateDefaultStations(orgId, projectId string) ([]models.KitchenStation, error)
+}
+
+func NewKitchenStationHandler(stationRepo repositories.IKitchenStationRepository) IKitchenStationHandler {
+	return &KitchenStationHandler{stationRepo: stationRepo}
+}
+
+// GetStationById busca estação por ID
+func (h *KitchenStationHandler) GetStationById(id string) (*models.KitchenStation, error) {
+	stationId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return h.stationRepo.GetStationById(stationId)
+}
+
+// ListStations lista estações do projeto
+func (h *KitchenStationHandler) ListStations(orgId, projectId string) ([]models.KitchenStation, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.stationRepo.ListStations(orgUUID, projectUUID)
+}
+
+// CreateStation cria nova estação
+func (h *KitchenStationHandler) CreateStation(station *models.KitchenStation) error {
+	exists, err := h.stationRepo.CheckStationCodeExists(station.OrganizationId, station.ProjectId, station.Code, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar duplicata: %w", err)
+	}
+	if exists {
+		return errors.New("already_exists: station with this code already exists in this project")
+	}
+
+	station.Id = uuid.New()
+	station.CreatedAt = time.Now()
+	station.UpdatedAt = time.Now()
+	return h.stationRepo.CreateStation(station)
+}
+
+// UpdateStation atualiza estação existente
+func (h *KitchenStationHandler) UpdateStation(station *models.KitchenStation) error {
+	exists, err := h.stationRepo.CheckStationCodeExists(station.OrganizationId, station.ProjectId, station.Code, &station.Id)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar duplicata: %w", err)
+	}
+	if exists {
+		return errors.New("already_exists: station with this code already exists in this project")
+	}
+
+	station.UpdatedAt = time.Now()
+	return h.stationRepo.UpdateStation(station)
+}
+
+// SoftDeleteStation remove estação logicamente
+func (h *KitchenStationHandler) SoftDeleteStation(id string) error {
+	stationId, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	return h.stationRepo.SoftDeleteStation(stationId)
+}
+
+// CreateDefaultStations cria as estações padrão (grelha, frios, bar e vinhos) que ainda não existem no projeto
+func (h *KitchenStationHandler) CreateDefaultStations(orgId, projectId string) ([]models.KitchenStation, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := []models.KitchenStation{
+		{Name: "Grelha", Code: "grill", ProductTypes: pq.StringArray{"prato"}, IsDefault: true, Order: 0},
+		{Name: "Frios", Code: "cold", ProductTypes: pq.StringArray{}, Order: 1},
+		{Name: "Bar", Code: "bar", ProductTypes: pq.StringArray{"bebida"}, Order: 2},
+		{Name: "Vinhos", Code: "wine", ProductTypes: pq.StringArray{"vinho"}, Order: 3},
+	}
+
+	created := make([]models.KitchenStation, 0, len(defaults))
+	for _, station := range defaults {
+		exists, err := h.stationRepo.CheckStationCodeExists(orgUUID, projectUUID, station.Code, nil)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			continue
+		}
+
+		station.OrganizationId = orgUUID
+		station.ProjectId = projectUUID
+		station.CategoryIds = pq.StringArray{}
+		station.Active = true
+		if err := h.CreateStation(&station); err != nil {
+			return nil, err
+		}
+		created = append(created, station)
+	}
+
+	return created, nil
+}
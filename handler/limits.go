@@ -3,7 +3,7 @@ package handler
 import (
 	"fmt"
 	"lep/repositories"
-	"time"
+	"lep/utils"
 
 	"github.com/google/uuid"
 )
@@ -66,6 +66,7 @@ type LimitHandler struct {
 	productRepo     repositories.IProductRepository
 	reservationRepo repositories.IReservationRepository
 	moduleRepo      repositories.IModuleRepository
+	projectRepo     repositories.IProjectRepository
 }
 
 // NewLimitHandler cria uma nova instância de LimitHandler
@@ -76,6 +77,7 @@ func NewLimitHandler(
 	productRepo repositories.IProductRepository,
 	reservationRepo repositories.IReservationRepository,
 	moduleRepo repositories.IModuleRepository,
+	projectRepo repositories.IProjectRepository,
 ) *LimitHandler {
 	return &LimitHandler{
 		planRepo:        planRepo,
@@ -84,9 +86,20 @@ func NewLimitHandler(
 		productRepo:     productRepo,
 		reservationRepo: reservationRepo,
 		moduleRepo:      moduleRepo,
+		projectRepo:     projectRepo,
 	}
 }
 
+// countReservationsToday reservas que acontecem hoje no fuso do projeto
+func (h *LimitHandler) countReservationsToday(orgUUID, projUUID uuid.UUID) (int, error) {
+	today := utils.ProjectClock(h.projectRepo, projUUID).Today()
+	reservations, err := h.reservationRepo.GetReservationsInRange(orgUUID, projUUID, today, today.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
+	}
+	return len(reservations), nil
+}
+
 // CheckLimit verifica se a organização pode criar mais um recurso do tipo especificado
 // Retorna: canCreate (pode criar), current (quantidade atual), limit (limite máximo), error
 func (h *LimitHandler) CheckLimit(orgId, projectId string, limitType LimitType) (bool, int, int, error) {
@@ -164,20 +177,8 @@ func (h *LimitHandler) countResource(orgId, projectId string, limitType LimitTyp
 		return len(products), nil
 
 	case LimitReservationsDay:
-		// Contar reservas do dia atual
-		reservations, err := h.reservationRepo.GetReservationsByProject(orgUUID, projUUID)
-		if err != nil {
-			return 0, err
-		}
-		// Filtrar apenas reservas de hoje (Datetime é string no formato "YYYY-MM-DD...")
-		today := time.Now().Format("2006-01-02")
-		count := 0
-		for _, r := range reservations {
-			if len(r.Datetime) >= 10 && r.Datetime[:10] == today {
-				count++
-			}
-		}
-		return count, nil
+		// Contar reservas do dia atual (no fuso do projeto)
+		return h.countReservationsToday(orgUUID, projUUID)
 
 	default:
 		return 0, fmt.Errorf("tipo de limite desconhecido: %s", limitType)
@@ -273,15 +274,7 @@ func (h *LimitHandler) GetUsageAndLimits(orgId, projectId string) (*UsageLimitsR
 	}
 
 	// Contar reservas do dia
-	if reservations, err := h.reservationRepo.GetReservationsByProject(orgUUID, projUUID); err == nil {
-		today := time.Now().Format("2006-01-02")
-		count := 0
-		for _, r := range reservations {
-			// Datetime está em formato string, comparar apenas a data
-			if len(r.Datetime) >= 10 && r.Datetime[:10] == today {
-				count++
-			}
-		}
+	if count, err := h.countReservationsToday(orgUUID, projUUID); err == nil {
 		response.Usage.ReservationsToday = count
 	}
 
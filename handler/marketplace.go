@@ -0,0 +1,467 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resourceMarketplace struct {
+	repo         *repositories.DBconn
+	orderHandler IOrderHandler
+	sync         *utils.MarketplaceSyncService
+}
+
+type IHandlerMarketplace interface {
+	GetIntegration(id string) (*models.MarketplaceIntegration, error)
+	ListIntegrations(orgId, projectId string) ([]models.MarketplaceIntegration, error)
+	CreateIntegration(integration *models.MarketplaceIntegration) error
+	UpdateIntegration(integration *models.MarketplaceIntegration) error
+	DeleteIntegration(id string) error
+	HandleWebhook(marketplace string, body []byte, header http.Header) (*models.MarketplaceWebhookResult, error)
+	GetEvent(id string) (*models.MarketplaceEvent, error)
+	ListEvents(orgId, projectId, direction, status string, limit int) ([]models.MarketplaceEvent, error)
+	RetryEvent(event *models.MarketplaceEvent) (*models.MarketplaceEvent, error)
+}
+
+func NewSourceHandlerMarketplace(repo *repositories.DBconn, orderHandler IOrderHandler, sync *utils.MarketplaceSyncService) IHandlerMarketplace {
+	return &resourceMarketplace{repo: repo, orderHandler: orderHandler, sync: sync}
+}
+
+// GetIntegration busca integração por ID
+func (r *resourceMarketplace) GetIntegration(id string) (*models.MarketplaceIntegration, error) {
+	integrationId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Marketplace.GetIntegrationById(integrationId)
+}
+
+// ListIntegrations lista integrações do projeto
+func (r *resourceMarketplace) ListIntegrations(orgId, projectId string) ([]models.MarketplaceIntegration, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Marketplace.ListIntegrations(orgUUID, projectUUID)
+}
+
+// CreateIntegration cadastra a loja do projeto em um marketplace; gera o segredo do webhook se não informado
+func (r *resourceMarketplace) CreateIntegration(integration *models.MarketplaceIntegration) error {
+	if _, err := utils.GetMarketplaceAdapter(integration.Marketplace); err != nil {
+		return fmt.Errorf("invalid_marketplace: %w", err)
+	}
+	if err := r.checkMerchantAvailable(integration); err != nil {
+		return err
+	}
+
+	if integration.WebhookSecret == "" {
+		integration.WebhookSecret = generateWebhookSecret()
+	}
+	integration.Id = uuid.New()
+	integration.Active = true
+	integration.CreatedAt = time.Now()
+	integration.UpdatedAt = time.Now()
+	return r.repo.Marketplace.CreateIntegration(integration)
+}
+
+// UpdateIntegration atualiza integração (segredo vazio mantém o atual)
+func (r *resourceMarketplace) UpdateIntegration(integration *models.MarketplaceIntegration) error {
+	stored, err := r.repo.Marketplace.GetIntegrationById(integration.Id)
+	if err != nil {
+		return err
+	}
+	if _, err := utils.GetMarketplaceAdapter(integration.Marketplace); err != nil {
+		return fmt.Errorf("invalid_marketplace: %w", err)
+	}
+	if integration.Active {
+		if err := r.checkMerchantAvailable(integration); err != nil {
+			return err
+		}
+	}
+
+	if integration.WebhookSecret == "" {
+		integration.WebhookSecret = stored.WebhookSecret
+	}
+	if integration.ApiToken == "" {
+		integration.ApiToken = stored.ApiToken
+	}
+	integration.CreatedAt = stored.CreatedAt
+	integration.UpdatedAt = time.Now()
+	return r.repo.Marketplace.UpdateIntegration(integration)
+}
+
+// DeleteIntegration exclui integração
+func (r *resourceMarketplace) DeleteIntegration(id string) error {
+	integrationId, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	return r.repo.Marketplace.SoftDeleteIntegration(integrationId)
+}
+
+// checkMerchantAvailable impede duas integrações ativas para a mesma loja do marketplace
+func (r *resourceMarketplace) checkMerchantAvailable(integration *models.MarketplaceIntegration) error {
+	existing, err := r.repo.Marketplace.GetIntegrationByMerchant(integration.Marketplace, integration.MerchantId)
+	if err == nil && existing.Id != integration.Id {
+		return fmt.Errorf("merchant %s already exists for marketplace %s", integration.MerchantId, integration.Marketplace)
+	}
+	return nil
+}
+
+// HandleWebhook valida a assinatura e registra os eventos recebidos do marketplace.
+// Cada evento é gravado uma única vez (marketplace + event_id): reenvios do mesmo evento
+// são contados como duplicados, exceto os que falharam antes, que são reprocessados.
+func (r *resourceMarketplace) HandleWebhook(marketplace string, body []byte, header http.Header) (*models.MarketplaceWebhookResult, error) {
+	adapter, err := utils.GetMarketplaceAdapter(marketplace)
+	if err != nil {
+		return nil, fmt.Errorf("marketplace %s not found", marketplace)
+	}
+
+	events, err := adapter.ParseWebhook(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid_marketplace: %w", err)
+	}
+	if len(events) == 0 {
+		return nil, errors.New("invalid_marketplace: webhook without events")
+	}
+	merchantId := events[0].MerchantId
+	for _, event := range events {
+		if event.EventId == "" || event.ExternalOrderId == "" {
+			return nil, errors.New("invalid_marketplace: event_id and order_id are required")
+		}
+		if event.MerchantId != merchantId {
+			return nil, errors.New("invalid_marketplace: all events of a webhook must belong to the same merchant")
+		}
+	}
+
+	integration, err := r.repo.Marketplace.GetIntegrationByMerchant(marketplace, merchantId)
+	if err != nil {
+		return nil, fmt.Errorf("marketplace integration for merchant %s not found", merchantId)
+	}
+	if err := adapter.VerifySignature(body, header, integration.WebhookSecret); err != nil {
+		return nil, err
+	}
+
+	result := &models.MarketplaceWebhookResult{Received: len(events), Events: make([]models.MarketplaceEvent, 0, len(events))}
+	for _, data := range events {
+		event, isNew, err := r.registerEvent(integration, data)
+		if err != nil {
+			return nil, err
+		}
+		if !isNew {
+			result.Duplicates++
+			result.Events = append(result.Events, *event)
+			continue
+		}
+
+		r.processEvent(integration, event, data)
+		result.Events = append(result.Events, *event)
+	}
+	return result, nil
+}
+
+// registerEvent grava o evento recebido. Retorna isNew=false para evento repetido;
+// evento repetido que falhou antes é devolvido como novo para ser processado de novo.
+func (r *resourceMarketplace) registerEvent(integration *models.MarketplaceIntegration, data models.MarketplaceOrderEvent) (*models.MarketplaceEvent, bool, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	event := &models.MarketplaceEvent{
+		Id:              uuid.New(),
+		OrganizationId:  integration.OrganizationId,
+		ProjectId:       integration.ProjectId,
+		IntegrationId:   integration.Id,
+		Marketplace:     integration.Marketplace,
+		EventId:         data.EventId,
+		Direction:       models.MarketplaceDirectionInbound,
+		Type:            data.Type,
+		ExternalOrderId: data.ExternalOrderId,
+		Status:          models.MarketplaceEventStatusProcessing,
+		Payload:         string(payload),
+		NextAttemptAt:   now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	created, err := r.repo.Marketplace.CreateEventIfNew(event)
+	if err != nil {
+		return nil, false, err
+	}
+	if created {
+		return event, true, nil
+	}
+
+	stored, err := r.repo.Marketplace.GetEvent(integration.Marketplace, data.EventId)
+	if err != nil {
+		return nil, false, err
+	}
+	if stored.Status != models.MarketplaceEventStatusFailed {
+		return stored, false, nil
+	}
+	claimed, err := r.repo.Marketplace.ClaimFailedEvent(stored.Id, now)
+	if err != nil || !claimed {
+		return stored, false, err
+	}
+	stored.Status = models.MarketplaceEventStatusProcessing
+	return stored, true, nil
+}
+
+// processEvent aplica o evento e grava o resultado; falhas ficam registradas para reprocessar
+func (r *resourceMarketplace) processEvent(integration *models.MarketplaceIntegration, event *models.MarketplaceEvent, data models.MarketplaceOrderEvent) {
+	var order *models.Order
+	var err error
+	event.Attempts++
+	event.LastError = ""
+	switch data.Type {
+	case models.MarketplaceEventOrderPlaced:
+		order, err = r.processPlaced(integration, event, data)
+	case models.MarketplaceEventOrderCancelled:
+		err = r.processCancelled(integration, event, data)
+	default:
+		event.Status = models.MarketplaceEventStatusIgnored
+	}
+
+	now := time.Now()
+	if err != nil {
+		event.Status = models.MarketplaceEventStatusFailed
+		event.LastError = err.Error()
+		log.Printf("⚠️ Marketplace %s: evento %s falhou: %v", integration.Marketplace, event.EventId, err)
+		utils.GetRealtimeBus().Publish(event.OrganizationId, event.ProjectId, utils.RealtimeTopicFloor, "marketplace.order_failed", event)
+	} else if event.Status == models.MarketplaceEventStatusProcessing {
+		event.Status = models.MarketplaceEventStatusProcessed
+	}
+	event.ProcessedAt = &now
+	event.UpdatedAt = now
+	if err := r.repo.Marketplace.UpdateEvent(event); err != nil {
+		log.Printf("⚠️ Marketplace %s: erro ao gravar evento %s: %v", integration.Marketplace, event.EventId, err)
+		return
+	}
+
+	// Confirmação só depois de o evento ligar o pedido à integração
+	if order != nil {
+		r.sync.SyncOrderStatus(order)
+	}
+}
+
+// processPlaced cria o pedido do marketplace, mapeando os itens pelo código PDV dos produtos
+func (r *resourceMarketplace) processPlaced(integration *models.MarketplaceIntegration, event *models.MarketplaceEvent, data models.MarketplaceOrderEvent) (*models.Order, error) {
+	if data.Order == nil {
+		return nil, errors.New("invalid_marketplace: order.placed event without order")
+	}
+
+	// Mesmo pedido enviado em outro evento: apenas vincula
+	existing, err := r.repo.Marketplace.GetOrderByExternalId(integration.OrganizationId, integration.ProjectId, integration.Marketplace, data.ExternalOrderId)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		event.OrderId = &existing.Id
+		return nil, nil
+	}
+
+	items, err := r.mapItems(integration, data.Order.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	orderType := data.Order.Type
+	if orderType == "" {
+		orderType = models.OrderTypeDelivery
+	}
+	if orderType != models.OrderTypeDelivery && orderType != models.OrderTypeTakeout {
+		return nil, fmt.Errorf("invalid_marketplace: unsupported order type %q", orderType)
+	}
+
+	order := &models.Order{
+		OrganizationId:  integration.OrganizationId,
+		ProjectId:       integration.ProjectId,
+		Type:            orderType,
+		DeliveryAddress: data.Order.Address,
+		DeliveryFee:     data.Order.DeliveryFee,
+		ScheduledFor:    data.Order.ScheduledFor,
+		Items:           items,
+		Note:            data.Order.Note,
+		Source:          integration.Marketplace,
+		ExternalOrderId: data.ExternalOrderId,
+		TrackingToken:   generateTrackingToken(),
+	}
+	if !integration.AutoAccept {
+		order.Status = models.OrderStatusAwaitingApproval
+	}
+
+	if data.Order.Customer.Phone != "" {
+		customer, err := findOrCreateCustomer(r.repo, integration.OrganizationId, integration.ProjectId, data.Order.Customer)
+		if err != nil {
+			return nil, err
+		}
+		order.CustomerId = &customer.Id
+	}
+
+	if err := r.orderHandler.CreateOrder(order); err != nil {
+		return nil, err
+	}
+	event.OrderId = &order.Id
+	return order, nil
+}
+
+// mapItems converte os itens do marketplace em itens do pedido; preço vem do cardápio.
+// Códigos sem produto correspondente falham com prefixo "unmapped_product:".
+func (r *resourceMarketplace) mapItems(integration *models.MarketplaceIntegration, requested []models.MarketplaceOrderItem) (models.OrderItems, error) {
+	if len(requested) == 0 {
+		return nil, errors.New("invalid_marketplace: order without items")
+	}
+
+	codes := make([]string, 0, len(requested))
+	for _, item := range requested {
+		codes = append(codes, item.Code)
+	}
+	products, err := r.repo.Products.GetProductsByPDVCodes(integration.OrganizationId, integration.ProjectId, codes)
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]models.Product, len(products))
+	for _, product := range products {
+		if product.PDVCode != nil {
+			byCode[*product.PDVCode] = product
+		}
+	}
+
+	items := make(models.OrderItems, 0, len(requested))
+	unmapped := make(map[string]bool)
+	for _, item := range requested {
+		product, ok := byCode[item.Code]
+		if !ok {
+			unmapped[fmt.Sprintf("%s (%s)", item.Code, item.Name)] = true
+			continue
+		}
+		items = append(items, models.OrderItem{
+			ProductId: product.Id,
+			Quantity:  item.Quantity,
+			Notes:     item.Notes,
+		})
+	}
+	if len(unmapped) > 0 {
+		missing := make([]string, 0, len(unmapped))
+		for code := range unmapped {
+			missing = append(missing, code)
+		}
+		sort.Strings(missing)
+		return nil, fmt.Errorf("unmapped_product: no product with pdv_code %s", strings.Join(missing, ", "))
+	}
+	return items, nil
+}
+
+// processCancelled cancela o pedido a pedido do marketplace (sem devolver o status para a plataforma)
+func (r *resourceMarketplace) processCancelled(integration *models.MarketplaceIntegration, event *models.MarketplaceEvent, data models.MarketplaceOrderEvent) error {
+	order, err := r.repo.Marketplace.GetOrderByExternalId(integration.OrganizationId, integration.ProjectId, integration.Marketplace, data.ExternalOrderId)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		event.Status = models.MarketplaceEventStatusIgnored
+		return nil
+	}
+	event.OrderId = &order.Id
+	if order.Status == models.OrderStatusCancelled || order.Status == models.OrderStatusDelivered {
+		event.Status = models.MarketplaceEventStatusIgnored
+		return nil
+	}
+
+	// Evento gravado antes da transição: a sincronização reconhece o cancelamento vindo do marketplace
+	event.UpdatedAt = time.Now()
+	if err := r.repo.Marketplace.UpdateEvent(event); err != nil {
+		return err
+	}
+
+	reason := "Cancelado pelo marketplace"
+	if data.Reason != "" {
+		reason += ": " + data.Reason
+	}
+	return r.orderHandler.UpdateOrderStatus(order.Id.String(), models.OrderStatusCancelled, reason, "")
+}
+
+// GetEvent busca evento por ID
+func (r *resourceMarketplace) GetEvent(id string) (*models.MarketplaceEvent, error) {
+	eventId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Marketplace.GetEventById(eventId)
+}
+
+// ListEvents lista webhooks recebidos e status enviados do projeto
+func (r *resourceMarketplace) ListEvents(orgId, projectId, direction, status string, limit int) ([]models.MarketplaceEvent, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return r.repo.Marketplace.ListEvents(orgUUID, projectUUID, direction, status, limit)
+}
+
+// RetryEvent reprocessa webhook com falha (ex: produto mapeado depois) ou reenfileira status não entregue
+func (r *resourceMarketplace) RetryEvent(event *models.MarketplaceEvent) (*models.MarketplaceEvent, error) {
+	if event.Direction == models.MarketplaceDirectionOutbound {
+		if err := r.sync.Retry(event); err != nil {
+			return nil, err
+		}
+		return event, nil
+	}
+
+	if event.Status != models.MarketplaceEventStatusFailed {
+		return nil, fmt.Errorf("invalid_marketplace_event: only failed events can be retried (status %s)", event.Status)
+	}
+	var data models.MarketplaceOrderEvent
+	if err := json.Unmarshal([]byte(event.Payload), &data); err != nil {
+		return nil, fmt.Errorf("invalid_marketplace_event: %w", err)
+	}
+	integration, err := r.repo.Marketplace.GetIntegrationById(event.IntegrationId)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed, err := r.repo.Marketplace.ClaimFailedEvent(event.Id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, errors.New("invalid_marketplace_event: event is already being processed")
+	}
+	event.Status = models.MarketplaceEventStatusProcessing
+	r.processEvent(integration, event, data)
+	return event, nil
+}
+
+// generateWebhookSecret segredo aleatório para assinar os webhooks da loja
+func generateWebhookSecret() string {
+	bytes := make([]byte, 24)
+	if _, err := rand.Read(bytes); err != nil {
+		return strings.ReplaceAll(uuid.New().String(), "-", "")
+	}
+	return hex.EncodeToString(bytes)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"lep/repositories"
 	"lep/repositories/models"
+	"lep/utils"
 	"time"
 
 	"github.com/google/uuid"
@@ -139,7 +140,8 @@ func (r *resourceMenu) GetActiveMenuByTimeRange(orgId, projectId string) (*model
 	if err != nil {
 		return nil, err
 	}
-	return r.repo.Menus.GetActiveMenuByTimeRange(orgUuid, projectUuid, time.Now())
+	now := utils.ProjectClock(r.repo.Projects, projectUuid).Now()
+	return r.repo.Menus.GetActiveMenuByTimeRange(orgUuid, projectUuid, now)
 }
 
 // ✨ SetMenuAsManualOverride define um cardápio como override manual
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"lep/repositories"
 	"lep/repositories/models"
 	"lep/utils"
@@ -16,9 +18,14 @@ type IOrderHandler interface {
 	ListOrders(orgId, projectId string) ([]models.Order, error)
 	UpdateOrder(order *models.Order) error
 	SoftDeleteOrder(id string) error
-	UpdateOrderStatus(orderId string, status string) error
+	UpdateOrderStatus(orderId, status, reason, changedBy string) error
+	ApproveOrder(orderId, changedBy string) (*models.Order, error)
+	GetStatusHistory(orderId string) ([]models.OrderStatusHistory, error)
 	GetKitchenQueue(orgId, projectId string) ([]models.Order, error)
+	GetStationQueue(orgId, projectId, stationId string) ([]models.StationTicket, error)
+	UpdateOrderItemStatus(orderId, itemId, status, changedBy string) (*models.Order, error)
 	CalculateEstimatedTime(order *models.Order) error
+	FireCourse(orderId, course, changedBy string) (*models.Order, error)
 }
 
 // Handler para propósito Order
@@ -26,26 +33,60 @@ type OrderHandler struct {
 	repo        repositories.IOrderRepository
 	productRepo repositories.IProductRepository
 	kitchenRepo repositories.IKitchenQueueRepository
+	stationRepo repositories.IKitchenStationRepository
+	historyRepo repositories.IOrderStatusHistoryRepository
+	stockRepo   repositories.IStockRepository
+	inventory   IHandlerInventory
+	printer     IHandlerPrint
+	prepTime    IHandlerPrepTime
+	delivery    IHandlerDelivery
+	marketplace *utils.MarketplaceSyncService
 }
 
-func NewOrderHandler(repo repositories.IOrderRepository, productRepo repositories.IProductRepository, kitchenRepo repositories.IKitchenQueueRepository) IOrderHandler {
-	return &OrderHandler{repo, productRepo, kitchenRepo}
+func NewOrderHandler(repo repositories.IOrderRepository, productRepo repositories.IProductRepository, kitchenRepo repositories.IKitchenQueueRepository, stationRepo repositories.IKitchenStationRepository, historyRepo repositories.IOrderStatusHistoryRepository, stockRepo repositories.IStockRepository, inventory IHandlerInventory, printer IHandlerPrint, prepTime IHandlerPrepTime, delivery IHandlerDelivery, marketplace *utils.MarketplaceSyncService) IOrderHandler {
+	return &OrderHandler{repo, productRepo, kitchenRepo, stationRepo, historyRepo, stockRepo, inventory, printer, prepTime, delivery, marketplace}
 }
 
 func (h *OrderHandler) CreateOrder(order *models.Order) error {
 	order.Id = uuid.New()
-	order.Status = "pending"
+	// Pedidos públicos e de marketplace podem ficar retidos para aprovação do garçom; os demais vão direto para a cozinha
+	if (order.Source != "public" && order.ExternalOrderId == "") || order.Status != models.OrderStatusAwaitingApproval {
+		order.Status = models.OrderStatusPending
+	}
 	order.CreatedAt = time.Now()
 	order.UpdatedAt = time.Now()
 
+	// Precificar, validar modificadores, calcular total e rotear itens para as estações da cozinha
+	if err := h.prepareItems(order, nil); err != nil {
+		return err
+	}
+
 	// Calcular tempo estimado automaticamente
 	err := h.CalculateEstimatedTime(order)
 	if err != nil {
 		return err
 	}
 
-	//gerar log de criação com defer
-	return h.repo.CreateOrder(order)
+	// Retirada/entrega: zona e taxa do delivery, horário agendado e previsão com o deslocamento
+	if err := h.delivery.PrepareOrder(order); err != nil {
+		return err
+	}
+
+	// Pedido e entrada inicial do histórico na mesma transação
+	entry := utils.NewOrderStatusEntry(order, "", order.Status, "", nil)
+	if err := h.repo.CreateOrderWithHistory(order, &entry); err != nil {
+		return err
+	}
+
+	if order.Status == models.OrderStatusAwaitingApproval {
+		// Ainda fora da cozinha: apenas o salão é avisado
+		utils.GetRealtimeBus().Publish(order.OrganizationId, order.ProjectId, utils.RealtimeTopicFloor, "order.awaiting_approval", order)
+		return nil
+	}
+
+	h.publish(order, "order.created")
+	h.printer.AutoPrintOrder(order)
+	return nil
 }
 
 func (h *OrderHandler) GetOrderById(id string) (*models.Order, error) {
@@ -57,32 +98,132 @@ func (h *OrderHandler) ListOrders(orgId, projectId string) ([]models.Order, erro
 }
 
 func (h *OrderHandler) UpdateOrder(order *models.Order) error {
-	order.UpdatedAt = time.Now()
-	return h.repo.UpdateOrder(order)
+	incoming := *order
+
+	// Mescla feita sobre a versão atual do pedido, já bloqueada
+	saved, err := h.updateOrder(order.Id.String(), nil, func(stored *models.Order) ([]models.OrderStatusHistory, error) {
+		merged := incoming
+		merged.UpdatedAt = time.Now()
+
+		// Itens já gravados mantêm o preço original; itens novos são precificados pelo cadastro
+		snapshots := make(map[uuid.UUID]models.OrderItem)
+		for _, item := range stored.Items {
+			snapshots[item.Id] = item
+		}
+
+		// Status só muda pelas rotas de status (máquina de estados + histórico)
+		merged.Status = stored.Status
+		merged.StartedAt = stored.StartedAt
+		merged.ReadyAt = stored.ReadyAt
+		merged.DeliveredAt = stored.DeliveredAt
+
+		// Origem, tipo, entrega e horário agendado são definidos na criação do pedido
+		merged.Source = stored.Source
+		merged.ExternalOrderId = stored.ExternalOrderId
+		merged.Type = stored.Type
+		merged.DeliveryAddress = stored.DeliveryAddress
+		merged.DeliveryZoneId = stored.DeliveryZoneId
+		merged.DeliveryFee = stored.DeliveryFee
+		merged.ScheduledFor = stored.ScheduledFor
+
+		// Vínculo com a NFC-e muda apenas na emissão e no cancelamento da nota
+		merged.FiscalDocumentId = stored.FiscalDocumentId
+
+		// Itens adicionados na edição também precisam de validação e estação
+		if err := h.prepareItems(&merged, snapshots); err != nil {
+			return nil, err
+		}
+
+		// Pedido já em preparo tem o estoque ajustado pela diferença de itens
+		*stored = merged
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	*order = *saved
+	h.publish(order, "order.updated")
+	return nil
 }
 
 func (h *OrderHandler) SoftDeleteOrder(id string) error {
-	return h.repo.SoftDeleteOrder(id)
+	order, err := h.repo.GetOrderById(id)
+	if err != nil {
+		return err
+	}
+
+	if err := h.repo.SoftDeleteOrder(id); err != nil {
+		return err
+	}
+
+	h.publish(order, "order.deleted")
+	return nil
 }
 
-// UpdateOrderStatus atualiza o status do pedido com timestamps apropriados
-func (h *OrderHandler) UpdateOrderStatus(orderId string, status string) error {
-	_, err := uuid.Parse(orderId)
+// UpdateOrderStatus aplica a transição de status validada pela máquina de estados e registra no histórico
+func (h *OrderHandler) UpdateOrderStatus(orderId, status, reason, changedBy string) error {
+	actor := parseActor(changedBy)
+
+	// Transição validada sobre o status atual, com o pedido bloqueado
+	order, err := h.updateOrder(orderId, actor, func(order *models.Order) ([]models.OrderStatusHistory, error) {
+		if err := utils.ValidateOrderStatusTransition(order.Status, status, reason); err != nil {
+			return nil, err
+		}
+		if status == models.OrderStatusReady && utils.HasHeldItems(order.Items) {
+			return nil, errors.New("invalid_transition: order has held courses, fire them first")
+		}
+
+		entry := utils.NewOrderStatusEntry(order, order.Status, status, reason, actor)
+
+		// Atualiza status e timestamps usando utils
+		utils.UpdateOrderStatus(order, status)
+		return []models.OrderStatusHistory{entry}, nil
+	})
 	if err != nil {
 		return err
 	}
 
-	// Busca o pedido atual
-	order, err := h.repo.GetOrderById(orderId)
+	h.publish(order, "order.status_changed")
+	h.marketplace.SyncOrderStatus(order)
+	return nil
+}
+
+// ApproveOrder libera pedido público retido para a fila da cozinha
+func (h *OrderHandler) ApproveOrder(orderId, changedBy string) (*models.Order, error) {
+	actor := parseActor(changedBy)
+
+	order, err := h.updateOrder(orderId, actor, func(order *models.Order) ([]models.OrderStatusHistory, error) {
+		if order.Status != models.OrderStatusAwaitingApproval {
+			return nil, &utils.OrderTransitionError{From: order.Status, To: models.OrderStatusPending}
+		}
+
+		entry := utils.NewOrderStatusEntry(order, order.Status, models.OrderStatusPending, "", actor)
+		utils.UpdateOrderStatus(order, models.OrderStatusPending)
+
+		// Fila da cozinha pode ter mudado desde a criação
+		if err := h.CalculateEstimatedTime(order); err != nil {
+			return nil, err
+		}
+		return []models.OrderStatusHistory{entry}, nil
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Atualiza status e timestamps usando utils
-	utils.UpdateOrderStatus(order, status)
+	h.publish(order, "order.created")
+	h.printer.AutoPrintOrder(order)
+	h.marketplace.SyncOrderStatus(order)
+	return order, nil
+}
 
-	// Salva no banco
-	return h.repo.UpdateOrder(order)
+// GetStatusHistory lista as transições de status do pedido
+func (h *OrderHandler) GetStatusHistory(orderId string) ([]models.OrderStatusHistory, error) {
+	orderUUID, err := uuid.Parse(orderId)
+	if err != nil {
+		return nil, err
+	}
+	return h.historyRepo.ListByOrder(orderUUID)
 }
 
 // GetKitchenQueue retorna a fila da cozinha
@@ -102,13 +243,20 @@ func (h *OrderHandler) GetKitchenQueue(orgId, projectId string) ([]models.Order,
 
 // CalculateEstimatedTime calcula e define o tempo estimado do pedido
 func (h *OrderHandler) CalculateEstimatedTime(order *models.Order) error {
-	if len(order.Items) == 0 {
+	// Só entram os itens na cozinha: tempos retidos são estimados quando disparados
+	var items models.OrderItems
+	for _, item := range order.Items {
+		if item.Status != models.OrderItemStatusHeld && item.Status != models.OrderItemStatusReady {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
 		return nil // Pedido sem itens
 	}
 
 	// Buscar informações dos produtos
 	var productIds []uuid.UUID
-	for _, item := range order.Items {
+	for _, item := range items {
 		productIds = append(productIds, item.ProductId)
 	}
 
@@ -117,17 +265,21 @@ func (h *OrderHandler) CalculateEstimatedTime(order *models.Order) error {
 		return err
 	}
 
-	// Calcular tempo de preparo
-	prepTime := utils.CalculateOrderPrepTime(order.Items, products)
-	order.EstimatedPrepTime = prepTime
-
 	// Buscar fila da cozinha para calcular tempo de espera
 	activeOrders, err := h.kitchenRepo.GetOrdersInPreparation(order.OrganizationId, order.ProjectId)
 	if err != nil {
 		return err
 	}
 
-	queueTime := utils.GetKitchenQueueTime(activeOrders)
+	// Tempos aprendidos do histórico quando há dados suficientes; senão o cadastro do produto
+	kitchenOrder := *order
+	kitchenOrder.Items = items
+	prepTime, queueTime, source, err := h.prepTime.Estimate(&kitchenOrder, products, activeOrders)
+	if err != nil {
+		return err
+	}
+	order.EstimatedPrepTime = prepTime
+	order.EstimateSource = source
 
 	// Calcular tempo estimado de entrega
 	deliveryTime := utils.CalculateEstimatedDeliveryTime(prepTime, queueTime)
@@ -135,3 +287,235 @@ func (h *OrderHandler) CalculateEstimatedTime(order *models.Order) error {
 
 	return nil
 }
+
+// GetStationQueue retorna a fila de uma estação, contendo apenas os itens roteados para ela
+func (h *OrderHandler) GetStationQueue(orgId, projectId, stationId string) ([]models.StationTicket, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	projUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	stationUUID, err := uuid.Parse(stationId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Estação de outro projeto é tratada como inexistente
+	station, err := h.stationRepo.GetStationById(stationUUID)
+	if err != nil || station.OrganizationId != orgUUID || station.ProjectId != projUUID {
+		return nil, errors.New("station not found")
+	}
+
+	orders, err := h.kitchenRepo.GetStationQueue(orgUUID, projUUID, stationUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets := make([]models.StationTicket, 0, len(orders))
+	for _, order := range orders {
+		items := utils.FilterItemsByStation(order.Items, stationUUID)
+
+		// Itens já prontos saem da tela da estação
+		pending := make([]models.OrderItem, 0, len(items))
+		for _, item := range items {
+			if item.Status != models.OrderItemStatusReady {
+				pending = append(pending, item)
+			}
+		}
+		if len(pending) == 0 {
+			continue
+		}
+
+		tickets = append(tickets, models.StationTicket{
+			OrderId:      order.Id,
+			TableId:      order.TableId,
+			TableNumber:  order.TableNumber,
+			OrderType:    order.Type,
+			ScheduledFor: order.ScheduledFor,
+			Note:         order.Note,
+			OrderStatus:  order.Status,
+			Items:        pending,
+			CreatedAt:    order.CreatedAt,
+		})
+	}
+
+	return tickets, nil
+}
+
+// UpdateOrderItemStatus atualiza o status de um item e avança o status do pedido pela máquina de estados
+func (h *OrderHandler) UpdateOrderItemStatus(orderId, itemId, status, changedBy string) (*models.Order, error) {
+	itemUUID, err := uuid.Parse(itemId)
+	if err != nil {
+		return nil, err
+	}
+
+	actor := parseActor(changedBy)
+	advanced := false
+	order, err := h.updateOrder(orderId, actor, func(order *models.Order) ([]models.OrderStatusHistory, error) {
+		if order.Status == models.OrderStatusDelivered || order.Status == models.OrderStatusCancelled {
+			return nil, errors.New("invalid_transition: order is already closed")
+		}
+		if order.Status == models.OrderStatusAwaitingApproval {
+			return nil, errors.New("invalid_transition: order is awaiting approval")
+		}
+
+		for _, item := range order.Items {
+			if item.Id == itemUUID && item.Status == models.OrderItemStatusHeld {
+				return nil, fmt.Errorf("invalid_transition: item course %s is held until fired", item.Course)
+			}
+		}
+
+		if !utils.UpdateOrderItemStatus(order, itemUUID, status) {
+			return nil, errors.New("item not found in order")
+		}
+
+		// Pedido só avança (ex.: pending -> preparing -> ready); item que volta não regride o pedido
+		var entries []models.OrderStatusHistory
+		for _, step := range utils.OrderStatusPath(order.Status, utils.DeriveOrderStatus(order.Status, order.Items)) {
+			entries = append(entries, utils.NewOrderStatusEntry(order, order.Status, step, "", actor))
+			utils.UpdateOrderStatus(order, step)
+		}
+		advanced = len(entries) > 0
+		return entries, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	h.publish(order, "order.item_status_changed")
+	if advanced {
+		h.marketplace.SyncOrderStatus(order)
+	}
+	return order, nil
+}
+
+// FireCourse libera para a cozinha os itens retidos de um tempo (vazio = próximo tempo retido),
+// recalcula o horário estimado e imprime apenas os itens disparados
+func (h *OrderHandler) FireCourse(orderId, course, changedBy string) (*models.Order, error) {
+	actor := parseActor(changedBy)
+	var fired []models.OrderItem
+	order, err := h.updateOrder(orderId, actor, func(order *models.Order) ([]models.OrderStatusHistory, error) {
+		if order.Status == models.OrderStatusDelivered || order.Status == models.OrderStatusCancelled {
+			return nil, errors.New("invalid_transition: order is already closed")
+		}
+		if order.Status == models.OrderStatusAwaitingApproval {
+			return nil, errors.New("invalid_transition: order is awaiting approval")
+		}
+
+		fireCourse := course
+		if fireCourse == "" {
+			fireCourse = utils.NextHeldCourse(order.Items)
+			if fireCourse == "" {
+				return nil, errors.New("invalid_course: order has no held course")
+			}
+		}
+
+		fired = utils.FireCourse(order, fireCourse, actor, time.Now())
+		if len(fired) == 0 {
+			return nil, fmt.Errorf("invalid_course: course %s has no held items", fireCourse)
+		}
+
+		return nil, h.CalculateEstimatedTime(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	h.publish(order, "order.course_fired")
+
+	ticket := *order
+	ticket.Items = fired
+	h.printer.AutoPrintOrder(&ticket)
+	return order, nil
+}
+
+// prepareItems precifica os itens pelo cadastro, valida os modificadores, recalcula o total
+// e define id, status inicial e estação de cada item.
+// Se o cliente enviou total_amount divergente, retorna *utils.PriceMismatchError.
+func (h *OrderHandler) prepareItems(order *models.Order, snapshots map[uuid.UUID]models.OrderItem) error {
+	if len(order.Items) == 0 {
+		order.TotalAmount = 0
+		return nil
+	}
+
+	var productIds []uuid.UUID
+	for _, item := range order.Items {
+		productIds = append(productIds, item.ProductId)
+	}
+
+	if err := utils.ValidateOrderCourses(order.Items); err != nil {
+		return err
+	}
+
+	products, err := h.productRepo.GetProductsByIds(productIds)
+	if err != nil {
+		return err
+	}
+
+	mismatches, err := utils.PriceOrderItems(order, products, snapshots)
+	if err != nil {
+		return err
+	}
+
+	if err := utils.ApplyItemModifiers(order.Items, products, snapshots); err != nil {
+		return err
+	}
+
+	clientTotal := order.TotalAmount
+	order.TotalAmount = utils.CalculateOrderItemsTotal(order.Items)
+	if clientTotal > 0 && !utils.TotalsMatch(clientTotal, order.TotalAmount) {
+		return &utils.PriceMismatchError{
+			ClientTotal: clientTotal,
+			ServerTotal: order.TotalAmount,
+			Items:       mismatches,
+		}
+	}
+
+	stations, err := h.stationRepo.ListActiveStations(order.OrganizationId, order.ProjectId)
+	if err != nil {
+		return err
+	}
+
+	utils.RouteOrderItems(order.Items, products, stations)
+
+	// Tempos seguintes ficam retidos até o garçom disparar
+	utils.ApplyCourseHolds(order, snapshots, time.Now())
+	utils.SyncOrderCourses(order)
+	return nil
+}
+
+// updateOrder bloqueia o pedido, aplica apply sobre a versão atual e grava pedido, histórico e
+// movimentações de estoque na mesma transação; depois dispara os alertas de estoque baixo
+func (h *OrderHandler) updateOrder(orderId string, actor *uuid.UUID, apply func(order *models.Order) ([]models.OrderStatusHistory, error)) (*models.Order, error) {
+	orderUUID, err := uuid.Parse(orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	order, movements, err := h.stockRepo.UpdateOrderWithStock(orderUUID, actor, apply)
+	if err != nil {
+		return nil, err
+	}
+
+	h.inventory.ProcessMovements(movements)
+	return order, nil
+}
+
+// publish envia o pedido para os assinantes do tópico da cozinha
+func (h *OrderHandler) publish(order *models.Order, eventType string) {
+	utils.GetRealtimeBus().Publish(order.OrganizationId, order.ProjectId, utils.RealtimeTopicKitchen, eventType, order)
+}
+
+// parseActor converte o id do usuário autenticado (vazio quando alterado pelo sistema)
+func parseActor(userId string) *uuid.UUID {
+	id, err := uuid.Parse(userId)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resourcePacing struct {
+	repo *repositories.DBconn
+}
+
+type IHandlerPacing interface {
+	GetRule(id string) (*models.PacingRule, error)
+	ListRules(orgId, projectId string) ([]models.PacingRule, error)
+	CreateRule(rule *models.PacingRule) error
+	UpdateRule(rule *models.PacingRule) error
+	DeleteRule(id string) error
+	NewChecker(orgId, projectId uuid.UUID, start, end time.Time) (*utils.PacingChecker, error)
+	Report(orgId, projectId uuid.UUID, date, service string) (*models.PacingReport, error)
+}
+
+func NewSourceHandlerPacing(repo *repositories.DBconn) IHandlerPacing {
+	return &resourcePacing{repo: repo}
+}
+
+func (r *resourcePacing) GetRule(id string) (*models.PacingRule, error) {
+	ruleId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.PacingRules.GetRuleById(ruleId)
+}
+
+func (r *resourcePacing) ListRules(orgId, projectId string) ([]models.PacingRule, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.PacingRules.ListRules(orgUUID, projectUUID)
+}
+
+func (r *resourcePacing) CreateRule(rule *models.PacingRule) error {
+	rule.Id = uuid.New()
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = time.Now()
+	return r.repo.PacingRules.CreateRule(rule)
+}
+
+func (r *resourcePacing) UpdateRule(rule *models.PacingRule) error {
+	return r.repo.PacingRules.UpdateRule(rule)
+}
+
+func (r *resourcePacing) DeleteRule(id string) error {
+	ruleId, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	return r.repo.PacingRules.SoftDeleteRule(ruleId)
+}
+
+// NewChecker carrega regras ativas e reservas ativas para conferir chegadas entre start e end
+func (r *resourcePacing) NewChecker(orgId, projectId uuid.UUID, start, end time.Time) (*utils.PacingChecker, error) {
+	return newPacingChecker(r.repo, r.repo.Reservations, orgId, projectId, start, end)
+}
+
+// Report ocupação de cada horário do dia (ou do serviço) frente aos limites de ritmo
+func (r *resourcePacing) Report(orgId, projectId uuid.UUID, date, service string) (*models.PacingReport, error) {
+	clock := utils.ProjectClock(r.repo.Projects, projectId)
+	day, err := clock.ParseDate(date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid_pacing: date must be YYYY-MM-DD")
+	}
+	if service != "" && service != models.PacingServiceLunch && service != models.PacingServiceDinner {
+		return nil, fmt.Errorf("invalid_pacing: service must be lunch, dinner or empty")
+	}
+	checker, err := newPacingChecker(r.repo, r.repo.Reservations, orgId, projectId, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.PacingReport{Date: date, Service: service, Slots: []models.PacingSlotUsage{}}
+	for _, slot := range utils.ServiceTimeSlots(day, checker.Settings, 0) {
+		at, err := utils.SlotDateTime(day, slot, clock.Location())
+		if err != nil {
+			continue
+		}
+		start, end, slotService := checker.SlotWindow(at)
+		if service != "" && slotService != service {
+			continue
+		}
+		usage := models.PacingSlotUsage{Time: slot, Datetime: clock.Format(at), Service: slotService}
+		usage.Covers, usage.Reservations = checker.Usage(start, end, uuid.Nil)
+
+		// Limite mais restritivo entre as regras que valem para o horário
+		rollingWindow := 0
+		for _, rule := range checker.RulesAt(at) {
+			usage.MaxCoversPerSlot = tightestLimit(usage.MaxCoversPerSlot, rule.MaxCoversPerSlot)
+			usage.MaxReservationsPerSlot = tightestLimit(usage.MaxReservationsPerSlot, rule.MaxReservationsPerSlot)
+			if rule.RollingWindowMinutes > 0 && rule.RollingMaxCovers > 0 && tightestLimit(usage.RollingMaxCovers, rule.RollingMaxCovers) == rule.RollingMaxCovers {
+				usage.RollingMaxCovers = rule.RollingMaxCovers
+				rollingWindow = rule.RollingWindowMinutes
+			}
+		}
+		if rollingWindow > 0 {
+			usage.RollingCovers, _ = checker.Usage(at, at.Add(time.Duration(rollingWindow)*time.Minute), uuid.Nil)
+		}
+		if usage.MaxCoversPerSlot > 0 {
+			usage.CoversUtilization = math.Round(float64(usage.Covers)/float64(usage.MaxCoversPerSlot)*1000) / 10
+		}
+		for _, reservation := range checker.Reservations {
+			if reservation.PacingOverrideReason != "" && reservation.StartsAt != nil &&
+				!reservation.StartsAt.Before(start) && reservation.StartsAt.Before(end) {
+				usage.Overrides++
+			}
+		}
+
+		report.Covers += usage.Covers
+		report.Reservations += usage.Reservations
+		report.Overrides += usage.Overrides
+		if usage.CoversUtilization > report.PeakUtilization {
+			report.PeakUtilization = usage.CoversUtilization
+		}
+		if (usage.MaxCoversPerSlot > 0 && usage.Covers >= usage.MaxCoversPerSlot) ||
+			(usage.MaxReservationsPerSlot > 0 && usage.Reservations >= usage.MaxReservationsPerSlot) {
+			report.SlotsAtLimit++
+		}
+		report.Slots = append(report.Slots, usage)
+	}
+	return report, nil
+}
+
+// newPacingChecker regras ativas e reservas ativas que contam nas janelas das chegadas entre start e end.
+// As reservas são lidas por reservations (a transação da reserva, quando a checagem roda dentro dela).
+func newPacingChecker(repo *repositories.DBconn, reservations repositories.IReservationRepository, orgId, projectId uuid.UUID, start, end time.Time) (*utils.PacingChecker, error) {
+	settings, err := repo.Settings.GetOrCreateSettings(orgId, projectId)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := repo.PacingRules.ListActiveRules(orgId, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Margem para a janela do horário e a maior janela móvel
+	margin := 30 * time.Minute
+	if settings.SlotIntervalMinutes > 0 {
+		margin = time.Duration(settings.SlotIntervalMinutes) * time.Minute
+	}
+	for _, rule := range rules {
+		if window := time.Duration(rule.RollingWindowMinutes) * time.Minute; window > margin {
+			margin = window
+		}
+	}
+	inRange, err := reservations.GetReservationsInRange(orgId, projectId, start.Add(-margin), end.Add(margin))
+	if err != nil {
+		return nil, err
+	}
+	active := make([]models.Reservation, 0, len(inRange))
+	for _, reservation := range inRange {
+		if reservation.Status == "confirmed" || reservation.Status == "pending" {
+			active = append(active, reservation)
+		}
+	}
+
+	return &utils.PacingChecker{
+		Rules:        rules,
+		Reservations: active,
+		Settings:     settings,
+		Location:     utils.ProjectClock(repo.Projects, projectId).Location(),
+	}, nil
+}
+
+// reservationPacing checagem de ritmo feita dentro da transação da reserva: na criação, ou quando a
+// atualização muda o horário, aumenta o grupo ou reativa a reserva. Devolve *models.PacingViolation como
+// erro; reservas com motivo de exceção da equipe passam.
+func reservationPacing(repo *repositories.DBconn) repositories.PacingCheck {
+	return func(reservations repositories.IReservationRepository, current *models.Reservation, reservation models.Reservation) error {
+		if reservation.StartsAt == nil || reservation.PacingOverrideReason != "" ||
+			(reservation.Status != "confirmed" && reservation.Status != "pending") {
+			return nil
+		}
+		if current != nil {
+			wasActive := current.Status == "confirmed" || current.Status == "pending"
+			moved := current.StartsAt == nil || !reservation.StartsAt.Equal(*current.StartsAt)
+			if wasActive && !moved && reservation.PartySize <= current.PartySize {
+				return nil
+			}
+		}
+
+		at := *reservation.StartsAt
+		checker, err := newPacingChecker(repo, reservations, reservation.OrganizationId, reservation.ProjectId, at, at)
+		if err != nil {
+			return err
+		}
+		if violation := checker.Check(at, reservation.PartySize, reservation.Id); violation != nil {
+			return violation
+		}
+		return nil
+	}
+}
+
+// tightestLimit menor limite positivo (0 = sem limite)
+func tightestLimit(current, limit int) int {
+	if limit <= 0 {
+		return current
+	}
+	if current == 0 || limit < current {
+		return limit
+	}
+	return current
+}
@@ -0,0 +1,308 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type resourcePix struct {
+	repo       *repositories.DBconn
+	tabHandler IHandlerTab
+}
+
+type IHandlerPix interface {
+	CreateCharge(orgId, projectId string, request models.PixChargeRequest) (*models.PixCharge, error)
+	GetCharge(id string) (*models.PixCharge, error)
+	ListCharges(orgId, projectId string) ([]models.PixCharge, error)
+	GetChargeQRCode(id string, size int) ([]byte, error)
+	ConfirmCharge(confirmation utils.PixConfirmation) (*models.PixCharge, error)
+	HandleWebhook(providerName string, req *http.Request) ([]models.PixCharge, error)
+	SimulatePayment(id string) (*models.PixCharge, error)
+}
+
+func NewSourceHandlerPix(repo *repositories.DBconn, tabHandler IHandlerTab) IHandlerPix {
+	return &resourcePix{repo: repo, tabHandler: tabHandler}
+}
+
+// CreateCharge gera cobrança Pix para uma comanda, para a comanda aberta da mesa ou para pedidos avulsos
+func (r *resourcePix) CreateCharge(orgId, projectId string, request models.PixChargeRequest) (*models.PixCharge, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := r.repo.Settings.GetOrCreateSettings(orgUUID, projectUUID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.PixKey == "" || settings.PixMerchantName == "" || settings.PixMerchantCity == "" {
+		return nil, errors.New("pix_not_configured: pix key, merchant name and city must be set in project settings")
+	}
+
+	charge := &models.PixCharge{
+		Id:             uuid.New(),
+		OrganizationId: orgUUID,
+		ProjectId:      projectUUID,
+		TxId:           utils.GeneratePixTxId(),
+		Kind:           "static",
+		OrderIds:       pq.StringArray{},
+		Status:         models.PixChargeStatusPending,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	if err := r.resolveChargeAmount(charge, request); err != nil {
+		return nil, err
+	}
+	if charge.Amount <= 0 {
+		return nil, errors.New("invalid_pix: amount must be greater than zero")
+	}
+
+	params := utils.PixPayloadParams{
+		Key:          settings.PixKey,
+		MerchantName: settings.PixMerchantName,
+		MerchantCity: settings.PixMerchantCity,
+		Amount:       charge.Amount,
+		TxId:         charge.TxId,
+	}
+
+	if request.Dynamic {
+		if settings.PixProvider == "" {
+			return nil, errors.New("pix_not_configured: dynamic charges require a pix provider in project settings")
+		}
+		provider, err := utils.GetPixProvider(settings.PixProvider)
+		if err != nil {
+			return nil, fmt.Errorf("pix_not_configured: %w", err)
+		}
+		location, err := provider.CreateCharge(charge)
+		if err != nil {
+			return nil, fmt.Errorf("error creating charge on provider: %w", err)
+		}
+		charge.Kind = "dynamic"
+		charge.Provider = provider.Name()
+		charge.Location = location
+		params.Location = location
+	} else {
+		charge.Provider = settings.PixProvider
+	}
+
+	charge.Payload, err = utils.BuildPixPayload(params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid_pix: %w", err)
+	}
+
+	if err := r.repo.PixCharges.CreateCharge(charge); err != nil {
+		return nil, err
+	}
+	return charge, nil
+}
+
+// resolveChargeAmount define valor e vínculos da cobrança a partir da comanda, mesa ou pedidos
+func (r *resourcePix) resolveChargeAmount(charge *models.PixCharge, request models.PixChargeRequest) error {
+	tabId := request.TabId
+
+	// Mesa sem pedidos explícitos: cobra o saldo da comanda aberta
+	if tabId == nil && request.TableId != nil && len(request.OrderIds) == 0 {
+		tab, err := r.repo.Tabs.GetOpenTabByTable(*request.TableId)
+		if err != nil {
+			return err
+		}
+		if tab == nil {
+			return errors.New("invalid_pix: table has no open tab; send order_ids instead")
+		}
+		tabId = &tab.Id
+	}
+
+	if tabId != nil {
+		summary, err := r.tabHandler.GetTabSummary(tabId.String())
+		if err != nil {
+			return fmt.Errorf("tab not found: %w", err)
+		}
+		if summary.Tab.OrganizationId != charge.OrganizationId || summary.Tab.ProjectId != charge.ProjectId {
+			return errors.New("tab not found in this project")
+		}
+		if summary.Tab.Status != models.TabStatusOpen {
+			return errors.New("invalid_pix: tab is already closed")
+		}
+		charge.TabId = &summary.Tab.Id
+		charge.TableId = &summary.Tab.TableId
+		charge.Amount = summary.Balance
+		for _, order := range summary.Orders {
+			charge.OrderIds = append(charge.OrderIds, order.Id.String())
+		}
+		return nil
+	}
+
+	if len(request.OrderIds) == 0 {
+		return errors.New("invalid_pix: tab_id, table_id or order_ids is required")
+	}
+
+	total := 0.0
+	for _, orderId := range request.OrderIds {
+		order, err := r.repo.Orders.GetOrderById(orderId.String())
+		if err != nil {
+			return fmt.Errorf("order %s not found: %w", orderId, err)
+		}
+		if order.OrganizationId != charge.OrganizationId || order.ProjectId != charge.ProjectId {
+			return fmt.Errorf("order %s not found in this project", orderId)
+		}
+		if order.Status == "cancelled" {
+			return fmt.Errorf("invalid_pix: order %s is cancelled", orderId)
+		}
+		if request.TableId != nil && (order.TableId == nil || *order.TableId != *request.TableId) {
+			return fmt.Errorf("invalid_pix: order %s does not belong to the table", orderId)
+		}
+		total += order.AmountDue()
+		charge.OrderIds = append(charge.OrderIds, order.Id.String())
+	}
+	charge.TableId = request.TableId
+	charge.Amount = utils.RoundMoney(total)
+	return nil
+}
+
+// GetCharge busca cobrança por ID
+func (r *resourcePix) GetCharge(id string) (*models.PixCharge, error) {
+	chargeId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.PixCharges.GetChargeById(chargeId)
+}
+
+// ListCharges lista cobranças do projeto
+func (r *resourcePix) ListCharges(orgId, projectId string) ([]models.PixCharge, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.PixCharges.ListCharges(orgUUID, projectUUID)
+}
+
+// GetChargeQRCode gera o PNG do QR Code da cobrança
+func (r *resourcePix) GetChargeQRCode(id string, size int) ([]byte, error) {
+	charge, err := r.GetCharge(id)
+	if err != nil {
+		return nil, err
+	}
+	return utils.GeneratePixQRCode(charge.Payload, size)
+}
+
+// ConfirmCharge marca a cobrança como recebida (idempotente) e, se houver comanda, registra o pagamento Pix nela
+func (r *resourcePix) ConfirmCharge(confirmation utils.PixConfirmation) (*models.PixCharge, error) {
+	charge, err := r.repo.PixCharges.GetChargeByTxId(confirmation.TxId)
+	if err != nil {
+		return nil, fmt.Errorf("charge not found: %w", err)
+	}
+	return r.confirmCharge(charge, confirmation)
+}
+
+// confirmCharge confere o valor recebido (deve ser exatamente o da cobrança) e grava a confirmação
+func (r *resourcePix) confirmCharge(charge *models.PixCharge, confirmation utils.PixConfirmation) (*models.PixCharge, error) {
+	if charge.Status == models.PixChargeStatusPaid {
+		return charge, nil
+	}
+
+	paidAmount := utils.RoundMoney(confirmation.Amount)
+	if math.Abs(paidAmount-charge.Amount) > 0.005 {
+		return nil, fmt.Errorf("invalid_pix: paid amount %.2f differs from charge amount %.2f", paidAmount, charge.Amount)
+	}
+
+	now := time.Now()
+	charge.EndToEndId = confirmation.EndToEndId
+	charge.PaidAmount = charge.Amount
+	charge.PaidAt = &now
+
+	updated, err := r.repo.PixCharges.MarkChargePaid(charge)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		// Confirmação concorrente já processada
+		return r.repo.PixCharges.GetChargeById(charge.Id)
+	}
+	charge.Status = models.PixChargeStatusPaid
+
+	if charge.TabId != nil {
+		payment := &models.TabPayment{
+			Method:    models.PaymentMethodPix,
+			Amount:    charge.PaidAmount,
+			Reference: charge.TxId,
+		}
+		if _, err := r.tabHandler.AddPayment(charge.TabId.String(), payment); err != nil {
+			log.Printf("⚠️ Pix %s recebido mas não registrado na comanda %s: %v", charge.TxId, charge.TabId, err)
+		}
+	}
+
+	utils.GetRealtimeBus().Publish(charge.OrganizationId, charge.ProjectId, utils.RealtimeTopicFloor, "pix.paid", charge)
+	return charge, nil
+}
+
+// HandleWebhook valida a requisição pelo provedor e confirma as cobranças informadas.
+// Só confirma cobranças criadas pelo mesmo provedor da URL.
+func (r *resourcePix) HandleWebhook(providerName string, req *http.Request) ([]models.PixCharge, error) {
+	provider, err := utils.GetPixProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	confirmations, err := provider.ParseWebhook(req)
+	if err != nil {
+		return nil, fmt.Errorf("invalid_pix: %w", err)
+	}
+
+	confirmed := make([]models.PixCharge, 0, len(confirmations))
+	for _, confirmation := range confirmations {
+		charge, err := r.repo.PixCharges.GetChargeByTxId(confirmation.TxId)
+		if err != nil {
+			log.Printf("⚠️ Webhook Pix (%s): txid %s não encontrado: %v", providerName, confirmation.TxId, err)
+			continue
+		}
+		if charge.Provider != provider.Name() {
+			log.Printf("⚠️ Webhook Pix (%s): txid %s pertence ao provedor %q", providerName, confirmation.TxId, charge.Provider)
+			continue
+		}
+		paid, err := r.confirmCharge(charge, confirmation)
+		if err != nil {
+			log.Printf("⚠️ Webhook Pix (%s): txid %s ignorado: %v", providerName, confirmation.TxId, err)
+			continue
+		}
+		confirmed = append(confirmed, *paid)
+	}
+	return confirmed, nil
+}
+
+// SimulatePayment confirma a cobrança pelo provedor fake (indisponível em produção)
+func (r *resourcePix) SimulatePayment(id string) (*models.PixCharge, error) {
+	if _, err := utils.GetPixProvider("fake"); err != nil {
+		return nil, errors.New("invalid_pix: fake provider is not available in this environment")
+	}
+
+	charge, err := r.GetCharge(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ConfirmCharge(utils.PixConfirmation{
+		TxId:       charge.TxId,
+		EndToEndId: "E" + utils.GeneratePixTxId(),
+		Amount:     charge.Amount,
+	})
+}
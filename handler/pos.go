@@ -0,0 +1,431 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resourcePos struct {
+	repo *repositories.DBconn
+}
+
+type IHandlerPos interface {
+	ImportCatalog(orgId, projectId, format string, data []byte, dryRun bool, startedBy string) (*models.PosSyncRun, error)
+	ExportSales(orgId, projectId, format string, from, to *time.Time, startedBy string) (*models.PosSalesExport, error)
+	GetRun(id string) (*models.PosSyncRun, error)
+	ListRuns(orgId, projectId, direction string, limit int) ([]models.PosSyncRun, error)
+}
+
+func NewSourceHandlerPos(repo *repositories.DBconn) IHandlerPos {
+	return &resourcePos{repo: repo}
+}
+
+// posProductTypes tipos de produto aceitos na importação
+var posProductTypes = map[string]bool{"prato": true, "bebida": true, "vinho": true}
+
+// ImportCatalog cria ou atualiza produtos pelo código PDV. Produto sem código com o mesmo nome
+// passa a usar o código do arquivo. Itens inválidos são registrados na execução sem interromper os demais;
+// dryRun apenas confere o arquivo e o que mudaria.
+func (r *resourcePos) ImportCatalog(orgId, projectId, format string, data []byte, dryRun bool, startedBy string) (*models.PosSyncRun, error) {
+	adapter, err := utils.GetPosAdapter(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid_pos_file: %w", err)
+	}
+	run, err := r.startRun(orgId, projectId, models.PosSyncImportCatalog, adapter.Name(), startedBy)
+	if err != nil {
+		return nil, err
+	}
+	run.DryRun = dryRun
+
+	items, failures, err := adapter.ParseCatalog(data)
+	if err != nil {
+		return run, r.failRun(run, err)
+	}
+	run.Total = len(items) + len(failures)
+	for _, failure := range failures {
+		run.Items = append(run.Items, failure)
+		run.Failed++
+	}
+
+	products, err := r.repo.Products.ListProducts(run.OrganizationId, run.ProjectId)
+	if err != nil {
+		return run, r.failRun(run, err)
+	}
+	categories, err := r.repo.Categories.GetCategoryList(run.OrganizationId, run.ProjectId)
+	if err != nil {
+		return run, r.failRun(run, err)
+	}
+
+	byCode := make(map[string]*models.Product)
+	byName := make(map[string]*models.Product)
+	for i := range products {
+		product := &products[i]
+		if product.PDVCode != nil && *product.PDVCode != "" {
+			byCode[*product.PDVCode] = product
+		}
+		byName[strings.ToLower(product.Name)] = product
+	}
+	seen := make(map[string]int)
+
+	for _, item := range items {
+		result := models.PosSyncItem{Line: item.Line, Code: item.Code, Name: item.Name}
+		if line, duplicated := seen[item.Code]; duplicated && item.Code != "" {
+			r.recordFailure(run, result, fmt.Sprintf("duplicate code (first seen on line %d)", line))
+			continue
+		}
+		seen[item.Code] = item.Line
+
+		product, action, oldPrice, err := r.applyCatalogItem(run, item, byCode, byName, categories)
+		if err != nil {
+			r.recordFailure(run, result, err.Error())
+			continue
+		}
+		if action == "" {
+			run.Unchanged++
+			continue
+		}
+
+		result.Action = action
+		result.Name = product.Name
+		result.ProductId = &product.Id
+		result.OldPrice = oldPrice
+		if action == models.PosSyncItemCreated || oldPrice != nil {
+			result.NewPrice = item.Price
+		}
+		if action == models.PosSyncItemCreated {
+			run.Created++
+		} else {
+			run.Updated++
+		}
+		run.Items = append(run.Items, result)
+	}
+
+	return run, r.finishRun(run)
+}
+
+// applyCatalogItem aplica um item do cardápio; action vazio = produto já estava igual.
+// oldPrice só é retornado quando o preço mudou.
+func (r *resourcePos) applyCatalogItem(run *models.PosSyncRun, item models.PosCatalogItem, byCode, byName map[string]*models.Product, categories []models.Category) (*models.Product, string, *float64, error) {
+	if item.Code == "" {
+		return nil, "", nil, errors.New("code is required")
+	}
+	if item.Price != nil && *item.Price < 0 {
+		return nil, "", nil, errors.New("price must not be negative")
+	}
+	if item.Type != "" && !posProductTypes[item.Type] {
+		return nil, "", nil, fmt.Errorf("unknown type %q (allowed: prato, bebida, vinho)", item.Type)
+	}
+
+	var categoryId *uuid.UUID
+	if item.Category != "" {
+		for _, category := range categories {
+			if strings.EqualFold(category.Name, item.Category) {
+				id := category.Id
+				categoryId = &id
+				break
+			}
+		}
+		if categoryId == nil {
+			return nil, "", nil, fmt.Errorf("category %q not found", item.Category)
+		}
+	}
+
+	now := time.Now()
+	product := byCode[item.Code]
+	if product == nil {
+		if linked := byName[strings.ToLower(item.Name)]; linked != nil && linked.PDVCode == nil && item.Name != "" {
+			product = linked
+		}
+	}
+
+	if product == nil {
+		if item.Name == "" || item.Price == nil {
+			return nil, "", nil, errors.New("name and price are required for a new product")
+		}
+		if byName[strings.ToLower(item.Name)] != nil {
+			return nil, "", nil, fmt.Errorf("product %q already exists with another pdv_code", item.Name)
+		}
+		code := item.Code
+		product = &models.Product{
+			Id:             uuid.New(),
+			OrganizationId: run.OrganizationId,
+			ProjectId:      run.ProjectId,
+			Name:           item.Name,
+			Description:    item.Description,
+			Type:           item.Type,
+			Active:         true,
+			PDVCode:        &code,
+			CategoryId:     categoryId,
+			PriceNormal:    *item.Price,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if product.Type == "" {
+			product.Type = "prato"
+		}
+		if item.Active != nil {
+			product.Active = *item.Active
+		}
+		if !run.DryRun {
+			if err := r.repo.Products.CreateProduct(product); err != nil {
+				return nil, "", nil, err
+			}
+		}
+		byCode[item.Code] = product
+		byName[strings.ToLower(product.Name)] = product
+		return product, models.PosSyncItemCreated, nil, nil
+	}
+
+	changed := false
+	if product.PDVCode == nil || *product.PDVCode != item.Code {
+		code := item.Code
+		product.PDVCode = &code
+		changed = true
+	}
+	if item.Name != "" && item.Name != product.Name {
+		if other := byName[strings.ToLower(item.Name)]; other != nil && other.Id != product.Id {
+			return nil, "", nil, fmt.Errorf("name %q is already used by another product", item.Name)
+		}
+		delete(byName, strings.ToLower(product.Name))
+		product.Name = item.Name
+		byName[strings.ToLower(product.Name)] = product
+		changed = true
+	}
+	if item.Description != "" && item.Description != product.Description {
+		product.Description = item.Description
+		changed = true
+	}
+	if item.Type != "" && item.Type != product.Type {
+		product.Type = item.Type
+		changed = true
+	}
+	if categoryId != nil && (product.CategoryId == nil || *product.CategoryId != *categoryId) {
+		product.CategoryId = categoryId
+		changed = true
+	}
+	if item.Active != nil && *item.Active != product.Active {
+		product.Active = *item.Active
+		changed = true
+	}
+	var oldPrice *float64
+	if item.Price != nil && !utils.TotalsMatch(*item.Price, product.PriceNormal) {
+		previous := product.PriceNormal
+		oldPrice = &previous
+		product.PriceNormal = *item.Price
+		changed = true
+	}
+	if !changed {
+		return product, "", nil, nil
+	}
+
+	product.UpdatedAt = now
+	if !run.DryRun {
+		if err := r.repo.Products.UpdateProductCatalog(product); err != nil {
+			return nil, "", nil, err
+		}
+	}
+	byCode[item.Code] = product
+	return product, models.PosSyncItemUpdated, oldPrice, nil
+}
+
+// ExportSales gera o arquivo de vendas dos pedidos entregues no período, um registro por item.
+// Sem "from", continua de onde a última exportação parou (ou do início do dia).
+// Itens de produtos sem código PDV ficam de fora e são registrados como "skipped".
+func (r *resourcePos) ExportSales(orgId, projectId, format string, from, to *time.Time, startedBy string) (*models.PosSalesExport, error) {
+	adapter, err := utils.GetPosAdapter(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid_pos_file: %w", err)
+	}
+	run, err := r.startRun(orgId, projectId, models.PosSyncExportSales, adapter.Name(), startedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	periodEnd := run.StartedAt
+	if to != nil {
+		periodEnd = *to
+	}
+	var periodStart time.Time
+	switch {
+	case from != nil:
+		periodStart = *from
+	default:
+		last, err := r.repo.PosSync.GetLastExport(run.OrganizationId, run.ProjectId)
+		if err != nil {
+			return nil, r.failRun(run, err)
+		}
+		if last != nil {
+			periodStart = *last.PeriodEnd
+		} else {
+			periodStart = time.Date(periodEnd.Year(), periodEnd.Month(), periodEnd.Day(), 0, 0, 0, 0, periodEnd.Location())
+		}
+	}
+	if !periodStart.Before(periodEnd) {
+		return nil, r.failRun(run, fmt.Errorf("invalid_pos_period: from (%s) must be before to (%s)",
+			periodStart.Format(time.RFC3339), periodEnd.Format(time.RFC3339)))
+	}
+	run.PeriodStart = &periodStart
+	run.PeriodEnd = &periodEnd
+
+	orders, err := r.repo.PosSync.ListDeliveredOrders(run.OrganizationId, run.ProjectId, periodStart, periodEnd)
+	if err != nil {
+		return nil, r.failRun(run, err)
+	}
+	var productIds []uuid.UUID
+	for _, order := range orders {
+		for _, item := range order.Items {
+			productIds = append(productIds, item.ProductId)
+		}
+	}
+	codes := make(map[uuid.UUID]string)
+	if len(productIds) > 0 {
+		products, err := r.repo.Products.GetProductsByIds(productIds)
+		if err != nil {
+			return nil, r.failRun(run, err)
+		}
+		for _, product := range products {
+			if product.PDVCode != nil && *product.PDVCode != "" {
+				codes[product.Id] = *product.PDVCode
+			}
+		}
+	}
+
+	sales := make([]models.PosSaleRecord, 0)
+	for _, order := range orders {
+		orderId := order.Id
+		for _, item := range order.Items {
+			code, ok := codes[item.ProductId]
+			if !ok {
+				run.Skipped++
+				productId := item.ProductId
+				run.Items = append(run.Items, models.PosSyncItem{
+					OrderId: &orderId, Name: item.ProductName, ProductId: &productId,
+					Action: models.PosSyncItemSkipped, Message: "product has no pdv_code",
+				})
+				continue
+			}
+			unitPrice := item.Price + utils.ModifiersDelta(item)
+			sales = append(sales, models.PosSaleRecord{
+				OrderId:     order.Id,
+				DeliveredAt: *order.DeliveredAt,
+				Code:        code,
+				Name:        item.ProductName,
+				Variant:     item.Variant,
+				Quantity:    item.Quantity,
+				UnitPrice:   unitPrice,
+				Total:       unitPrice * float64(item.Quantity),
+				Source:      order.Source,
+				Type:        order.Type,
+			})
+		}
+	}
+	run.Total = len(sales)
+
+	content, err := adapter.WriteSales(sales)
+	if err != nil {
+		return nil, r.failRun(run, err)
+	}
+	if err := r.finishRun(run); err != nil {
+		return nil, err
+	}
+
+	return &models.PosSalesExport{
+		Run:         run,
+		ContentType: adapter.ContentType(),
+		FileName:    fmt.Sprintf("vendas_%s_%s.%s", periodStart.Format("20060102T1504"), periodEnd.Format("20060102T1504"), adapter.FileExtension()),
+		Content:     content,
+	}, nil
+}
+
+// GetRun busca execução por ID
+func (r *resourcePos) GetRun(id string) (*models.PosSyncRun, error) {
+	runId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.PosSync.GetRunById(runId)
+}
+
+// ListRuns lista importações e exportações do projeto
+func (r *resourcePos) ListRuns(orgId, projectId, direction string, limit int) ([]models.PosSyncRun, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return r.repo.PosSync.ListRuns(orgUUID, projectUUID, direction, limit)
+}
+
+// startRun registra a execução como "running"
+func (r *resourcePos) startRun(orgId, projectId, direction, format, startedBy string) (*models.PosSyncRun, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &models.PosSyncRun{
+		Id:             uuid.New(),
+		OrganizationId: orgUUID,
+		ProjectId:      projectUUID,
+		Direction:      direction,
+		Format:         format,
+		Status:         models.PosSyncStatusRunning,
+		Items:          models.PosSyncItems{},
+		StartedAt:      time.Now(),
+	}
+	if userId, err := uuid.Parse(startedBy); err == nil {
+		run.StartedBy = &userId
+	}
+	if err := r.repo.PosSync.CreateRun(run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// recordFailure registra item rejeitado
+func (r *resourcePos) recordFailure(run *models.PosSyncRun, item models.PosSyncItem, message string) {
+	item.Action = models.PosSyncItemFailed
+	item.Message = message
+	run.Items = append(run.Items, item)
+	run.Failed++
+}
+
+// finishRun grava o resultado: "partial" quando algum item foi rejeitado ou ficou de fora
+func (r *resourcePos) finishRun(run *models.PosSyncRun) error {
+	now := time.Now()
+	run.Status = models.PosSyncStatusCompleted
+	if run.Failed > 0 || run.Skipped > 0 {
+		run.Status = models.PosSyncStatusPartial
+	}
+	run.FinishedAt = &now
+	return r.repo.PosSync.UpdateRun(run)
+}
+
+// failRun grava a falha geral da execução e devolve o erro original
+func (r *resourcePos) failRun(run *models.PosSyncRun, cause error) error {
+	now := time.Now()
+	run.Status = models.PosSyncStatusFailed
+	run.Error = cause.Error()
+	run.FinishedAt = &now
+	if err := r.repo.PosSync.UpdateRun(run); err != nil {
+		return err
+	}
+	return cause
+}
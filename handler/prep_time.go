@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resourcePrepTime struct {
+	repo    *repositories.DBconn
+	service *utils.PrepTimeService
+}
+
+type IHandlerPrepTime interface {
+	Estimate(order *models.Order, products []models.Product, activeOrders []models.Order) (int, int, string, error)
+	GetStats(orgId, projectId string) (*models.PrepTimeStats, error)
+	Recompute(orgId, projectId string) (*models.PrepTimeStats, error)
+	GetAccuracy(orgId, projectId string, from, to time.Time) (*models.PrepTimeAccuracyReport, error)
+}
+
+func NewSourceHandlerPrepTime(repo *repositories.DBconn) IHandlerPrepTime {
+	return &resourcePrepTime{repo: repo, service: utils.NewPrepTimeService(repo.PrepTimes, repo.Projects)}
+}
+
+// Estimate calcula tempo de preparo e de fila do pedido, usando os tempos aprendidos
+// quando há histórico suficiente e o cadastro do produto/fila estimada quando não há
+func (r *resourcePrepTime) Estimate(order *models.Order, products []models.Product, activeOrders []models.Order) (int, int, string, error) {
+	productIds := make([]uuid.UUID, 0, len(products))
+	for _, product := range products {
+		productIds = append(productIds, product.Id)
+	}
+
+	prepStats, err := r.repo.PrepTimes.ListPrepStatsByProducts(order.OrganizationId, order.ProjectId, productIds)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	queueStats, err := r.repo.PrepTimes.ListQueueStats(order.OrganizationId, order.ProjectId)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	model := utils.NewPrepTimeModel(prepStats, queueStats)
+
+	hour := time.Now().In(r.service.Location(order.ProjectId)).Hour()
+	prepTime, source := utils.EstimateOrderPrepTime(order.Items, products, model, hour)
+
+	queueTime := utils.GetKitchenQueueTime(activeOrders)
+	queueMinutes, queueLearned := model.QueueMinutes(len(activeOrders))
+	if queueLearned {
+		queueTime = int(queueMinutes + 0.5)
+	}
+
+	return prepTime, queueTime, utils.CombineEstimateSource(source, queueLearned), nil
+}
+
+// GetStats retorna as estatísticas aprendidas do projeto
+func (r *resourcePrepTime) GetStats(orgId, projectId string) (*models.PrepTimeStats, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	prepStats, err := r.repo.PrepTimes.ListPrepStats(orgUUID, projectUUID)
+	if err != nil {
+		return nil, err
+	}
+	queueStats, err := r.repo.PrepTimes.ListQueueStats(orgUUID, projectUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.PrepTimeStats{MinSamples: utils.PrepTimeMinSamples, Products: prepStats, Queue: queueStats}
+	if len(prepStats) > 0 {
+		stats.ComputedAt = &prepStats[0].ComputedAt
+	} else if len(queueStats) > 0 {
+		stats.ComputedAt = &queueStats[0].ComputedAt
+	}
+	return stats, nil
+}
+
+// Recompute recalcula agora as estatísticas do projeto (o job faz isso a cada hora)
+func (r *resourcePrepTime) Recompute(orgId, projectId string) (*models.PrepTimeStats, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.service.RecomputeProject(orgUUID, projectUUID)
+}
+
+// GetAccuracy compara o tempo estimado com o real dos pedidos iniciados no período
+func (r *resourcePrepTime) GetAccuracy(orgId, projectId string, from, to time.Time) (*models.PrepTimeAccuracyReport, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := r.repo.PrepTimes.ListFinishedOrders(orgUUID, projectUUID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	productSet := make(map[uuid.UUID]bool)
+	var productIds []uuid.UUID
+	for _, order := range orders {
+		for _, item := range order.Items {
+			if !productSet[item.ProductId] {
+				productSet[item.ProductId] = true
+				productIds = append(productIds, item.ProductId)
+			}
+		}
+	}
+
+	var products []models.Product
+	if len(productIds) > 0 {
+		products, err = r.repo.Products.GetProductsByIds(productIds)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	prepStats, err := r.repo.PrepTimes.ListPrepStatsByProducts(orgUUID, projectUUID, productIds)
+	if err != nil {
+		return nil, err
+	}
+	model := utils.NewPrepTimeModel(prepStats, nil)
+
+	sources, overall, productAccuracy := utils.BuildPrepTimeAccuracy(orders, products, model, r.service.Location(projectUUID))
+	return &models.PrepTimeAccuracyReport{
+		From:     from,
+		To:       to,
+		Overall:  overall,
+		Sources:  sources,
+		Products: productAccuracy,
+	}, nil
+}
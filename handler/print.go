@@ -0,0 +1,368 @@
+package handler
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resourcePrint struct {
+	repo         *repositories.DBconn
+	tabHandler   IHandlerTab
+	printService *utils.PrintService
+}
+
+type IHandlerPrint interface {
+	GetPrinter(id string) (*models.Printer, error)
+	ListPrinters(orgId, projectId string) ([]models.Printer, error)
+	CreatePrinter(printer *models.Printer) error
+	UpdatePrinter(printer *models.Printer) error
+	DeletePrinter(id string) error
+	PrintTestPage(printer *models.Printer, requestedBy string) (*models.PrintJob, error)
+	PrintOrder(order *models.Order, printerId *uuid.UUID, requestedBy string) ([]models.PrintJob, error)
+	AutoPrintOrder(order *models.Order)
+	PrintTableReceipt(table *models.Table, printerId *uuid.UUID, requestedBy string) (*models.PrintJob, error)
+	GetJob(id string) (*models.PrintJob, error)
+	ListJobs(orgId, projectId, status string, limit int) ([]models.PrintJob, error)
+	RetryJob(job *models.PrintJob) error
+	GetAgent(orgId, projectId string) (*models.PrintAgent, error)
+	GenerateAgentToken(orgId, projectId string) (*models.PrintAgentToken, error)
+	AuthenticateAgent(token string) (*models.PrintAgent, error)
+	ClaimAgentJobs(agent *models.PrintAgent, limit int) ([]models.PrintAgentJob, error)
+	CompleteAgentJob(job *models.PrintJob, result models.PrintAgentResult) error
+}
+
+func NewSourceHandlerPrint(repo *repositories.DBconn, tabHandler IHandlerTab) IHandlerPrint {
+	return &resourcePrint{repo: repo, tabHandler: tabHandler, printService: utils.NewPrintService(repo.Printers)}
+}
+
+// GetPrinter busca impressora por ID
+func (r *resourcePrint) GetPrinter(id string) (*models.Printer, error) {
+	printerId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Printers.GetPrinterById(printerId)
+}
+
+// ListPrinters lista impressoras do projeto
+func (r *resourcePrint) ListPrinters(orgId, projectId string) ([]models.Printer, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Printers.ListPrinters(orgUUID, projectUUID)
+}
+
+// CreatePrinter cadastra impressora
+func (r *resourcePrint) CreatePrinter(printer *models.Printer) error {
+	if err := r.validateStation(printer); err != nil {
+		return err
+	}
+
+	printer.Id = uuid.New()
+	printer.Active = true
+	printer.CreatedAt = time.Now()
+	printer.UpdatedAt = time.Now()
+	return r.repo.Printers.CreatePrinter(printer)
+}
+
+// UpdatePrinter atualiza impressora
+func (r *resourcePrint) UpdatePrinter(printer *models.Printer) error {
+	if err := r.validateStation(printer); err != nil {
+		return err
+	}
+
+	printer.UpdatedAt = time.Now()
+	return r.repo.Printers.UpdatePrinter(printer)
+}
+
+// DeletePrinter exclui impressora
+func (r *resourcePrint) DeletePrinter(id string) error {
+	printerId, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	return r.repo.Printers.SoftDeletePrinter(printerId)
+}
+
+// PrintTestPage envia cupom de teste para a impressora
+func (r *resourcePrint) PrintTestPage(printer *models.Printer, requestedBy string) (*models.PrintJob, error) {
+	payload := utils.RenderTestPage(*printer, time.Now())
+	return r.printService.Enqueue(*printer, models.PrintJobTypeTest, payload, nil, nil, parseActor(requestedBy))
+}
+
+// PrintOrder (re)imprime as comandas do pedido nas impressoras de cozinha.
+// Com printerId, imprime o pedido inteiro apenas naquela impressora.
+func (r *resourcePrint) PrintOrder(order *models.Order, printerId *uuid.UUID, requestedBy string) ([]models.PrintJob, error) {
+	return r.printOrder(order, printerId, false, parseActor(requestedBy))
+}
+
+// AutoPrintOrder imprime o pedido que acabou de entrar na fila da cozinha nas impressoras com impressão automática
+func (r *resourcePrint) AutoPrintOrder(order *models.Order) {
+	if _, err := r.printOrder(order, nil, true, nil); err != nil && !errors.Is(err, errNoPrinter) {
+		log.Printf("⚠️ Erro ao imprimir pedido %s: %v", order.Id, err)
+	}
+}
+
+// PrintTableReceipt imprime a conferência de conta da comanda aberta na mesa
+func (r *resourcePrint) PrintTableReceipt(table *models.Table, printerId *uuid.UUID, requestedBy string) (*models.PrintJob, error) {
+	tab, err := r.repo.Tabs.GetOpenTabByTable(table.Id)
+	if err != nil {
+		return nil, err
+	}
+	if tab == nil {
+		return nil, fmt.Errorf("invalid_print: table %d has no open tab", table.Number)
+	}
+
+	summary, err := r.tabHandler.GetTabSummary(tab.Id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	printer, err := r.receiptPrinter(table.OrganizationId, table.ProjectId, printerId)
+	if err != nil {
+		return nil, err
+	}
+
+	title := ""
+	if project, err := r.repo.Projects.GetProjectById(table.ProjectId); err == nil {
+		title = project.Name
+	}
+
+	payload := utils.RenderReceipt(*summary, &table.Number, utils.TicketOptions{
+		PaperWidth: printer.PaperWidth,
+		Title:      title,
+		PrintedAt:  time.Now(),
+	})
+	return r.printService.Enqueue(*printer, models.PrintJobTypeReceipt, payload, nil, &tab.Id, parseActor(requestedBy))
+}
+
+// GetJob busca trabalho de impressão por ID
+func (r *resourcePrint) GetJob(id string) (*models.PrintJob, error) {
+	jobId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Printers.GetJobById(jobId)
+}
+
+// ListJobs lista a fila de impressão do projeto
+func (r *resourcePrint) ListJobs(orgId, projectId, status string, limit int) ([]models.PrintJob, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Printers.ListJobs(orgUUID, projectUUID, status, limit)
+}
+
+// RetryJob reenvia trabalho que falhou (ou já foi impresso) com os mesmos bytes
+func (r *resourcePrint) RetryJob(job *models.PrintJob) error {
+	return r.printService.Retry(job)
+}
+
+// GetAgent busca o agente de impressão do projeto
+func (r *resourcePrint) GetAgent(orgId, projectId string) (*models.PrintAgent, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Printers.GetAgentByProject(orgUUID, projectUUID)
+}
+
+// GenerateAgentToken gera (ou troca) o token do agente do projeto; o anterior deixa de valer
+func (r *resourcePrint) GenerateAgentToken(orgId, projectId string) (*models.PrintAgentToken, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return nil, err
+	}
+	token := hex.EncodeToString(bytes)
+
+	agent, err := r.repo.Printers.GetAgentByProject(orgUUID, projectUUID)
+	if err != nil {
+		agent = &models.PrintAgent{
+			Id:             uuid.New(),
+			OrganizationId: orgUUID,
+			ProjectId:      projectUUID,
+			CreatedAt:      time.Now(),
+		}
+	}
+	agent.TokenHash = hashAgentToken(token)
+	agent.UpdatedAt = time.Now()
+	if err := r.repo.Printers.SaveAgent(agent); err != nil {
+		return nil, err
+	}
+	return &models.PrintAgentToken{Token: token, Agent: *agent}, nil
+}
+
+// AuthenticateAgent identifica o agente pelo token e registra a consulta
+func (r *resourcePrint) AuthenticateAgent(token string) (*models.PrintAgent, error) {
+	if token == "" {
+		return nil, errors.New("missing print agent token")
+	}
+	agent, err := r.repo.Printers.GetAgentByTokenHash(hashAgentToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.repo.Printers.TouchAgent(agent.Id, time.Now()); err != nil {
+		log.Printf("Error updating print agent %s: %v", agent.Id, err)
+	}
+	return agent, nil
+}
+
+// ClaimAgentJobs entrega ao agente os trabalhos prontos das impressoras "agent" do projeto
+func (r *resourcePrint) ClaimAgentJobs(agent *models.PrintAgent, limit int) ([]models.PrintAgentJob, error) {
+	return r.printService.ClaimAgentJobs(agent.OrganizationId, agent.ProjectId, limit)
+}
+
+// CompleteAgentJob grava o resultado do envio feito pelo agente
+func (r *resourcePrint) CompleteAgentJob(job *models.PrintJob, result models.PrintAgentResult) error {
+	return r.printService.CompleteAgentJob(job, result)
+}
+
+// hashAgentToken só o hash do token fica no banco
+func hashAgentToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+var errNoPrinter = errors.New("printer_not_configured: no active printer for this job")
+
+// printOrder renderiza uma comanda por impressora de cozinha com os itens da sua estação
+func (r *resourcePrint) printOrder(order *models.Order, printerId *uuid.UUID, auto bool, requestedBy *uuid.UUID) ([]models.PrintJob, error) {
+	printers, err := r.repo.Printers.ListPrinters(order.OrganizationId, order.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	stationNames := make(map[uuid.UUID]string)
+	if stations, err := r.repo.KitchenStations.ListStations(order.OrganizationId, order.ProjectId); err == nil {
+		for _, station := range stations {
+			stationNames[station.Id] = station.Name
+		}
+	}
+
+	// Tempos retidos só vão para a cozinha quando o garçom disparar
+	kitchenItems := utils.KitchenItems(order.Items)
+
+	var jobs []models.PrintJob
+	now := time.Now()
+	for _, printer := range printers {
+		if !printer.Active {
+			continue
+		}
+
+		items := kitchenItems
+		title := "COZINHA"
+		switch {
+		case printerId != nil:
+			if printer.Id != *printerId {
+				continue
+			}
+		case printer.Purpose != models.PrinterPurposeKitchen || (auto && !printer.AutoPrint):
+			continue
+		case printer.StationId != nil:
+			items = stationItems(kitchenItems, *printer.StationId)
+			title = stationNames[*printer.StationId]
+		}
+		if len(items) == 0 {
+			continue
+		}
+
+		payload := utils.RenderKitchenTicket(*order, items, utils.TicketOptions{
+			PaperWidth: printer.PaperWidth,
+			Title:      title,
+			PrintedAt:  now,
+			Reprint:    !auto,
+		})
+		job, err := r.printService.Enqueue(printer, models.PrintJobTypeKitchen, payload, &order.Id, nil, requestedBy)
+		if err != nil {
+			return jobs, err
+		}
+		jobs = append(jobs, *job)
+	}
+
+	if len(jobs) == 0 {
+		return nil, errNoPrinter
+	}
+	return jobs, nil
+}
+
+// receiptPrinter escolhe a impressora informada ou a primeira impressora de conta ativa
+func (r *resourcePrint) receiptPrinter(orgId, projectId uuid.UUID, printerId *uuid.UUID) (*models.Printer, error) {
+	printers, err := r.repo.Printers.ListPrinters(orgId, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range printers {
+		printer := &printers[i]
+		if !printer.Active {
+			continue
+		}
+		if printerId != nil && printer.Id == *printerId {
+			return printer, nil
+		}
+		if printerId == nil && printer.Purpose == models.PrinterPurposeReceipt {
+			return printer, nil
+		}
+	}
+	return nil, errNoPrinter
+}
+
+// validateStation garante que a estação vinculada pertence ao mesmo projeto
+func (r *resourcePrint) validateStation(printer *models.Printer) error {
+	if printer.StationId == nil {
+		return nil
+	}
+	if printer.Purpose != models.PrinterPurposeKitchen {
+		return errors.New("invalid_print: only kitchen printers can be linked to a station")
+	}
+
+	station, err := r.repo.KitchenStations.GetStationById(*printer.StationId)
+	if err != nil || station.OrganizationId != printer.OrganizationId || station.ProjectId != printer.ProjectId {
+		return errors.New("invalid_print: station not found in this project")
+	}
+	return nil
+}
+
+// stationItems itens do pedido roteados para a estação
+func stationItems(items models.OrderItems, stationId uuid.UUID) []models.OrderItem {
+	var result []models.OrderItem
+	for _, item := range items {
+		if item.StationId != nil && *item.StationId == stationId {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -69,6 +69,7 @@ func (r *resourceProducts) CreateProduct(product *models.Product) error {
 	}
 
 	product.Id = uuid.New()
+	assignOptionGroupIds(product)
 	return r.repo.Products.CreateProduct(product)
 }
 
@@ -82,7 +83,51 @@ func (r *resourceProducts) UpdateProduct(updatedProduct *models.Product) error {
 		return errors.New("already_exists: product with this name already exists in this project")
 	}
 
-	return r.repo.Products.UpdateProduct(updatedProduct)
+	assignOptionGroupIds(updatedProduct)
+
+	stored, err := r.repo.Products.GetProductById(updatedProduct.Id)
+	if err != nil {
+		return err
+	}
+
+	// Estoque não é gravado junto com o cadastro (evita sobrescrever baixas concorrentes dos pedidos)
+	requestedStock := updatedProduct.Stock
+	updatedProduct.Stock = stored.Stock
+	updatedProduct.OutOfStock = stored.OutOfStock
+	if err := r.repo.Products.UpdateProduct(updatedProduct); err != nil {
+		return err
+	}
+
+	// Estoque alterado pelo cadastro vira um ajuste no livro, aplicado sobre o estoque atual com o produto bloqueado
+	if requestedStock != nil {
+		before := 0
+		if stored.Stock != nil {
+			before = *stored.Stock
+		}
+		if *requestedStock != before || stored.Stock == nil {
+			movement, err := r.repo.Stock.AdjustStock(updatedProduct.Id, *requestedStock-before,
+				models.StockMovementManualAdjustment, "product update", nil)
+			if err != nil {
+				return err
+			}
+			updatedProduct.Stock = &movement.StockAfter
+		}
+	}
+	return nil
+}
+
+// assignOptionGroupIds gera ids para grupos e opções novos (ids existentes são preservados)
+func assignOptionGroupIds(product *models.Product) {
+	for i := range product.OptionGroups {
+		if product.OptionGroups[i].Id == uuid.Nil {
+			product.OptionGroups[i].Id = uuid.New()
+		}
+		for j := range product.OptionGroups[i].Options {
+			if product.OptionGroups[i].Options[j].Id == uuid.Nil {
+				product.OptionGroups[i].Options[j].Id = uuid.New()
+			}
+		}
+	}
 }
 
 func (r *resourceProducts) DeleteProduct(id string) error {
@@ -37,6 +37,7 @@ type IProjectHandler interface {
 	SetDefaultProject(orgId, projectId string) error
 	GenerateSlug(name string) string
 	ProjectSlugExists(orgId, slug string) (bool, error)
+	Clock(projectId string) *utils.Clock
 }
 
 func NewProjectHandler(
@@ -62,6 +63,15 @@ func (h *ProjectHandler) GetProjectById(id string) (*models.Project, error) {
 	return h.projectRepo.GetProjectById(projectId)
 }
 
+// Clock relógio no fuso horário do projeto (ID inválido ou projeto não encontrado usa o fuso padrão)
+func (h *ProjectHandler) Clock(projectId string) *utils.Clock {
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return utils.NewClock(nil)
+	}
+	return utils.ProjectClock(h.projectRepo, projectUUID)
+}
+
 // GetProjectsByOrganization busca projetos por organização
 func (h *ProjectHandler) GetProjectsByOrganization(orgId string) ([]models.Project, error) {
 	orgUUID, err := uuid.Parse(orgId)
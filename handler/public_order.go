@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resourcePublicOrder struct {
+	repo         *repositories.DBconn
+	orderHandler IOrderHandler
+}
+
+type IHandlerPublicOrder interface {
+	CreatePublicOrder(orgId, projectId string, tableNumber int, request models.PublicOrderRequest) (*models.Order, error)
+	CreatePublicTakeoutOrder(orgId, projectId string, request models.PublicTakeoutOrderRequest) (*models.Order, error)
+	GetOrderTracking(token string) (*models.PublicOrderTracking, error)
+}
+
+func NewSourceHandlerPublicOrder(repo *repositories.DBconn, orderHandler IOrderHandler) IHandlerPublicOrder {
+	return &resourcePublicOrder{repo: repo, orderHandler: orderHandler}
+}
+
+// CreatePublicOrder cria pedido feito pelo cliente na mesa, validado contra o cardápio ativo
+func (r *resourcePublicOrder) CreatePublicOrder(orgId, projectId string, tableNumber int, request models.PublicOrderRequest) (*models.Order, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(request.Items) == 0 {
+		return nil, errors.New("invalid_items: order must have at least one item")
+	}
+
+	table, err := r.repo.Tables.GetTableByNumber(orgUUID, projectUUID, tableNumber)
+	if err != nil {
+		return nil, fmt.Errorf("table %d not found", tableNumber)
+	}
+
+	if err := r.validateAgainstActiveMenu(orgUUID, projectUUID, request.Items); err != nil {
+		return nil, err
+	}
+
+	settings, err := r.repo.Settings.GetOrCreateSettings(orgUUID, projectUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &models.Order{
+		OrganizationId: orgUUID,
+		ProjectId:      projectUUID,
+		TableId:        &table.Id,
+		TableNumber:    &table.Number,
+		Items:          publicOrderItems(request.Items),
+		TotalAmount:    request.TotalAmount,
+		Note:           request.Note,
+		Source:         "public",
+		TrackingToken:  generateTrackingToken(),
+	}
+	if settings.PublicOrderRequiresApproval {
+		order.Status = models.OrderStatusAwaitingApproval
+	}
+
+	if err := r.orderHandler.CreateOrder(order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// CreatePublicTakeoutOrder cria pedido de retirada ou entrega feito pelo cliente no site.
+// O cliente é localizado pelo telefone (ou email) e cadastrado se ainda não existir.
+func (r *resourcePublicOrder) CreatePublicTakeoutOrder(orgId, projectId string, request models.PublicTakeoutOrderRequest) (*models.Order, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Type != models.OrderTypeTakeout && request.Type != models.OrderTypeDelivery {
+		return nil, fmt.Errorf("invalid_order_type: type must be takeout or delivery")
+	}
+	if len(request.Items) == 0 {
+		return nil, errors.New("invalid_items: order must have at least one item")
+	}
+	if strings.TrimSpace(request.Customer.Name) == "" || strings.TrimSpace(request.Customer.Phone) == "" {
+		return nil, errors.New("invalid_customer: customer name and phone are required")
+	}
+
+	if err := r.validateAgainstActiveMenu(orgUUID, projectUUID, request.Items); err != nil {
+		return nil, err
+	}
+
+	customer, err := findOrCreateCustomer(r.repo, orgUUID, projectUUID, request.Customer)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &models.Order{
+		OrganizationId:  orgUUID,
+		ProjectId:       projectUUID,
+		CustomerId:      &customer.Id,
+		Type:            request.Type,
+		DeliveryAddress: request.Address,
+		ScheduledFor:    request.ScheduledFor,
+		Items:           publicOrderItems(request.Items),
+		TotalAmount:     request.TotalAmount,
+		Note:            request.Note,
+		Source:          "public",
+		TrackingToken:   generateTrackingToken(),
+	}
+
+	if err := r.orderHandler.CreateOrder(order); err != nil {
+		return nil, err
+	}
+
+	// Endereço fica só no pedido: o cadastro do cliente não é alterado por pedido anônimo
+	return order, nil
+}
+
+// findOrCreateCustomer localiza o cliente pelo telefone ou email; cadastra se não existir
+func findOrCreateCustomer(repo *repositories.DBconn, orgId, projectId uuid.UUID, data models.PublicOrderCustomer) (*models.Customer, error) {
+	if customer, err := repo.Customers.GetCustomerByPhone(orgId, projectId, data.Phone); err == nil {
+		return customer, nil
+	}
+	if data.Email != "" {
+		if customer, err := repo.Customers.GetCustomerByEmail(orgId, projectId, data.Email); err == nil {
+			return customer, nil
+		}
+	}
+
+	customer := &models.Customer{
+		Id:             uuid.New(),
+		OrganizationId: orgId,
+		ProjectId:      projectId,
+		Name:           data.Name,
+		Email:          data.Email,
+		Phone:          data.Phone,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if err := repo.Customers.CreateCustomer(customer); err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+// publicOrderItems copia os itens enviados pelo cliente.
+// Preço, estação e status dos itens são sempre definidos pelo servidor.
+func publicOrderItems(requested []models.OrderItem) models.OrderItems {
+	items := make(models.OrderItems, 0, len(requested))
+	for _, item := range requested {
+		items = append(items, models.OrderItem{
+			ProductId: item.ProductId,
+			Variant:   item.Variant,
+			Quantity:  item.Quantity,
+			Seat:      item.Seat,
+			Price:     item.Price,
+			Notes:     item.Notes,
+			Modifiers: item.Modifiers,
+		})
+	}
+	return items
+}
+
+// GetOrderTracking retorna o andamento do pedido para o cliente
+func (r *resourcePublicOrder) GetOrderTracking(token string) (*models.PublicOrderTracking, error) {
+	if token == "" {
+		return nil, errors.New("order not found")
+	}
+
+	order, err := r.repo.Orders.GetOrderByTrackingToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("order not found: %w", err)
+	}
+
+	history, err := r.repo.OrderStatusHistory.ListByOrder(order.Id)
+	if err != nil {
+		return nil, err
+	}
+	progress, remaining := utils.GetOrderProgressFromHistory(*order, history)
+
+	return &models.PublicOrderTracking{
+		OrderId:           order.Id,
+		TableNumber:       order.TableNumber,
+		Type:              order.Type,
+		ScheduledFor:      order.ScheduledFor,
+		DeliveryFee:       order.DeliveryFee,
+		Status:            order.Status,
+		Items:             order.Items,
+		TotalAmount:       order.AmountDue(),
+		ProgressPercent:   progress,
+		RemainingMinutes:  remaining,
+		EstimatedDelivery: order.EstimatedDeliveryTime,
+		CreatedAt:         order.CreatedAt,
+	}, nil
+}
+
+// validateAgainstActiveMenu garante que todos os produtos pertencem ao cardápio ativo no momento
+func (r *resourcePublicOrder) validateAgainstActiveMenu(orgId, projectId uuid.UUID, items []models.OrderItem) error {
+	now := utils.ProjectClock(r.repo.Projects, projectId).Now()
+	menu, err := r.repo.Menus.GetActiveMenuByTimeRange(orgId, projectId, now)
+	if err != nil || menu == nil {
+		return errors.New("menu_unavailable: no active menu for this project")
+	}
+
+	categories, err := r.repo.Categories.GetCategoriesByMenu(menu.Id)
+	if err != nil {
+		return err
+	}
+	allowed := make(map[uuid.UUID]bool, len(categories))
+	for _, category := range categories {
+		if category.Active {
+			allowed[category.Id] = true
+		}
+	}
+
+	productIds := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		productIds = append(productIds, item.ProductId)
+	}
+
+	products, err := r.repo.Products.GetProductsByIds(productIds)
+	if err != nil {
+		return err
+	}
+	productMap := make(map[uuid.UUID]models.Product, len(products))
+	for _, product := range products {
+		productMap[product.Id] = product
+	}
+
+	for _, item := range items {
+		product, exists := productMap[item.ProductId]
+		if !exists || product.OrganizationId != orgId || product.ProjectId != projectId {
+			return fmt.Errorf("invalid_items: product %s not found", item.ProductId)
+		}
+		if !product.Active || product.CategoryId == nil || !allowed[*product.CategoryId] {
+			return fmt.Errorf("invalid_items: product %s is not available in the current menu", product.Name)
+		}
+	}
+
+	return nil
+}
+
+// generateTrackingToken gera token aleatório para o cliente acompanhar o pedido
+func generateTrackingToken() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return uuid.New().String()
+	}
+	return hex.EncodeToString(bytes)
+}
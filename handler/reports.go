@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"lep/repositories"
 	"lep/repositories/models"
+	"lep/utils"
 	"time"
 
 	"github.com/google/uuid"
@@ -104,17 +105,18 @@ func (r *ReportsHandler) GetOccupancyReport(orgId, projectId string, startDate,
 	}
 	totalTables := len(tables)
 
-	// Buscar reservas no período
-	reservations, err := r.repo.Reservations.GetReservationsByProject(orgUUID, projectUUID)
+	// Buscar reservas no período (dias no fuso do projeto)
+	clock := utils.ProjectClock(r.repo.Projects, projectUUID)
+	startDate, endDate = clock.Date(startDate), clock.Date(endDate)
+	reservations, err := r.repo.Reservations.GetReservationsInRange(orgUUID, projectUUID, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filtrar reservas no período
+	// Filtrar reservas confirmadas
 	var periodReservations []models.Reservation
 	for _, res := range reservations {
-		resDate, _ := time.Parse("2006-01-02", res.Datetime)
-		if resDate.After(startDate) && resDate.Before(endDate) && res.Status == "confirmed" {
+		if res.Status == "confirmed" {
 			periodReservations = append(periodReservations, res)
 		}
 	}
@@ -129,12 +131,11 @@ func (r *ReportsHandler) GetOccupancyReport(orgId, projectId string, startDate,
 	// Iterar por cada dia do período
 	for d := startDate; d.Before(endDate); d = d.AddDate(0, 0, 1) {
 		dayStart := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, d.Location())
-		dayEnd := dayStart.Add(24 * time.Hour)
+		dayEnd := dayStart.AddDate(0, 0, 1)
 
 		reservationsThisDay := 0
 		for _, res := range periodReservations {
-			resDate, _ := time.Parse("2006-01-02", res.Datetime)
-			if resDate.After(dayStart) && resDate.Before(dayEnd) {
+			if !res.StartsAt.Before(dayStart) && res.StartsAt.Before(dayEnd) {
 				reservationsThisDay++
 			}
 		}
@@ -183,22 +184,18 @@ func (r *ReportsHandler) GetReservationReport(orgId, projectId string, startDate
 	orgUUID, _ := uuid.Parse(orgId)
 	projectUUID, _ := uuid.Parse(projectId)
 
-	// Buscar reservas no período
-	reservations, err := r.repo.Reservations.GetReservationsByProject(orgUUID, projectUUID)
+	// Buscar reservas no período (dias no fuso do projeto)
+	clock := utils.ProjectClock(r.repo.Projects, projectUUID)
+	startDate, endDate = clock.Date(startDate), clock.Date(endDate)
+	periodReservations, err := r.repo.Reservations.GetReservationsInRange(orgUUID, projectUUID, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filtrar e contar por status
+	// Contar por status
 	statusBreakdown := make(map[string]int)
-	var periodReservations []models.Reservation
-
-	for _, res := range reservations {
-		resDate, _ := time.Parse("2006-01-02", res.Datetime)
-		if resDate.After(startDate) && resDate.Before(endDate) {
-			periodReservations = append(periodReservations, res)
-			statusBreakdown[res.Status]++
-		}
+	for _, res := range periodReservations {
+		statusBreakdown[res.Status]++
 	}
 
 	// Calcular métricas
@@ -327,19 +324,18 @@ func (r *ReportsHandler) ExportReportToCSV(reportType, orgId, projectId string,
 
 // GenerateDailyMetrics - Gera métricas diárias para armazenamento
 func (r *ReportsHandler) GenerateDailyMetrics(orgId, projectId uuid.UUID, date time.Time) error {
-	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	dayEnd := dayStart.Add(24 * time.Hour)
+	dayStart := utils.ProjectClock(r.repo.Projects, projectId).Date(date)
+	dayEnd := dayStart.AddDate(0, 0, 1)
 
 	// Contar reservas do dia
-	reservations, err := r.repo.Reservations.GetReservationsByProject(orgId, projectId)
+	reservations, err := r.repo.Reservations.GetReservationsInRange(orgId, projectId, dayStart, dayEnd)
 	if err != nil {
 		return err
 	}
 
 	reservationCount := 0
 	for _, res := range reservations {
-		resDate, _ := time.Parse("2006-01-02", res.Datetime)
-		if resDate.After(dayStart) && resDate.Before(dayEnd) && res.Status == "confirmed" {
+		if res.Status == "confirmed" {
 			reservationCount++
 		}
 	}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"fmt"
 	"lep/repositories"
 	"lep/repositories/models"
+	"lep/utils"
 	"time"
 
 	"github.com/google/uuid"
@@ -34,25 +36,54 @@ func (r *resourceReservation) GetReservation(id string) (*models.Reservation, er
 }
 
 func (r *resourceReservation) CreateReservation(reservation *models.Reservation) error {
+	if err := NormalizeReservationDatetime(r.repo, reservation); err != nil {
+		return err
+	}
 	reservation.Id = uuid.New()
 	if reservation.Status == "" {
 		reservation.Status = "confirmed"
 	}
 	reservation.CreatedAt = time.Now()
 	reservation.UpdatedAt = time.Now()
-	err := r.repo.Reservations.CreateReservation(reservation)
+
+	// Permanência prevista do grupo fica gravada na reserva; a mesa é reservada de forma exclusiva
+	settings, err := r.repo.Settings.GetOrCreateSettings(reservation.OrganizationId, reservation.ProjectId)
+	if err != nil {
+		return err
+	}
+	if reservation.DurationMinutes <= 0 {
+		reservation.DurationMinutes = utils.DiningDurationMinutes(settings, reservation.PartySize)
+	}
+	err = r.repo.Reservations.CreateReservationExclusive(reservation, utils.DiningDurationMinutes(settings, 0), reservationPacing(r.repo))
 	if err != nil {
 		return err
 	}
+	utils.GetRealtimeBus().Publish(reservation.OrganizationId, reservation.ProjectId, utils.RealtimeTopicFloor, "reservation.created", reservation)
 	return nil
 }
 
 func (r *resourceReservation) UpdateReservation(updatedReservation *models.Reservation) error {
+	// Atualização parcial sem datetime mantém o horário atual
+	if updatedReservation.Datetime != "" {
+		if err := NormalizeReservationDatetime(r.repo, updatedReservation); err != nil {
+			return err
+		}
+	}
+	settings, err := r.repo.Settings.GetOrCreateSettings(updatedReservation.OrganizationId, updatedReservation.ProjectId)
+	if err != nil {
+		return err
+	}
+	if updatedReservation.PartySize > 0 && updatedReservation.DurationMinutes <= 0 {
+		updatedReservation.DurationMinutes = utils.DiningDurationMinutes(settings, updatedReservation.PartySize)
+	}
+	defaultDuration := utils.DiningDurationMinutes(settings, 0)
 	updatedReservation.UpdatedAt = time.Now()
-	err := r.repo.Reservations.UpdateReservation(updatedReservation)
+	// Mesa trocada pela equipe substitui a escolha do alocador (e a montagem) e o ritmo é conferido na mesma transação
+	err = r.repo.Reservations.UpdateReservationExclusive(updatedReservation, defaultDuration, reservationPacing(r.repo))
 	if err != nil {
 		return err
 	}
+	utils.GetRealtimeBus().Publish(updatedReservation.OrganizationId, updatedReservation.ProjectId, utils.RealtimeTopicFloor, "reservation.updated", updatedReservation)
 	return nil
 }
 
@@ -61,10 +92,15 @@ func (r *resourceReservation) DeleteReservation(id string) error {
 	if err != nil {
 		return err
 	}
+	reservation, err := r.repo.Reservations.GetReservationById(uuid)
+	if err != nil {
+		return err
+	}
 	err = r.repo.Reservations.SoftDeleteReservation(uuid)
 	if err != nil {
 		return err
 	}
+	utils.GetRealtimeBus().Publish(reservation.OrganizationId, reservation.ProjectId, utils.RealtimeTopicFloor, "reservation.deleted", reservation)
 	return nil
 }
 
@@ -88,6 +124,20 @@ func (r *resourceReservation) IsTableAvailable(tableId uuid.UUID, datetime time.
 	return r.repo.Reservations.IsReservationTableAvailable(tableId, datetime, durationMinutes)
 }
 
+// NormalizeReservationDatetime interpreta o datetime no fuso do projeto e grava o instante (starts_at)
+// e a forma local com offset devolvida pela API
+func NormalizeReservationDatetime(repo *repositories.DBconn, reservation *models.Reservation) error {
+	clock := utils.ProjectClock(repo.Projects, reservation.ProjectId)
+	datetime, err := clock.ParseDatetime(reservation.Datetime)
+	if err != nil {
+		return fmt.Errorf("invalid_datetime: %w", err)
+	}
+	startsAt := datetime.UTC()
+	reservation.StartsAt = &startsAt
+	reservation.Datetime = clock.Format(datetime)
+	return nil
+}
+
 func NewSourceHandlerReservation(repo *repositories.DBconn) IHandlerReservation {
 	return &resourceReservation{repo: repo}
 }
\ No newline at end of file
@@ -64,8 +64,15 @@ func (r *ReservationEnhancedHandler) CreateReservationWithTriggers(reservation *
 	reservation.CreatedAt = time.Now()
 	reservation.UpdatedAt = time.Now()
 
-	// Criar reserva
-	if err := r.repo.Reservations.CreateReservation(reservation); err != nil {
+	// Criar reserva com a mesa bloqueada: a checagem de conflito é refeita dentro da transação
+	settings, err := r.repo.Settings.GetOrCreateSettings(reservation.OrganizationId, reservation.ProjectId)
+	if err != nil {
+		return err
+	}
+	if reservation.DurationMinutes <= 0 {
+		reservation.DurationMinutes = utils.DiningDurationMinutes(settings, reservation.PartySize)
+	}
+	if err := r.repo.Reservations.CreateReservationExclusive(reservation, utils.DiningDurationMinutes(settings, 0), reservationPacing(r.repo)); err != nil {
 		return err
 	}
 
@@ -91,6 +98,8 @@ func (r *ReservationEnhancedHandler) CreateReservationWithTriggers(reservation *
 		table = t
 	}
 
+	utils.GetRealtimeBus().Publish(reservation.OrganizationId, reservation.ProjectId, utils.RealtimeTopicFloor, "reservation.created", reservation)
+
 	// Trigger de notificação imediata (reserva criada)
 	if err := r.eventService.TriggerReservationCreated(reservation.OrganizationId, reservation.ProjectId, reservation, customer, table); err != nil {
 		fmt.Printf("Error triggering reservation created event: %v\n", err)
@@ -139,11 +148,20 @@ func (r *ReservationEnhancedHandler) UpdateReservationWithTriggers(updatedReserv
 		}
 	}
 
+	settings, err := r.repo.Settings.GetOrCreateSettings(updatedReservation.OrganizationId, updatedReservation.ProjectId)
+	if err != nil {
+		return err
+	}
+	if updatedReservation.PartySize > 0 && updatedReservation.DurationMinutes <= 0 {
+		updatedReservation.DurationMinutes = utils.DiningDurationMinutes(settings, updatedReservation.PartySize)
+	}
 	updatedReservation.UpdatedAt = time.Now()
-	if err := r.repo.Reservations.UpdateReservation(updatedReservation); err != nil {
+	if err := r.repo.Reservations.UpdateReservationExclusive(updatedReservation, utils.DiningDurationMinutes(settings, 0), nil); err != nil {
 		return err
 	}
 
+	utils.GetRealtimeBus().Publish(updatedReservation.OrganizationId, updatedReservation.ProjectId, utils.RealtimeTopicFloor, "reservation.updated", updatedReservation)
+
 	// Buscar dados adicionais para o trigger
 	customer, err := r.repo.Customers.GetCustomerById(updatedReservation.CustomerId)
 	if err != nil {
@@ -187,6 +205,8 @@ func (r *ReservationEnhancedHandler) CancelReservationWithTriggers(id, reason st
 		return err
 	}
 
+	utils.GetRealtimeBus().Publish(reservation.OrganizationId, reservation.ProjectId, utils.RealtimeTopicFloor, "reservation.cancelled", reservation)
+
 	// Liberar mesa (se houver)
 	if reservation.TableId != nil {
 		if err := r.updateTableStatus(*reservation.TableId, "livre"); err != nil {
@@ -255,16 +275,16 @@ func (r *ReservationEnhancedHandler) ValidateReservation(reservation *models.Res
 		return fmt.Errorf("settings not found: %w", err)
 	}
 
+	// Horário interpretado no fuso do projeto (preenche starts_at e o datetime local com offset)
+	if err := NormalizeReservationDatetime(r.repo, reservation); err != nil {
+		return err
+	}
+	reservationTime := *reservation.StartsAt
+
 	// Validar antecedência mínima
 	now := time.Now()
 	minAdvanceTime := now.Add(time.Duration(settings.MinAdvanceHours) * time.Hour)
 
-	// Parse reservation.Datetime from string to time.Time
-	reservationTime, err := time.Parse(time.RFC3339, reservation.Datetime)
-	if err != nil {
-		return fmt.Errorf("invalid reservation datetime format: %w", err)
-	}
-
 	if reservationTime.Before(minAdvanceTime) {
 		return fmt.Errorf("reservation must be at least %d hours in advance", settings.MinAdvanceHours)
 	}
@@ -276,11 +296,7 @@ func (r *ReservationEnhancedHandler) ValidateReservation(reservation *models.Res
 	}
 
 	// Validar períodos bloqueados
-	reservationTime, err = time.Parse(time.RFC3339, reservation.Datetime)
-	if err != nil {
-		return fmt.Errorf("invalid reservation datetime format: %w", err)
-	}
-	if err := r.checkBlockedPeriods(reservation.OrganizationId, reservation.ProjectId, reservationTime); err != nil {
+	if err := r.checkBlockedPeriods(reservation.OrganizationId, reservation.ProjectId, reservationTime, reservation.TableId); err != nil {
 		return err
 	}
 
@@ -298,34 +314,31 @@ func (r *ReservationEnhancedHandler) ValidateReservation(reservation *models.Res
 		return fmt.Errorf("party size (%d) exceeds table capacity (%d)", reservation.PartySize, table.Capacity)
 	}
 
-	// Validar conflitos de horário (só para mesa não "livre")
-	if table.Status != "livre" {
-		if err := r.checkTimeConflicts(reservation); err != nil {
-			return err
-		}
+	// Validar conflitos de horário (o status atual da mesa não diz nada sobre o horário da reserva)
+	if err := r.checkTimeConflicts(reservation); err != nil {
+		return err
 	}
 
 	return nil
 }
 
-// checkBlockedPeriods - Verifica se a data/hora está em período bloqueado
-func (r *ReservationEnhancedHandler) checkBlockedPeriods(orgId, projectId uuid.UUID, datetime time.Time) error {
-	// Implementação simplificada - assumindo que o repository existirá
-	// Na prática seria: r.repo.BlockedPeriods.CheckPeriodBlocked(orgId, projectId, datetime)
-
-	// Por agora, uma validação básica - não permitir reservas em domingos
-	if datetime.Weekday() == time.Sunday {
-		return fmt.Errorf("reservations are not allowed on Sundays")
+// checkBlockedPeriods - Verifica se a data/hora está em período bloqueado do projeto ou da mesa
+func (r *ReservationEnhancedHandler) checkBlockedPeriods(orgId, projectId uuid.UUID, datetime time.Time, tableId *uuid.UUID) error {
+	periods, err := r.repo.BlockedPeriods.GetActiveBlockedPeriodsInRange(orgId, projectId, datetime, datetime)
+	if err != nil {
+		return fmt.Errorf("error checking blocked periods: %w", err)
 	}
 
-	// Não permitir reservas muito tarde (após 23h)
-	if datetime.Hour() >= 23 {
-		return fmt.Errorf("reservations are not allowed after 11 PM")
+	var table *models.Table
+	if tableId != nil {
+		if table, err = r.repo.Tables.GetTableById(*tableId); err != nil {
+			return fmt.Errorf("table not found: %w", err)
+		}
 	}
 
-	// Não permitir reservas muito cedo (antes das 10h)
-	if datetime.Hour() < 10 {
-		return fmt.Errorf("reservations are not allowed before 10 AM")
+	loc := utils.ProjectClock(r.repo.Projects, projectId).Location()
+	if period := models.FindBlockingPeriod(periods, datetime, loc, table); period != nil {
+		return fmt.Errorf("blocked_period: reservations are not allowed at this time (%s)", period.Name)
 	}
 
 	return nil
@@ -343,50 +356,47 @@ func (r *ReservationEnhancedHandler) updateTableStatus(tableId uuid.UUID, status
 	return r.repo.Tables.UpdateTable(table)
 }
 
-// checkTimeConflicts - Verifica conflitos de horário para a mesa
+// checkTimeConflicts - Verifica conflitos de horário para a mesa (permanência das configurações do projeto)
 func (r *ReservationEnhancedHandler) checkTimeConflicts(reservation *models.Reservation) error {
-	if reservation.TableId == nil {
+	if reservation.TableId == nil || reservation.StartsAt == nil {
 		return nil
 	}
-	// Parse reservation.Datetime from string to time.Time
-	reservationTime, err := time.Parse(time.RFC3339, reservation.Datetime)
+	clock := utils.ProjectClock(r.repo.Projects, reservation.ProjectId)
+	settings, err := r.repo.Settings.GetOrCreateSettings(reservation.OrganizationId, reservation.ProjectId)
 	if err != nil {
-		return fmt.Errorf("invalid reservation datetime format: %w", err)
+		return err
 	}
 
-	// Buscar reservas existentes para a mesa no mesmo dia
-	dayStart := time.Date(reservationTime.Year(), reservationTime.Month(), reservationTime.Day(), 0, 0, 0, 0, reservationTime.Location())
-	dayEnd := dayStart.Add(24 * time.Hour)
+	reservationStart := *reservation.StartsAt
+	reservationEnd := reservationStart.Add(utils.ReservationDuration(settings, *reservation))
 
-	existingReservations, err := r.repo.Reservations.GetReservationsByTableAndDateRange(*reservation.TableId, dayStart, dayEnd)
+	// Buscar reservas da mesa que ainda podem estar em andamento ou começam antes do fim desta
+	existingReservations, err := r.repo.Reservations.GetReservationsByTableAndDateRange(*reservation.TableId,
+		reservationStart.Add(-utils.MaxDiningDuration(settings)), reservationEnd)
 	if err != nil {
 		return err
 	}
 
-	// Verificar conflitos (assumindo 2 horas por reserva)
-	reservationStart := reservationTime
-	reservationEnd := reservationStart.Add(2 * time.Hour)
-
 	for _, existing := range existingReservations {
 		// Pular a própria reserva se for uma atualização
 		if existing.Id == reservation.Id {
 			continue
 		}
 
-		// Pular reservas canceladas
-		if existing.Status == "cancelled" {
+		// Só reservas ativas ocupam a mesa
+		if existing.Status != "confirmed" && existing.Status != "pending" {
 			continue
 		}
 
-		existingStart, err := time.Parse(time.RFC3339, existing.Datetime)
-		if err != nil {
-			continue // skip if the datetime is invalid
+		if existing.StartsAt == nil {
+			continue
 		}
-		existingEnd := existingStart.Add(2 * time.Hour)
+		existingStart := *existing.StartsAt
+		existingEnd := existingStart.Add(utils.ReservationDuration(settings, existing))
 
 		// Verificar sobreposição
 		if reservationStart.Before(existingEnd) && reservationEnd.After(existingStart) {
-			return fmt.Errorf("time conflict with existing reservation at %s", existingStart.Format("15:04"))
+			return fmt.Errorf("reservation_conflict: time conflict with existing reservation at %s", clock.ClockTime(existingStart))
 		}
 	}
 
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Limites anti-spam dos chamados públicos (por mesa; o limite por IP fica na rota)
+const (
+	serviceRequestMaxOpen     = 5                // chamados não resolvidos ao mesmo tempo
+	serviceRequestMaxInWindow = 10               // chamados criados na janela
+	serviceRequestWindow      = 10 * time.Minute // janela do limite acima
+	serviceRequestMaxMessage  = 200              // caracteres da mensagem
+)
+
+type resourceServiceRequest struct {
+	repo *repositories.DBconn
+}
+
+type IHandlerServiceRequest interface {
+	CreatePublicRequest(orgId, projectId string, tableNumber int, request models.PublicServiceRequest, clientIP string) (*models.ServiceRequest, bool, error)
+	ListPublicRequests(orgId, projectId string, tableNumber int) ([]models.PublicServiceRequestStatus, error)
+	GetRequest(id string) (*models.ServiceRequest, error)
+	ListRequests(orgId, projectId, status, environmentId, tableId string, limit int) ([]models.ServiceRequest, error)
+	AcknowledgeRequest(id, userId string) (*models.ServiceRequest, error)
+	ResolveRequest(id, userId string) (*models.ServiceRequest, error)
+	GetReport(orgId, projectId string, from, to time.Time) (*models.ServiceRequestReport, error)
+}
+
+func NewSourceHandlerServiceRequest(repo *repositories.DBconn) IHandlerServiceRequest {
+	return &resourceServiceRequest{repo: repo}
+}
+
+// CreatePublicRequest registra o chamado feito pelo cliente na mesa.
+// Um chamado do mesmo tipo ainda não resolvido é devolvido sem criar outro (created = false).
+func (r *resourceServiceRequest) CreatePublicRequest(orgId, projectId string, tableNumber int, request models.PublicServiceRequest, clientIP string) (*models.ServiceRequest, bool, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, false, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	requestType := strings.ToLower(strings.TrimSpace(request.Type))
+	message := strings.TrimSpace(request.Message)
+	if !isServiceRequestType(requestType) {
+		return nil, false, fmt.Errorf("invalid_service_request: type must be one of %s", strings.Join(models.ServiceRequestTypes, ", "))
+	}
+	if requestType == models.ServiceRequestTypeCustom && message == "" {
+		return nil, false, errors.New("invalid_service_request: message is required for custom requests")
+	}
+	if len([]rune(message)) > serviceRequestMaxMessage {
+		return nil, false, fmt.Errorf("invalid_service_request: message must have up to %d characters", serviceRequestMaxMessage)
+	}
+
+	table, err := r.repo.Tables.GetTableByNumber(orgUUID, projectUUID, tableNumber)
+	if err != nil {
+		return nil, false, fmt.Errorf("table %d not found", tableNumber)
+	}
+
+	open, err := r.repo.ServiceRequests.ListOpenByTable(table.Id)
+	if err != nil {
+		return nil, false, err
+	}
+	for i := range open {
+		if open[i].Type == requestType && (requestType != models.ServiceRequestTypeCustom || open[i].Message == message) {
+			return &open[i], false, nil
+		}
+	}
+	if len(open) >= serviceRequestMaxOpen {
+		return nil, false, errors.New("too_many_requests: this table already has open requests")
+	}
+	recent, err := r.repo.ServiceRequests.CountByTableSince(table.Id, time.Now().Add(-serviceRequestWindow))
+	if err != nil {
+		return nil, false, err
+	}
+	if recent >= serviceRequestMaxInWindow {
+		return nil, false, errors.New("too_many_requests: too many requests from this table, please wait")
+	}
+
+	serviceRequest := &models.ServiceRequest{
+		Id:             uuid.New(),
+		OrganizationId: orgUUID,
+		ProjectId:      projectUUID,
+		TableId:        table.Id,
+		TableNumber:    table.Number,
+		EnvironmentId:  table.EnvironmentId,
+		Type:           requestType,
+		Message:        message,
+		Status:         models.ServiceRequestStatusOpen,
+		ClientIP:       clientIP,
+	}
+	if err := r.repo.ServiceRequests.CreateRequest(serviceRequest); err != nil {
+		return nil, false, err
+	}
+
+	utils.GetRealtimeBus().Publish(orgUUID, projectUUID, utils.RealtimeTopicFloor, "service_request.created", serviceRequest)
+	return serviceRequest, true, nil
+}
+
+// ListPublicRequests chamados ainda não resolvidos da mesa (acompanhamento pelo cliente)
+func (r *resourceServiceRequest) ListPublicRequests(orgId, projectId string, tableNumber int) ([]models.PublicServiceRequestStatus, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := r.repo.Tables.GetTableByNumber(orgUUID, projectUUID, tableNumber)
+	if err != nil {
+		return nil, fmt.Errorf("table %d not found", tableNumber)
+	}
+
+	open, err := r.repo.ServiceRequests.ListOpenByTable(table.Id)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]models.PublicServiceRequestStatus, 0, len(open))
+	for _, request := range open {
+		statuses = append(statuses, models.PublicServiceRequestStatus{
+			Id:             request.Id,
+			Type:           request.Type,
+			Status:         request.Status,
+			CreatedAt:      request.CreatedAt,
+			AcknowledgedAt: request.AcknowledgedAt,
+		})
+	}
+	return statuses, nil
+}
+
+func (r *resourceServiceRequest) GetRequest(id string) (*models.ServiceRequest, error) {
+	requestId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.ServiceRequests.GetRequestById(requestId)
+}
+
+// ListRequests lista chamados do projeto (padrão: não resolvidos, mais antigos primeiro)
+func (r *resourceServiceRequest) ListRequests(orgId, projectId, status, environmentId, tableId string, limit int) ([]models.ServiceRequest, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return r.repo.ServiceRequests.ListRequests(orgUUID, projectUUID, status, environmentId, tableId, limit)
+}
+
+// AcknowledgeRequest marca o chamado como visto pela equipe
+func (r *resourceServiceRequest) AcknowledgeRequest(id, userId string) (*models.ServiceRequest, error) {
+	request, err := r.GetRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != models.ServiceRequestStatusOpen {
+		return nil, fmt.Errorf("invalid_service_request: request is already %s", request.Status)
+	}
+
+	now := time.Now()
+	request.Status = models.ServiceRequestStatusAcknowledged
+	request.AcknowledgedAt = &now
+	request.AcknowledgedBy = parseActor(userId)
+	if err := r.repo.ServiceRequests.UpdateRequest(request); err != nil {
+		return nil, err
+	}
+
+	utils.GetRealtimeBus().Publish(request.OrganizationId, request.ProjectId, utils.RealtimeTopicFloor, "service_request.acknowledged", request)
+	return request, nil
+}
+
+// ResolveRequest encerra o chamado (chamados abertos são considerados vistos no mesmo momento)
+func (r *resourceServiceRequest) ResolveRequest(id, userId string) (*models.ServiceRequest, error) {
+	request, err := r.GetRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status == models.ServiceRequestStatusResolved {
+		return nil, errors.New("invalid_service_request: request is already resolved")
+	}
+
+	now := time.Now()
+	user := parseActor(userId)
+	if request.AcknowledgedAt == nil {
+		request.AcknowledgedAt = &now
+		request.AcknowledgedBy = user
+	}
+	request.Status = models.ServiceRequestStatusResolved
+	request.ResolvedAt = &now
+	request.ResolvedBy = user
+	if err := r.repo.ServiceRequests.UpdateRequest(request); err != nil {
+		return nil, err
+	}
+
+	utils.GetRealtimeBus().Publish(request.OrganizationId, request.ProjectId, utils.RealtimeTopicFloor, "service_request.resolved", request)
+	return request, nil
+}
+
+// GetReport tempos de resposta aos chamados por setor (ambiente da mesa)
+func (r *resourceServiceRequest) GetReport(orgId, projectId string, from, to time.Time) (*models.ServiceRequestReport, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := r.repo.ServiceRequests.ListRequestsInPeriod(orgUUID, projectUUID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	environments, err := r.repo.Environments.GetEnvironmentsByProject(orgUUID, projectUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	overall, sections := utils.BuildServiceRequestReport(requests, environments)
+	return &models.ServiceRequestReport{
+		From:     from,
+		To:       to,
+		Overall:  overall,
+		Sections: sections,
+	}, nil
+}
+
+func isServiceRequestType(requestType string) bool {
+	for _, allowed := range models.ServiceRequestTypes {
+		if requestType == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resourceSLA struct {
+	repo *repositories.DBconn
+}
+
+type IHandlerSLA interface {
+	GetReport(orgId, projectId string, from, to time.Time) (*models.SLAReport, error)
+	ListBreaches(orgId, projectId, breachType string, open bool, limit int) ([]models.OrderSLABreach, error)
+}
+
+func NewSourceHandlerSLA(repo *repositories.DBconn) IHandlerSLA {
+	return &resourceSLA{repo: repo}
+}
+
+// GetReport calcula o percentual de pedidos no prazo por estação, turno e produto
+func (r *resourceSLA) GetReport(orgId, projectId string, from, to time.Time) (*models.SLAReport, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := r.repo.Settings.GetOrCreateSettings(orgUUID, projectUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := r.repo.PrepTimes.ListFinishedOrders(orgUUID, projectUUID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	stations, err := r.repo.KitchenStations.ListStations(orgUUID, projectUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	productSet := make(map[uuid.UUID]bool)
+	var productIds []uuid.UUID
+	for _, order := range orders {
+		for _, item := range order.Items {
+			if !productSet[item.ProductId] {
+				productSet[item.ProductId] = true
+				productIds = append(productIds, item.ProductId)
+			}
+		}
+	}
+	var products []models.Product
+	if len(productIds) > 0 {
+		products, err = r.repo.Products.GetProductsByIds(productIds)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	breaches, err := r.repo.SLA.CountBreaches(orgUUID, projectUUID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := utils.ProjectClock(r.repo.Projects, projectUUID).Location()
+	overall, byStation, byShift, byProduct := utils.BuildSLAReport(orders, stations, products, settings, loc)
+	return &models.SLAReport{
+		From:         from,
+		To:           to,
+		GraceMinutes: settings.SlaGraceMinutes,
+		Overall:      overall,
+		Stations:     byStation,
+		Shifts:       byShift,
+		Products:     byProduct,
+		Breaches:     breaches,
+	}, nil
+}
+
+// ListBreaches lista as violações de SLA registradas pelo monitor
+func (r *resourceSLA) ListBreaches(orgId, projectId, breachType string, open bool, limit int) ([]models.OrderSLABreach, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.SLA.ListBreaches(orgUUID, projectUUID, breachType, open, limit)
+}
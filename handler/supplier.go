@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resourceSupplier struct {
+	repo *repositories.DBconn
+}
+
+type IHandlerSupplier interface {
+	GetSupplier(id string) (*models.Supplier, error)
+	ListSuppliers(orgId, projectId string) ([]models.Supplier, error)
+	CreateSupplier(supplier *models.Supplier) error
+	UpdateSupplier(supplier *models.Supplier) error
+	DeleteSupplier(id string) error
+	GetPurchaseOrder(id string) (*models.PurchaseOrder, error)
+	ListPurchaseOrders(orgId, projectId, status string) ([]models.PurchaseOrder, error)
+	CreatePurchaseOrder(order *models.PurchaseOrder, createdBy string) error
+	UpdatePurchaseOrder(existing *models.PurchaseOrder, update *models.PurchaseOrder) error
+	SendPurchaseOrder(order *models.PurchaseOrder) error
+	CancelPurchaseOrder(order *models.PurchaseOrder) error
+	ReceivePurchaseOrder(order *models.PurchaseOrder, request models.PurchaseOrderReceiveRequest) error
+}
+
+func NewSourceHandlerSupplier(repo *repositories.DBconn) IHandlerSupplier {
+	return &resourceSupplier{repo: repo}
+}
+
+// GetSupplier busca fornecedor por ID
+func (r *resourceSupplier) GetSupplier(id string) (*models.Supplier, error) {
+	supplierId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Suppliers.GetSupplierById(supplierId)
+}
+
+// ListSuppliers lista fornecedores do projeto
+func (r *resourceSupplier) ListSuppliers(orgId, projectId string) ([]models.Supplier, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Suppliers.ListSuppliers(orgUUID, projectUUID)
+}
+
+// CreateSupplier cria fornecedor
+func (r *resourceSupplier) CreateSupplier(supplier *models.Supplier) error {
+	now := time.Now()
+	supplier.Id = uuid.New()
+	supplier.Active = true
+	supplier.CreatedAt = now
+	supplier.UpdatedAt = now
+	return r.repo.Suppliers.CreateSupplier(supplier)
+}
+
+// UpdateSupplier atualiza fornecedor
+func (r *resourceSupplier) UpdateSupplier(supplier *models.Supplier) error {
+	supplier.UpdatedAt = time.Now()
+	return r.repo.Suppliers.UpdateSupplier(supplier)
+}
+
+// DeleteSupplier exclui fornecedor
+func (r *resourceSupplier) DeleteSupplier(id string) error {
+	supplierId, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	return r.repo.Suppliers.SoftDeleteSupplier(supplierId)
+}
+
+// GetPurchaseOrder busca pedido de compra por ID
+func (r *resourceSupplier) GetPurchaseOrder(id string) (*models.PurchaseOrder, error) {
+	orderId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Suppliers.GetPurchaseOrderById(orderId)
+}
+
+// ListPurchaseOrders lista pedidos de compra do projeto
+func (r *resourceSupplier) ListPurchaseOrders(orgId, projectId, status string) ([]models.PurchaseOrder, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Suppliers.ListPurchaseOrders(orgUUID, projectUUID, status)
+}
+
+// CreatePurchaseOrder cria pedido de compra em rascunho
+func (r *resourceSupplier) CreatePurchaseOrder(order *models.PurchaseOrder, createdBy string) error {
+	order.Id = uuid.New()
+	if err := r.preparePurchaseOrder(order); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	order.Status = models.PurchaseOrderStatusDraft
+	order.CreatedBy = parseActor(createdBy)
+	order.SentAt = nil
+	order.ReceivedAt = nil
+	order.CancelledAt = nil
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	return r.repo.Suppliers.CreatePurchaseOrder(order)
+}
+
+// UpdatePurchaseOrder altera fornecedor, itens e observações de um rascunho
+func (r *resourceSupplier) UpdatePurchaseOrder(existing *models.PurchaseOrder, update *models.PurchaseOrder) error {
+	if existing.Status != models.PurchaseOrderStatusDraft {
+		return fmt.Errorf("invalid_transition: purchase order in status %s can no longer be edited", existing.Status)
+	}
+
+	existing.SupplierId = update.SupplierId
+	existing.Notes = update.Notes
+	existing.ExpectedAt = update.ExpectedAt
+	existing.Items = update.Items
+	if err := r.preparePurchaseOrder(existing); err != nil {
+		return err
+	}
+
+	existing.UpdatedAt = time.Now()
+	return r.repo.Suppliers.UpdatePurchaseOrder(existing)
+}
+
+// SendPurchaseOrder marca o rascunho como enviado ao fornecedor
+func (r *resourceSupplier) SendPurchaseOrder(order *models.PurchaseOrder) error {
+	return r.changeStatus(order, models.PurchaseOrderStatusDraft, models.PurchaseOrderStatusSent)
+}
+
+// CancelPurchaseOrder cancela pedido ainda não recebido
+func (r *resourceSupplier) CancelPurchaseOrder(order *models.PurchaseOrder) error {
+	if order.Status != models.PurchaseOrderStatusDraft && order.Status != models.PurchaseOrderStatusSent {
+		return fmt.Errorf("invalid_transition: cannot cancel purchase order in status %s", order.Status)
+	}
+	return r.changeStatus(order, order.Status, models.PurchaseOrderStatusCancelled)
+}
+
+// ReceivePurchaseOrder dá entrada dos ingredientes no estoque.
+// Sem itens na requisição, considera recebido exatamente o que foi pedido.
+func (r *resourceSupplier) ReceivePurchaseOrder(order *models.PurchaseOrder, request models.PurchaseOrderReceiveRequest) error {
+	if order.Status != models.PurchaseOrderStatusSent {
+		return fmt.Errorf("invalid_transition: cannot receive purchase order in status %s", order.Status)
+	}
+
+	received := make(map[uuid.UUID]float64, len(order.Items))
+	if len(request.Items) == 0 {
+		for _, item := range order.Items {
+			received[item.Id] = item.Quantity
+		}
+	} else {
+		itemIds := make(map[uuid.UUID]bool, len(order.Items))
+		for _, item := range order.Items {
+			itemIds[item.Id] = true
+		}
+		for _, item := range request.Items {
+			if !itemIds[item.ItemId] {
+				return fmt.Errorf("invalid_purchase_order: item %s does not belong to this purchase order", item.ItemId)
+			}
+			if item.Quantity < 0 {
+				return errors.New("invalid_purchase_order: received quantity must not be negative")
+			}
+			received[item.ItemId] = item.Quantity
+		}
+	}
+
+	return r.repo.Suppliers.ReceivePurchaseOrder(order, received)
+}
+
+// preparePurchaseOrder valida fornecedor e itens e recalcula os totais
+func (r *resourceSupplier) preparePurchaseOrder(order *models.PurchaseOrder) error {
+	supplier, err := r.repo.Suppliers.GetSupplierById(order.SupplierId)
+	if err != nil || supplier.OrganizationId != order.OrganizationId || supplier.ProjectId != order.ProjectId {
+		return errors.New("invalid_purchase_order: supplier not found in this project")
+	}
+	if !supplier.Active {
+		return errors.New("invalid_purchase_order: supplier is inactive")
+	}
+
+	if len(order.Items) == 0 {
+		return errors.New("invalid_purchase_order: purchase order must have at least one item")
+	}
+
+	ingredientIds := make([]uuid.UUID, 0, len(order.Items))
+	for _, item := range order.Items {
+		if item.Quantity <= 0 {
+			return errors.New("invalid_purchase_order: quantity must be greater than zero")
+		}
+		if item.UnitCost < 0 {
+			return errors.New("invalid_purchase_order: unit_cost must not be negative")
+		}
+		ingredientIds = append(ingredientIds, item.IngredientId)
+	}
+
+	ingredients, err := r.repo.Ingredients.GetIngredientsByIds(ingredientIds)
+	if err != nil {
+		return err
+	}
+	valid := make(map[uuid.UUID]bool, len(ingredients))
+	for _, ingredient := range ingredients {
+		if ingredient.OrganizationId == order.OrganizationId && ingredient.ProjectId == order.ProjectId {
+			valid[ingredient.Id] = true
+		}
+	}
+
+	order.Total = 0
+	for i := range order.Items {
+		item := &order.Items[i]
+		if !valid[item.IngredientId] {
+			return fmt.Errorf("invalid_purchase_order: ingredient %s not found in this project", item.IngredientId)
+		}
+		item.Id = uuid.New()
+		item.PurchaseOrderId = order.Id
+		item.ReceivedQuantity = 0
+		item.Total = utils.RoundMoney(item.Quantity * item.UnitCost)
+		order.Total += item.Total
+	}
+	order.Total = utils.RoundMoney(order.Total)
+	return nil
+}
+
+// changeStatus aplica a transição de status se o pedido ainda estiver no status esperado
+func (r *resourceSupplier) changeStatus(order *models.PurchaseOrder, from, to string) error {
+	if order.Status != from {
+		return fmt.Errorf("invalid_transition: cannot change purchase order from %s to %s", order.Status, to)
+	}
+
+	updated, err := r.repo.Suppliers.UpdatePurchaseOrderStatus(order.Id, from, to)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.New("invalid_transition: purchase order was changed by another request")
+	}
+
+	refreshed, err := r.repo.Suppliers.GetPurchaseOrderById(order.Id)
+	if err != nil {
+		return err
+	}
+	*order = *refreshed
+	return nil
+}
@@ -0,0 +1,362 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resourceTab struct {
+	repo *repositories.DBconn
+}
+
+type IHandlerTab interface {
+	OpenTab(tab *models.Tab) error
+	GetTab(id string) (*models.Tab, error)
+	GetTabSummary(id string) (*models.TabSummary, error)
+	ListTabs(orgId, projectId, status string) ([]models.Tab, error)
+	AttachOrder(tabId, orderId string) (*models.Tab, error)
+	DetachOrder(tabId, orderId string) (*models.Tab, error)
+	SetServiceCharge(tabId string, percent float64) (*models.Tab, error)
+	SplitTab(tabId string, request models.TabSplitRequest) ([]models.TabSplitShare, error)
+	AddPayment(tabId string, payment *models.TabPayment) (*models.TabPayment, error)
+	CloseTab(tabId string) (*models.Tab, error)
+}
+
+func NewSourceHandlerTab(repo *repositories.DBconn) IHandlerTab {
+	return &resourceTab{repo: repo}
+}
+
+// OpenTab abre comanda na mesa com a taxa de serviço configurada no projeto
+func (r *resourceTab) OpenTab(tab *models.Tab) error {
+	table, err := r.repo.Tables.GetTableById(tab.TableId)
+	if err != nil {
+		return fmt.Errorf("table not found: %w", err)
+	}
+	if table.OrganizationId != tab.OrganizationId || table.ProjectId != tab.ProjectId {
+		return errors.New("table not found in this project")
+	}
+	if table.MergedIntoId != nil {
+		return errors.New("invalid_tab: table is merged into another table; use the main table's tab")
+	}
+
+	existing, err := r.repo.Tabs.GetOpenTabByTable(tab.TableId)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return errors.New("already_exists: table already has an open tab")
+	}
+
+	settings, err := r.repo.Settings.GetOrCreateSettings(tab.OrganizationId, tab.ProjectId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tab.Id = uuid.New()
+	tab.Status = models.TabStatusOpen
+	tab.ServiceChargePercent = settings.ServiceChargePercent
+	tab.Subtotal = 0
+	tab.ServiceCharge = 0
+	tab.Total = 0
+	tab.PaidTotal = 0
+	tab.TipsTotal = 0
+	tab.OpenedAt = now
+	tab.ClosedAt = nil
+	tab.CreatedAt = now
+	tab.UpdatedAt = now
+
+	if err := r.repo.Tabs.CreateTab(tab); err != nil {
+		return err
+	}
+
+	utils.GetRealtimeBus().Publish(tab.OrganizationId, tab.ProjectId, utils.RealtimeTopicFloor, "tab.opened", tab)
+	return nil
+}
+
+// GetTab busca comanda por ID
+func (r *resourceTab) GetTab(id string) (*models.Tab, error) {
+	tabId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Tabs.GetTabById(tabId)
+}
+
+// GetTabSummary retorna comanda com pedidos, pagamentos e saldo
+func (r *resourceTab) GetTabSummary(id string) (*models.TabSummary, error) {
+	tab, err := r.GetTab(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pedidos podem ter sido criados já com tab_id; totais são calculados na leitura, sem gravar
+	if tab.Status == models.TabStatusOpen {
+		if err := computeTabTotals(r.repo.Tabs, tab); err != nil {
+			return nil, err
+		}
+	}
+
+	orders, err := r.repo.Tabs.ListTabOrders(tab.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := r.repo.Tabs.ListPayments(tab.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TabSummary{
+		Tab:      *tab,
+		Orders:   orders,
+		Payments: payments,
+		Balance:  utils.RoundMoney(tab.Balance()),
+	}, nil
+}
+
+// ListTabs lista comandas do projeto
+func (r *resourceTab) ListTabs(orgId, projectId, status string) ([]models.Tab, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.Tabs.ListTabs(orgUUID, projectUUID, status)
+}
+
+// AttachOrder vincula pedido à comanda e recalcula os totais com a comanda travada
+func (r *resourceTab) AttachOrder(tabId, orderId string) (*models.Tab, error) {
+	return r.withOpenTab(tabId, func(tabs repositories.ITabRepository, tab *models.Tab) error {
+		order, err := r.repo.Orders.GetOrderById(orderId)
+		if err != nil {
+			return fmt.Errorf("order not found: %w", err)
+		}
+		if order.OrganizationId != tab.OrganizationId || order.ProjectId != tab.ProjectId {
+			return errors.New("order not found in this project")
+		}
+		if order.Status == "cancelled" {
+			return errors.New("invalid_tab: cancelled orders cannot be attached")
+		}
+		if order.TabId != nil && *order.TabId != tab.Id {
+			return errors.New("invalid_tab: order already belongs to another tab")
+		}
+
+		if err := tabs.AttachOrder(order.Id, tab.Id); err != nil {
+			return err
+		}
+		return recalculateTab(tabs, tab)
+	})
+}
+
+// DetachOrder remove pedido da comanda e recalcula os totais com a comanda travada
+func (r *resourceTab) DetachOrder(tabId, orderId string) (*models.Tab, error) {
+	return r.withOpenTab(tabId, func(tabs repositories.ITabRepository, tab *models.Tab) error {
+		order, err := r.repo.Orders.GetOrderById(orderId)
+		if err != nil {
+			return fmt.Errorf("order not found: %w", err)
+		}
+		if order.TabId == nil || *order.TabId != tab.Id {
+			return errors.New("invalid_tab: order does not belong to this tab")
+		}
+
+		if err := tabs.DetachOrder(order.Id); err != nil {
+			return err
+		}
+		return recalculateTab(tabs, tab)
+	})
+}
+
+// SetServiceCharge altera a taxa de serviço da comanda (0 remove a taxa)
+func (r *resourceTab) SetServiceCharge(tabId string, percent float64) (*models.Tab, error) {
+	if percent < 0 || percent > 100 {
+		return nil, errors.New("invalid_tab: service charge percent must be between 0 and 100")
+	}
+
+	return r.withOpenTab(tabId, func(tabs repositories.ITabRepository, tab *models.Tab) error {
+		tab.ServiceChargePercent = percent
+		return recalculateTab(tabs, tab)
+	})
+}
+
+// SplitTab calcula a divisão da conta por item, por lugar ou igualitária
+func (r *resourceTab) SplitTab(tabId string, request models.TabSplitRequest) ([]models.TabSplitShare, error) {
+	tab, err := r.GetTab(tabId)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := r.repo.Tabs.ListTabOrders(tab.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch request.Mode {
+	case "even":
+		parts := request.Parts
+		if parts == 0 {
+			parts = tab.Guests
+		}
+		return utils.SplitTabEvenly(*tab, parts)
+	case "seat":
+		return utils.SplitTabBySeat(*tab, orders), nil
+	case "item":
+		return utils.SplitTabByItems(*tab, orders, request.Groups)
+	default:
+		return nil, errors.New("invalid_tab: mode must be one of even, seat, item")
+	}
+}
+
+// AddPayment registra pagamento parcial/total. Dinheiro acima do saldo gera troco;
+// cartão e Pix não podem exceder o saldo. Saldo conferido com a comanda travada,
+// para pagamentos simultâneos (caixa e confirmação Pix) não pagarem além do total.
+func (r *resourceTab) AddPayment(tabId string, payment *models.TabPayment) (*models.TabPayment, error) {
+	id, err := uuid.Parse(tabId)
+	if err != nil {
+		return nil, err
+	}
+
+	var paidTab *models.Tab
+	err = r.repo.Tabs.WithTabLock(id, func(tabs repositories.ITabRepository, tab *models.Tab) error {
+		if tab.Status != models.TabStatusOpen {
+			return errors.New("tab_closed: tab is already closed")
+		}
+
+		// Garantir saldo atualizado antes de abater
+		if err := recalculateTab(tabs, tab); err != nil {
+			return err
+		}
+
+		balance := utils.RoundMoney(tab.Balance())
+		if balance <= 0 {
+			return errors.New("invalid_tab: tab is already fully paid")
+		}
+		amount := utils.RoundMoney(payment.Amount)
+		payment.ChangeGiven = 0
+		if amount > balance {
+			if payment.Method != models.PaymentMethodCash {
+				return fmt.Errorf("invalid_tab: payment of %.2f exceeds balance of %.2f", amount, balance)
+			}
+			payment.ChangeGiven = utils.RoundMoney(amount - balance)
+			amount = balance
+		}
+
+		now := time.Now()
+		payment.Id = uuid.New()
+		payment.OrganizationId = tab.OrganizationId
+		payment.ProjectId = tab.ProjectId
+		payment.TabId = tab.Id
+		payment.Amount = amount
+		payment.Tip = utils.RoundMoney(payment.Tip)
+		payment.PaidAt = now
+		payment.CreatedAt = now
+
+		if err := tabs.CreatePayment(payment); err != nil {
+			return err
+		}
+		paidTab = tab
+		return recalculateTab(tabs, tab)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.GetRealtimeBus().Publish(paidTab.OrganizationId, paidTab.ProjectId, utils.RealtimeTopicFloor, "tab.payment", payment)
+	return payment, nil
+}
+
+// CloseTab fecha a comanda quitada e libera a mesa. Saldo conferido com a comanda travada,
+// para pedido vinculado ou pagamento simultâneo não ficar de fora do fechamento.
+func (r *resourceTab) CloseTab(tabId string) (*models.Tab, error) {
+	tab, err := r.withOpenTab(tabId, func(tabs repositories.ITabRepository, tab *models.Tab) error {
+		if err := computeTabTotals(tabs, tab); err != nil {
+			return err
+		}
+		if balance := utils.RoundMoney(tab.Balance()); balance > 0 {
+			return fmt.Errorf("tab_not_paid: remaining balance of %.2f", balance)
+		}
+
+		now := time.Now()
+		tab.Status = models.TabStatusClosed
+		tab.ClosedAt = &now
+		return tabs.CloseTab(tab)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.GetRealtimeBus().Publish(tab.OrganizationId, tab.ProjectId, utils.RealtimeTopicFloor, "tab.closed", tab)
+	return tab, nil
+}
+
+// withOpenTab executa fn com a comanda travada e relida, garantindo que ainda está aberta
+func (r *resourceTab) withOpenTab(tabId string, fn func(tabs repositories.ITabRepository, tab *models.Tab) error) (*models.Tab, error) {
+	id, err := uuid.Parse(tabId)
+	if err != nil {
+		return nil, err
+	}
+
+	var locked *models.Tab
+	err = r.repo.Tabs.WithTabLock(id, func(tabs repositories.ITabRepository, tab *models.Tab) error {
+		if tab.Status != models.TabStatusOpen {
+			return errors.New("tab_closed: tab is already closed")
+		}
+		locked = tab
+		return fn(tabs, tab)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return locked, nil
+}
+
+// recalculateTab recalcula e grava os totais pelo repositório da transação que trava a comanda
+func recalculateTab(tabs repositories.ITabRepository, tab *models.Tab) error {
+	if err := computeTabTotals(tabs, tab); err != nil {
+		return err
+	}
+	return tabs.UpdateTabTotals(tab)
+}
+
+// computeTabTotals soma pedidos e pagamentos nos totais da comanda, sem gravar
+func computeTabTotals(tabs repositories.ITabRepository, tab *models.Tab) error {
+	orders, err := tabs.ListTabOrders(tab.Id)
+	if err != nil {
+		return err
+	}
+
+	payments, err := tabs.ListPayments(tab.Id)
+	if err != nil {
+		return err
+	}
+
+	subtotal := 0.0
+	for _, order := range orders {
+		if order.Status == "cancelled" {
+			continue
+		}
+		subtotal += order.TotalAmount
+	}
+
+	paid, tips := 0.0, 0.0
+	for _, payment := range payments {
+		paid += payment.Amount
+		tips += payment.Tip
+	}
+
+	tab.Subtotal = utils.RoundMoney(subtotal)
+	tab.ServiceCharge = utils.CalculateServiceCharge(tab.Subtotal, tab.ServiceChargePercent)
+	tab.Total = utils.RoundMoney(tab.Subtotal + tab.ServiceCharge)
+	tab.PaidTotal = utils.RoundMoney(paid)
+	tab.TipsTotal = utils.RoundMoney(tips)
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"lep/repositories"
 	"lep/repositories/models"
+	"lep/utils"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,6 +21,9 @@ type IHandlerTables interface {
 	UpdateTable(updatedTable *models.Table) error
 	DeleteTable(id string) error
 	ListTables(orgId, projectId string, environmentId *string) ([]models.Table, error)
+	TransferTable(orgId, projectId, tableId string, request models.TableTransferRequest, changedBy string) (*models.TableServiceResult, error)
+	MergeTables(orgId, projectId, tableId string, request models.TableMergeRequest, changedBy string) (*models.TableServiceResult, error)
+	SplitTables(orgId, projectId, tableId string, request models.TableSplitRequest) (*models.TableServiceResult, error)
 }
 
 func (r *resourceTables) GetTable(id string) (*models.Table, error) {
@@ -64,6 +68,11 @@ func (r *resourceTables) UpdateTable(updatedTable *models.Table) error {
 		return errors.New("already_exists: table with this number already exists in this project")
 	}
 
+	// União de mesas só muda pelas rotas de merge/split
+	if stored, err := r.repo.Tables.GetById(updatedTable.Id); err == nil {
+		updatedTable.MergedIntoId = stored.MergedIntoId
+	}
+
 	updatedTable.UpdatedAt = time.Now()
 	err = r.repo.Tables.UpdateTable(updatedTable)
 	if err != nil {
@@ -108,6 +117,493 @@ func (r *resourceTables) ListTables(orgId, projectId string, environmentId *stri
 	return resp, nil
 }
 
+// TransferTable move pedidos inteiros, itens avulsos ou todo o atendimento da mesa para outra mesa.
+// Pedidos lançados em comanda seguem para a comanda da mesa de destino (aberta se preciso).
+func (r *resourceTables) TransferTable(orgId, projectId, tableId string, request models.TableTransferRequest, changedBy string) (*models.TableServiceResult, error) {
+	source, err := r.getProjectTable(orgId, projectId, tableId)
+	if err != nil {
+		return nil, err
+	}
+	target, err := r.getProjectTable(orgId, projectId, request.ToTableId.String())
+	if err != nil {
+		return nil, err
+	}
+	if source.Id == target.Id {
+		return nil, errors.New("invalid_transfer: source and target tables must be different")
+	}
+
+	now := time.Now()
+	actor := parseActor(changedBy)
+	var targetTab *models.Tab
+	moves := []models.TableMove{}
+	err = r.repo.Tables.WithServiceLock([]uuid.UUID{source.Id, target.Id}, func(tables repositories.ITableRepository, tabs repositories.ITabRepository) (*models.TableServiceChange, error) {
+		// Mesas, comandas e pedidos relidos sob o lock
+		if source, err = tables.GetById(source.Id); err != nil {
+			return nil, err
+		}
+		if target, err = tables.GetById(target.Id); err != nil {
+			return nil, err
+		}
+		if source.MergedIntoId != nil || target.MergedIntoId != nil {
+			return nil, errors.New("invalid_transfer: merged tables are served by their main table")
+		}
+
+		orders, err := tables.ListOpenOrdersByTable(source.Id)
+		if err != nil {
+			return nil, err
+		}
+		sourceTab, err := tabs.GetOpenTabByTable(source.Id)
+		if err != nil {
+			return nil, err
+		}
+		if targetTab, err = tabs.GetOpenTabByTable(target.Id); err != nil {
+			return nil, err
+		}
+
+		change := &models.TableServiceChange{}
+
+		// Comanda da mesa de destino para os pedidos que estavam em comanda
+		destinationTab := func() (*models.Tab, error) {
+			if targetTab != nil {
+				return targetTab, nil
+			}
+			tab, err := r.newServiceTab(target, sourceTab, now)
+			if err != nil {
+				return nil, err
+			}
+			change.NewTabs = append(change.NewTabs, *tab)
+			targetTab = tab
+			return tab, nil
+		}
+		moveOrder := func(order *models.Order) error {
+			if order.TabId != nil {
+				tab, err := destinationTab()
+				if err != nil {
+					return err
+				}
+				order.TabId = &tab.Id
+			}
+			order.TableId = &target.Id
+			if order.TableNumber != nil {
+				number := target.Number
+				order.TableNumber = &number
+			}
+			order.UpdatedAt = now
+			change.Orders = append(change.Orders, *order)
+
+			orderId := order.Id
+			moves = append(moves, tableMove(source, target, &orderId, nil, nil, order.TotalAmount))
+			return nil
+		}
+
+		whole := len(request.OrderIds) == 0 && len(request.Items) == 0
+		remaining := len(orders)
+		if whole {
+			merged, err := tables.ListMergedTables(source.Id)
+			if err != nil {
+				return nil, err
+			}
+			if len(merged) > 0 {
+				return nil, errors.New("invalid_transfer: split the merged tables before moving the whole table")
+			}
+			if len(orders) == 0 && sourceTab == nil {
+				return nil, fmt.Errorf("invalid_transfer: table %d has no open orders or tab", source.Number)
+			}
+
+			// A comanda acompanha a mesa; se o destino já tem comanda, as duas viram uma só
+			if sourceTab != nil {
+				if targetTab == nil {
+					sourceTab.TableId = target.Id
+					targetTab = sourceTab
+				} else {
+					targetTab.Guests += sourceTab.Guests
+					change.TabMerges = append(change.TabMerges, models.TabMerge{FromTabId: sourceTab.Id, ToTabId: targetTab.Id})
+				}
+				change.Tabs = append(change.Tabs, *targetTab)
+				sourceTab = nil
+			}
+			for i := range orders {
+				if err := moveOrder(&orders[i]); err != nil {
+					return nil, err
+				}
+			}
+			remaining = 0
+		} else {
+			openOrders := make(map[uuid.UUID]*models.Order, len(orders))
+			for i := range orders {
+				openOrders[orders[i].Id] = &orders[i]
+			}
+			findOrder := func(id uuid.UUID) (*models.Order, error) {
+				order, ok := openOrders[id]
+				if !ok {
+					return nil, fmt.Errorf("invalid_transfer: order %s is not open at table %d", id, source.Number)
+				}
+				delete(openOrders, id) // cada pedido entra uma vez só
+				return order, nil
+			}
+
+			for _, orderId := range request.OrderIds {
+				order, err := findOrder(orderId)
+				if err != nil {
+					return nil, err
+				}
+				if err := moveOrder(order); err != nil {
+					return nil, err
+				}
+				remaining--
+			}
+
+			for _, selection := range request.Items {
+				if len(selection.ItemIds) == 0 {
+					return nil, fmt.Errorf("invalid_transfer: no items selected for order %s", selection.OrderId)
+				}
+				order, err := findOrder(selection.OrderId)
+				if err != nil {
+					return nil, err
+				}
+				if len(selection.ItemIds) == len(order.Items) {
+					if err := moveOrder(order); err != nil {
+						return nil, err
+					}
+					remaining--
+					continue
+				}
+
+				split, err := utils.SplitOrderItems(order, selection.ItemIds, now)
+				if err != nil {
+					return nil, err
+				}
+				split.TableId = &target.Id
+				if split.TableNumber != nil {
+					number := target.Number
+					split.TableNumber = &number
+				}
+				if split.TabId != nil {
+					tab, err := destinationTab()
+					if err != nil {
+						return nil, err
+					}
+					split.TabId = &tab.Id
+				}
+
+				// Cada parte segue o status dos itens que ficaram com ela
+				change.History = append(change.History, utils.NewOrderStatusEntry(split, "", split.Status, "", actor))
+				change.History = append(change.History, advanceOrderStatus(order, actor)...)
+				change.History = append(change.History, advanceOrderStatus(split, actor)...)
+
+				for _, item := range split.Items {
+					change.StockTransfers = append(change.StockTransfers, models.OrderStockTransfer{
+						FromOrderId: order.Id,
+						ToOrderId:   split.Id,
+						ProductId:   item.ProductId,
+						Quantity:    item.Quantity,
+					})
+				}
+				change.SplitOrders = append(change.SplitOrders, *order)
+				change.NewOrders = append(change.NewOrders, *split)
+
+				orderId, newOrderId := order.Id, split.Id
+				moves = append(moves, tableMove(source, target, &orderId, &newOrderId, selection.ItemIds, split.TotalAmount))
+			}
+		}
+
+		source.Status = utils.TableStatusAfterService(source.Status, remaining > 0 || sourceTab != nil)
+		target.Status = utils.TableStatusAfterService(target.Status, true)
+		change.Tables = append(change.Tables, *source, *target)
+		return change, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.serviceResult(target, targetTab, moves)
+	if err != nil {
+		return nil, err
+	}
+	utils.GetRealtimeBus().Publish(target.OrganizationId, target.ProjectId, utils.RealtimeTopicFloor, "table.transferred", result)
+	return result, nil
+}
+
+// MergeTables une mesas à mesa principal: capacidade somada e uma conta só
+// (comandas das mesas unidas são absorvidas pela comanda da principal)
+func (r *resourceTables) MergeTables(orgId, projectId, tableId string, request models.TableMergeRequest, changedBy string) (*models.TableServiceResult, error) {
+	main, err := r.getProjectTable(orgId, projectId, tableId)
+	if err != nil {
+		return nil, err
+	}
+	if len(request.TableIds) == 0 {
+		return nil, errors.New("invalid_merge: table_ids is required")
+	}
+
+	tableIds := []uuid.UUID{main.Id}
+	seen := map[uuid.UUID]bool{main.Id: true}
+	for _, id := range request.TableIds {
+		if seen[id] {
+			return nil, errors.New("invalid_merge: tables must be distinct and different from the main table")
+		}
+		seen[id] = true
+		if _, err := r.getProjectTable(orgId, projectId, id.String()); err != nil {
+			return nil, err
+		}
+		tableIds = append(tableIds, id)
+	}
+
+	now := time.Now()
+	var mainTab *models.Tab
+	moves := []models.TableMove{}
+	err = r.repo.Tables.WithServiceLock(tableIds, func(tables repositories.ITableRepository, tabs repositories.ITabRepository) (*models.TableServiceChange, error) {
+		// Mesas, comandas e pedidos relidos sob o lock
+		if main, err = tables.GetById(main.Id); err != nil {
+			return nil, err
+		}
+		if main.MergedIntoId != nil {
+			return nil, fmt.Errorf("invalid_merge: table %d is merged into another table", main.Number)
+		}
+
+		if mainTab, err = tabs.GetOpenTabByTable(main.Id); err != nil {
+			return nil, err
+		}
+		orders, err := tables.ListOpenOrdersByTable(main.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		change := &models.TableServiceChange{}
+		for _, id := range request.TableIds {
+			table, err := tables.GetById(id)
+			if err != nil {
+				return nil, err
+			}
+			if table.MergedIntoId != nil {
+				return nil, fmt.Errorf("invalid_merge: table %d is already merged", table.Number)
+			}
+			merged, err := tables.ListMergedTables(table.Id)
+			if err != nil {
+				return nil, err
+			}
+			if len(merged) > 0 {
+				return nil, fmt.Errorf("invalid_merge: table %d has merged tables; split them first", table.Number)
+			}
+
+			tab, err := tabs.GetOpenTabByTable(table.Id)
+			if err != nil {
+				return nil, err
+			}
+			if tab != nil {
+				if mainTab == nil {
+					tab.TableId = main.Id
+					mainTab = tab
+				} else {
+					mainTab.Guests += tab.Guests
+					change.TabMerges = append(change.TabMerges, models.TabMerge{FromTabId: tab.Id, ToTabId: mainTab.Id})
+				}
+			}
+
+			tableOrders, err := tables.ListOpenOrdersByTable(table.Id)
+			if err != nil {
+				return nil, err
+			}
+			orders = append(orders, tableOrders...)
+
+			table.MergedIntoId = &main.Id
+			table.Status = utils.TableStatusAfterService(table.Status, true)
+			change.Tables = append(change.Tables, *table)
+			moves = append(moves, tableMove(table, main, nil, nil, nil, 0))
+		}
+
+		if mainTab == nil {
+			if mainTab, err = r.newServiceTab(main, nil, now); err != nil {
+				return nil, err
+			}
+			change.NewTabs = append(change.NewTabs, *mainTab)
+		} else {
+			change.Tabs = append(change.Tabs, *mainTab)
+		}
+
+		// Pedidos abertos de todas as mesas vão para a conta única
+		for _, order := range orders {
+			if order.TabId != nil && *order.TabId == mainTab.Id {
+				continue
+			}
+			order.TabId = &mainTab.Id
+			order.UpdatedAt = now
+			change.Orders = append(change.Orders, order)
+		}
+
+		main.Status = utils.TableStatusAfterService(main.Status, true)
+		change.Tables = append(change.Tables, *main)
+		return change, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.serviceResult(main, mainTab, moves)
+	if err != nil {
+		return nil, err
+	}
+	utils.GetRealtimeBus().Publish(main.OrganizationId, main.ProjectId, utils.RealtimeTopicFloor, "table.merged", result)
+	return result, nil
+}
+
+// SplitTables separa mesas unidas (vazio = todas). Os pedidos já lançados continuam na conta da principal.
+func (r *resourceTables) SplitTables(orgId, projectId, tableId string, request models.TableSplitRequest) (*models.TableServiceResult, error) {
+	main, err := r.getProjectTable(orgId, projectId, tableId)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := r.repo.Tables.ListMergedTables(main.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	tableIds := []uuid.UUID{main.Id}
+	for _, table := range merged {
+		tableIds = append(tableIds, table.Id)
+	}
+
+	moves := []models.TableMove{}
+	err = r.repo.Tables.WithServiceLock(tableIds, func(tables repositories.ITableRepository, tabs repositories.ITabRepository) (*models.TableServiceChange, error) {
+		// Mesas unidas relidas sob o lock
+		if main, err = tables.GetById(main.Id); err != nil {
+			return nil, err
+		}
+		merged, err := tables.ListMergedTables(main.Id)
+		if err != nil {
+			return nil, err
+		}
+		if len(merged) == 0 {
+			return nil, fmt.Errorf("invalid_split: table %d has no merged tables", main.Number)
+		}
+
+		selected := make(map[uuid.UUID]bool, len(request.TableIds))
+		for _, id := range request.TableIds {
+			selected[id] = true
+		}
+		for _, id := range request.TableIds {
+			found := false
+			for _, table := range merged {
+				found = found || table.Id == id
+			}
+			if !found {
+				return nil, fmt.Errorf("invalid_split: table %s is not merged into table %d", id, main.Number)
+			}
+		}
+
+		change := &models.TableServiceChange{}
+		for _, table := range merged {
+			if len(selected) > 0 && !selected[table.Id] {
+				continue
+			}
+			table.MergedIntoId = nil
+			table.Status = utils.TableStatusAfterService(table.Status, false)
+			change.Tables = append(change.Tables, table)
+			moves = append(moves, tableMove(main, &table, nil, nil, nil, 0))
+		}
+		return change, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mainTab, err := r.repo.Tabs.GetOpenTabByTable(main.Id)
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.serviceResult(main, mainTab, moves)
+	if err != nil {
+		return nil, err
+	}
+	utils.GetRealtimeBus().Publish(main.OrganizationId, main.ProjectId, utils.RealtimeTopicFloor, "table.split", result)
+	return result, nil
+}
+
+// getProjectTable busca a mesa garantindo que pertence ao projeto
+func (r *resourceTables) getProjectTable(orgId, projectId, tableId string) (*models.Table, error) {
+	id, err := uuid.Parse(tableId)
+	if err != nil {
+		return nil, err
+	}
+	table, err := r.repo.Tables.GetById(id)
+	if err != nil {
+		return nil, fmt.Errorf("table not found: %w", err)
+	}
+	if table.OrganizationId.String() != orgId || table.ProjectId.String() != projectId {
+		return nil, errors.New("table not found in this project")
+	}
+	return table, nil
+}
+
+// newServiceTab comanda aberta na mesa durante troca/união (taxa de serviço da comanda de origem ou do projeto)
+func (r *resourceTables) newServiceTab(table *models.Table, from *models.Tab, now time.Time) (*models.Tab, error) {
+	percent := 0.0
+	if from != nil {
+		percent = from.ServiceChargePercent
+	} else {
+		settings, err := r.repo.Settings.GetOrCreateSettings(table.OrganizationId, table.ProjectId)
+		if err != nil {
+			return nil, err
+		}
+		percent = settings.ServiceChargePercent
+	}
+
+	return &models.Tab{
+		Id:                   uuid.New(),
+		OrganizationId:       table.OrganizationId,
+		ProjectId:            table.ProjectId,
+		TableId:              table.Id,
+		Status:               models.TabStatusOpen,
+		ServiceChargePercent: percent,
+		OpenedAt:             now,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}, nil
+}
+
+// serviceResult atendimento da mesa com as mesas unidas e a capacidade somada
+func (r *resourceTables) serviceResult(table *models.Table, tab *models.Tab, moves []models.TableMove) (*models.TableServiceResult, error) {
+	merged, err := r.repo.Tables.ListMergedTables(table.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	capacity := table.Capacity
+	for _, m := range merged {
+		capacity += m.Capacity
+	}
+
+	return &models.TableServiceResult{
+		Table:            *table,
+		MergedTables:     merged,
+		CombinedCapacity: capacity,
+		Tab:              tab,
+		Moves:            moves,
+	}, nil
+}
+
+// advanceOrderStatus avança o pedido até o status derivado dos itens que ficaram com ele
+func advanceOrderStatus(order *models.Order, actor *uuid.UUID) []models.OrderStatusHistory {
+	var entries []models.OrderStatusHistory
+	for _, step := range utils.OrderStatusPath(order.Status, utils.DeriveOrderStatus(order.Status, order.Items)) {
+		entries = append(entries, utils.NewOrderStatusEntry(order, order.Status, step, "", actor))
+		utils.UpdateOrderStatus(order, step)
+	}
+	return entries
+}
+
+func tableMove(from, to *models.Table, orderId, newOrderId *uuid.UUID, itemIds []uuid.UUID, amount float64) models.TableMove {
+	return models.TableMove{
+		OrderId:         orderId,
+		NewOrderId:      newOrderId,
+		ItemIds:         itemIds,
+		FromTableId:     from.Id,
+		FromTableNumber: from.Number,
+		ToTableId:       to.Id,
+		ToTableNumber:   to.Number,
+		Amount:          amount,
+	}
+}
+
 func NewSourceHandlerTables(repo *repositories.DBconn) IHandlerTables {
 	return &resourceTables{repo: repo}
 }
@@ -0,0 +1,352 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"lep/utils"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type resourceTableAllocation struct {
+	repo *repositories.DBconn
+}
+
+type IHandlerTableAllocation interface {
+	GetCombination(id string) (*models.TableCombination, error)
+	ListCombinations(orgId, projectId string) ([]models.TableCombination, error)
+	CreateCombination(combination *models.TableCombination) error
+	UpdateCombination(combination *models.TableCombination) error
+	DeleteCombination(id string) error
+	NewAllocator(orgId, projectId uuid.UUID, start, end time.Time) (*utils.TableAllocator, error)
+	Reoptimize(orgId, projectId uuid.UUID, request models.TableReoptimizeRequest) (*models.TableReoptimizeResult, error)
+	SuggestTimes(orgId, projectId uuid.UUID, at time.Time, partySize int, environmentId *uuid.UUID) ([]models.ReservationTimeSuggestion, error)
+}
+
+func NewSourceHandlerTableAllocation(repo *repositories.DBconn) IHandlerTableAllocation {
+	return &resourceTableAllocation{repo: repo}
+}
+
+func (r *resourceTableAllocation) GetCombination(id string) (*models.TableCombination, error) {
+	combinationId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.TableCombinations.GetCombinationById(combinationId)
+}
+
+func (r *resourceTableAllocation) ListCombinations(orgId, projectId string) ([]models.TableCombination, error) {
+	orgUUID, err := uuid.Parse(orgId)
+	if err != nil {
+		return nil, err
+	}
+	projectUUID, err := uuid.Parse(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.TableCombinations.ListCombinations(orgUUID, projectUUID)
+}
+
+// CreateCombination cadastra montagem (mesas do projeto)
+func (r *resourceTableAllocation) CreateCombination(combination *models.TableCombination) error {
+	if err := r.checkTables(combination); err != nil {
+		return err
+	}
+	combination.Id = uuid.New()
+	combination.CreatedAt = time.Now()
+	combination.UpdatedAt = time.Now()
+	return r.repo.TableCombinations.CreateCombination(combination)
+}
+
+func (r *resourceTableAllocation) UpdateCombination(combination *models.TableCombination) error {
+	if err := r.checkTables(combination); err != nil {
+		return err
+	}
+	return r.repo.TableCombinations.UpdateCombination(combination)
+}
+
+func (r *resourceTableAllocation) DeleteCombination(id string) error {
+	combinationId, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	return r.repo.TableCombinations.SoftDeleteCombination(combinationId)
+}
+
+// NewAllocator carrega mesas, montagens, reservas ativas e bloqueios para alocar reservas que começam entre start e end
+func (r *resourceTableAllocation) NewAllocator(orgId, projectId uuid.UUID, start, end time.Time) (*utils.TableAllocator, error) {
+	settings, err := r.repo.Settings.GetOrCreateSettings(orgId, projectId)
+	if err != nil {
+		return nil, err
+	}
+	duration := utils.MaxDiningDuration(settings)
+
+	tables, err := r.repo.Tables.ListTables(orgId, projectId, nil)
+	if err != nil {
+		return nil, err
+	}
+	combinations, err := r.repo.TableCombinations.ListActiveCombinations(orgId, projectId)
+	if err != nil {
+		return nil, err
+	}
+	blockedPeriods, err := r.repo.BlockedPeriods.GetActiveBlockedPeriodsInRange(orgId, projectId, start, end.Add(duration))
+	if err != nil {
+		return nil, err
+	}
+
+	// Reservas que ainda ocupam a mesa no início do período ou começam antes do fim da última permanência
+	reservations, err := r.repo.Reservations.GetReservationsInRange(orgId, projectId, start.Add(-duration), end.Add(duration))
+	if err != nil {
+		return nil, err
+	}
+	active := make([]models.Reservation, 0, len(reservations))
+	for _, reservation := range reservations {
+		if reservation.Status == "confirmed" || reservation.Status == "pending" {
+			active = append(active, reservation)
+		}
+	}
+
+	return &utils.TableAllocator{
+		Tables:         tables,
+		Combinations:   combinations,
+		Reservations:   active,
+		BlockedPeriods: blockedPeriods,
+		Location:       utils.ProjectClock(r.repo.Projects, projectId).Location(),
+		Settings:       settings,
+	}, nil
+}
+
+// Reoptimize redistribui as mesas das reservas de um serviço para reduzir lugares sobrando.
+// Só mudam reservas com mesas escolhidas pelo alocador (ou ainda sem mesa); as da equipe ficam fixas.
+// Grupos maiores são encaixados primeiro. O plano só é gravado se nenhuma reserva que tinha mesa ficar sem.
+func (r *resourceTableAllocation) Reoptimize(orgId, projectId uuid.UUID, request models.TableReoptimizeRequest) (*models.TableReoptimizeResult, error) {
+	clock := utils.ProjectClock(r.repo.Projects, projectId)
+	date, err := clock.ParseDate(request.Date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid_reoptimize: date must be YYYY-MM-DD")
+	}
+	settings, err := r.repo.Settings.GetOrCreateSettings(orgId, projectId)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := serviceWindow(clock, date, settings, request.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	allocator, err := r.NewAllocator(orgId, projectId, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reservas do serviço que podem mudar de mesa; as demais seguem ocupando as suas
+	var movable, fixed []models.Reservation
+	for _, reservation := range allocator.Reservations {
+		inService := !reservation.StartsAt.Before(start) && reservation.StartsAt.Before(end)
+		if inService && (reservation.AutoAssigned || len(utils.ReservationTableIds(reservation)) == 0) {
+			movable = append(movable, reservation)
+		} else {
+			fixed = append(fixed, reservation)
+		}
+	}
+
+	result := &models.TableReoptimizeResult{
+		Date:       request.Date,
+		Service:    request.Service,
+		Unassigned: []uuid.UUID{},
+		Changes:    []models.TableReassignment{},
+	}
+	for _, reservation := range fixed {
+		if !reservation.StartsAt.Before(start) && reservation.StartsAt.Before(end) {
+			result.Fixed++
+		}
+	}
+	result.Reservations = len(movable) + result.Fixed
+
+	sort.SliceStable(movable, func(i, j int) bool {
+		if movable[i].PartySize != movable[j].PartySize {
+			return movable[i].PartySize > movable[j].PartySize
+		}
+		return movable[i].StartsAt.Before(*movable[j].StartsAt)
+	})
+
+	planner := *allocator
+	planner.Reservations = append([]models.Reservation{}, fixed...)
+	lostTable := false
+	var updates []models.Reservation
+	for _, reservation := range movable {
+		current := utils.ReservationTableIds(reservation)
+		if len(current) > 0 {
+			if waste := allocator.CapacityOf(current) - reservation.PartySize; waste > 0 {
+				result.WastedSeatsBefore += waste
+			}
+		}
+
+		// Mantém o grupo no ambiente em que já estava, quando possível
+		var environmentId *uuid.UUID
+		if len(current) > 0 {
+			environmentId = tableEnvironment(allocator.Tables, current[0])
+		}
+		allocation := planner.Allocate(*reservation.StartsAt, reservation.PartySize, environmentId, reservation.Id)
+		if allocation == nil {
+			result.Unassigned = append(result.Unassigned, reservation.Id)
+			lostTable = lostTable || len(current) > 0
+			continue
+		}
+		result.WastedSeatsAfter += allocation.WastedSeats
+
+		planned := reservation
+		utils.ApplyTableAllocation(&planned, allocation)
+		planner.Reservations = append(planner.Reservations, planned)
+		if sameTables(current, allocation.TableIds) {
+			continue
+		}
+		result.Changes = append(result.Changes, models.TableReassignment{
+			ReservationId: reservation.Id,
+			Datetime:      reservation.Datetime,
+			PartySize:     reservation.PartySize,
+			FromTableIds:  current,
+			To:            allocation,
+		})
+		updates = append(updates, planned)
+	}
+
+	if request.DryRun || len(updates) == 0 {
+		return result, nil
+	}
+	if lostTable {
+		return result, errors.New("reoptimize_conflict: some reservations would lose their tables; nothing was changed")
+	}
+	if err := r.repo.Reservations.UpdateReservationTables(updates, utils.DiningDurationMinutes(settings, 0)); err != nil {
+		return nil, err
+	}
+	result.Applied = true
+	utils.GetRealtimeBus().Publish(orgId, projectId, utils.RealtimeTopicFloor, "reservation.tables_reoptimized", result)
+	return result, nil
+}
+
+// SuggestTimes até três horários do mesmo dia, os mais próximos de at, em que o grupo ainda tem mesa
+// e cabe nos limites de ritmo
+func (r *resourceTableAllocation) SuggestTimes(orgId, projectId uuid.UUID, at time.Time, partySize int, environmentId *uuid.UUID) ([]models.ReservationTimeSuggestion, error) {
+	clock := utils.ProjectClock(r.repo.Projects, projectId)
+	dayStart := clock.StartOfDay(at)
+	settings, err := r.repo.Settings.GetOrCreateSettings(orgId, projectId)
+	if err != nil {
+		return nil, err
+	}
+	allocator, err := r.NewAllocator(orgId, projectId, dayStart, dayStart.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	pacing, err := newPacingChecker(r.repo, r.repo.Reservations, orgId, projectId, dayStart, dayStart.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	noTableFits := !allocator.FitsParty(partySize)
+	now := clock.Now()
+	var free []time.Time
+	for _, slot := range utils.ServiceTimeSlots(dayStart, settings, 0) {
+		dt, err := utils.SlotDateTime(dayStart, slot, clock.Location())
+		if err != nil || dt.Equal(at) || !dt.After(now) || allocator.ProjectBlocked(dt) || pacing.Check(dt, partySize, uuid.Nil) != nil {
+			continue
+		}
+		if allocator.Allocate(dt, partySize, environmentId, uuid.Nil) != nil ||
+			(noTableFits && allocator.Placeholder(dt, partySize, uuid.Nil) != nil) {
+			free = append(free, dt)
+		}
+	}
+
+	distance := func(t time.Time) time.Duration {
+		if t.Before(at) {
+			return at.Sub(t)
+		}
+		return t.Sub(at)
+	}
+	sort.SliceStable(free, func(i, j int) bool { return distance(free[i]) < distance(free[j]) })
+	if len(free) > 3 {
+		free = free[:3]
+	}
+	sort.Slice(free, func(i, j int) bool { return free[i].Before(free[j]) })
+
+	suggestions := make([]models.ReservationTimeSuggestion, 0, len(free))
+	for _, dt := range free {
+		suggestions = append(suggestions, models.ReservationTimeSuggestion{Time: clock.ClockTime(dt), Datetime: clock.Format(dt)})
+	}
+	return suggestions, nil
+}
+
+// checkTables confere se as mesas da montagem existem no projeto
+func (r *resourceTableAllocation) checkTables(combination *models.TableCombination) error {
+	for _, tableId := range combination.TableIds {
+		id, err := uuid.Parse(tableId)
+		if err != nil {
+			return fmt.Errorf("invalid_table_combination: invalid table id %s", tableId)
+		}
+		table, err := r.repo.Tables.GetTableById(id)
+		if err != nil || table.OrganizationId != combination.OrganizationId || table.ProjectId != combination.ProjectId {
+			return fmt.Errorf("invalid_table_combination: table %s not found in this project", tableId)
+		}
+	}
+	return nil
+}
+
+// serviceWindow período das reservas do serviço no dia local: almoço, jantar ou o dia inteiro
+func serviceWindow(clock *utils.Clock, date time.Time, settings *models.Settings, service string) (time.Time, time.Time, error) {
+	from, to := "", ""
+	switch service {
+	case "":
+		return date, date.AddDate(0, 0, 1), nil
+	case "lunch":
+		from, to = settings.LunchStart, settings.LunchEnd
+		if from == "" || to == "" {
+			from, to = "12:00", "14:30"
+		}
+	case "dinner":
+		from, to = settings.DinnerStart, settings.DinnerEnd
+		if from == "" || to == "" {
+			from, to = "19:00", "22:00"
+		}
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid_reoptimize: service must be lunch, dinner or empty")
+	}
+	start, err := clock.At(date, from)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid_reoptimize: invalid service hours %s", from)
+	}
+	end, err := clock.At(date, to)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid_reoptimize: invalid service hours %s", to)
+	}
+	// Último horário do serviço incluso
+	return start, end.Add(time.Minute), nil
+}
+
+func tableEnvironment(tables []models.Table, tableId uuid.UUID) *uuid.UUID {
+	for _, table := range tables {
+		if table.Id == tableId {
+			return table.EnvironmentId
+		}
+	}
+	return nil
+}
+
+func sameTables(a, b []uuid.UUID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[uuid.UUID]bool, len(a))
+	for _, id := range a {
+		set[id] = true
+	}
+	for _, id := range b {
+		if !set[id] {
+			return false
+		}
+	}
+	return true
+}
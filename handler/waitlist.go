@@ -3,6 +3,7 @@ package handler
 import (
 	"lep/repositories"
 	"lep/repositories/models"
+	"lep/utils"
 	"time"
 
 	"github.com/google/uuid"
@@ -41,6 +42,7 @@ func (r *resourceWaitlist) CreateWaitlist(waitlist *models.Waitlist) error {
 	if err != nil {
 		return err
 	}
+	utils.GetRealtimeBus().Publish(waitlist.OrganizationId, waitlist.ProjectId, utils.RealtimeTopicWaitlist, "waitlist.created", waitlist)
 	return nil
 }
 
@@ -50,6 +52,7 @@ func (r *resourceWaitlist) UpdateWaitlist(updatedWaitlist *models.Waitlist) erro
 	if err != nil {
 		return err
 	}
+	utils.GetRealtimeBus().Publish(updatedWaitlist.OrganizationId, updatedWaitlist.ProjectId, utils.RealtimeTopicWaitlist, "waitlist.updated", updatedWaitlist)
 	return nil
 }
 
@@ -58,10 +61,15 @@ func (r *resourceWaitlist) DeleteWaitlist(id string) error {
 	if err != nil {
 		return err
 	}
+	waitlist, err := r.repo.Waitlists.GetWaitlistById(uuid)
+	if err != nil {
+		return err
+	}
 	err = r.repo.Waitlists.SoftDeleteWaitlist(uuid)
 	if err != nil {
 		return err
 	}
+	utils.GetRealtimeBus().Publish(waitlist.OrganizationId, waitlist.ProjectId, utils.RealtimeTopicWaitlist, "waitlist.deleted", waitlist)
 	return nil
 }
 
@@ -45,6 +45,8 @@ func (w *WaitlistEnhancedHandler) AddToWaitlist(waitlist *models.Waitlist) error
 		return err
 	}
 
+	utils.GetRealtimeBus().Publish(waitlist.OrganizationId, waitlist.ProjectId, utils.RealtimeTopicWaitlist, "waitlist.created", waitlist)
+
 	// Converter automaticamente para lead
 	if err := w.ConvertToLead(waitlist.Id); err != nil {
 		// Log erro mas não interrompe
@@ -187,7 +189,9 @@ func (w *WaitlistEnhancedHandler) NotifyNextInLine(orgId, projectId uuid.UUID) e
 				// Atualizar status para "notified"
 				wait.Status = "notified"
 				wait.UpdatedAt = time.Now()
-				w.repo.Waitlists.UpdateWaitlist(&wait)
+				if err := w.repo.Waitlists.UpdateWaitlist(&wait); err == nil {
+					utils.GetRealtimeBus().Publish(orgId, projectId, utils.RealtimeTopicWaitlist, "waitlist.notified", wait)
+				}
 
 				break // Apenas o primeiro da fila para esta mesa
 			}
@@ -212,7 +216,12 @@ func (w *WaitlistEnhancedHandler) RemoveFromWaitlist(id string) error {
 
 	waitlistItem.Status = "seated"
 	waitlistItem.UpdatedAt = time.Now()
-	return w.repo.Waitlists.UpdateWaitlist(waitlistItem)
+	if err := w.repo.Waitlists.UpdateWaitlist(waitlistItem); err != nil {
+		return err
+	}
+
+	utils.GetRealtimeBus().Publish(waitlistItem.OrganizationId, waitlistItem.ProjectId, utils.RealtimeTopicWaitlist, "waitlist.seated", waitlistItem)
+	return nil
 }
 
 // GetWaitlistByProject - Lista fila de espera do projeto
@@ -9,7 +9,6 @@ import (
 	"lep/utils"
 	"log"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -76,32 +75,8 @@ func setupCORS(r *gin.Engine) {
 		log.Println("CORS: Allowing all origins (development mode)")
 	} else {
 		// Restrictive CORS for production
-		allowedOrigins := map[string]bool{
-			"http://localhost:5173":                                          true,
-			"http://localhost:5174":                                          true,
-			"https://lep-front.vercel.app":                                   true,
-			"https://lep-front-git-main-leps-projects-a55eafc4.vercel.app":   true,
-			"https://lep-front-nw6k.vercel.app":                              true,
-			"https://lep-front-stage.vercel.app":                             true,
-		}
-
 		r.Use(cors.New(cors.Config{
-			AllowOriginFunc: func(origin string) bool {
-				// Remove trailing slash for comparison
-				origin = strings.TrimSuffix(origin, "/")
-
-				// Check fixed origins
-				if allowedOrigins[origin] {
-					return true
-				}
-
-				// Allow any subdomain of lepgo.com.br (e.g., admin.lepgo.com.br, www.lepgo.com.br)
-				if origin == "https://lepgo.com.br" || strings.HasSuffix(origin, ".lepgo.com.br") {
-					return strings.HasPrefix(origin, "https://")
-				}
-
-				return false
-			},
+			AllowOriginFunc:  config.IsAllowedOrigin,
 			AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 			AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Lpe-Organization-Id", "X-Lpe-Project-Id"},
 			ExposeHeaders:    []string{"Content-Length"},
@@ -165,13 +140,9 @@ func setupRoutes(r *gin.Engine) {
 func startServer(r *gin.Engine) {
 	port := fmt.Sprintf(":%s", config.PORT)
 
-	// Configure trusted proxies for Cloud Run
-	if config.IsGCP() {
-		// Cloud Run uses internal proxies - trust all for now
-		r.SetTrustedProxies(nil)
-	} else {
-		// Local development - no trusted proxies
-		r.SetTrustedProxies(nil)
+	// Configure trusted proxies so ClientIP() is the real client (Cloud Run proxy by default, none locally)
+	if err := r.SetTrustedProxies(config.TRUSTED_PROXIES); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
 	}
 
 	log.Printf("🌟 LEP System starting on port %s", config.PORT)
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"lep/handler"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PrintAgentTokenHeader cabeçalho com o token do agente de impressão, gerado em POST /printer/agent/token
+const PrintAgentTokenHeader = "X-Print-Agent-Token"
+
+// PrintAgentMiddleware autentica o agente de impressão da rede local pelo token do projeto.
+// O agente só enxerga a fila de impressão do próprio projeto.
+func PrintAgentMiddleware(printHandler handler.IHandlerPrint) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		agent, err := printHandler.AuthenticateAgent(c.GetHeader(PrintAgentTokenHeader))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token do agente de impressão inválido"})
+			c.Abort()
+			return
+		}
+
+		c.Set("print_agent", agent)
+		c.Set("organization_id", agent.OrganizationId.String())
+		c.Set("project_id", agent.ProjectId.String())
+		c.Next()
+	}
+}
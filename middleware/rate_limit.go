@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitWindow contador de requisições de um cliente na janela atual
+type rateLimitWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimitMiddleware limita requisições por IP e recurso em janela fixa (rotas públicas).
+// A chave inclui os parâmetros concretos da rota (loja, projeto, mesa, token), então cada
+// cliente tem um limite por mesa/loja. Estado mantido em memória: em múltiplas instâncias o limite vale por instância.
+func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	clients := make(map[string]*rateLimitWindow)
+	lastCleanup := time.Now()
+
+	return func(c *gin.Context) {
+		key := c.ClientIP() + "|" + c.FullPath()
+		for _, param := range c.Params {
+			key += "|" + param.Value
+		}
+		now := time.Now()
+
+		mu.Lock()
+		// Remover janelas expiradas periodicamente para não crescer indefinidamente
+		if now.Sub(lastCleanup) > window {
+			for k, w := range clients {
+				if now.Sub(w.start) >= window {
+					delete(clients, k)
+				}
+			}
+			lastCleanup = now
+		}
+
+		entry, exists := clients[key]
+		if !exists || now.Sub(entry.start) >= window {
+			entry = &rateLimitWindow{start: now}
+			clients[key] = entry
+		}
+		entry.count++
+		count := entry.count
+		retryAfter := entry.start.Add(window).Sub(now)
+		mu.Unlock()
+
+		if count > limit {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Too many requests",
+				"message": "Muitas requisições. Tente novamente em instantes.",
+				"code":    "RATE_LIMITED",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
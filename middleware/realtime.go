@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"lep/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RealtimeTicketMiddleware autentica os streams pelo ticket da query (?ticket=), emitido em POST /realtime/ticket.
+// O ticket já carrega usuário, organização e projeto validados na emissão.
+func RealtimeTicketMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.Query("ticket")
+		if raw == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Ticket de conexão necessário"})
+			c.Abort()
+			return
+		}
+
+		ticket, err := utils.ParseRealtimeTicket(raw)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Ticket inválido ou expirado"})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", ticket.UserId)
+		c.Set("user_type", ticket.UserType)
+		c.Set("organization_id", ticket.OrganizationId)
+		c.Set("project_id", ticket.ProjectId)
+		c.Next()
+	}
+}
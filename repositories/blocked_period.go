@@ -42,7 +42,7 @@ func (r *BlockedPeriodRepository) GetBlockedPeriodById(id uuid.UUID) (*models.Bl
 func (r *BlockedPeriodRepository) GetBlockedPeriodsByProject(orgId, projectId uuid.UUID) ([]models.BlockedPeriod, error) {
 	var periods []models.BlockedPeriod
 	err := r.db.Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId).
-		Order("start_datetime ASC").Find(&periods).Error
+		Order("start_date_time ASC").Find(&periods).Error
 	return periods, err
 }
 
@@ -56,23 +56,26 @@ func (r *BlockedPeriodRepository) SoftDeleteBlockedPeriod(id uuid.UUID) error {
 	return r.db.Model(&models.BlockedPeriod{}).Where("id = ?", id).Update("deleted_at", now).Error
 }
 
-// CheckPeriodBlocked - Verifica se um horário específico está bloqueado
+// CheckPeriodBlocked - Verifica se um horário específico está bloqueado para o projeto inteiro
+// (recorrência calculada no fuso do horário informado)
 func (r *BlockedPeriodRepository) CheckPeriodBlocked(orgId, projectId uuid.UUID, datetime time.Time) (bool, error) {
-	var count int64
-	err := r.db.Model(&models.BlockedPeriod{}).
-		Where("organization_id = ? AND project_id = ? AND active = true AND deleted_at IS NULL", orgId, projectId).
-		Where("start_datetime <= ? AND end_datetime >= ?", datetime, datetime).
-		Count(&count).Error
+	periods, err := r.GetActiveBlockedPeriodsInRange(orgId, projectId, datetime, datetime)
+	if err != nil {
+		return false, err
+	}
 
-	return count > 0, err
+	return models.FindBlockingPeriod(periods, datetime, datetime.Location(), nil) != nil, nil
 }
 
-// GetActiveBlockedPeriodsInRange - Busca períodos bloqueados em um intervalo
+// GetActiveBlockedPeriodsInRange - Busca períodos bloqueados que podem ter ocorrência no intervalo:
+// avulsos que se sobrepõem ao intervalo e recorrentes iniciados antes do fim e ainda vigentes
 func (r *BlockedPeriodRepository) GetActiveBlockedPeriodsInRange(orgId, projectId uuid.UUID, start, end time.Time) ([]models.BlockedPeriod, error) {
 	var periods []models.BlockedPeriod
 	err := r.db.Where("organization_id = ? AND project_id = ? AND active = true AND deleted_at IS NULL", orgId, projectId).
-		Where("(start_datetime <= ? AND end_datetime >= ?) OR (start_datetime >= ? AND start_datetime <= ?)",
-			end, start, start, end).
+		Where("start_date_time <= ?", end).
+		Where("(COALESCE(recurring_type, '') IN ('', ?) AND end_date_time >= ?) OR (COALESCE(recurring_type, '') NOT IN ('', ?) AND (recurring_until IS NULL OR recurring_until >= ?))",
+			models.BlockedPeriodRecurringNone, start, models.BlockedPeriodRecurringNone, start.AddDate(0, 0, -1)).
+		Order("start_date_time ASC").
 		Find(&periods).Error
 	return periods, err
-}
\ No newline at end of file
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DeliveryZoneRepository struct {
+	db *gorm.DB
+}
+
+type IDeliveryZoneRepository interface {
+	CreateZone(zone *models.DeliveryZone) error
+	GetZoneById(id uuid.UUID) (*models.DeliveryZone, error)
+	ListZones(orgId, projectId uuid.UUID) ([]models.DeliveryZone, error)
+	UpdateZone(zone *models.DeliveryZone) error
+	SoftDeleteZone(id uuid.UUID) error
+	ListScheduledOrderTimes(orgId, projectId uuid.UUID, from, to time.Time) ([]time.Time, error)
+}
+
+func NewDeliveryZoneRepository(db *gorm.DB) IDeliveryZoneRepository {
+	return &DeliveryZoneRepository{db: db}
+}
+
+// CreateZone cadastra zona de entrega
+func (r *DeliveryZoneRepository) CreateZone(zone *models.DeliveryZone) error {
+	return r.db.Create(zone).Error
+}
+
+// GetZoneById busca zona de entrega por ID
+func (r *DeliveryZoneRepository) GetZoneById(id uuid.UUID) (*models.DeliveryZone, error) {
+	var zone models.DeliveryZone
+	err := r.db.First(&zone, "id = ? AND deleted_at IS NULL", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+
+// ListZones lista zonas de entrega do projeto
+func (r *DeliveryZoneRepository) ListZones(orgId, projectId uuid.UUID) ([]models.DeliveryZone, error) {
+	var zones []models.DeliveryZone
+	err := r.db.Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId).
+		Order("fee ASC, name ASC").
+		Find(&zones).Error
+	return zones, err
+}
+
+// UpdateZone atualiza zona de entrega
+func (r *DeliveryZoneRepository) UpdateZone(zone *models.DeliveryZone) error {
+	return r.db.Save(zone).Error
+}
+
+// SoftDeleteZone exclui zona de entrega
+func (r *DeliveryZoneRepository) SoftDeleteZone(id uuid.UUID) error {
+	return r.db.Model(&models.DeliveryZone{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error
+}
+
+// ListScheduledOrderTimes horários dos pedidos de retirada/entrega agendados no período (sem os cancelados)
+func (r *DeliveryZoneRepository) ListScheduledOrderTimes(orgId, projectId uuid.UUID, from, to time.Time) ([]time.Time, error) {
+	var times []time.Time
+	err := r.db.Model(&models.Order{}).
+		Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId).
+		Where("type IN (?, ?) AND status <> ?", models.OrderTypeTakeout, models.OrderTypeDelivery, models.OrderStatusCancelled).
+		Where("scheduled_for >= ? AND scheduled_for < ?", from, to).
+		Pluck("scheduled_for", &times).Error
+	return times, err
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"errors"
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FiscalRepository struct {
+	db *gorm.DB
+}
+
+type IFiscalRepository interface {
+	NextNumber(orgId uuid.UUID, environment, series, startNumber int) (int, error)
+	CreateDocument(document *models.FiscalDocument) error
+	UpdateDocument(document *models.FiscalDocument) error
+	ReleaseOrders(document *models.FiscalDocument) error
+	GetDocumentById(id uuid.UUID) (*models.FiscalDocument, error)
+	ListDocuments(orgId, projectId uuid.UUID, status, orderId string, limit int) ([]models.FiscalDocument, error)
+}
+
+func NewFiscalRepository(db *gorm.DB) IFiscalRepository {
+	return &FiscalRepository{db: db}
+}
+
+// NextNumber próximo número da série (maior número emitido + 1, respeitando o número inicial configurado)
+func (r *FiscalRepository) NextNumber(orgId uuid.UUID, environment, series, startNumber int) (int, error) {
+	var last int
+	err := r.db.Model(&models.FiscalDocument{}).
+		Where("organization_id = ? AND model = ? AND environment = ? AND series = ?", orgId, models.FiscalModelNfce, environment, series).
+		Select("COALESCE(MAX(number), 0)").Scan(&last).Error
+	if err != nil {
+		return 0, err
+	}
+	if last+1 < startNumber {
+		return startNumber, nil
+	}
+	return last + 1, nil
+}
+
+// CreateDocument grava a nota e vincula os pedidos numa transação.
+// Falha se algum pedido já estiver vinculado a outra nota (emissão simultânea).
+func (r *FiscalRepository) CreateDocument(document *models.FiscalDocument) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(document).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.Order{}).
+			Where("id IN ? AND fiscal_document_id IS NULL", []string(document.OrderIds)).
+			Updates(map[string]interface{}{"fiscal_document_id": document.Id, "updated_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(len(document.OrderIds)) {
+			return errors.New("fiscal_conflict: order already has a fiscal document")
+		}
+		return nil
+	})
+}
+
+// UpdateDocument grava o retorno da SEFAZ
+func (r *FiscalRepository) UpdateDocument(document *models.FiscalDocument) error {
+	document.UpdatedAt = time.Now()
+	return r.db.Save(document).Error
+}
+
+// ReleaseOrders grava a nota rejeitada ou cancelada e desvincula os pedidos para nova emissão
+func (r *FiscalRepository) ReleaseOrders(document *models.FiscalDocument) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		document.UpdatedAt = time.Now()
+		if err := tx.Save(document).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Order{}).Where("fiscal_document_id = ?", document.Id).
+			Updates(map[string]interface{}{"fiscal_document_id": nil, "updated_at": time.Now()}).Error
+	})
+}
+
+// GetDocumentById busca nota por ID
+func (r *FiscalRepository) GetDocumentById(id uuid.UUID) (*models.FiscalDocument, error) {
+	var document models.FiscalDocument
+	err := r.db.First(&document, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// ListDocuments lista notas do projeto sem o XML (mais recentes primeiro), opcionalmente por status ou pedido
+func (r *FiscalRepository) ListDocuments(orgId, projectId uuid.UUID, status, orderId string, limit int) ([]models.FiscalDocument, error) {
+	var documents []models.FiscalDocument
+	query := r.db.Where("organization_id = ? AND project_id = ?", orgId, projectId)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if orderId != "" {
+		query = query.Where("? = ANY(order_ids)", orderId)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Omit("xml").Order("issued_at DESC").Find(&documents).Error
+	return documents, err
+}
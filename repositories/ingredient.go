@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type IngredientRepository struct {
+	db *gorm.DB
+}
+
+type IIngredientRepository interface {
+	CreateIngredient(ingredient *models.Ingredient) error
+	GetIngredientById(id uuid.UUID) (*models.Ingredient, error)
+	GetIngredientsByIds(ids []uuid.UUID) ([]models.Ingredient, error)
+	ListIngredients(orgId, projectId uuid.UUID) ([]models.Ingredient, error)
+	UpdateIngredient(ingredient *models.Ingredient) error
+	SoftDeleteIngredient(id uuid.UUID) error
+	GetRecipe(productId uuid.UUID) ([]models.RecipeItem, error)
+	ListRecipes(orgId, projectId uuid.UUID) ([]models.RecipeItem, error)
+	ReplaceRecipe(productId uuid.UUID, items []models.RecipeItem) error
+	ListStartedOrders(orgId, projectId uuid.UUID, from, to time.Time) ([]models.Order, error)
+	CreateCount(count *models.InventoryCount) error
+	GetCountById(id uuid.UUID) (*models.InventoryCount, error)
+	ListCounts(orgId, projectId uuid.UUID, limit int) ([]models.InventoryCount, error)
+}
+
+func NewIngredientRepository(db *gorm.DB) IIngredientRepository {
+	return &IngredientRepository{db: db}
+}
+
+// CreateIngredient cria ingrediente
+func (r *IngredientRepository) CreateIngredient(ingredient *models.Ingredient) error {
+	return r.db.Create(ingredient).Error
+}
+
+// GetIngredientById busca ingrediente por ID
+func (r *IngredientRepository) GetIngredientById(id uuid.UUID) (*models.Ingredient, error) {
+	var ingredient models.Ingredient
+	err := r.db.First(&ingredient, "id = ? AND deleted_at IS NULL", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ingredient, nil
+}
+
+// GetIngredientsByIds busca ingredientes (não excluídos) por IDs
+func (r *IngredientRepository) GetIngredientsByIds(ids []uuid.UUID) ([]models.Ingredient, error) {
+	var ingredients []models.Ingredient
+	if len(ids) == 0 {
+		return ingredients, nil
+	}
+	err := r.db.Where("id IN ? AND deleted_at IS NULL", ids).Find(&ingredients).Error
+	return ingredients, err
+}
+
+// ListIngredients lista ingredientes do projeto
+func (r *IngredientRepository) ListIngredients(orgId, projectId uuid.UUID) ([]models.Ingredient, error) {
+	var ingredients []models.Ingredient
+	err := r.db.Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId).
+		Order("name ASC").
+		Find(&ingredients).Error
+	return ingredients, err
+}
+
+// UpdateIngredient atualiza cadastro do ingrediente (estoque e custo só mudam por recebimento ou contagem)
+func (r *IngredientRepository) UpdateIngredient(ingredient *models.Ingredient) error {
+	return r.db.Model(&models.Ingredient{}).Where("id = ?", ingredient.Id).
+		Updates(map[string]interface{}{
+			"name":       ingredient.Name,
+			"unit":       ingredient.Unit,
+			"min_stock":  ingredient.MinStock,
+			"active":     ingredient.Active,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// SoftDeleteIngredient exclui ingrediente e remove das fichas técnicas
+func (r *IngredientRepository) SoftDeleteIngredient(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Ingredient{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Where("ingredient_id = ?", id).Delete(&models.RecipeItem{}).Error
+	})
+}
+
+// GetRecipe busca a ficha técnica do produto
+func (r *IngredientRepository) GetRecipe(productId uuid.UUID) ([]models.RecipeItem, error) {
+	var items []models.RecipeItem
+	err := r.db.Where("product_id = ?", productId).Order("created_at ASC").Find(&items).Error
+	return items, err
+}
+
+// ListRecipes lista todas as fichas técnicas do projeto
+func (r *IngredientRepository) ListRecipes(orgId, projectId uuid.UUID) ([]models.RecipeItem, error) {
+	var items []models.RecipeItem
+	err := r.db.Where("organization_id = ? AND project_id = ?", orgId, projectId).Find(&items).Error
+	return items, err
+}
+
+// ReplaceRecipe substitui a ficha técnica do produto
+func (r *IngredientRepository) ReplaceRecipe(productId uuid.UUID, items []models.RecipeItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productId).Delete(&models.RecipeItem{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.Create(&items).Error
+	})
+}
+
+// ListStartedOrders lista pedidos que entraram em preparo no período e não foram cancelados
+func (r *IngredientRepository) ListStartedOrders(orgId, projectId uuid.UUID, from, to time.Time) ([]models.Order, error) {
+	var orders []models.Order
+	err := r.db.Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId).
+		Where("status IN ?", []string{models.OrderStatusPreparing, models.OrderStatusReady, models.OrderStatusDelivered}).
+		Where("started_at >= ? AND started_at < ?", from, to).
+		Find(&orders).Error
+	return orders, err
+}
+
+// CreateCount grava a contagem e redefine o estoque dos ingredientes contados
+func (r *IngredientRepository) CreateCount(count *models.InventoryCount) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(count).Error; err != nil {
+			return err
+		}
+		for _, item := range count.Items {
+			if err := tx.Model(&models.Ingredient{}).Where("id = ?", item.IngredientId).
+				Updates(map[string]interface{}{
+					"stock":           item.Counted,
+					"last_counted_at": count.CountedAt,
+					"updated_at":      time.Now(),
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetCountById busca contagem com suas linhas
+func (r *IngredientRepository) GetCountById(id uuid.UUID) (*models.InventoryCount, error) {
+	var count models.InventoryCount
+	err := r.db.Preload("Items").First(&count, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+// ListCounts lista contagens do projeto, mais recentes primeiro
+func (r *IngredientRepository) ListCounts(orgId, projectId uuid.UUID, limit int) ([]models.InventoryCount, error) {
+	var counts []models.InventoryCount
+	query := r.db.Preload("Items").Where("organization_id = ? AND project_id = ?", orgId, projectId)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Order("counted_at DESC").Find(&counts).Error
+	return counts, err
+}
@@ -12,6 +12,10 @@ type DBconn struct {
 	Customers           ICustomersRepository
 	LoggedLists         ILoggedListsRepository
 	Orders              IOrderRepository
+	OrderStatusHistory  IOrderStatusHistoryRepository
+	Stock               IStockRepository
+	Ingredients         IIngredientRepository
+	Suppliers           ISupplierRepository
 	Organizations       IOrganizationRepository
 	Products            IProductRepository
 	Reservations        IReservationRepository
@@ -21,11 +25,25 @@ type DBconn struct {
 	Clients             IClientRepository
 	Waitlists           WaitlistRepositoryInterface
 	KitchenQueue        IKitchenQueueRepository
+	KitchenStations     IKitchenStationRepository
+	Tabs                ITabRepository
+	PixCharges          IPixChargeRepository
+	Printers            IPrinterRepository
+	DeliveryZones       IDeliveryZoneRepository
+	Marketplace         IMarketplaceRepository
+	PosSync             IPosSyncRepository
+	Fiscal              IFiscalRepository
+	ServiceRequests     IServiceRequestRepository
+	PrepTimes           IPrepTimeRepository
+	SLA                 ISLARepository
 	Projects            IProjectRepository
 	Settings            ISettingsRepository
 	DisplaySettings     IDisplaySettingsRepository
 	ThemeCustomization  IThemeCustomizationRepository
 	Environments        IEnvironmentRepository
+	BlockedPeriods      IBlockedPeriodRepository
+	TableCombinations   ITableCombinationRepository
+	PacingRules         IPacingRuleRepository
 	Notifications       INotificationRepository
 	Tags                ITagRepository
 	Menus               IMenuRepository
@@ -61,17 +79,35 @@ func (r *DBconn) InjectPostgres(db *gorm.DB) {
 	r.Products = NewConnProduct(db)
 	r.Customers = NewConnCustomer(db)
 	r.Orders = NewConnOrder(db)
+	r.OrderStatusHistory = NewOrderStatusHistoryRepository(db)
+	r.Stock = NewStockRepository(db)
+	r.Ingredients = NewIngredientRepository(db)
+	r.Suppliers = NewSupplierRepository(db)
 	r.Tables = NewConnTable(db)
 	r.AuditLogs = NewConnAuditLog(db)
 	r.AccessLogs = NewAccessLogRepository(db)
 	r.Reservations = NewConnReservation(db)
 	r.Waitlists = NewWaitlistRepository(db)
 	r.KitchenQueue = NewKitchenQueueRepository(db)
+	r.KitchenStations = NewKitchenStationRepository(db)
+	r.Tabs = NewTabRepository(db)
+	r.PixCharges = NewPixChargeRepository(db)
+	r.Printers = NewPrinterRepository(db)
+	r.DeliveryZones = NewDeliveryZoneRepository(db)
+	r.Marketplace = NewMarketplaceRepository(db)
+	r.PosSync = NewPosSyncRepository(db)
+	r.Fiscal = NewFiscalRepository(db)
+	r.ServiceRequests = NewServiceRequestRepository(db)
+	r.PrepTimes = NewPrepTimeRepository(db)
+	r.SLA = NewSLARepository(db)
 	r.Projects = NewProjectRepository(db)
 	r.Settings = NewSettingsRepository(db)
 	r.DisplaySettings = NewDisplaySettingsRepository(db)
 	r.ThemeCustomization = NewThemeCustomizationRepository(db)
 	r.Environments = NewEnvironmentRepository(db)
+	r.BlockedPeriods = NewBlockedPeriodRepository(db)
+	r.TableCombinations = NewTableCombinationRepository(db)
+	r.PacingRules = NewPacingRuleRepository(db)
 	r.Notifications = NewNotificationRepository(db)
 	r.Tags = NewConnTag(db)
 	r.Menus = NewConnMenu(db)
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"fmt"
 	"lep/repositories/models"
 
 	"github.com/google/uuid"
@@ -17,6 +18,7 @@ type IKitchenQueueRepository interface {
 	GetKitchenQueue(orgId, projectId uuid.UUID) ([]models.Order, error)
 	UpdateOrderStatus(orderId uuid.UUID, status string) error
 	GetOrdersInPreparation(orgId, projectId uuid.UUID) ([]models.Order, error)
+	GetStationQueue(orgId, projectId, stationId uuid.UUID) ([]models.Order, error)
 }
 
 func NewKitchenQueueRepository(db *gorm.DB) IKitchenQueueRepository {
@@ -51,11 +53,13 @@ func (r *KitchenQueueRepository) GetOrdersByStatus(orgId, projectId uuid.UUID, s
 func (r *KitchenQueueRepository) GetKitchenQueue(orgId, projectId uuid.UUID) ([]models.Order, error) {
 	var orders []models.Order
 
-	// Ordena por: pedidos preparando primeiro, depois por tempo de criação
+	// Ordena por: pedidos com itens a fazer antes dos que só aguardam o disparo de um tempo,
+	// pedidos preparando primeiro, depois por tempo de criação (retirada/entrega agendada pelo horário marcado)
 	err := r.db.Where(
 		"organization_id = ? AND project_id = ? AND status IN (?, ?) AND deleted_at IS NULL",
 		orgId, projectId, "pending", "preparing",
-	).Order("CASE WHEN status = 'preparing' THEN 0 ELSE 1 END, created_at ASC").Find(&orders).Error
+	).Order(`CASE WHEN EXISTS (SELECT 1 FROM jsonb_array_elements(items) AS item WHERE item->>'status' IN ('queued', 'preparing')) THEN 0 ELSE 1 END,
+		CASE WHEN status = 'preparing' THEN 0 ELSE 1 END, COALESCE(scheduled_for, created_at) ASC`).Find(&orders).Error
 
 	return orders, err
 }
@@ -75,4 +79,18 @@ func (r *KitchenQueueRepository) GetOrdersInPreparation(orgId, projectId uuid.UU
 	).Find(&orders).Error
 
 	return orders, err
-}
\ No newline at end of file
+}
+
+// GetStationQueue retorna os pedidos ativos que possuem ao menos um item roteado para a estação
+// (retirada/entrega agendada entra na ordem pelo horário marcado)
+func (r *KitchenQueueRepository) GetStationQueue(orgId, projectId, stationId uuid.UUID) ([]models.Order, error) {
+	var orders []models.Order
+
+	err := r.db.Where(
+		"organization_id = ? AND project_id = ? AND status IN (?, ?) AND deleted_at IS NULL",
+		orgId, projectId, "pending", "preparing",
+	).Where("items @> ?::jsonb", fmt.Sprintf(`[{"station_id":"%s"}]`, stationId.String())).
+		Order("COALESCE(scheduled_for, created_at) ASC").Find(&orders).Error
+
+	return orders, err
+}
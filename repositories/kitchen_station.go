@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type KitchenStationRepository struct {
+	db *gorm.DB
+}
+
+type IKitchenStationRepository interface {
+	GetStationById(id uuid.UUID) (*models.KitchenStation, error)
+	ListStations(orgId, projectId uuid.UUID) ([]models.KitchenStation, error)
+	ListActiveStations(orgId, projectId uuid.UUID) ([]models.KitchenStation, error)
+	CheckStationCodeExists(orgId, projectId uuid.UUID, code string, excludeId *uuid.UUID) (bool, error)
+	CreateStation(station *models.KitchenStation) error
+	UpdateStation(station *models.KitchenStation) error
+	SoftDeleteStation(id uuid.UUID) error
+}
+
+func NewKitchenStationRepository(db *gorm.DB) IKitchenStationRepository {
+	return &KitchenStationRepository{db: db}
+}
+
+// GetStationById busca estação por ID
+func (r *KitchenStationRepository) GetStationById(id uuid.UUID) (*models.KitchenStation, error) {
+	var station models.KitchenStation
+	err := r.db.First(&station, "id = ? AND deleted_at IS NULL", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &station, nil
+}
+
+// ListStations lista as estações do projeto
+func (r *KitchenStationRepository) ListStations(orgId, projectId uuid.UUID) ([]models.KitchenStation, error) {
+	var stations []models.KitchenStation
+	err := r.db.Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId).
+		Order(`"order" ASC, name ASC`).Find(&stations).Error
+	return stations, err
+}
+
+// ListActiveStations lista apenas as estações ativas (usadas no roteamento)
+func (r *KitchenStationRepository) ListActiveStations(orgId, projectId uuid.UUID) ([]models.KitchenStation, error) {
+	var stations []models.KitchenStation
+	err := r.db.Where("organization_id = ? AND project_id = ? AND active = true AND deleted_at IS NULL", orgId, projectId).
+		Order(`"order" ASC, name ASC`).Find(&stations).Error
+	return stations, err
+}
+
+// CheckStationCodeExists verifica se já existe estação com o mesmo código no projeto
+func (r *KitchenStationRepository) CheckStationCodeExists(orgId, projectId uuid.UUID, code string, excludeId *uuid.UUID) (bool, error) {
+	var count int64
+	query := r.db.Model(&models.KitchenStation{}).
+		Where("organization_id = ? AND project_id = ? AND LOWER(code) = LOWER(?) AND deleted_at IS NULL", orgId, projectId, code)
+
+	if excludeId != nil {
+		query = query.Where("id != ?", *excludeId)
+	}
+
+	err := query.Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateStation cria nova estação
+func (r *KitchenStationRepository) CreateStation(station *models.KitchenStation) error {
+	return r.db.Create(station).Error
+}
+
+// UpdateStation atualiza estação existente
+func (r *KitchenStationRepository) UpdateStation(station *models.KitchenStation) error {
+	station.UpdatedAt = time.Now()
+	return r.db.Save(station).Error
+}
+
+// SoftDeleteStation remove estação logicamente
+func (r *KitchenStationRepository) SoftDeleteStation(id uuid.UUID) error {
+	return r.db.Model(&models.KitchenStation{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error
+}
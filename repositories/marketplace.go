@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MarketplaceRepository struct {
+	db *gorm.DB
+}
+
+type IMarketplaceRepository interface {
+	CreateIntegration(integration *models.MarketplaceIntegration) error
+	GetIntegrationById(id uuid.UUID) (*models.MarketplaceIntegration, error)
+	GetIntegrationByMerchant(marketplace, merchantId string) (*models.MarketplaceIntegration, error)
+	ListIntegrations(orgId, projectId uuid.UUID) ([]models.MarketplaceIntegration, error)
+	UpdateIntegration(integration *models.MarketplaceIntegration) error
+	SoftDeleteIntegration(id uuid.UUID) error
+	CreateEventIfNew(event *models.MarketplaceEvent) (bool, error)
+	GetEventById(id uuid.UUID) (*models.MarketplaceEvent, error)
+	GetEvent(marketplace, eventId string) (*models.MarketplaceEvent, error)
+	ListEvents(orgId, projectId uuid.UUID, direction, status string, limit int) ([]models.MarketplaceEvent, error)
+	GetInboundEvent(orderId uuid.UUID, eventType string) (*models.MarketplaceEvent, error)
+	ClaimFailedEvent(id uuid.UUID, now time.Time) (bool, error)
+	ListDueOutbound(now time.Time, stuckBefore time.Time, limit int) ([]models.MarketplaceEvent, error)
+	ClaimOutbound(id uuid.UUID, now time.Time, stuckBefore time.Time) (bool, error)
+	UpdateEvent(event *models.MarketplaceEvent) error
+	GetOrderByExternalId(orgId, projectId uuid.UUID, source, externalOrderId string) (*models.Order, error)
+}
+
+func NewMarketplaceRepository(db *gorm.DB) IMarketplaceRepository {
+	return &MarketplaceRepository{db: db}
+}
+
+// CreateIntegration cadastra integração com marketplace
+func (r *MarketplaceRepository) CreateIntegration(integration *models.MarketplaceIntegration) error {
+	return r.db.Create(integration).Error
+}
+
+// GetIntegrationById busca integração por ID
+func (r *MarketplaceRepository) GetIntegrationById(id uuid.UUID) (*models.MarketplaceIntegration, error) {
+	var integration models.MarketplaceIntegration
+	err := r.db.First(&integration, "id = ? AND deleted_at IS NULL", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &integration, nil
+}
+
+// GetIntegrationByMerchant busca a integração ativa pela loja no marketplace
+func (r *MarketplaceRepository) GetIntegrationByMerchant(marketplace, merchantId string) (*models.MarketplaceIntegration, error) {
+	var integration models.MarketplaceIntegration
+	err := r.db.Where("marketplace = ? AND merchant_id = ? AND active = ? AND deleted_at IS NULL", marketplace, merchantId, true).
+		First(&integration).Error
+	if err != nil {
+		return nil, err
+	}
+	return &integration, nil
+}
+
+// ListIntegrations lista integrações do projeto
+func (r *MarketplaceRepository) ListIntegrations(orgId, projectId uuid.UUID) ([]models.MarketplaceIntegration, error) {
+	var integrations []models.MarketplaceIntegration
+	err := r.db.Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId).
+		Order("marketplace ASC, merchant_id ASC").
+		Find(&integrations).Error
+	return integrations, err
+}
+
+// UpdateIntegration atualiza integração
+func (r *MarketplaceRepository) UpdateIntegration(integration *models.MarketplaceIntegration) error {
+	return r.db.Save(integration).Error
+}
+
+// SoftDeleteIntegration exclui integração
+func (r *MarketplaceRepository) SoftDeleteIntegration(id uuid.UUID) error {
+	return r.db.Model(&models.MarketplaceIntegration{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error
+}
+
+// CreateEventIfNew grava o evento se o par marketplace + event_id ainda não existe.
+// Retorna false quando o evento já foi recebido (webhook duplicado), mesmo com requisições simultâneas.
+func (r *MarketplaceRepository) CreateEventIfNew(event *models.MarketplaceEvent) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "marketplace"}, {Name: "event_id"}},
+		DoNothing: true,
+	}).Create(event)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetEventById busca evento por ID
+func (r *MarketplaceRepository) GetEventById(id uuid.UUID) (*models.MarketplaceEvent, error) {
+	var event models.MarketplaceEvent
+	err := r.db.First(&event, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// GetEvent busca evento pelo id no marketplace
+func (r *MarketplaceRepository) GetEvent(marketplace, eventId string) (*models.MarketplaceEvent, error) {
+	var event models.MarketplaceEvent
+	err := r.db.First(&event, "marketplace = ? AND event_id = ?", marketplace, eventId).Error
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ListEvents lista eventos do projeto, mais recentes primeiro
+func (r *MarketplaceRepository) ListEvents(orgId, projectId uuid.UUID, direction, status string, limit int) ([]models.MarketplaceEvent, error) {
+	var events []models.MarketplaceEvent
+	query := r.db.Where("organization_id = ? AND project_id = ?", orgId, projectId)
+	if direction != "" {
+		query = query.Where("direction = ?", direction)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Order("created_at DESC").Find(&events).Error
+	return events, err
+}
+
+// GetInboundEvent busca o evento do tipo informado recebido do marketplace para o pedido (nil se não houver)
+func (r *MarketplaceRepository) GetInboundEvent(orderId uuid.UUID, eventType string) (*models.MarketplaceEvent, error) {
+	var events []models.MarketplaceEvent
+	err := r.db.Where("order_id = ? AND direction = ? AND type = ?", orderId, models.MarketplaceDirectionInbound, eventType).
+		Order("created_at ASC").Limit(1).Find(&events).Error
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+	return &events[0], nil
+}
+
+// ClaimFailedEvent marca evento recebido com falha como "processing" para reprocessar.
+// Retorna false quando o evento não está mais com falha (outro processo já o pegou).
+func (r *MarketplaceRepository) ClaimFailedEvent(id uuid.UUID, now time.Time) (bool, error) {
+	result := r.db.Model(&models.MarketplaceEvent{}).
+		Where("id = ? AND status = ?", id, models.MarketplaceEventStatusFailed).
+		Updates(map[string]interface{}{"status": models.MarketplaceEventStatusProcessing, "updated_at": now})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListDueOutbound lista status a enviar cuja tentativa chegou (inclui os presos em "processing" desde stuckBefore)
+func (r *MarketplaceRepository) ListDueOutbound(now time.Time, stuckBefore time.Time, limit int) ([]models.MarketplaceEvent, error) {
+	var events []models.MarketplaceEvent
+	err := r.db.Where("direction = ?", models.MarketplaceDirectionOutbound).
+		Where("(status = ? AND next_attempt_at <= ?) OR (status = ? AND updated_at < ?)",
+			models.MarketplaceEventStatusPending, now, models.MarketplaceEventStatusProcessing, stuckBefore).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// ClaimOutbound marca o envio como "processing" se ainda estiver disponível.
+// Retorna false quando outro processo já o pegou.
+func (r *MarketplaceRepository) ClaimOutbound(id uuid.UUID, now time.Time, stuckBefore time.Time) (bool, error) {
+	result := r.db.Model(&models.MarketplaceEvent{}).
+		Where("id = ?", id).
+		Where("(status = ? AND next_attempt_at <= ?) OR (status = ? AND updated_at < ?)",
+			models.MarketplaceEventStatusPending, now, models.MarketplaceEventStatusProcessing, stuckBefore).
+		Updates(map[string]interface{}{"status": models.MarketplaceEventStatusProcessing, "updated_at": now})
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdateEvent grava o resultado do processamento
+func (r *MarketplaceRepository) UpdateEvent(event *models.MarketplaceEvent) error {
+	return r.db.Save(event).Error
+}
+
+// GetOrderByExternalId busca o pedido criado a partir do pedido do marketplace (nil se não houver)
+func (r *MarketplaceRepository) GetOrderByExternalId(orgId, projectId uuid.UUID, source, externalOrderId string) (*models.Order, error) {
+	var orders []models.Order
+	err := r.db.Where("organization_id = ? AND project_id = ? AND source = ? AND external_order_id = ? AND deleted_at IS NULL",
+		orgId, projectId, source, externalOrderId).
+		Limit(1).Find(&orders).Error
+	if err != nil || len(orders) == 0 {
+		return nil, err
+	}
+	return &orders[0], nil
+}
@@ -106,6 +106,7 @@ func (r *MenuRepository) GetMenuOptions(organizationId, projectId uuid.UUID) ([]
 // 1. Se houver manual_override, retorna esse
 // 2. Se houver cardápio com horário ativo, retorna o de maior prioridade
 // 3. Caso contrário, retorna o de maior prioridade geral
+// currentTime deve estar no fuso do projeto: os ranges são comparados com o horário local.
 func (r *MenuRepository) GetActiveMenuByTimeRange(organizationId, projectId uuid.UUID, currentTime time.Time) (*models.Menu, error) {
 	// 1. Verificar se há manual override ativo
 	var menuOverride models.Menu
@@ -124,19 +125,20 @@ func (r *MenuRepository) GetActiveMenuByTimeRange(organizationId, projectId uuid
 		return nil, err
 	}
 
-	// 2. Buscar cardápios com horário ativo agora
-	currentTimeStr := currentTime.Format("15:04") // HH:MM
+	// 2. Buscar cardápios com horário ativo agora (comparado em Go, no relógio local do projeto)
 	var menusInTimeRange []models.Menu
 
-	// Query: verificar se hora atual está dentro do range de horário
 	query := r.db.
 		Where("organization_id = ? AND project_id = ? AND active = ? AND deleted_at IS NULL", organizationId, projectId, true).
 		Where("time_range_start IS NOT NULL AND time_range_end IS NOT NULL").
-		Where("TO_CHAR(time_range_start, 'HH24:MI') <= ? AND TO_CHAR(time_range_end, 'HH24:MI') >= ?", currentTimeStr, currentTimeStr).
 		Order(`"priority" ASC, "order" ASC`)
 
-	if err := query.Find(&menusInTimeRange).Error; err == nil && len(menusInTimeRange) > 0 {
-		return &menusInTimeRange[0], nil
+	if err := query.Find(&menusInTimeRange).Error; err == nil {
+		for i := range menusInTimeRange {
+			if menuTimeRangeActive(menusInTimeRange[i], currentTime) {
+				return &menusInTimeRange[i], nil
+			}
+		}
 	}
 
 	// 3. Fallback: retorna cardápio com maior prioridade
@@ -153,6 +155,25 @@ func (r *MenuRepository) GetActiveMenuByTimeRange(organizationId, projectId uuid
 	return &menuDefault, nil
 }
 
+// menuTimeRangeActive indica se o horário local de now cai no range do cardápio.
+// O range guarda só a hora do dia: é lido com o offset atual do fuso de now (a data gravada é ignorada)
+// e pode virar a meia-noite (ex: 22:00-02:00).
+func menuTimeRangeActive(menu models.Menu, now time.Time) bool {
+	if menu.TimeRangeStart == nil || menu.TimeRangeEnd == nil {
+		return false
+	}
+	_, offset := now.Zone()
+	clockTime := func(t time.Time) string {
+		return t.UTC().Add(time.Duration(offset) * time.Second).Format("15:04")
+	}
+	current := now.Format("15:04")
+	start, end := clockTime(*menu.TimeRangeStart), clockTime(*menu.TimeRangeEnd)
+	if start <= end {
+		return start <= current && current <= end
+	}
+	return current >= start || current <= end
+}
+
 // ✨ GetMenuWithHighestPriority retorna o cardápio com maior prioridade
 func (r *MenuRepository) GetMenuWithHighestPriority(organizationId, projectId uuid.UUID) (*models.Menu, error) {
 	var menu models.Menu
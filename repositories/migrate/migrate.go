@@ -2,6 +2,7 @@ package migrate
 
 import (
 	"fmt"
+	"log"
 
 	"gorm.io/gorm"
 )
@@ -12,6 +13,7 @@ type resourceMigrate struct {
 
 type IMigrate interface {
 	MigrateRun(modelsToMigrate ...interface{})
+	BackfillReservationStartsAt()
 }
 
 func (r *resourceMigrate) MigrateRun(modelsToMigrate ...interface{}) {
@@ -31,6 +33,26 @@ func (r *resourceMigrate) MigrateRun(modelsToMigrate ...interface{}) {
 	}
 }
 
+// BackfillReservationStartsAt preenche starts_at das reservas antigas a partir do datetime.
+// Datetime sem offset é horário local no fuso do projeto.
+func (r *resourceMigrate) BackfillReservationStartsAt() {
+	result := r.db.Exec(`
+		UPDATE reservations AS r SET starts_at = CASE
+			WHEN r.datetime ~ '(Z|[+-][0-9]{2}:?[0-9]{2})$' THEN r.datetime::timestamptz
+			ELSE r.datetime::timestamp AT TIME ZONE COALESCE(NULLIF(p.time_zone, ''), 'America/Sao_Paulo')
+		END
+		FROM projects AS p
+		WHERE p.id = r.project_id AND r.starts_at IS NULL
+			AND r.datetime ~ '^[0-9]{4}-[0-9]{2}-[0-9]{2}[T ][0-9]{2}:[0-9]{2}'`)
+	if result.Error != nil {
+		log.Printf("Error backfilling reservations.starts_at: %v", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Backfilled starts_at for %d reservations", result.RowsAffected)
+	}
+}
+
 func NewConnMigrate(db *gorm.DB) IMigrate {
 	return &resourceMigrate{db: db}
 }
@@ -4,22 +4,171 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // --- SPRINT 4: Validações Avançadas ---
 
-// BlockedPeriod - Períodos bloqueados para reservas
+// Recorrência dos períodos bloqueados
+const (
+	BlockedPeriodRecurringNone    = "none"
+	BlockedPeriodRecurringDaily   = "daily"
+	BlockedPeriodRecurringWeekly  = "weekly"
+	BlockedPeriodRecurringMonthly = "monthly"
+	BlockedPeriodRecurringYearly  = "yearly"
+)
+
+// BlockedPeriod - Períodos bloqueados para reservas.
+// StartDateTime/EndDateTime definem a primeira ocorrência; as demais repetem o mesmo horário e duração
+// conforme RecurringType a cada RecurringInterval, até RecurringUntil, exceto nas ExceptionDates.
+// Sem ambiente nem mesas o bloqueio vale para o projeto inteiro.
 type BlockedPeriod struct {
-	Id             uuid.UUID  `gorm:"primaryKey;autoIncrement" json:"id"`
-	OrganizationId uuid.UUID  `json:"organization_id"`
-	ProjectId      uuid.UUID  `json:"project_id"`
-	Name           string     `json:"name"` // ex: "Manutenção", "Evento Privado"
-	Description    string     `json:"description,omitempty"`
-	StartDateTime  time.Time  `json:"start_datetime"`
-	EndDateTime    time.Time  `json:"end_datetime"`
-	RecurringType  string     `json:"recurring_type,omitempty"` // "none", "weekly", "monthly"
-	Active         bool       `json:"active" gorm:"default:true"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
-	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+	Id                uuid.UUID      `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrganizationId    uuid.UUID      `json:"organization_id"`
+	ProjectId         uuid.UUID      `json:"project_id"`
+	Name              string         `json:"name"` // ex: "Manutenção", "Evento Privado"
+	Description       string         `json:"description,omitempty"`
+	StartDateTime     time.Time      `json:"start_datetime"`
+	EndDateTime       time.Time      `json:"end_datetime"`
+	RecurringType     string         `json:"recurring_type,omitempty"`                           // "none", "daily", "weekly", "monthly", "yearly"
+	RecurringInterval int            `json:"recurring_interval,omitempty" gorm:"default:1"`      // a cada N dias/semanas/meses/anos
+	RecurringWeekdays pq.Int64Array  `json:"recurring_weekdays,omitempty" gorm:"type:integer[]"` // weekly: 0=domingo ... 6=sábado (vazio = dia do início)
+	RecurringUntil    *time.Time     `json:"recurring_until,omitempty"`                          // última data com ocorrência
+	ExceptionDates    pq.StringArray `json:"exception_dates,omitempty" gorm:"type:text[]"`       // datas (YYYY-MM-DD) em que a ocorrência não acontece
+	EnvironmentId     *uuid.UUID     `json:"environment_id,omitempty"`                           // bloqueia apenas as mesas do ambiente
+	TableIds          pq.StringArray `json:"table_ids,omitempty" gorm:"type:text[]"`             // bloqueia apenas estas mesas
+	Active            bool           `json:"active" gorm:"default:true"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         *time.Time     `json:"deleted_at,omitempty"`
+}
+
+// Scoped indica bloqueio restrito a um ambiente ou a mesas específicas
+func (p *BlockedPeriod) Scoped() bool {
+	return p.EnvironmentId != nil || len(p.TableIds) > 0
+}
+
+// AppliesToTable indica se o bloqueio atinge a mesa (bloqueios sem escopo atingem todas)
+func (p *BlockedPeriod) AppliesToTable(table *Table) bool {
+	if !p.Scoped() {
+		return true
+	}
+	if p.EnvironmentId != nil && table.EnvironmentId != nil && *p.EnvironmentId == *table.EnvironmentId {
+		return true
+	}
+	for _, id := range p.TableIds {
+		if id == table.Id.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers indica se o horário cai em alguma ocorrência do período (início incluso, fim excluído).
+// Datas e dias da semana da recorrência são calculados no relógio de loc.
+func (p *BlockedPeriod) Covers(t time.Time, loc *time.Location) bool {
+	if !p.Active || p.DeletedAt != nil {
+		return false
+	}
+	duration := p.EndDateTime.Sub(p.StartDateTime)
+	if duration <= 0 || t.Before(p.StartDateTime) {
+		return false
+	}
+	if p.RecurringType == "" || p.RecurringType == BlockedPeriodRecurringNone {
+		return t.Before(p.EndDateTime)
+	}
+
+	// Ocorrências que podem conter t começam entre t-duração e t
+	start := p.StartDateTime.In(loc)
+	t = t.In(loc)
+	last := blockedPeriodDate(t)
+	for day := blockedPeriodDate(t.Add(-duration)); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if !p.occursOn(day, start, loc) {
+			continue
+		}
+		occurrence := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), start.Second(), 0, loc)
+		if !occurrence.After(t) && t.Before(occurrence.Add(duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+// occursOn indica se uma ocorrência começa no dia (data civil em UTC) pela regra de recorrência
+func (p *BlockedPeriod) occursOn(day, start time.Time, loc *time.Location) bool {
+	first := blockedPeriodDate(start)
+	if day.Before(first) {
+		return false
+	}
+	if p.RecurringUntil != nil && day.After(blockedPeriodDate(p.RecurringUntil.In(loc))) {
+		return false
+	}
+	for _, exception := range p.ExceptionDates {
+		if exception == day.Format("2006-01-02") {
+			return false
+		}
+	}
+
+	interval := p.RecurringInterval
+	if interval < 1 {
+		interval = 1
+	}
+	switch p.RecurringType {
+	case BlockedPeriodRecurringDaily:
+		return blockedPeriodDays(first, day)%interval == 0
+	case BlockedPeriodRecurringWeekly:
+		if !p.onWeekday(day.Weekday(), first.Weekday()) {
+			return false
+		}
+		// Semanas contadas de domingo a sábado a partir da semana do início
+		firstWeek := first.AddDate(0, 0, -int(first.Weekday()))
+		week := day.AddDate(0, 0, -int(day.Weekday()))
+		return (blockedPeriodDays(firstWeek, week)/7)%interval == 0
+	case BlockedPeriodRecurringMonthly:
+		// Meses sem o dia do início (ex.: 31) não têm ocorrência
+		months := (day.Year()-first.Year())*12 + int(day.Month()) - int(first.Month())
+		return day.Day() == first.Day() && months%interval == 0
+	case BlockedPeriodRecurringYearly:
+		return day.Month() == first.Month() && day.Day() == first.Day() && (day.Year()-first.Year())%interval == 0
+	}
+	return false
+}
+
+func (p *BlockedPeriod) onWeekday(weekday, startWeekday time.Weekday) bool {
+	if len(p.RecurringWeekdays) == 0 {
+		return weekday == startWeekday
+	}
+	for _, w := range p.RecurringWeekdays {
+		if time.Weekday(w) == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// FindBlockingPeriod primeiro período que bloqueia o horário.
+// Com mesa considera também os bloqueios do ambiente/mesas; sem mesa apenas os do projeto inteiro.
+func FindBlockingPeriod(periods []BlockedPeriod, t time.Time, loc *time.Location, table *Table) *BlockedPeriod {
+	for i := range periods {
+		period := &periods[i]
+		if table == nil && period.Scoped() {
+			continue
+		}
+		if table != nil && !period.AppliesToTable(table) {
+			continue
+		}
+		if period.Covers(t, loc) {
+			return period
+		}
+	}
+	return nil
+}
+
+// blockedPeriodDate data civil do horário (meia-noite UTC, para contar dias sem horário de verão)
+func blockedPeriodDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func blockedPeriodDays(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
 }
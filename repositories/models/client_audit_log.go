@@ -56,10 +56,14 @@ func (ClientAuditConfig) TableName() string {
 
 // Constantes para os tipos de ação de cliente
 const (
-	ClientAuditActionCreate       = "CREATE"
-	ClientAuditActionUpdate       = "UPDATE"
-	ClientAuditActionDelete       = "DELETE"
-	ClientAuditActionStatusChange = "STATUS_CHANGE"
+	ClientAuditActionCreate         = "CREATE"
+	ClientAuditActionUpdate         = "UPDATE"
+	ClientAuditActionDelete         = "DELETE"
+	ClientAuditActionStatusChange   = "STATUS_CHANGE"
+	ClientAuditActionTransfer       = "TRANSFER"        // troca de mesa (pedido ou itens)
+	ClientAuditActionMerge          = "MERGE"           // união de mesas
+	ClientAuditActionSplit          = "SPLIT"           // separação de mesas unidas
+	ClientAuditActionPacingOverride = "PACING_OVERRIDE" // reserva aceita pela equipe acima do limite de ritmo
 )
 
 // Constantes para tipos de entidade de cliente
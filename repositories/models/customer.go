@@ -15,6 +15,7 @@ type Customer struct {
 	Email          string     `json:"email"`
 	Phone          string     `json:"phone"`
 	BirthDate      string     `json:"birth_date,omitempty"`
+	Address        *Address   `gorm:"type:jsonb" json:"address,omitempty"` // último endereço de entrega
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
 	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de pedido
+const (
+	OrderTypeDineIn   = "dine_in"  // consumo na mesa
+	OrderTypeTakeout  = "takeout"  // retirada no balcão
+	OrderTypeDelivery = "delivery" // entrega no endereço do cliente
+)
+
+// Formato da zona de entrega
+const (
+	DeliveryZoneTypeRadius  = "radius"  // raio em km a partir da localização do projeto
+	DeliveryZoneTypePolygon = "polygon" // polígono desenhado no mapa
+)
+
+// --- Address (endereço do cliente / entrega) ---
+type Address struct {
+	Street       string   `json:"street"`
+	Number       string   `json:"number,omitempty"`
+	Complement   string   `json:"complement,omitempty"`
+	Neighborhood string   `json:"neighborhood,omitempty"`
+	City         string   `json:"city,omitempty"`
+	State        string   `json:"state,omitempty"`
+	ZipCode      string   `json:"zip_code,omitempty"`
+	Reference    string   `json:"reference,omitempty"` // ponto de referência para o entregador
+	Latitude     *float64 `json:"latitude,omitempty"`
+	Longitude    *float64 `json:"longitude,omitempty"`
+}
+
+// HasLocation indica se o endereço tem coordenadas para localizar a zona de entrega
+func (a *Address) HasLocation() bool {
+	return a != nil && a.Latitude != nil && a.Longitude != nil
+}
+
+// Value implementa driver.Valuer para serializar para o banco
+func (a Address) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+// Scan implementa sql.Scanner para deserializar do banco
+func (a *Address) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*a = Address{}
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("cannot scan value into Address: unsupported type")
+	}
+
+	if len(bytes) == 0 || string(bytes) == "null" {
+		*a = Address{}
+		return nil
+	}
+	return json.Unmarshal(bytes, a)
+}
+
+// GeoPolygon vértices [latitude, longitude] do polígono gravados em JSONB
+type GeoPolygon [][2]float64
+
+// Value implementa driver.Valuer para serializar para o banco
+func (p GeoPolygon) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(p)
+}
+
+// Scan implementa sql.Scanner para deserializar do banco
+func (p *GeoPolygon) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*p = GeoPolygon{}
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("cannot scan value into GeoPolygon: unsupported type")
+	}
+
+	if len(bytes) == 0 || string(bytes) == "null" {
+		*p = GeoPolygon{}
+		return nil
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+// --- DeliveryZone (zona de entrega com taxa e pedido mínimo) ---
+type DeliveryZone struct {
+	Id               uuid.UUID  `gorm:"primaryKey" json:"id"`
+	OrganizationId   uuid.UUID  `json:"organization_id" gorm:"index"`
+	ProjectId        uuid.UUID  `json:"project_id" gorm:"index"`
+	Name             string     `json:"name"`
+	Type             string     `json:"type"`                                // "radius" ou "polygon"
+	RadiusKm         float64    `json:"radius_km,omitempty"`                 // zona por raio
+	Polygon          GeoPolygon `gorm:"type:jsonb" json:"polygon,omitempty"` // zona por polígono
+	Fee              float64    `json:"fee"`                                 // taxa de entrega
+	MinOrderValue    float64    `json:"min_order_value"`                     // valor mínimo dos itens (0 = sem mínimo)
+	EstimatedMinutes int        `json:"estimated_minutes"`                   // tempo de deslocamento somado ao preparo
+	Active           bool       `json:"active" gorm:"default:true"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+}
+
+// DeliveryQuote taxa, pedido mínimo e previsão para um endereço
+type DeliveryQuote struct {
+	ZoneId           uuid.UUID `json:"zone_id"`
+	ZoneName         string    `json:"zone_name"`
+	Fee              float64   `json:"fee"`
+	MinOrderValue    float64   `json:"min_order_value"`
+	EstimatedMinutes int       `json:"estimated_minutes"`
+	DistanceKm       float64   `json:"distance_km,omitempty"` // distância em linha reta até o restaurante
+}
+
+// OrderSlot horário de retirada/entrega com a ocupação da cozinha
+type OrderSlot struct {
+	Time      string    `json:"time"` // "HH:MM"
+	StartsAt  time.Time `json:"starts_at"`
+	Orders    int       `json:"orders"`   // pedidos já agendados no horário
+	Capacity  int       `json:"capacity"` // 0 = sem limite
+	Available bool      `json:"available"`
+}
+
+// PublicOrderCustomer identificação do cliente em pedido de retirada/entrega
+type PublicOrderCustomer struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone"`
+	Email string `json:"email,omitempty"`
+}
+
+// PublicTakeoutOrderRequest pedido de retirada ou entrega feito pelo cliente no site
+type PublicTakeoutOrderRequest struct {
+	PublicOrderRequest
+	Type         string              `json:"type"` // "takeout" ou "delivery"
+	Customer     PublicOrderCustomer `json:"customer"`
+	Address      *Address            `json:"address,omitempty"`       // obrigatório para delivery
+	ScheduledFor *time.Time          `json:"scheduled_for,omitempty"` // vazio = o quanto antes
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Documentos fiscais e ambientes da SEFAZ
+const (
+	FiscalModelNfce = "65" // NFC-e
+
+	FiscalEnvironmentProduction   = 1
+	FiscalEnvironmentHomologation = 2
+)
+
+// Status do documento fiscal
+const (
+	FiscalStatusSigned     = "signed"     // assinado, aguardando transmissão (ou falha de comunicação com a SEFAZ)
+	FiscalStatusAuthorized = "authorized" // autorizado (cStat 100)
+	FiscalStatusRejected   = "rejected"   // rejeitado pela SEFAZ; pedidos ficam livres para nova emissão
+	FiscalStatusCancelled  = "cancelled"  // cancelamento homologado
+)
+
+// Meios de pagamento da NFC-e (tPag)
+const (
+	FiscalPaymentCash    = "01" // dinheiro
+	FiscalPaymentCredit  = "03" // cartão de crédito
+	FiscalPaymentDebit   = "04" // cartão de débito
+	FiscalPaymentVoucher = "10" // vale-alimentação
+	FiscalPaymentMeal    = "11" // vale-refeição
+	FiscalPaymentPix     = "17" // Pix
+	FiscalPaymentOther   = "99" // outros (exige descrição)
+)
+
+// FiscalPayment grupo de pagamento (detPag) da nota
+type FiscalPayment struct {
+	Method      string  `json:"method"` // tPag: "01", "03", "04", "10", "11", "17", "99"
+	Amount      float64 `json:"amount"`
+	Description string  `json:"description,omitempty"` // obrigatório para "99"
+}
+
+type FiscalPayments []FiscalPayment
+
+// Value implementa driver.Valuer para serializar como JSON
+func (fp FiscalPayments) Value() (driver.Value, error) {
+	if len(fp) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(fp)
+}
+
+// Scan implementa sql.Scanner para deserializar do banco
+func (fp *FiscalPayments) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*fp = FiscalPayments{}
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("cannot scan value into FiscalPayments: unsupported type")
+	}
+
+	if len(bytes) == 0 || string(bytes) == "null" {
+		*fp = FiscalPayments{}
+		return nil
+	}
+	return json.Unmarshal(bytes, fp)
+}
+
+// --- FiscalDocument (NFC-e emitida para pedidos entregues) ---
+// A numeração é por organização (CNPJ do emitente), ambiente e série.
+type FiscalDocument struct {
+	Id               uuid.UUID      `gorm:"primaryKey" json:"id"`
+	OrganizationId   uuid.UUID      `json:"organization_id" gorm:"uniqueIndex:idx_fiscal_number"`
+	ProjectId        uuid.UUID      `json:"project_id" gorm:"index"`
+	Model            string         `json:"model" gorm:"uniqueIndex:idx_fiscal_number"`       // "65" NFC-e
+	Environment      int            `json:"environment" gorm:"uniqueIndex:idx_fiscal_number"` // 1 produção, 2 homologação
+	Series           int            `json:"series" gorm:"uniqueIndex:idx_fiscal_number"`
+	Number           int            `json:"number" gorm:"uniqueIndex:idx_fiscal_number"`
+	AccessKey        string         `json:"access_key" gorm:"uniqueIndex;size:44"` // chave de acesso (44 dígitos)
+	Status           string         `json:"status"`                                // "signed", "authorized", "rejected", "cancelled"
+	Provider         string         `json:"provider"`                              // provedor de transmissão usado
+	OrderIds         pq.StringArray `gorm:"type:text[]" json:"order_ids"`
+	CustomerDocument string         `json:"customer_document,omitempty"` // CPF/CNPJ do consumidor na nota
+	TotalAmount      float64        `json:"total_amount"`                // vNF
+	Payments         FiscalPayments `gorm:"type:jsonb;default:'[]'" json:"payments"`
+	Change           float64        `json:"change"`                    // troco (vTroco)
+	QRCodeUrl        string         `json:"qrcode_url"`                // URL do QR Code impresso no DANFE NFC-e
+	ConsultUrl       string         `json:"consult_url"`               // urlChave: consulta pela chave de acesso
+	XML              string         `gorm:"type:text" json:"-"`        // NFe assinada; após autorização, o nfeProc com o protocolo
+	Protocol         string         `json:"protocol,omitempty"`        // protocolo de autorização
+	StatusCode       string         `json:"status_code,omitempty"`     // cStat da última resposta da SEFAZ
+	StatusMessage    string         `json:"status_message,omitempty"`  // xMotivo
+	CancelProtocol   string         `json:"cancel_protocol,omitempty"` // protocolo do evento de cancelamento
+	CancelReason     string         `json:"cancel_reason,omitempty"`
+	IssuedAt         time.Time      `json:"issued_at"` // dhEmi
+	AuthorizedAt     *time.Time     `json:"authorized_at,omitempty"`
+	CancelledAt      *time.Time     `json:"cancelled_at,omitempty"`
+	CreatedBy        *uuid.UUID     `json:"created_by,omitempty"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+// NfceIssueRequest emissão de NFC-e para um ou mais pedidos entregues
+type NfceIssueRequest struct {
+	OrderIds         []uuid.UUID     `json:"order_ids"`
+	Payments         []FiscalPayment `json:"payments,omitempty"`          // vazio = dinheiro no valor total
+	CustomerDocument string          `json:"customer_document,omitempty"` // CPF ou CNPJ (opcional)
+	CustomerName     string          `json:"customer_name,omitempty"`
+	AdditionalInfo   string          `json:"additional_info,omitempty"` // informações complementares (infCpl)
+}
+
+// NfceCancelRequest cancelamento de NFC-e autorizada (justificativa de 15 a 255 caracteres)
+type NfceCancelRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Unidades de medida de ingrediente (a ficha técnica usa a mesma unidade do ingrediente)
+const (
+	IngredientUnitGram       = "g"
+	IngredientUnitKilogram   = "kg"
+	IngredientUnitMilliliter = "ml"
+	IngredientUnitLiter      = "l"
+	IngredientUnitPiece      = "un"
+)
+
+// --- Ingredient (insumo controlado pela cozinha) ---
+type Ingredient struct {
+	Id               uuid.UUID  `gorm:"primaryKey" json:"id"`
+	OrganizationId   uuid.UUID  `json:"organization_id"`
+	ProjectId        uuid.UUID  `json:"project_id"`
+	Name             string     `json:"name"`
+	Unit             string     `json:"unit"`                       // "g", "kg", "ml", "l", "un"
+	Stock            float64    `json:"stock"`                      // última contagem + recebimentos desde então
+	UnitCost         float64    `json:"unit_cost"`                  // custo médio por unidade
+	MinStock         float64    `json:"min_stock"`                  // ponto de reposição
+	TheoreticalStock float64    `json:"theoretical_stock" gorm:"-"` // estoque menos o consumo teórico desde a última contagem
+	LastCountedAt    *time.Time `json:"last_counted_at,omitempty"`  // data da última contagem física
+	Active           bool       `json:"active" gorm:"default:true"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+}
+
+// --- RecipeItem (ficha técnica: quantidade de ingrediente por unidade vendida do produto) ---
+type RecipeItem struct {
+	Id             uuid.UUID `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID `json:"organization_id"`
+	ProjectId      uuid.UUID `json:"project_id"`
+	ProductId      uuid.UUID `json:"product_id" gorm:"index"`
+	IngredientId   uuid.UUID `json:"ingredient_id" gorm:"index"`
+	Quantity       float64   `json:"quantity"` // na unidade do ingrediente
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// RecipeRequest substitui a ficha técnica do produto
+type RecipeRequest struct {
+	Items []RecipeItem `json:"items"`
+}
+
+// RecipeItemCost custo de um ingrediente na ficha técnica
+type RecipeItemCost struct {
+	IngredientId   uuid.UUID `json:"ingredient_id"`
+	IngredientName string    `json:"ingredient_name"`
+	Unit           string    `json:"unit"`
+	Quantity       float64   `json:"quantity"`
+	UnitCost       float64   `json:"unit_cost"`
+	Cost           float64   `json:"cost"`
+}
+
+// ProductFoodCost custo da ficha técnica em relação ao preço de venda
+type ProductFoodCost struct {
+	ProductId       uuid.UUID        `json:"product_id"`
+	ProductName     string           `json:"product_name"`
+	Price           float64          `json:"price"`
+	RecipeCost      float64          `json:"recipe_cost"`
+	FoodCostPercent float64          `json:"food_cost_percent"` // 0 quando o produto não tem preço único (ex.: vinhos por variante)
+	Items           []RecipeItemCost `json:"items"`
+}
+
+// IngredientConsumption consumo teórico de um ingrediente no período
+type IngredientConsumption struct {
+	IngredientId   uuid.UUID `json:"ingredient_id"`
+	IngredientName string    `json:"ingredient_name"`
+	Unit           string    `json:"unit"`
+	Quantity       float64   `json:"quantity"`
+	Cost           float64   `json:"cost"`
+}
+
+// ConsumptionReport consumo teórico calculado a partir dos pedidos que entraram em preparo no período
+type ConsumptionReport struct {
+	From        time.Time               `json:"from"`
+	To          time.Time               `json:"to"`
+	OrdersCount int                     `json:"orders_count"`
+	TotalCost   float64                 `json:"total_cost"`
+	Items       []IngredientConsumption `json:"items"`
+}
+
+// --- InventoryCount (contagem física periódica com relatório de divergência) ---
+type InventoryCount struct {
+	Id                uuid.UUID            `gorm:"primaryKey" json:"id"`
+	OrganizationId    uuid.UUID            `json:"organization_id"`
+	ProjectId         uuid.UUID            `json:"project_id"`
+	Notes             string               `json:"notes,omitempty"`
+	TotalVarianceCost float64              `json:"total_variance_cost"` // soma do valor das divergências (negativo = falta)
+	Items             []InventoryCountItem `json:"items" gorm:"foreignKey:CountId"`
+	CountedBy         *uuid.UUID           `json:"counted_by,omitempty"`
+	CountedAt         time.Time            `json:"counted_at"`
+	CreatedAt         time.Time            `json:"created_at"`
+}
+
+// --- InventoryCountItem (linha da contagem: esperado x contado) ---
+type InventoryCountItem struct {
+	Id             uuid.UUID `gorm:"primaryKey" json:"id"`
+	CountId        uuid.UUID `json:"count_id" gorm:"index"`
+	IngredientId   uuid.UUID `json:"ingredient_id"`
+	IngredientName string    `json:"ingredient_name"`
+	Unit           string    `json:"unit"`
+	Expected       float64   `json:"expected"` // estoque teórico no momento da contagem
+	Counted        float64   `json:"counted"`
+	Variance       float64   `json:"variance"` // contado - esperado
+	UnitCost       float64   `json:"unit_cost"`
+	VarianceCost   float64   `json:"variance_cost"`
+}
+
+// InventoryCountRequest quantidades contadas por ingrediente
+type InventoryCountRequest struct {
+	Notes string `json:"notes"`
+	Items []struct {
+		IngredientId uuid.UUID `json:"ingredient_id"`
+		Counted      float64   `json:"counted"`
+	} `json:"items"`
+}
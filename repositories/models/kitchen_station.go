@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// --- KitchenStation (praça/estação de preparo da cozinha) ---
+// Cada item de pedido é roteado para uma estação com base na categoria ou no tipo do produto
+type KitchenStation struct {
+	Id             uuid.UUID      `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrganizationId uuid.UUID      `json:"organization_id" gorm:"not null"`
+	ProjectId      uuid.UUID      `json:"project_id" gorm:"not null"`
+	Name           string         `json:"name" gorm:"not null"`             // ex: "Grelha", "Bar"
+	Code           string         `json:"code" gorm:"size:50"`              // ex: "grill", "cold", "bar", "wine"
+	ProductTypes   pq.StringArray `json:"product_types" gorm:"type:text[]"` // tipos de produto roteados: "prato", "bebida", "vinho"
+	CategoryIds    pq.StringArray `json:"category_ids" gorm:"type:text[]"`  // categorias roteadas (têm prioridade sobre o tipo)
+	IsDefault      bool           `json:"is_default" gorm:"default:false"`  // recebe itens sem regra correspondente
+	Order          int            `json:"order" gorm:"default:0"`
+	Active         bool           `json:"active" gorm:"default:true"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      *time.Time     `json:"deleted_at,omitempty"`
+}
+
+// StationTicket representa um pedido visto por uma estação (apenas os itens daquela estação)
+type StationTicket struct {
+	OrderId      uuid.UUID   `json:"order_id"`
+	TableId      *uuid.UUID  `json:"table_id,omitempty"`
+	TableNumber  *int        `json:"table_number,omitempty"`
+	OrderType    string      `json:"order_type"`              // "dine_in", "takeout", "delivery"
+	ScheduledFor *time.Time  `json:"scheduled_for,omitempty"` // retirada/entrega agendada
+	Note         string      `json:"note,omitempty"`
+	OrderStatus  string      `json:"order_status"`
+	Items        []OrderItem `json:"items"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Direção do evento de marketplace
+const (
+	MarketplaceDirectionInbound  = "inbound"  // webhook recebido do marketplace
+	MarketplaceDirectionOutbound = "outbound" // status enviado ao marketplace
+)
+
+// Status do processamento do evento
+const (
+	MarketplaceEventStatusPending    = "pending"
+	MarketplaceEventStatusProcessing = "processing"
+	MarketplaceEventStatusProcessed  = "processed"
+	MarketplaceEventStatusIgnored    = "ignored" // evento sem efeito (pedido desconhecido ou já finalizado)
+	MarketplaceEventStatusFailed     = "failed"
+)
+
+// Tipos de evento (formato canônico, independente do marketplace)
+const (
+	MarketplaceEventOrderPlaced    = "order.placed"
+	MarketplaceEventOrderCancelled = "order.cancelled"
+	MarketplaceEventOrderStatus    = "order.status" // status enviado ao marketplace
+)
+
+// --- MarketplaceIntegration (loja do projeto em um marketplace de delivery) ---
+type MarketplaceIntegration struct {
+	Id             uuid.UUID  `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID  `json:"organization_id" gorm:"index"`
+	ProjectId      uuid.UUID  `json:"project_id" gorm:"index"`
+	Marketplace    string     `json:"marketplace" gorm:"index:idx_marketplace_merchant"` // adaptador registrado (ex: "fake")
+	MerchantId     string     `json:"merchant_id" gorm:"index:idx_marketplace_merchant"` // id da loja no marketplace
+	WebhookSecret  string     `json:"webhook_secret"`                                    // segredo HMAC dos webhooks recebidos
+	ApiBaseUrl     string     `json:"api_base_url"`                                      // API do marketplace para envio de status
+	ApiToken       string     `json:"api_token,omitempty"`
+	AutoAccept     bool       `json:"auto_accept" gorm:"default:true"` // false = pedido aguarda aprovação do garçom
+	Active         bool       `json:"active" gorm:"default:true"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+}
+
+// --- MarketplaceEvent (registro de webhooks recebidos e status enviados) ---
+// O par marketplace + event_id é único: webhook repetido não gera outro pedido.
+type MarketplaceEvent struct {
+	Id              uuid.UUID  `gorm:"primaryKey" json:"id"`
+	OrganizationId  uuid.UUID  `json:"organization_id" gorm:"index"`
+	ProjectId       uuid.UUID  `json:"project_id" gorm:"index"`
+	IntegrationId   uuid.UUID  `json:"integration_id"`
+	Marketplace     string     `json:"marketplace" gorm:"uniqueIndex:idx_marketplace_event"`
+	EventId         string     `json:"event_id" gorm:"uniqueIndex:idx_marketplace_event"`
+	Direction       string     `json:"direction"` // "inbound", "outbound"
+	Type            string     `json:"type"`      // "order.placed", "order.cancelled", "order.status"
+	ExternalOrderId string     `json:"external_order_id" gorm:"index"`
+	OrderId         *uuid.UUID `json:"order_id,omitempty" gorm:"index"`
+	Status          string     `json:"status"`                   // "pending", "processing", "processed", "ignored", "failed"
+	Payload         string     `json:"payload" gorm:"type:text"` // evento canônico (inbound) ou status enviado (outbound)
+	Attempts        int        `json:"attempts"`
+	NextAttemptAt   time.Time  `json:"next_attempt_at"`
+	LastError       string     `json:"last_error,omitempty"`
+	ProcessedAt     *time.Time `json:"processed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// MarketplaceOrderEvent evento do webhook convertido pelo adaptador para o formato canônico
+type MarketplaceOrderEvent struct {
+	EventId         string            `json:"event_id"`
+	Type            string            `json:"type"` // "order.placed", "order.cancelled"
+	MerchantId      string            `json:"merchant_id"`
+	ExternalOrderId string            `json:"order_id"`
+	Order           *MarketplaceOrder `json:"order,omitempty"`  // order.placed
+	Reason          string            `json:"reason,omitempty"` // order.cancelled
+}
+
+// MarketplaceOrder pedido recebido do marketplace
+type MarketplaceOrder struct {
+	Type         string                 `json:"type"` // "delivery" ou "takeout"
+	Customer     PublicOrderCustomer    `json:"customer"`
+	Address      *Address               `json:"address,omitempty"`
+	Items        []MarketplaceOrderItem `json:"items"`
+	DeliveryFee  float64                `json:"delivery_fee"`
+	Total        float64                `json:"total"` // valor cobrado pelo marketplace (informativo)
+	Note         string                 `json:"note,omitempty"`
+	ScheduledFor *time.Time             `json:"scheduled_for,omitempty"`
+}
+
+// MarketplaceOrderItem item do pedido do marketplace; Code é o PDVCode do produto
+type MarketplaceOrderItem struct {
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	Notes     string  `json:"notes,omitempty"`
+}
+
+// MarketplaceWebhookResult resultado do processamento de um webhook
+type MarketplaceWebhookResult struct {
+	Received   int                `json:"received"`
+	Duplicates int                `json:"duplicates"` // eventos já recebidos antes (ignorados)
+	Events     []MarketplaceEvent `json:"events"`
+}
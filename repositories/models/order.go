@@ -9,14 +9,99 @@ import (
 	"github.com/google/uuid"
 )
 
+// Status de item na cozinha
+const (
+	OrderItemStatusHeld      = "held" // tempo seguinte retido até o garçom disparar (fora da fila)
+	OrderItemStatusQueued    = "queued"
+	OrderItemStatusPreparing = "preparing"
+	OrderItemStatusReady     = "ready"
+)
+
+// Tempos do pedido, na ordem em que saem da cozinha
+const (
+	OrderCourseStarter = "entrada"
+	OrderCourseMain    = "principal"
+	OrderCourseDessert = "sobremesa"
+)
+
 // --- OrderItem (item do pedido) ---
 type OrderItem struct {
-	ProductId uuid.UUID `json:"product_id"`
-	Quantity  int       `json:"quantity"`
-	Price     float64   `json:"price"` // valor unitário
-	Notes     string    `json:"notes,omitempty"` // observações do item
+	Id          uuid.UUID           `json:"id"`
+	ProductId   uuid.UUID           `json:"product_id"`
+	ProductName string              `json:"product_name,omitempty"` // snapshot do nome no momento do pedido
+	Variant     string              `json:"variant,omitempty"`      // vinhos: "bottle", "half_bottle", "glass"
+	Quantity    int                 `json:"quantity"`
+	Seat        int                 `json:"seat,omitempty"`       // lugar na mesa (divisão da conta por lugar)
+	Price       float64             `json:"price"`                // valor unitário (snapshot do cadastro)
+	Notes       string              `json:"notes,omitempty"`      // observações do item
+	Modifiers   []OrderItemModifier `json:"modifiers,omitempty"`  // opções escolhidas (preço já validado)
+	StationId   *uuid.UUID          `json:"station_id,omitempty"` // estação da cozinha responsável
+	Course      string              `json:"course,omitempty"`     // "entrada", "principal", "sobremesa" (vazio = sai direto)
+	Status      string              `json:"status,omitempty"`     // "held", "queued", "preparing", "ready"
+	FiredAt     *time.Time          `json:"fired_at,omitempty"`   // quando o item foi liberado para a cozinha
+	StartedAt   *time.Time          `json:"started_at,omitempty"` // quando a estação começou o item
+	ReadyAt     *time.Time          `json:"ready_at,omitempty"`   // quando o item ficou pronto
+}
+
+// --- OrderCourse (situação de um tempo do pedido) ---
+type OrderCourse struct {
+	Course    string     `json:"course"`
+	Status    string     `json:"status"` // "held", "queued", "preparing", "ready"
+	Items     int        `json:"items"`
+	FiredAt   *time.Time `json:"fired_at,omitempty"`
+	FiredBy   *uuid.UUID `json:"fired_by,omitempty"` // garçom que disparou (nulo para o primeiro tempo)
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	ReadyAt   *time.Time `json:"ready_at,omitempty"`
 }
 
+// OrderCourses tempos do pedido gravados em JSONB
+type OrderCourses []OrderCourse
+
+// Value implementa driver.Valuer para serializar para o banco
+func (oc OrderCourses) Value() (driver.Value, error) {
+	if len(oc) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(oc)
+}
+
+// Scan implementa sql.Scanner para deserializar do banco
+func (oc *OrderCourses) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*oc = OrderCourses{}
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("cannot scan value into OrderCourses: unsupported type")
+	}
+
+	if len(bytes) == 0 || string(bytes) == "null" {
+		*oc = OrderCourses{}
+		return nil
+	}
+	return json.Unmarshal(bytes, oc)
+}
+
+// FireCourseRequest disparo de tempo pelo garçom (vazio = próximo tempo retido)
+type FireCourseRequest struct {
+	Course string `json:"course"`
+}
+
+// Status do pedido (transições validadas em utils.ValidateOrderStatusTransition)
+const (
+	OrderStatusAwaitingApproval = "awaiting_approval" // pedido público aguardando aprovação do garçom (fora da fila da cozinha)
+	OrderStatusPending          = "pending"
+	OrderStatusPreparing        = "preparing"
+	OrderStatusReady            = "ready"
+	OrderStatusDelivered        = "delivered"
+	OrderStatusCancelled        = "cancelled"
+)
+
 // OrderItems é um tipo customizado para array de OrderItem que funciona com JSONB
 type OrderItems []OrderItem
 
@@ -78,17 +163,56 @@ type Order struct {
 	TableId               *uuid.UUID  `json:"table_id,omitempty"`
 	TableNumber           *int        `json:"table_number,omitempty"` // Para pedidos públicos
 	CustomerId            *uuid.UUID  `json:"customer_id,omitempty"`
+	TabId                 *uuid.UUID  `json:"tab_id,omitempty" gorm:"index"` // comanda da mesa
 	Items                 OrderItems  `gorm:"type:jsonb" json:"items"`
-	TotalAmount           float64     `json:"total_amount"`
+	Courses               OrderCourses `gorm:"type:jsonb" json:"courses,omitempty"` // tempos (entrada, principal, sobremesa)
+	TotalAmount           float64     `json:"total_amount"` // total dos itens (sem a taxa de entrega)
 	Note                  string      `json:"note,omitempty"`
-	Source                string      `json:"source"`                            // "internal" ou "public"
-	Status                string      `json:"status"`                            // "pending", "preparing", "ready", "delivered", "cancelled"
+	Source                string      `json:"source" gorm:"index:idx_order_external,unique,where:external_order_id <> ''"` // "internal", "public" ou o marketplace de origem
+	ExternalOrderId       string      `json:"external_order_id,omitempty" gorm:"index:idx_order_external"`                   // id do pedido no marketplace
+	Type                  string      `json:"type" gorm:"default:'dine_in'"`     // "dine_in", "takeout", "delivery"
+	DeliveryAddress       *Address    `gorm:"type:jsonb" json:"delivery_address,omitempty"` // endereço de entrega (delivery)
+	DeliveryZoneId        *uuid.UUID  `json:"delivery_zone_id,omitempty"`         // zona que definiu a taxa de entrega
+	DeliveryFee           float64     `json:"delivery_fee"`                      // taxa de entrega (cobrada à parte do total dos itens)
+	ScheduledFor          *time.Time  `json:"scheduled_for,omitempty" gorm:"index"` // horário de retirada/entrega escolhido
+	Status                string      `json:"status"`                            // "awaiting_approval", "pending", "preparing", "ready", "delivered", "cancelled"
+	TrackingToken         string      `json:"-" gorm:"index"`                    // token de acompanhamento entregue ao cliente (pedidos públicos)
 	EstimatedPrepTime     int         `json:"estimated_prep_time_minutes"`       // tempo estimado total em minutos
 	EstimatedDeliveryTime *time.Time  `json:"estimated_delivery_time,omitempty"` // hora estimada de entrega
+	EstimateSource        string      `json:"estimate_source,omitempty"`         // "configured", "learned", "mixed"
 	StartedAt             *time.Time  `json:"started_at,omitempty"`              // quando começou a preparar
 	ReadyAt               *time.Time  `json:"ready_at,omitempty"`                // quando ficou pronto
 	DeliveredAt           *time.Time  `json:"delivered_at,omitempty"`            // quando foi entregue
+	FiscalDocumentId      *uuid.UUID  `json:"fiscal_document_id,omitempty" gorm:"index"` // NFC-e que cobre o pedido
 	CreatedAt             time.Time   `json:"created_at"`
 	UpdatedAt             time.Time   `json:"updated_at"`
 	DeletedAt             *time.Time  `json:"deleted_at,omitempty"`
 }
+
+// AmountDue valor cobrado do cliente: itens mais a taxa de entrega
+func (o Order) AmountDue() float64 {
+	return o.TotalAmount + o.DeliveryFee
+}
+
+// PublicOrderRequest pedido feito pelo cliente via QR Code da mesa
+type PublicOrderRequest struct {
+	Items       []OrderItem `json:"items"`
+	Note        string      `json:"note,omitempty"`
+	TotalAmount float64     `json:"total_amount,omitempty"` // total exibido ao cliente (conferido no servidor)
+}
+
+// PublicOrderTracking visão do pedido exibida ao cliente pelo token de acompanhamento
+type PublicOrderTracking struct {
+	OrderId           uuid.UUID   `json:"order_id"`
+	TableNumber       *int        `json:"table_number,omitempty"`
+	Type              string      `json:"type"`
+	ScheduledFor      *time.Time  `json:"scheduled_for,omitempty"`
+	DeliveryFee       float64     `json:"delivery_fee,omitempty"`
+	Status            string      `json:"status"`
+	Items             []OrderItem `json:"items"`
+	TotalAmount       float64     `json:"total_amount"` // itens + taxa de entrega
+	ProgressPercent   float64     `json:"progress_percent"`
+	RemainingMinutes  int         `json:"remaining_minutes"`
+	EstimatedDelivery *time.Time  `json:"estimated_delivery,omitempty"`
+	CreatedAt         time.Time   `json:"created_at"`
+}
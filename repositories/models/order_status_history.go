@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// --- OrderStatusHistory (histórico de transições de status do pedido) ---
+type OrderStatusHistory struct {
+	Id             uuid.UUID  `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID  `json:"organization_id"`
+	ProjectId      uuid.UUID  `json:"project_id"`
+	OrderId        uuid.UUID  `json:"order_id" gorm:"index"`
+	FromStatus     string     `json:"from_status"` // vazio na criação do pedido
+	ToStatus       string     `json:"to_status"`
+	Reason         string     `json:"reason,omitempty"`     // obrigatório no cancelamento
+	ChangedBy      *uuid.UUID `json:"changed_by,omitempty"` // usuário; nulo quando alterado pelo sistema ou pelo cliente
+	ChangedAt      time.Time  `json:"changed_at"`
+}
+
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
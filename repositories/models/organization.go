@@ -8,16 +8,25 @@ import (
 
 // --- Organization (organização mãe) ---
 type Organization struct {
-	Id          uuid.UUID  `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name        string     `json:"name" gorm:"not null"`
-	Slug        string     `json:"slug" gorm:"unique;size:100"` // Identificador único para subdomínio
-	Email       string     `gorm:"unique" json:"email"`
-	Phone       string     `json:"phone,omitempty"`
-	Address     string     `json:"address,omitempty"`
-	Website     string     `json:"website,omitempty"`
-	Description string     `json:"description,omitempty"`
-	Active      bool       `gorm:"default:true" json:"active"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Id          uuid.UUID `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string    `json:"name" gorm:"not null"`
+	Slug        string    `json:"slug" gorm:"unique;size:100"` // Identificador único para subdomínio
+	Email       string    `gorm:"unique" json:"email"`
+	Phone       string    `json:"phone,omitempty"`
+	Address     string    `json:"address,omitempty"`
+	Website     string    `json:"website,omitempty"`
+	Description string    `json:"description,omitempty"`
+
+	// Dados fiscais do emitente (NFC-e)
+	Cnpj              string   `json:"cnpj,omitempty"`
+	LegalName         string   `json:"legal_name,omitempty"`         // razão social
+	StateRegistration string   `json:"state_registration,omitempty"` // inscrição estadual
+	TaxRegime         int      `json:"tax_regime,omitempty"`         // CRT: 1 Simples Nacional, 2 Simples (excesso de sublimite), 3 Regime normal, 4 MEI
+	FiscalAddress     *Address `gorm:"type:jsonb" json:"fiscal_address,omitempty"`
+	CityCode          string   `json:"city_code,omitempty"` // código IBGE do município do emitente
+
+	Active    bool       `gorm:"default:true" json:"active"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Serviços de uma regra de ritmo ("" = almoço e jantar)
+const (
+	PacingServiceLunch  = "lunch"
+	PacingServiceDinner = "dinner"
+)
+
+// PacingRule - Ritmo de chegada das reservas (limite de pessoas e de reservas por horário).
+// Os limites por horário valem para cada janela de SlotIntervalMinutes das configurações;
+// a janela móvel limita qualquer intervalo de RollingWindowMinutes. Limite 0 = sem limite.
+// Todas as regras ativas que valem para o serviço e o dia da semana se aplicam juntas.
+type PacingRule struct {
+	Id                     uuid.UUID     `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrganizationId         uuid.UUID     `json:"organization_id"`
+	ProjectId              uuid.UUID     `json:"project_id"`
+	Name                   string        `json:"name"`
+	Service                string        `json:"service,omitempty"`                          // "lunch", "dinner" ou vazio (os dois)
+	Weekdays               pq.Int64Array `json:"weekdays,omitempty" gorm:"type:integer[]"`   // 0=domingo ... 6=sábado (vazio = todos)
+	MaxCoversPerSlot       int           `json:"max_covers_per_slot" gorm:"default:0"`       // pessoas chegando no mesmo horário
+	MaxReservationsPerSlot int           `json:"max_reservations_per_slot" gorm:"default:0"` // reservas no mesmo horário
+	RollingWindowMinutes   int           `json:"rolling_window_minutes" gorm:"default:0"`
+	RollingMaxCovers       int           `json:"rolling_max_covers" gorm:"default:0"`
+	RollingMaxReservations int           `json:"rolling_max_reservations" gorm:"default:0"`
+	Active                 bool          `json:"active" gorm:"default:true"`
+	CreatedAt              time.Time     `json:"created_at"`
+	UpdatedAt              time.Time     `json:"updated_at"`
+	DeletedAt              *time.Time    `json:"deleted_at,omitempty"`
+}
+
+// AppliesTo indica se a regra vale para o serviço e o dia da semana
+func (r *PacingRule) AppliesTo(service string, weekday time.Weekday) bool {
+	if !r.Active || r.DeletedAt != nil {
+		return false
+	}
+	if r.Service != "" && r.Service != service {
+		return false
+	}
+	if len(r.Weekdays) == 0 {
+		return true
+	}
+	for _, w := range r.Weekdays {
+		if time.Weekday(w) == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// PacingViolation limite de ritmo que a reserva ultrapassaria
+type PacingViolation struct {
+	RuleId      uuid.UUID `json:"rule_id"`
+	RuleName    string    `json:"rule_name"`
+	Limit       string    `json:"limit"` // "covers_per_slot", "reservations_per_slot", "rolling_covers", "rolling_reservations"
+	Max         int       `json:"max"`
+	Current     int       `json:"current"` // ocupação da janela sem a nova reserva
+	Requested   int       `json:"requested"`
+	WindowStart string    `json:"window_start"` // RFC3339 local do projeto
+	WindowEnd   string    `json:"window_end"`
+}
+
+func (v *PacingViolation) Error() string {
+	return fmt.Sprintf("pacing_limit: %s of rule %q reached (%d of %d between %s and %s)",
+		v.Limit, v.RuleName, v.Current, v.Max, v.WindowStart, v.WindowEnd)
+}
+
+// PacingSlotUsage ocupação de um horário no relatório de ritmo
+type PacingSlotUsage struct {
+	Time                   string  `json:"time"`
+	Datetime               string  `json:"datetime"`
+	Service                string  `json:"service"`
+	Covers                 int     `json:"covers"`
+	Reservations           int     `json:"reservations"`
+	MaxCoversPerSlot       int     `json:"max_covers_per_slot"`       // menor limite entre as regras (0 = sem limite)
+	MaxReservationsPerSlot int     `json:"max_reservations_per_slot"` // idem
+	CoversUtilization      float64 `json:"covers_utilization"`        // % do limite de pessoas (0 sem limite)
+	RollingCovers          int     `json:"rolling_covers"`            // pessoas na janela móvel que começa no horário
+	RollingMaxCovers       int     `json:"rolling_max_covers"`
+	Overrides              int     `json:"overrides"` // reservas aceitas pela equipe acima do limite
+}
+
+// PacingReport relatório de ritmo de um dia
+type PacingReport struct {
+	Date            string            `json:"date"`
+	Service         string            `json:"service,omitempty"`
+	Covers          int               `json:"covers"`
+	Reservations    int               `json:"reservations"`
+	PeakUtilization float64           `json:"peak_utilization"` // maior % de ocupação de pessoas entre os horários
+	SlotsAtLimit    int               `json:"slots_at_limit"`
+	Overrides       int               `json:"overrides"`
+	Slots           []PacingSlotUsage `json:"slots"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Status da cobrança Pix
+const (
+	PixChargeStatusPending = "pending"
+	PixChargeStatusPaid    = "paid"
+)
+
+// --- PixCharge (cobrança Pix gerada para pedidos ou comanda) ---
+type PixCharge struct {
+	Id             uuid.UUID      `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID      `json:"organization_id"`
+	ProjectId      uuid.UUID      `json:"project_id"`
+	TxId           string         `json:"txid" gorm:"uniqueIndex"`
+	Kind           string         `json:"kind"` // "static", "dynamic"
+	Provider       string         `json:"provider,omitempty"`
+	Amount         float64        `json:"amount"`
+	OrderIds       pq.StringArray `json:"order_ids" gorm:"type:text[]"`
+	TableId        *uuid.UUID     `json:"table_id,omitempty"`
+	TabId          *uuid.UUID     `json:"tab_id,omitempty"`
+	Payload        string         `json:"payload"`            // "copia e cola"
+	Location       string         `json:"location,omitempty"` // URL do PSP (dinâmico)
+	Status         string         `json:"status" gorm:"default:'pending'"`
+	EndToEndId     string         `json:"end_to_end_id,omitempty"`
+	PaidAmount     float64        `json:"paid_amount"`
+	PaidAt         *time.Time     `json:"paid_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// PixChargeRequest dados para gerar cobrança Pix (pedidos avulsos ou todos os pedidos abertos da mesa)
+type PixChargeRequest struct {
+	OrderIds []uuid.UUID `json:"order_ids,omitempty"`
+	TableId  *uuid.UUID  `json:"table_id,omitempty"`
+	TabId    *uuid.UUID  `json:"tab_id,omitempty"`
+	Dynamic  bool        `json:"dynamic"`
+}
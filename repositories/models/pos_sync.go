@@ -0,0 +1,132 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Direção e status das sincronizações com o PDV
+const (
+	PosSyncImportCatalog = "catalog_import" // cardápio do PDV → produtos
+	PosSyncExportSales   = "sales_export"   // pedidos entregues → vendas do PDV
+
+	PosSyncStatusRunning   = "running"
+	PosSyncStatusCompleted = "completed"
+	PosSyncStatusPartial   = "partial" // concluída com itens rejeitados
+	PosSyncStatusFailed    = "failed"  // arquivo inválido ou erro geral
+)
+
+// Resultado de cada item da sincronização
+const (
+	PosSyncItemCreated = "created"
+	PosSyncItemUpdated = "updated"
+	PosSyncItemFailed  = "failed"
+	PosSyncItemSkipped = "skipped" // venda sem código PDV
+)
+
+// PosSyncItem item criado, alterado ou rejeitado na sincronização (itens sem mudança não são registrados)
+type PosSyncItem struct {
+	Line      int        `json:"line,omitempty"` // linha/posição no arquivo importado
+	Code      string     `json:"code,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	OrderId   *uuid.UUID `json:"order_id,omitempty"` // exportação: pedido de origem
+	Action    string     `json:"action"`             // "created", "updated", "failed", "skipped"
+	OldPrice  *float64   `json:"old_price,omitempty"`
+	NewPrice  *float64   `json:"new_price,omitempty"`
+	Message   string     `json:"message,omitempty"`
+	ProductId *uuid.UUID `json:"product_id,omitempty"`
+}
+
+type PosSyncItems []PosSyncItem
+
+// Value implementa driver.Valuer para serializar como JSON
+func (pi PosSyncItems) Value() (driver.Value, error) {
+	if len(pi) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(pi)
+}
+
+// Scan implementa sql.Scanner para deserializar do banco
+func (pi *PosSyncItems) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*pi = PosSyncItems{}
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("cannot scan value into PosSyncItems: unsupported type")
+	}
+
+	if len(bytes) == 0 || string(bytes) == "null" {
+		*pi = PosSyncItems{}
+		return nil
+	}
+	return json.Unmarshal(bytes, pi)
+}
+
+// --- PosSyncRun (execução de importação de cardápio ou exportação de vendas) ---
+type PosSyncRun struct {
+	Id             uuid.UUID    `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID    `json:"organization_id" gorm:"index"`
+	ProjectId      uuid.UUID    `json:"project_id" gorm:"index"`
+	Direction      string       `json:"direction"` // "catalog_import", "sales_export"
+	Format         string       `json:"format"`    // adaptador usado ("csv", "json")
+	Status         string       `json:"status"`    // "running", "completed", "partial", "failed"
+	DryRun         bool         `json:"dry_run"`   // importação apenas conferida, sem gravar
+	Total          int          `json:"total"`     // itens lidos (importação) ou vendas exportadas
+	Created        int          `json:"created"`
+	Updated        int          `json:"updated"`
+	Unchanged      int          `json:"unchanged"`
+	Failed         int          `json:"failed"`
+	Skipped        int          `json:"skipped"`
+	PeriodStart    *time.Time   `json:"period_start,omitempty"` // exportação: pedidos entregues a partir de
+	PeriodEnd      *time.Time   `json:"period_end,omitempty"`   // exportação: até (exclusivo)
+	Items          PosSyncItems `gorm:"type:jsonb;default:'[]'" json:"items"`
+	Error          string       `json:"error,omitempty"`
+	StartedBy      *uuid.UUID   `json:"started_by,omitempty"`
+	StartedAt      time.Time    `json:"started_at"`
+	FinishedAt     *time.Time   `json:"finished_at,omitempty"`
+}
+
+// PosCatalogItem produto do cardápio do PDV no formato canônico dos adaptadores
+type PosCatalogItem struct {
+	Line        int      `json:"-"`
+	Code        string   `json:"code"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Price       *float64 `json:"price"`
+	Category    string   `json:"category,omitempty"` // nome da categoria (opcional)
+	Type        string   `json:"type,omitempty"`     // "prato", "bebida", "vinho" (padrão "prato" em produto novo)
+	Active      *bool    `json:"active,omitempty"`
+}
+
+// PosSaleRecord venda exportada para o PDV: um registro por item de pedido entregue
+type PosSaleRecord struct {
+	OrderId     uuid.UUID `json:"order_id"`
+	DeliveredAt time.Time `json:"delivered_at"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	Variant     string    `json:"variant,omitempty"`
+	Quantity    int       `json:"quantity"`
+	UnitPrice   float64   `json:"unit_price"`
+	Total       float64   `json:"total"`
+	Source      string    `json:"source"` // "internal", "public" ou o marketplace
+	Type        string    `json:"type"`   // "dine_in", "takeout", "delivery"
+}
+
+// PosSalesExport arquivo gerado pela exportação de vendas
+type PosSalesExport struct {
+	Run         *PosSyncRun
+	ContentType string
+	FileName    string
+	Content     []byte
+}
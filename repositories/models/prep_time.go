@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Origem do tempo estimado do pedido
+const (
+	EstimateSourceConfigured = "configured" // Product.PrepTimeMinutes e fila estimada
+	EstimateSourceLearned    = "learned"    // todos os itens com histórico suficiente
+	EstimateSourceMixed      = "mixed"      // parte dos itens com histórico, parte com o cadastro
+)
+
+// --- PrepTimeStat (tempo real de preparo aprendido por produto e hora do dia) ---
+type PrepTimeStat struct {
+	Id             uuid.UUID `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID `json:"organization_id" gorm:"index:idx_prep_time_stat_project"`
+	ProjectId      uuid.UUID `json:"project_id" gorm:"index:idx_prep_time_stat_project"`
+	ProductId      uuid.UUID `json:"product_id"`
+	HourOfDay      int       `json:"hour_of_day"`    // 0-23 no fuso do projeto; -1 = todas as horas
+	SampleCount    int       `json:"sample_count"`   // linhas de pedido medidas
+	AvgMinutes     float64   `json:"avg_minutes"`    // por unidade (quantidade normalizada)
+	MedianMinutes  float64   `json:"median_minutes"` // usado na estimativa
+	P90Minutes     float64   `json:"p90_minutes"`
+	ComputedAt     time.Time `json:"computed_at"`
+}
+
+// --- QueueTimeStat (espera real até o preparo começar, por tamanho da fila) ---
+type QueueTimeStat struct {
+	Id                uuid.UUID `gorm:"primaryKey" json:"id"`
+	OrganizationId    uuid.UUID `json:"organization_id" gorm:"index:idx_queue_time_stat_project"`
+	ProjectId         uuid.UUID `json:"project_id" gorm:"index:idx_queue_time_stat_project"`
+	QueueDepth        int       `json:"queue_depth"` // pedidos em preparo na chegada (último valor agrupa os maiores)
+	SampleCount       int       `json:"sample_count"`
+	AvgWaitMinutes    float64   `json:"avg_wait_minutes"`
+	MedianWaitMinutes float64   `json:"median_wait_minutes"` // usado na estimativa
+	ComputedAt        time.Time `json:"computed_at"`
+}
+
+// ProjectRef par organização/projeto
+type ProjectRef struct {
+	OrganizationId uuid.UUID `json:"organization_id"`
+	ProjectId      uuid.UUID `json:"project_id"`
+}
+
+// PrepTimeStats estatísticas aprendidas do projeto
+type PrepTimeStats struct {
+	MinSamples int             `json:"min_samples"`
+	ComputedAt *time.Time      `json:"computed_at,omitempty"`
+	Products   []PrepTimeStat  `json:"products"`
+	Queue      []QueueTimeStat `json:"queue"`
+}
+
+// PrepTimeAccuracy comparação entre estimado e real por origem da estimativa
+type PrepTimeAccuracy struct {
+	Source               string  `json:"source"`
+	Orders               int     `json:"orders"`
+	AvgEstimatedMinutes  float64 `json:"avg_estimated_prep_minutes"`
+	AvgActualMinutes     float64 `json:"avg_actual_prep_minutes"`
+	MeanErrorMinutes     float64 `json:"mean_error_minutes"`          // real - estimado (positivo = atrasou)
+	MeanAbsErrorMinutes  float64 `json:"mean_absolute_error_minutes"` // preparo
+	ReadyMeanAbsMinutes  float64 `json:"ready_time_mean_absolute_error_minutes"`
+	WithinFiveMinutesPct float64 `json:"ready_within_5_minutes_pct"` // ficou pronto até 5 min do horário prometido
+}
+
+// ProductPrepAccuracy tempo cadastrado x aprendido x real por produto
+type ProductPrepAccuracy struct {
+	ProductId         uuid.UUID `json:"product_id"`
+	Name              string    `json:"name"`
+	Samples           int       `json:"samples"`
+	ConfiguredMinutes *int      `json:"configured_minutes,omitempty"`
+	LearnedMinutes    *float64  `json:"learned_minutes,omitempty"` // nil enquanto não há amostras suficientes
+	AvgActualMinutes  float64   `json:"avg_actual_minutes"`
+}
+
+// PrepTimeAccuracyReport relatório de acurácia do tempo estimado no período
+type PrepTimeAccuracyReport struct {
+	From     time.Time             `json:"from"`
+	To       time.Time             `json:"to"`
+	Overall  PrepTimeAccuracy      `json:"overall"`
+	Sources  []PrepTimeAccuracy    `json:"sources"`
+	Products []ProductPrepAccuracy `json:"products"`
+}
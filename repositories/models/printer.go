@@ -0,0 +1,108 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Finalidade da impressora
+const (
+	PrinterPurposeKitchen = "kitchen" // comandas de cozinha (da estação ou de todas, se sem estação)
+	PrinterPurposeReceipt = "receipt" // conferência de conta do cliente
+)
+
+// Entrega dos trabalhos à impressora
+const (
+	PrinterDeliveryAgent  = "agent"  // o agente de impressão na rede do restaurante busca os trabalhos (padrão)
+	PrinterDeliveryDirect = "direct" // a API conecta na impressora (só quando roda na mesma rede)
+)
+
+// Tipos e status de trabalho de impressão
+const (
+	PrintJobTypeKitchen = "kitchen"
+	PrintJobTypeReceipt = "receipt"
+	PrintJobTypeTest    = "test"
+
+	PrintJobStatusPending  = "pending"
+	PrintJobStatusPrinting = "printing"
+	PrintJobStatusPrinted  = "printed"
+	PrintJobStatusFailed   = "failed" // esgotou as tentativas
+)
+
+// --- Printer (impressora térmica ESC/POS em rede) ---
+type Printer struct {
+	Id             uuid.UUID  `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID  `json:"organization_id"`
+	ProjectId      uuid.UUID  `json:"project_id"`
+	Name           string     `json:"name"`
+	Host           string     `json:"host"`                          // IP privado na rede local
+	Port           int        `json:"port" gorm:"default:9100"`      // porta RAW
+	PaperWidth     int        `json:"paper_width" gorm:"default:80"` // 58 ou 80 (mm)
+	Purpose        string     `json:"purpose"`                       // "kitchen", "receipt"
+	Delivery       string     `json:"delivery" gorm:"default:agent"` // "agent", "direct"
+	StationId      *uuid.UUID `json:"station_id,omitempty"`          // cozinha: imprime só os itens da estação
+	AutoPrint      bool       `json:"auto_print"`                    // imprime quando o pedido entra na fila da cozinha
+	Active         bool       `json:"active" gorm:"default:true"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+}
+
+// --- PrintJob (fila de impressão com novas tentativas) ---
+type PrintJob struct {
+	Id             uuid.UUID  `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID  `json:"organization_id"`
+	ProjectId      uuid.UUID  `json:"project_id"`
+	PrinterId      uuid.UUID  `json:"printer_id" gorm:"index"`
+	Type           string     `json:"type"` // "kitchen", "receipt", "test"
+	OrderId        *uuid.UUID `json:"order_id,omitempty" gorm:"index"`
+	TabId          *uuid.UUID `json:"tab_id,omitempty"`
+	Payload        []byte     `json:"-"` // bytes ESC/POS já renderizados
+	Status         string     `json:"status" gorm:"index"`
+	Attempts       int        `json:"attempts"`
+	MaxAttempts    int        `json:"max_attempts"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	PrintedAt      *time.Time `json:"printed_at,omitempty"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty"` // nulo na impressão automática
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// --- PrintAgent (agente de impressão na rede local do restaurante, um por projeto) ---
+type PrintAgent struct {
+	Id             uuid.UUID  `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID  `json:"organization_id"`
+	ProjectId      uuid.UUID  `json:"project_id" gorm:"uniqueIndex"`
+	TokenHash      string     `json:"-" gorm:"uniqueIndex"` // SHA-256 do token; o token só é mostrado ao gerar
+	LastSeenAt     *time.Time `json:"last_seen_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// PrintAgentToken token gerado para o agente (devolvido uma única vez)
+type PrintAgentToken struct {
+	Token string     `json:"token"`
+	Agent PrintAgent `json:"agent"`
+}
+
+// PrintAgentJob trabalho entregue ao agente com o destino na rede local
+type PrintAgentJob struct {
+	Id        uuid.UUID `json:"id"`
+	PrinterId uuid.UUID `json:"printer_id"`
+	Host      string    `json:"host"`
+	Port      int       `json:"port"`
+	Payload   []byte    `json:"payload"` // ESC/POS em base64
+}
+
+// PrintAgentResult resultado da tentativa feita pelo agente
+type PrintAgentResult struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// PrintRequest impressão/reimpressão manual (impressora opcional)
+type PrintRequest struct {
+	PrinterId *uuid.UUID `json:"printer_id,omitempty"`
+}
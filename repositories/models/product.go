@@ -48,9 +48,24 @@ type Product struct {
 	PriceGlass      *float64       `json:"price_glass,omitempty"`      // preço taça
 
 	// Campos existentes
-	Stock           *int      `json:"stock,omitempty"`
+	Stock           *int      `json:"stock,omitempty"`               // nulo = estoque não controlado
+	LowStockThreshold *int    `json:"low_stock_threshold,omitempty"` // nulo = usa o limite das configurações
+	OutOfStock      bool      `json:"out_of_stock" gorm:"default:false"` // desativado automaticamente por estoque zerado
 	PrepTimeMinutes *int      `json:"prep_time_minutes,omitempty"`
 
+	// Dados fiscais (NFC-e)
+	NCM             string    `json:"ncm,omitempty"`             // 8 dígitos
+	CFOP            string    `json:"cfop,omitempty"`            // vazio = 5102
+	CEST            string    `json:"cest,omitempty"`            // exigido para itens com substituição tributária
+	FiscalUnit      string    `json:"fiscal_unit,omitempty"`     // unidade comercial (vazio = "UN")
+	TaxOrigin       int       `json:"tax_origin"`                // origem da mercadoria (0 = nacional)
+	TaxCST          string    `json:"tax_cst,omitempty"`         // CSOSN (Simples Nacional) ou CST do ICMS; vazio = 102 / 00
+	ICMSRate        float64   `json:"icms_rate,omitempty"`       // alíquota do ICMS (CST 00)
+	PisCofinsCST    string    `json:"pis_cofins_cst,omitempty"`  // CST do PIS/COFINS; vazio = 49
+
+	// Grupos de opções/modificadores (ex: ponto da carne, adicionais)
+	OptionGroups ProductOptionGroups `gorm:"type:jsonb;default:'[]'" json:"option_groups"`
+
 	// Timestamps
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
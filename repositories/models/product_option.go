@@ -0,0 +1,72 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// --- ProductOption (opção de um grupo, ex: "Bacon extra") ---
+type ProductOption struct {
+	Id         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	PriceDelta float64   `json:"price_delta"` // acréscimo (ou desconto, se negativo) no preço unitário
+	Active     bool      `json:"active"`
+}
+
+// --- ProductOptionGroup (grupo de opções, ex: "Ponto da carne") ---
+type ProductOptionGroup struct {
+	Id            uuid.UUID       `json:"id"`
+	Name          string          `json:"name"`
+	Required      bool            `json:"required"`
+	MinSelections int             `json:"min_selections"`
+	MaxSelections int             `json:"max_selections"` // 0 = sem limite
+	Options       []ProductOption `json:"options"`
+}
+
+// ProductOptionGroups é um tipo customizado para array de ProductOptionGroup que funciona com JSONB
+type ProductOptionGroups []ProductOptionGroup
+
+// Value implementa driver.Valuer para serializar para o banco
+func (g ProductOptionGroups) Value() (driver.Value, error) {
+	if len(g) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(g)
+}
+
+// Scan implementa sql.Scanner para deserializar do banco
+func (g *ProductOptionGroups) Scan(value interface{}) error {
+	if value == nil {
+		*g = ProductOptionGroups{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("cannot scan value into ProductOptionGroups: unsupported type")
+	}
+
+	if len(bytes) == 0 || string(bytes) == "null" {
+		*g = ProductOptionGroups{}
+		return nil
+	}
+
+	return json.Unmarshal(bytes, g)
+}
+
+// --- OrderItemModifier (opção escolhida para um item do pedido) ---
+type OrderItemModifier struct {
+	GroupId    uuid.UUID `json:"group_id"`
+	GroupName  string    `json:"group_name,omitempty"`
+	OptionId   uuid.UUID `json:"option_id"`
+	OptionName string    `json:"option_name,omitempty"`
+	PriceDelta float64   `json:"price_delta"`
+}
@@ -31,6 +31,10 @@ type Project struct {
 	// Configurações de Notificação
 	NotificationResponsiblePhone *string `json:"notification_responsible_phone,omitempty"` // Número que recebe cópia das notificações de reserva
 
+	// Localização do restaurante (centro das zonas de entrega por raio)
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+
 	// Configurações gerais
 	TimeZone  string     `json:"timezone" gorm:"default:'America/Sao_Paulo'"`
 	Active    bool       `json:"active" gorm:"default:true"`
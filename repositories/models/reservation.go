@@ -4,20 +4,27 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // --- Reservation (reserva de mesa) ---
 type Reservation struct {
-	Id             uuid.UUID  `gorm:"primaryKey;autoIncrement" json:"id"`
-	OrganizationId uuid.UUID  `json:"organization_id"`
-	ProjectId      uuid.UUID  `json:"project_id"`
-	CustomerId     uuid.UUID  `json:"customer_id"`
-	TableId        *uuid.UUID `json:"table_id,omitempty"`
-	Datetime       string     `json:"datetime"`
-	PartySize      int        `json:"party_size"`
-	Note           string     `json:"note,omitempty"`
-	Status         string     `json:"status"` // "confirmed", "cancelled", "completed", "no_show", "pending", "not_approved"
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
-	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+	Id                   uuid.UUID      `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrganizationId       uuid.UUID      `json:"organization_id"`
+	ProjectId            uuid.UUID      `json:"project_id"`
+	CustomerId           uuid.UUID      `json:"customer_id"`
+	TableId              *uuid.UUID     `json:"table_id,omitempty"`
+	TableIds             pq.StringArray `json:"table_ids,omitempty" gorm:"type:text[]"` // todas as mesas quando a reserva junta mesas (TableId é a principal)
+	AutoAssigned         bool           `json:"auto_assigned"`                          // mesas escolhidas pelo alocador (podem ser redistribuídas)
+	Datetime             string         `json:"datetime"`                               // horário local do projeto com offset (RFC3339), ex: 2026-03-10T19:30:00-04:00
+	StartsAt             *time.Time     `json:"-" gorm:"index"`                         // mesmo horário como instante, usado em buscas e ordenação
+	PartySize            int            `json:"party_size"`
+	DurationMinutes      int            `json:"duration_minutes"` // permanência prevista, definida na criação (0 = a das configurações)
+	Note                 string         `json:"note,omitempty"`
+	Status               string         `json:"status"`                           // "confirmed", "cancelled", "completed", "no_show", "pending", "not_approved"
+	PacingOverrideReason string         `json:"pacing_override_reason,omitempty"` // motivo da equipe para aceitar acima do limite de ritmo
+	PacingOverrideBy     *uuid.UUID     `json:"pacing_override_by,omitempty"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+	DeletedAt            *time.Time     `json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de chamado feitos pelo cliente na mesa
+const (
+	ServiceRequestTypeWaiter  = "waiter"  // chamar garçom
+	ServiceRequestTypeBill    = "bill"    // pedir a conta
+	ServiceRequestTypeWater   = "water"   // água
+	ServiceRequestTypeCutlery = "cutlery" // talheres
+	ServiceRequestTypeCustom  = "custom"  // mensagem livre
+)
+
+// ServiceRequestTypes tipos aceitos no chamado público
+var ServiceRequestTypes = []string{ServiceRequestTypeWaiter, ServiceRequestTypeBill, ServiceRequestTypeWater, ServiceRequestTypeCutlery, ServiceRequestTypeCustom}
+
+// Status do chamado
+const (
+	ServiceRequestStatusOpen         = "open"
+	ServiceRequestStatusAcknowledged = "acknowledged"
+	ServiceRequestStatusResolved     = "resolved"
+)
+
+// --- ServiceRequest (chamado da mesa: garçom, conta, água, talheres ou mensagem) ---
+type ServiceRequest struct {
+	Id             uuid.UUID  `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID  `json:"organization_id" gorm:"index:idx_service_request_project"`
+	ProjectId      uuid.UUID  `json:"project_id" gorm:"index:idx_service_request_project"`
+	TableId        uuid.UUID  `json:"table_id" gorm:"index"`
+	TableNumber    int        `json:"table_number"`
+	EnvironmentId  *uuid.UUID `json:"environment_id,omitempty"` // ambiente (setor) da mesa no momento do chamado
+	Type           string     `json:"type"`                     // "waiter", "bill", "water", "cutlery", "custom"
+	Message        string     `json:"message,omitempty"`
+	Status         string     `json:"status" gorm:"default:'open'"`
+	ClientIP       string     `json:"-"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy *uuid.UUID `json:"acknowledged_by,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	ResolvedBy     *uuid.UUID `json:"resolved_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// PublicServiceRequest chamado enviado pelo cliente no QR Code da mesa
+type PublicServiceRequest struct {
+	Type    string `json:"type"`
+	Message string `json:"message,omitempty"` // obrigatória para "custom"
+}
+
+// PublicServiceRequestStatus andamento do chamado exibido ao cliente
+type PublicServiceRequestStatus struct {
+	Id             uuid.UUID  `json:"id"`
+	Type           string     `json:"type"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+}
+
+// ServiceRequestMetrics tempos de resposta de um setor (ou do total) no período
+type ServiceRequestMetrics struct {
+	EnvironmentId         *uuid.UUID     `json:"environment_id,omitempty"`
+	Label                 string         `json:"label"`
+	Total                 int            `json:"total"`
+	Open                  int            `json:"open"` // ainda sem resolução
+	AvgAcknowledgeSeconds float64        `json:"avg_acknowledge_seconds"`
+	P90AcknowledgeSeconds float64        `json:"p90_acknowledge_seconds"`
+	AvgResolveSeconds     float64        `json:"avg_resolve_seconds"`
+	ByType                map[string]int `json:"by_type"`
+}
+
+// ServiceRequestReport tempos de resposta aos chamados por setor
+type ServiceRequestReport struct {
+	From     time.Time               `json:"from"`
+	To       time.Time               `json:"to"`
+	Overall  ServiceRequestMetrics   `json:"overall"`
+	Sections []ServiceRequestMetrics `json:"sections"`
+}
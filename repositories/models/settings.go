@@ -41,6 +41,53 @@ type Settings struct {
 	EnableDinner          bool   `json:"enable_dinner" gorm:"default:true"`
 	DiningDurationMinutes int    `json:"dining_duration_minutes" gorm:"default:120"` // tempo médio de permanência na mesa
 
+	// Permanência de grupos grandes: a partir de LargePartySize pessoas vale LargePartyDiningDurationMinutes (0 = desativado)
+	LargePartySize                  int `json:"large_party_size" gorm:"default:0"`
+	LargePartyDiningDurationMinutes int `json:"large_party_dining_duration_minutes" gorm:"default:0"`
+
+	// Comanda: taxa de serviço (10% padrão no Brasil; 0 = sem taxa)
+	ServiceChargePercent float64 `json:"service_charge_percent" gorm:"default:10"`
+
+	// Pix: chave e dados do recebedor para o BR Code; provedor "" gera apenas códigos estáticos
+	PixKey          string `json:"pix_key" gorm:"default:''"`
+	PixMerchantName string `json:"pix_merchant_name" gorm:"default:''"`
+	PixMerchantCity string `json:"pix_merchant_city" gorm:"default:''"`
+	PixProvider     string `json:"pix_provider" gorm:"default:''"`
+
+	// NFC-e: ambiente (1 produção, 2 homologação), série e número inicial, CSC do QR Code,
+	// certificado A1 (.pfx) e provedor de transmissão. Dados do emitente vêm da organização.
+	NfceEnabled             bool   `json:"nfce_enabled" gorm:"default:false"`
+	NfceEnvironment         int    `json:"nfce_environment" gorm:"default:2"`
+	NfceSeries              int    `json:"nfce_series" gorm:"default:1"`
+	NfceStartNumber         int    `json:"nfce_start_number" gorm:"default:1"` // primeiro número ao migrar de outro emissor
+	NfceCscId               string `json:"nfce_csc_id" gorm:"default:''"`
+	NfceCsc                 string `json:"nfce_csc,omitempty" gorm:"default:''"`                  // não é devolvido nas consultas
+	NfceCertificatePath     string `json:"nfce_certificate_path" gorm:"default:''"`
+	NfceCertificatePassword string `json:"nfce_certificate_password,omitempty" gorm:"default:''"` // não é devolvida nas consultas
+	NfceProvider            string `json:"nfce_provider" gorm:"default:'mock'"`
+	NfceQrCodeUrl           string `json:"nfce_qrcode_url" gorm:"default:''"` // vazio = URL da SEFAZ da UF do emitente
+	NfceConsultUrl          string `json:"nfce_consult_url" gorm:"default:''"`
+
+	// Pedidos via QR Code da mesa aguardam aprovação do garçom antes de ir para a cozinha
+	PublicOrderRequiresApproval bool `json:"public_order_requires_approval" gorm:"default:false"`
+
+	// Estoque: alerta para a equipe quando o produto atinge o limite (produto pode ter limite próprio)
+	LowStockThreshold int `json:"low_stock_threshold" gorm:"default:5"`
+
+	// SLA da cozinha: tolerância além do horário estimado e tempo máximo em cada status (0 = não monitora)
+	SlaGraceMinutes        int `json:"sla_grace_minutes" gorm:"default:5"`
+	SlaPendingMaxMinutes   int `json:"sla_pending_max_minutes" gorm:"default:15"`
+	SlaPreparingMaxMinutes int `json:"sla_preparing_max_minutes" gorm:"default:45"`
+	SlaReadyMaxMinutes     int `json:"sla_ready_max_minutes" gorm:"default:10"`
+
+	// Retirada e entrega: horários seguem o funcionamento (almoço/jantar) com intervalo próprio,
+	// limite de pedidos por horário (capacidade da cozinha; 0 = sem limite) e antecedência mínima
+	EnableTakeout            bool `json:"enable_takeout" gorm:"default:true"`
+	EnableDelivery           bool `json:"enable_delivery" gorm:"default:false"`
+	OrderSlotIntervalMinutes int  `json:"order_slot_interval_minutes" gorm:"default:15"`
+	OrderSlotCapacity        int  `json:"order_slot_capacity" gorm:"default:10"`
+	OrderLeadMinutes         int  `json:"order_lead_minutes" gorm:"default:30"`
+
 	// Agenda semanal de funcionamento (JSON)
 	// Formato: {"0":{"enabled":false,"enable_lunch":false,"enable_dinner":false},...}
 	// Chaves: 0=Domingo, 1=Segunda, ..., 6=Sábado
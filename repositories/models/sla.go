@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de violação de SLA da cozinha
+const (
+	SLABreachTypeLate  = "late"  // passou do horário estimado (mais a tolerância)
+	SLABreachTypeStuck = "stuck" // parado no mesmo status além do limite
+)
+
+// Turnos usados no relatório (janelas de almoço/jantar das configurações)
+const (
+	SLAShiftLunch  = "lunch"
+	SLAShiftDinner = "dinner"
+	SLAShiftOther  = "other"
+)
+
+// --- OrderSLABreach (violação de SLA registrada pelo monitor) ---
+type OrderSLABreach struct {
+	Id             uuid.UUID  `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID  `json:"organization_id" gorm:"index:idx_sla_breach_project"`
+	ProjectId      uuid.UUID  `json:"project_id" gorm:"index:idx_sla_breach_project"`
+	OrderId        uuid.UUID  `json:"order_id" gorm:"uniqueIndex:idx_sla_breach_order"`
+	Type           string     `json:"type" gorm:"uniqueIndex:idx_sla_breach_order"`         // "late", "stuck"
+	OrderStatus    string     `json:"order_status" gorm:"uniqueIndex:idx_sla_breach_order"` // status do pedido na detecção
+	ExpectedAt     time.Time  `json:"expected_at"`                                          // limite que foi ultrapassado
+	DetectedAt     time.Time  `json:"detected_at"`
+	DelayMinutes   int        `json:"delay_minutes"`         // atraso na detecção
+	Alerted        bool       `json:"alerted"`               // alerta enviado ao telefone responsável
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"` // pedido saiu do status / ficou pronto
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// SLAOnTime pedidos/itens no prazo de um grupo do relatório
+type SLAOnTime struct {
+	Key             string  `json:"key"`
+	Label           string  `json:"label"`
+	Total           int     `json:"total"`
+	OnTime          int     `json:"on_time"`
+	OnTimePct       float64 `json:"on_time_pct"`
+	AvgDelayMinutes float64 `json:"avg_delay_minutes"` // média dos atrasados
+}
+
+// SLAReport relatório de cumprimento do horário estimado no período
+type SLAReport struct {
+	From         time.Time      `json:"from"`
+	To           time.Time      `json:"to"`
+	GraceMinutes int            `json:"grace_minutes"`
+	Overall      SLAOnTime      `json:"overall"`
+	Stations     []SLAOnTime    `json:"stations"`
+	Shifts       []SLAOnTime    `json:"shifts"`
+	Products     []SLAOnTime    `json:"products"`
+	Breaches     map[string]int `json:"breaches"` // violações registradas por tipo
+}
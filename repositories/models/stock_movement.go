@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de movimentação de estoque
+const (
+	StockMovementOrderDeduction   = "order_deduction"   // baixa quando o pedido entra em preparo
+	StockMovementOrderRestore     = "order_restore"     // estorno no cancelamento/remoção de itens
+	StockMovementManualAdjustment = "manual_adjustment" // ajuste manual (contagem, correção)
+	StockMovementRestock          = "restock"           // entrada de mercadoria
+	StockMovementLoss             = "loss"              // perda/quebra
+	StockMovementOrderTransfer    = "order_transfer"    // baixa que acompanha itens transferidos entre pedidos (troca de mesa)
+)
+
+// --- StockMovement (livro de movimentações de estoque do produto) ---
+type StockMovement struct {
+	Id             uuid.UUID  `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID  `json:"organization_id"`
+	ProjectId      uuid.UUID  `json:"project_id"`
+	ProductId      uuid.UUID  `json:"product_id" gorm:"index"`
+	OrderId        *uuid.UUID `json:"order_id,omitempty" gorm:"index"`
+	Type           string     `json:"type"`
+	Quantity       int        `json:"quantity"` // positivo = entrada, negativo = saída
+	StockBefore    int        `json:"stock_before"`
+	StockAfter     int        `json:"stock_after"`
+	Reason         string     `json:"reason,omitempty"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty"` // nulo quando gerado pelo sistema
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// StockAdjustmentRequest ajuste manual de estoque
+type StockAdjustmentRequest struct {
+	Quantity int    `json:"quantity"` // variação (ex.: 12 entrada, -2 perda)
+	Type     string `json:"type"`     // "manual_adjustment" (padrão), "restock", "loss"
+	Reason   string `json:"reason"`
+}
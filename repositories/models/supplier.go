@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status do pedido de compra (draft -> sent -> received; cancelado antes do recebimento)
+const (
+	PurchaseOrderStatusDraft     = "draft"
+	PurchaseOrderStatusSent      = "sent"
+	PurchaseOrderStatusReceived  = "received"
+	PurchaseOrderStatusCancelled = "cancelled"
+)
+
+// --- Supplier (fornecedor de ingredientes) ---
+type Supplier struct {
+	Id             uuid.UUID  `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID  `json:"organization_id"`
+	ProjectId      uuid.UUID  `json:"project_id"`
+	Name           string     `json:"name"`
+	Document       string     `json:"document,omitempty"` // CNPJ/CPF
+	ContactName    string     `json:"contact_name,omitempty"`
+	Phone          string     `json:"phone,omitempty"`
+	Email          string     `json:"email,omitempty"`
+	Notes          string     `json:"notes,omitempty"`
+	Active         bool       `json:"active" gorm:"default:true"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+}
+
+// --- PurchaseOrder (pedido de compra ao fornecedor) ---
+type PurchaseOrder struct {
+	Id             uuid.UUID           `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID           `json:"organization_id"`
+	ProjectId      uuid.UUID           `json:"project_id"`
+	SupplierId     uuid.UUID           `json:"supplier_id" gorm:"index"`
+	Status         string              `json:"status" gorm:"default:'draft'"` // "draft", "sent", "received", "cancelled"
+	Notes          string              `json:"notes,omitempty"`
+	Total          float64             `json:"total"`
+	Items          []PurchaseOrderItem `json:"items" gorm:"foreignKey:PurchaseOrderId"`
+	ExpectedAt     *time.Time          `json:"expected_at,omitempty"` // previsão de entrega
+	SentAt         *time.Time          `json:"sent_at,omitempty"`
+	ReceivedAt     *time.Time          `json:"received_at,omitempty"`
+	CancelledAt    *time.Time          `json:"cancelled_at,omitempty"`
+	CreatedBy      *uuid.UUID          `json:"created_by,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+// --- PurchaseOrderItem (ingrediente comprado) ---
+type PurchaseOrderItem struct {
+	Id               uuid.UUID `gorm:"primaryKey" json:"id"`
+	PurchaseOrderId  uuid.UUID `json:"purchase_order_id" gorm:"index"`
+	IngredientId     uuid.UUID `json:"ingredient_id"`
+	Quantity         float64   `json:"quantity"`          // na unidade do ingrediente
+	UnitCost         float64   `json:"unit_cost"`         // custo por unidade negociado
+	Total            float64   `json:"total"`             // quantity * unit_cost
+	ReceivedQuantity float64   `json:"received_quantity"` // quantidade efetivamente recebida
+}
+
+// PurchaseOrderReceiveRequest quantidades recebidas por item (vazio = tudo conforme pedido)
+type PurchaseOrderReceiveRequest struct {
+	Items []struct {
+		ItemId   uuid.UUID `json:"item_id"`
+		Quantity float64   `json:"quantity"`
+	} `json:"items"`
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status da comanda
+const (
+	TabStatusOpen   = "open"
+	TabStatusClosed = "closed"
+)
+
+// Métodos de pagamento
+const (
+	PaymentMethodCash = "cash"
+	PaymentMethodCard = "card"
+	PaymentMethodPix  = "pix"
+)
+
+// --- Tab (comanda/conta de uma mesa) ---
+type Tab struct {
+	Id                   uuid.UUID  `gorm:"primaryKey" json:"id"`
+	OrganizationId       uuid.UUID  `json:"organization_id"`
+	ProjectId            uuid.UUID  `json:"project_id"`
+	TableId              uuid.UUID  `json:"table_id"`
+	CustomerId           *uuid.UUID `json:"customer_id,omitempty"`
+	Status               string     `json:"status" gorm:"default:'open'"` // "open", "closed"
+	Guests               int        `json:"guests"`                       // número de pessoas (divisão igualitária)
+	ServiceChargePercent float64    `json:"service_charge_percent"`       // taxa de serviço aplicada (padrão vem de Settings)
+	Subtotal             float64    `json:"subtotal"`                     // soma dos pedidos
+	ServiceCharge        float64    `json:"service_charge"`               // valor da taxa de serviço
+	Total                float64    `json:"total"`                        // subtotal + taxa de serviço
+	PaidTotal            float64    `json:"paid_total"`                   // soma dos pagamentos (sem gorjeta)
+	TipsTotal            float64    `json:"tips_total"`                   // soma das gorjetas
+	Note                 string     `json:"note,omitempty"`
+	OpenedAt             time.Time  `json:"opened_at"`
+	ClosedAt             *time.Time `json:"closed_at,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+	DeletedAt            *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Balance retorna o valor que ainda falta pagar
+func (t Tab) Balance() float64 {
+	return t.Total - t.PaidTotal
+}
+
+// --- TabPayment (pagamento parcial ou total de uma comanda) ---
+type TabPayment struct {
+	Id             uuid.UUID `gorm:"primaryKey" json:"id"`
+	OrganizationId uuid.UUID `json:"organization_id"`
+	ProjectId      uuid.UUID `json:"project_id"`
+	TabId          uuid.UUID `json:"tab_id" gorm:"index"`
+	Method         string    `json:"method"`                // "cash", "card", "pix"
+	Amount         float64   `json:"amount"`                // valor abatido da conta
+	Tip            float64   `json:"tip"`                   // gorjeta (fora da taxa de serviço)
+	ChangeGiven    float64   `json:"change_given"`          // troco (apenas dinheiro)
+	Reference      string    `json:"reference,omitempty"`   // NSU do cartão, txid do Pix, etc.
+	SplitLabel     string    `json:"split_label,omitempty"` // parte da divisão que este pagamento quita
+	PaidAt         time.Time `json:"paid_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TabSummary comanda com pedidos, pagamentos e saldo
+type TabSummary struct {
+	Tab      Tab          `json:"tab"`
+	Orders   []Order      `json:"orders"`
+	Payments []TabPayment `json:"payments"`
+	Balance  float64      `json:"balance"`
+}
+
+// TabSplitRequest pedido de divisão da conta
+type TabSplitRequest struct {
+	Mode   string          `json:"mode"`             // "even", "seat", "item"
+	Parts  int             `json:"parts,omitempty"`  // divisão igualitária
+	Groups []TabSplitGroup `json:"groups,omitempty"` // divisão por item
+}
+
+// TabSplitGroup itens atribuídos a uma parte (divisão por item)
+type TabSplitGroup struct {
+	Label   string      `json:"label"`
+	ItemIds []uuid.UUID `json:"item_ids"`
+}
+
+// TabSplitShare valor devido por uma parte da divisão
+type TabSplitShare struct {
+	Label         string  `json:"label"`
+	Subtotal      float64 `json:"subtotal"`
+	ServiceCharge float64 `json:"service_charge"`
+	Total         float64 `json:"total"`
+}
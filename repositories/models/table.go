@@ -14,9 +14,82 @@ type Table struct {
 	EnvironmentId  *uuid.UUID `json:"environment_id,omitempty"` // vinculação com ambiente
 	Number         int        `json:"number"`
 	Capacity       int        `json:"capacity"`
-	Location       string     `json:"location,omitempty"`            // descrição adicional da localização
-	Status         string     `json:"status" gorm:"default:'livre'"` // "livre", "ocupada", "reservada"
+	Location       string     `json:"location,omitempty"`                    // descrição adicional da localização
+	Status         string     `json:"status" gorm:"default:'livre'"`         // "livre", "ocupada", "reservada"
+	MergedIntoId   *uuid.UUID `json:"merged_into_id,omitempty" gorm:"index"` // mesa principal quando unida (uma conta só)
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
 	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
 }
+
+// TableTransferRequest troca de mesa: pedidos inteiros ou itens avulsos.
+// Sem pedidos e sem itens, todo o atendimento da mesa (pedidos abertos e comanda) é transferido.
+type TableTransferRequest struct {
+	ToTableId uuid.UUID            `json:"to_table_id"`
+	OrderIds  []uuid.UUID          `json:"order_ids,omitempty"`
+	Items     []TableTransferItems `json:"items,omitempty"`
+}
+
+// TableTransferItems itens de um pedido que vão para a outra mesa (em um pedido novo)
+type TableTransferItems struct {
+	OrderId uuid.UUID   `json:"order_id"`
+	ItemIds []uuid.UUID `json:"item_ids"`
+}
+
+// TableMergeRequest mesas unidas à mesa principal
+type TableMergeRequest struct {
+	TableIds []uuid.UUID `json:"table_ids"`
+}
+
+// TableSplitRequest mesas separadas da principal (vazio = todas)
+type TableSplitRequest struct {
+	TableIds []uuid.UUID `json:"table_ids,omitempty"`
+}
+
+// TableMove movimentação registrada na auditoria (pedido ou mesa)
+type TableMove struct {
+	OrderId         *uuid.UUID  `json:"order_id,omitempty"`
+	NewOrderId      *uuid.UUID  `json:"new_order_id,omitempty"` // pedido criado com os itens transferidos
+	ItemIds         []uuid.UUID `json:"item_ids,omitempty"`
+	FromTableId     uuid.UUID   `json:"from_table_id"`
+	FromTableNumber int         `json:"from_table_number"`
+	ToTableId       uuid.UUID   `json:"to_table_id"`
+	ToTableNumber   int         `json:"to_table_number"`
+	Amount          float64     `json:"amount"`
+}
+
+// TableServiceResult atendimento da mesa após troca, união ou separação
+type TableServiceResult struct {
+	Table            Table       `json:"table"`
+	MergedTables     []Table     `json:"merged_tables,omitempty"`
+	CombinedCapacity int         `json:"combined_capacity"`
+	Tab              *Tab        `json:"tab,omitempty"`
+	Moves            []TableMove `json:"moves"`
+}
+
+// TableServiceChange alterações de mesas, comandas, pedidos e estoque gravadas na mesma transação
+type TableServiceChange struct {
+	Tables         []Table
+	NewTabs        []Tab
+	Tabs           []Tab
+	TabMerges      []TabMerge           // comandas absorvidas por outra (pedidos e pagamentos)
+	Orders         []Order              // pedidos que mudaram de mesa ou de comanda
+	SplitOrders    []Order              // pedidos que cederam itens (itens, total e status)
+	NewOrders      []Order              // pedidos criados com itens transferidos
+	History        []OrderStatusHistory // histórico dos pedidos criados/avançados
+	StockTransfers []OrderStockTransfer // baixa de estoque que acompanha os itens transferidos
+}
+
+// TabMerge comanda absorvida por outra
+type TabMerge struct {
+	FromTabId uuid.UUID
+	ToTabId   uuid.UUID
+}
+
+// OrderStockTransfer quantidade já baixada que passa de um pedido para outro
+type OrderStockTransfer struct {
+	FromOrderId uuid.UUID
+	ToOrderId   uuid.UUID
+	ProductId   uuid.UUID
+	Quantity    int
+}
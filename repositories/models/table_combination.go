@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// TableCombination mesas que podem ser juntadas para um grupo maior.
+// Capacity é a capacidade da montagem (juntar mesas costuma perder lugares nas emendas).
+type TableCombination struct {
+	Id             uuid.UUID      `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrganizationId uuid.UUID      `json:"organization_id"`
+	ProjectId      uuid.UUID      `json:"project_id"`
+	Name           string         `json:"name,omitempty"` // ex: "Mesas 4+5"
+	TableIds       pq.StringArray `json:"table_ids" gorm:"type:text[]"`
+	Capacity       int            `json:"capacity"`
+	MinPartySize   int            `json:"min_party_size,omitempty"` // grupos menores não ocupam a montagem
+	Active         bool           `json:"active" gorm:"default:true"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      *time.Time     `json:"deleted_at,omitempty"`
+}
+
+// TableAllocation mesa(s) escolhida(s) para uma reserva
+type TableAllocation struct {
+	TableIds      []uuid.UUID `json:"table_ids"`
+	TableNumbers  []int       `json:"table_numbers"`
+	EnvironmentId *uuid.UUID  `json:"environment_id,omitempty"`
+	CombinationId *uuid.UUID  `json:"combination_id,omitempty"`
+	Capacity      int         `json:"capacity"`
+	WastedSeats   int         `json:"wasted_seats"`
+}
+
+// TableReoptimizeRequest redistribuição das mesas de um serviço (almoço ou jantar do dia)
+type TableReoptimizeRequest struct {
+	Date    string `json:"date"`    // YYYY-MM-DD (local do projeto)
+	Service string `json:"service"` // "lunch", "dinner" ou vazio (dia inteiro)
+	DryRun  bool   `json:"dry_run"` // apenas calcula, sem gravar
+}
+
+// TableReassignment mudança de mesa proposta/aplicada para uma reserva
+type TableReassignment struct {
+	ReservationId uuid.UUID        `json:"reservation_id"`
+	Datetime      string           `json:"datetime"`
+	PartySize     int              `json:"party_size"`
+	FromTableIds  []uuid.UUID      `json:"from_table_ids"`
+	To            *TableAllocation `json:"to,omitempty"` // nil = sem mesa no novo plano
+}
+
+// TableReoptimizeResult resultado da redistribuição
+type TableReoptimizeResult struct {
+	Date              string              `json:"date"`
+	Service           string              `json:"service,omitempty"`
+	Reservations      int                 `json:"reservations"`
+	Fixed             int                 `json:"fixed"` // reservas com mesa escolhida pela equipe (não mudam)
+	WastedSeatsBefore int                 `json:"wasted_seats_before"`
+	WastedSeatsAfter  int                 `json:"wasted_seats_after"`
+	Unassigned        []uuid.UUID         `json:"unassigned"` // reservas que ficariam sem mesa
+	Changes           []TableReassignment `json:"changes"`
+	Applied           bool                `json:"applied"`
+}
+
+// ReservationTimeSuggestion horário próximo com mesa livre, sugerido quando a reserva conflita
+type ReservationTimeSuggestion struct {
+	Time     string `json:"time"`     // HH:MM local
+	Datetime string `json:"datetime"` // RFC3339 local do projeto
+}
@@ -15,8 +15,10 @@ type IOrderRepository interface {
 	GetOrderById(id string) (*models.Order, error)
 	ListOrders(OrganizationId, projectId string) ([]models.Order, error)
 	CreateOrder(order *models.Order) error
+	CreateOrderWithHistory(order *models.Order, entry *models.OrderStatusHistory) error
 	UpdateOrder(order *models.Order) error
 	SoftDeleteOrder(id string) error
+	GetOrderByTrackingToken(token string) (*models.Order, error)
 }
 
 func NewConnOrder(db *gorm.DB) IOrderRepository {
@@ -27,6 +29,16 @@ func (r *OrderRepository) CreateOrder(order *models.Order) error {
 	return r.db.Create(order).Error
 }
 
+// CreateOrderWithHistory grava o pedido e a entrada inicial do histórico na mesma transação
+func (r *OrderRepository) CreateOrderWithHistory(order *models.Order, entry *models.OrderStatusHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
+
 func (r *OrderRepository) GetOrderById(id string) (*models.Order, error) {
 	var order models.Order
 	err := r.db.First(&order, "id = ? AND deleted_at IS NULL", id).Error
@@ -49,3 +61,13 @@ func (r *OrderRepository) UpdateOrder(order *models.Order) error {
 func (r *OrderRepository) SoftDeleteOrder(id string) error {
 	return r.db.Model(&models.Order{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error
 }
+
+// GetOrderByTrackingToken busca pedido público pelo token de acompanhamento
+func (r *OrderRepository) GetOrderByTrackingToken(token string) (*models.Order, error) {
+	var order models.Order
+	err := r.db.First(&order, "tracking_token = ? AND deleted_at IS NULL", token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"lep/repositories/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OrderStatusHistoryRepository struct {
+	db *gorm.DB
+}
+
+type IOrderStatusHistoryRepository interface {
+	CreateEntry(entry *models.OrderStatusHistory) error
+	ListByOrder(orderId uuid.UUID) ([]models.OrderStatusHistory, error)
+	ListLatestByOrders(orderIds []uuid.UUID) ([]models.OrderStatusHistory, error)
+}
+
+func NewOrderStatusHistoryRepository(db *gorm.DB) IOrderStatusHistoryRepository {
+	return &OrderStatusHistoryRepository{db: db}
+}
+
+func (r *OrderStatusHistoryRepository) CreateEntry(entry *models.OrderStatusHistory) error {
+	return r.db.Create(entry).Error
+}
+
+// ListByOrder lista as transições do pedido em ordem cronológica
+func (r *OrderStatusHistoryRepository) ListByOrder(orderId uuid.UUID) ([]models.OrderStatusHistory, error) {
+	var entries []models.OrderStatusHistory
+	err := r.db.Where("order_id = ?", orderId).Order("changed_at ASC").Find(&entries).Error
+	return entries, err
+}
+
+// ListLatestByOrders retorna a última transição de cada pedido informado
+func (r *OrderStatusHistoryRepository) ListLatestByOrders(orderIds []uuid.UUID) ([]models.OrderStatusHistory, error) {
+	var entries []models.OrderStatusHistory
+	if len(orderIds) == 0 {
+		return entries, nil
+	}
+	err := r.db.Raw(`SELECT DISTINCT ON (order_id) * FROM order_status_history
+		WHERE order_id IN ? ORDER BY order_id, changed_at DESC`, orderIds).
+		Scan(&entries).Error
+	return entries, err
+}
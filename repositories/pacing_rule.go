@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type IPacingRuleRepository interface {
+	CreateRule(rule *models.PacingRule) error
+	GetRuleById(id uuid.UUID) (*models.PacingRule, error)
+	ListRules(orgId, projectId uuid.UUID) ([]models.PacingRule, error)
+	ListActiveRules(orgId, projectId uuid.UUID) ([]models.PacingRule, error)
+	UpdateRule(rule *models.PacingRule) error
+	SoftDeleteRule(id uuid.UUID) error
+}
+
+type PacingRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewPacingRuleRepository(db *gorm.DB) IPacingRuleRepository {
+	return &PacingRuleRepository{db: db}
+}
+
+func (r *PacingRuleRepository) CreateRule(rule *models.PacingRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *PacingRuleRepository) GetRuleById(id uuid.UUID) (*models.PacingRule, error) {
+	var rule models.PacingRule
+	err := r.db.First(&rule, "id = ? AND deleted_at IS NULL", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *PacingRuleRepository) ListRules(orgId, projectId uuid.UUID) ([]models.PacingRule, error) {
+	var rules []models.PacingRule
+	err := r.db.Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId).
+		Order("created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *PacingRuleRepository) ListActiveRules(orgId, projectId uuid.UUID) ([]models.PacingRule, error) {
+	var rules []models.PacingRule
+	err := r.db.Where("organization_id = ? AND project_id = ? AND active = ? AND deleted_at IS NULL", orgId, projectId, true).
+		Order("created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *PacingRuleRepository) UpdateRule(rule *models.PacingRule) error {
+	rule.UpdatedAt = time.Now()
+	return r.db.Save(rule).Error
+}
+
+func (r *PacingRuleRepository) SoftDeleteRule(id uuid.UUID) error {
+	return r.db.Model(&models.PacingRule{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error
+}
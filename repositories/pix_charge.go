@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PixChargeRepository struct {
+	db *gorm.DB
+}
+
+type IPixChargeRepository interface {
+	CreateCharge(charge *models.PixCharge) error
+	GetChargeById(id uuid.UUID) (*models.PixCharge, error)
+	GetChargeByTxId(txId string) (*models.PixCharge, error)
+	ListCharges(orgId, projectId uuid.UUID) ([]models.PixCharge, error)
+	UpdateCharge(charge *models.PixCharge) error
+	MarkChargePaid(charge *models.PixCharge) (bool, error)
+}
+
+func NewPixChargeRepository(db *gorm.DB) IPixChargeRepository {
+	return &PixChargeRepository{db: db}
+}
+
+// CreateCharge cria cobrança Pix
+func (r *PixChargeRepository) CreateCharge(charge *models.PixCharge) error {
+	return r.db.Create(charge).Error
+}
+
+// GetChargeById busca cobrança por ID
+func (r *PixChargeRepository) GetChargeById(id uuid.UUID) (*models.PixCharge, error) {
+	var charge models.PixCharge
+	err := r.db.First(&charge, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &charge, nil
+}
+
+// GetChargeByTxId busca cobrança pelo txid informado no BR Code
+func (r *PixChargeRepository) GetChargeByTxId(txId string) (*models.PixCharge, error) {
+	var charge models.PixCharge
+	err := r.db.First(&charge, "tx_id = ?", txId).Error
+	if err != nil {
+		return nil, err
+	}
+	return &charge, nil
+}
+
+// ListCharges lista cobranças do projeto (mais recentes primeiro)
+func (r *PixChargeRepository) ListCharges(orgId, projectId uuid.UUID) ([]models.PixCharge, error) {
+	var charges []models.PixCharge
+	err := r.db.Where("organization_id = ? AND project_id = ?", orgId, projectId).
+		Order("created_at DESC").Find(&charges).Error
+	return charges, err
+}
+
+// UpdateCharge atualiza cobrança
+func (r *PixChargeRepository) UpdateCharge(charge *models.PixCharge) error {
+	charge.UpdatedAt = time.Now()
+	return r.db.Save(charge).Error
+}
+
+// MarkChargePaid marca a cobrança como paga apenas se ainda estiver pendente.
+// Retorna false quando outra confirmação já foi processada (webhook duplicado).
+func (r *PixChargeRepository) MarkChargePaid(charge *models.PixCharge) (bool, error) {
+	result := r.db.Model(&models.PixCharge{}).
+		Where("id = ? AND status = ?", charge.Id, models.PixChargeStatusPending).
+		Updates(map[string]interface{}{
+			"status":        models.PixChargeStatusPaid,
+			"end_to_end_id": charge.EndToEndId,
+			"paid_amount":   charge.PaidAmount,
+			"paid_at":       charge.PaidAt,
+			"updated_at":    time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PosSyncRepository struct {
+	db *gorm.DB
+}
+
+type IPosSyncRepository interface {
+	CreateRun(run *models.PosSyncRun) error
+	UpdateRun(run *models.PosSyncRun) error
+	GetRunById(id uuid.UUID) (*models.PosSyncRun, error)
+	ListRuns(orgId, projectId uuid.UUID, direction string, limit int) ([]models.PosSyncRun, error)
+	GetLastExport(orgId, projectId uuid.UUID) (*models.PosSyncRun, error)
+	ListDeliveredOrders(orgId, projectId uuid.UUID, from, to time.Time) ([]models.Order, error)
+}
+
+func NewPosSyncRepository(db *gorm.DB) IPosSyncRepository {
+	return &PosSyncRepository{db: db}
+}
+
+// CreateRun registra o início da sincronização
+func (r *PosSyncRepository) CreateRun(run *models.PosSyncRun) error {
+	return r.db.Create(run).Error
+}
+
+// UpdateRun grava o resultado da sincronização
+func (r *PosSyncRepository) UpdateRun(run *models.PosSyncRun) error {
+	return r.db.Save(run).Error
+}
+
+// GetRunById busca execução por ID
+func (r *PosSyncRepository) GetRunById(id uuid.UUID) (*models.PosSyncRun, error) {
+	var run models.PosSyncRun
+	err := r.db.First(&run, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// ListRuns lista execuções do projeto, mais recentes primeiro
+func (r *PosSyncRepository) ListRuns(orgId, projectId uuid.UUID, direction string, limit int) ([]models.PosSyncRun, error) {
+	var runs []models.PosSyncRun
+	query := r.db.Where("organization_id = ? AND project_id = ?", orgId, projectId)
+	if direction != "" {
+		query = query.Where("direction = ?", direction)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Order("started_at DESC").Find(&runs).Error
+	return runs, err
+}
+
+// GetLastExport última exportação de vendas concluída (nil se não houver)
+func (r *PosSyncRepository) GetLastExport(orgId, projectId uuid.UUID) (*models.PosSyncRun, error) {
+	var runs []models.PosSyncRun
+	err := r.db.Where("organization_id = ? AND project_id = ? AND direction = ? AND status IN ? AND period_end IS NOT NULL",
+		orgId, projectId, models.PosSyncExportSales, []string{models.PosSyncStatusCompleted, models.PosSyncStatusPartial}).
+		Order("period_end DESC").Limit(1).Find(&runs).Error
+	if err != nil || len(runs) == 0 {
+		return nil, err
+	}
+	return &runs[0], nil
+}
+
+// ListDeliveredOrders pedidos entregues no período [from, to)
+func (r *PosSyncRepository) ListDeliveredOrders(orgId, projectId uuid.UUID, from, to time.Time) ([]models.Order, error) {
+	var orders []models.Order
+	err := r.db.Where("organization_id = ? AND project_id = ? AND status = ? AND deleted_at IS NULL", orgId, projectId, models.OrderStatusDelivered).
+		Where("delivered_at >= ? AND delivered_at < ?", from, to).
+		Order("delivered_at ASC").Find(&orders).Error
+	return orders, err
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PrepTimeRepository struct {
+	db *gorm.DB
+}
+
+type IPrepTimeRepository interface {
+	ListProjectsWithFinishedOrders(since time.Time) ([]models.ProjectRef, error)
+	ListFinishedOrders(orgId, projectId uuid.UUID, from, to time.Time) ([]models.Order, error)
+	ReplaceStats(orgId, projectId uuid.UUID, prepStats []models.PrepTimeStat, queueStats []models.QueueTimeStat) error
+	ListPrepStats(orgId, projectId uuid.UUID) ([]models.PrepTimeStat, error)
+	ListPrepStatsByProducts(orgId, projectId uuid.UUID, productIds []uuid.UUID) ([]models.PrepTimeStat, error)
+	ListQueueStats(orgId, projectId uuid.UUID) ([]models.QueueTimeStat, error)
+}
+
+func NewPrepTimeRepository(db *gorm.DB) IPrepTimeRepository {
+	return &PrepTimeRepository{db: db}
+}
+
+// ListProjectsWithFinishedOrders lista os projetos com pedidos prontos desde a data
+func (r *PrepTimeRepository) ListProjectsWithFinishedOrders(since time.Time) ([]models.ProjectRef, error) {
+	var refs []models.ProjectRef
+	err := r.db.Model(&models.Order{}).
+		Distinct("organization_id", "project_id").
+		Where("ready_at >= ? AND deleted_at IS NULL", since).
+		Scan(&refs).Error
+	return refs, err
+}
+
+// ListFinishedOrders lista pedidos que começaram a ser preparados no período e já ficaram prontos
+func (r *PrepTimeRepository) ListFinishedOrders(orgId, projectId uuid.UUID, from, to time.Time) ([]models.Order, error) {
+	var orders []models.Order
+	err := r.db.Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId).
+		Where("status IN ?", []string{models.OrderStatusReady, models.OrderStatusDelivered}).
+		Where("started_at >= ? AND started_at < ? AND ready_at IS NOT NULL", from, to).
+		Order("started_at ASC").
+		Find(&orders).Error
+	return orders, err
+}
+
+// ReplaceStats substitui as estatísticas do projeto pelas recém-calculadas
+func (r *PrepTimeRepository) ReplaceStats(orgId, projectId uuid.UUID, prepStats []models.PrepTimeStat, queueStats []models.QueueTimeStat) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("organization_id = ? AND project_id = ?", orgId, projectId).
+			Delete(&models.PrepTimeStat{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ? AND project_id = ?", orgId, projectId).
+			Delete(&models.QueueTimeStat{}).Error; err != nil {
+			return err
+		}
+		if len(prepStats) > 0 {
+			if err := tx.CreateInBatches(prepStats, 200).Error; err != nil {
+				return err
+			}
+		}
+		if len(queueStats) > 0 {
+			if err := tx.Create(&queueStats).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListPrepStats lista estatísticas de preparo do projeto
+func (r *PrepTimeRepository) ListPrepStats(orgId, projectId uuid.UUID) ([]models.PrepTimeStat, error) {
+	var stats []models.PrepTimeStat
+	err := r.db.Where("organization_id = ? AND project_id = ?", orgId, projectId).
+		Order("product_id ASC, hour_of_day ASC").
+		Find(&stats).Error
+	return stats, err
+}
+
+// ListPrepStatsByProducts lista estatísticas de preparo dos produtos informados
+func (r *PrepTimeRepository) ListPrepStatsByProducts(orgId, projectId uuid.UUID, productIds []uuid.UUID) ([]models.PrepTimeStat, error) {
+	var stats []models.PrepTimeStat
+	if len(productIds) == 0 {
+		return stats, nil
+	}
+	err := r.db.Where("organization_id = ? AND project_id = ? AND product_id IN ?", orgId, projectId, productIds).
+		Find(&stats).Error
+	return stats, err
+}
+
+// ListQueueStats lista estatísticas de espera na fila do projeto
+func (r *PrepTimeRepository) ListQueueStats(orgId, projectId uuid.UUID) ([]models.QueueTimeStat, error) {
+	var stats []models.QueueTimeStat
+	err := r.db.Where("organization_id = ? AND project_id = ?", orgId, projectId).
+		Order("queue_depth ASC").
+		Find(&stats).Error
+	return stats, err
+}
@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PrinterRepository struct {
+	db *gorm.DB
+}
+
+type IPrinterRepository interface {
+	CreatePrinter(printer *models.Printer) error
+	GetPrinterById(id uuid.UUID) (*models.Printer, error)
+	ListPrinters(orgId, projectId uuid.UUID) ([]models.Printer, error)
+	UpdatePrinter(printer *models.Printer) error
+	SoftDeletePrinter(id uuid.UUID) error
+	CreateJob(job *models.PrintJob) error
+	GetJobById(id uuid.UUID) (*models.PrintJob, error)
+	ListJobs(orgId, projectId uuid.UUID, status string, limit int) ([]models.PrintJob, error)
+	ListDueJobs(now time.Time, stuckBefore time.Time, limit int) ([]models.PrintJob, error)
+	ListAgentDueJobs(orgId, projectId uuid.UUID, now time.Time, stuckBefore time.Time, limit int) ([]models.PrintJob, error)
+	ClaimJob(id uuid.UUID, now time.Time, stuckBefore time.Time) (bool, error)
+	UpdateJob(job *models.PrintJob) error
+	GetAgentByProject(orgId, projectId uuid.UUID) (*models.PrintAgent, error)
+	GetAgentByTokenHash(tokenHash string) (*models.PrintAgent, error)
+	SaveAgent(agent *models.PrintAgent) error
+	TouchAgent(id uuid.UUID, seenAt time.Time) error
+}
+
+func NewPrinterRepository(db *gorm.DB) IPrinterRepository {
+	return &PrinterRepository{db: db}
+}
+
+// CreatePrinter cadastra impressora
+func (r *PrinterRepository) CreatePrinter(printer *models.Printer) error {
+	return r.db.Create(printer).Error
+}
+
+// GetPrinterById busca impressora por ID
+func (r *PrinterRepository) GetPrinterById(id uuid.UUID) (*models.Printer, error) {
+	var printer models.Printer
+	err := r.db.First(&printer, "id = ? AND deleted_at IS NULL", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &printer, nil
+}
+
+// ListPrinters lista impressoras do projeto
+func (r *PrinterRepository) ListPrinters(orgId, projectId uuid.UUID) ([]models.Printer, error) {
+	var printers []models.Printer
+	err := r.db.Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId).
+		Order("name ASC").
+		Find(&printers).Error
+	return printers, err
+}
+
+// UpdatePrinter atualiza impressora
+func (r *PrinterRepository) UpdatePrinter(printer *models.Printer) error {
+	return r.db.Save(printer).Error
+}
+
+// SoftDeletePrinter exclui impressora
+func (r *PrinterRepository) SoftDeletePrinter(id uuid.UUID) error {
+	return r.db.Model(&models.Printer{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error
+}
+
+// CreateJob coloca trabalho na fila de impressão
+func (r *PrinterRepository) CreateJob(job *models.PrintJob) error {
+	return r.db.Create(job).Error
+}
+
+// GetJobById busca trabalho de impressão por ID
+func (r *PrinterRepository) GetJobById(id uuid.UUID) (*models.PrintJob, error) {
+	var job models.PrintJob
+	err := r.db.First(&job, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListJobs lista trabalhos de impressão do projeto, mais recentes primeiro
+func (r *PrinterRepository) ListJobs(orgId, projectId uuid.UUID, status string, limit int) ([]models.PrintJob, error) {
+	var jobs []models.PrintJob
+	query := r.db.Where("organization_id = ? AND project_id = ?", orgId, projectId)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Order("created_at DESC").Find(&jobs).Error
+	return jobs, err
+}
+
+// ListDueJobs lista trabalhos de impressoras diretas prontos para nova tentativa (inclui os presos em "printing" desde stuckBefore)
+func (r *PrinterRepository) ListDueJobs(now time.Time, stuckBefore time.Time, limit int) ([]models.PrintJob, error) {
+	var jobs []models.PrintJob
+	err := r.dueJobs(now, stuckBefore).
+		Where("printers.delivery = ?", models.PrinterDeliveryDirect).
+		Order("print_jobs.next_attempt_at ASC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// ListAgentDueJobs lista trabalhos prontos das impressoras do projeto atendidas pelo agente
+func (r *PrinterRepository) ListAgentDueJobs(orgId, projectId uuid.UUID, now time.Time, stuckBefore time.Time, limit int) ([]models.PrintJob, error) {
+	var jobs []models.PrintJob
+	err := r.dueJobs(now, stuckBefore).
+		Where("print_jobs.organization_id = ? AND print_jobs.project_id = ?", orgId, projectId).
+		Where("printers.delivery = ?", models.PrinterDeliveryAgent).
+		Order("print_jobs.next_attempt_at ASC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// dueJobs trabalhos pendentes cujo horário chegou ou presos em "printing", com a impressora para filtrar a entrega
+func (r *PrinterRepository) dueJobs(now time.Time, stuckBefore time.Time) *gorm.DB {
+	return r.db.Model(&models.PrintJob{}).
+		Joins("JOIN printers ON printers.id = print_jobs.printer_id").
+		Where("(print_jobs.status = ? AND print_jobs.next_attempt_at <= ?) OR (print_jobs.status = ? AND print_jobs.updated_at < ?)",
+			models.PrintJobStatusPending, now, models.PrintJobStatusPrinting, stuckBefore)
+}
+
+// ClaimJob marca o trabalho como "printing" se ainda estiver disponível.
+// Retorna false quando outro processo já o pegou.
+func (r *PrinterRepository) ClaimJob(id uuid.UUID, now time.Time, stuckBefore time.Time) (bool, error) {
+	result := r.db.Model(&models.PrintJob{}).
+		Where("id = ?", id).
+		Where("(status = ? AND next_attempt_at <= ?) OR (status = ? AND updated_at < ?)",
+			models.PrintJobStatusPending, now, models.PrintJobStatusPrinting, stuckBefore).
+		Updates(map[string]interface{}{"status": models.PrintJobStatusPrinting, "updated_at": now})
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdateJob grava o resultado da tentativa
+func (r *PrinterRepository) UpdateJob(job *models.PrintJob) error {
+	return r.db.Save(job).Error
+}
+
+// GetAgentByProject busca o agente de impressão do projeto
+func (r *PrinterRepository) GetAgentByProject(orgId, projectId uuid.UUID) (*models.PrintAgent, error) {
+	var agent models.PrintAgent
+	err := r.db.First(&agent, "organization_id = ? AND project_id = ?", orgId, projectId).Error
+	if err != nil {
+		return nil, err
+	}
+	return &agent, nil
+}
+
+// GetAgentByTokenHash busca o agente pelo hash do token apresentado
+func (r *PrinterRepository) GetAgentByTokenHash(tokenHash string) (*models.PrintAgent, error) {
+	var agent models.PrintAgent
+	err := r.db.First(&agent, "token_hash = ?", tokenHash).Error
+	if err != nil {
+		return nil, err
+	}
+	return &agent, nil
+}
+
+// SaveAgent cria ou atualiza o agente (novo token invalida o anterior)
+func (r *PrinterRepository) SaveAgent(agent *models.PrintAgent) error {
+	return r.db.Save(agent).Error
+}
+
+// TouchAgent registra a última consulta do agente
+func (r *PrinterRepository) TouchAgent(id uuid.UUID, seenAt time.Time) error {
+	return r.db.Model(&models.PrintAgent{}).Where("id = ?", id).Update("last_seen_at", seenAt).Error
+}
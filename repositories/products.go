@@ -16,6 +16,7 @@ type IProductRepository interface {
 	GetProduct(id int) (*models.Product, error)
 	GetProductById(id uuid.UUID) (*models.Product, error)
 	GetProductsByIds(ids []uuid.UUID) ([]models.Product, error)
+	GetProductsByPDVCodes(orgId, projectId uuid.UUID, codes []string) ([]models.Product, error)
 	GetProductByPurchase(id string) ([]models.Product, error)
 	ListProducts(OrganizationId, projectId uuid.UUID) ([]models.Product, error)
 	ListProductsWithTags(organizationId, projectId uuid.UUID) ([]models.Product, error)
@@ -25,6 +26,7 @@ type IProductRepository interface {
 	UpdateProduct(product *models.Product) error
 	UpdateProductOrder(id uuid.UUID, order int) error
 	UpdateProductStatus(id uuid.UUID, active bool) error
+	UpdateProductCatalog(product *models.Product) error
 	DeleteProduct(id int) error
 	DeleteProductsByPurchase(purchaseId string) error
 	SoftDeleteProduct(id uuid.UUID) error
@@ -138,8 +140,9 @@ func (r *resourceProduct) CreateProduct(product *models.Product) error {
 	return r.db.Create(product).Error
 }
 
+// UpdateProduct grava o cadastro sem tocar no estoque: estoque só muda pelo livro (AdjustStock e pedidos)
 func (r *resourceProduct) UpdateProduct(product *models.Product) error {
-	return r.db.Save(product).Error
+	return r.db.Omit("stock", "out_of_stock").Save(product).Error
 }
 
 func (r *resourceProduct) GetProduct(id int) (*models.Product, error) {
@@ -181,6 +184,14 @@ func (r *resourceProduct) GetProductsByIds(ids []uuid.UUID) ([]models.Product, e
 	return products, err
 }
 
+// GetProductsByPDVCodes busca produtos do projeto pelo código de integração (PDV / marketplace)
+func (r *resourceProduct) GetProductsByPDVCodes(orgId, projectId uuid.UUID, codes []string) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Where("organization_id = ? AND project_id = ? AND pdv_code IN ? AND deleted_at IS NULL", orgId, projectId, codes).
+		Find(&products).Error
+	return products, err
+}
+
 // AddTagToProduct adiciona uma tag a um produto
 func (r *resourceProduct) AddTagToProduct(productId, tagId uuid.UUID) error {
 	productTag := models.ProductTag{
@@ -228,6 +239,22 @@ func (r *resourceProduct) UpdateProductStatus(id uuid.UUID, active bool) error {
 	return r.db.Model(&models.Product{}).Where("id = ?", id).Update("active", active).Error
 }
 
+// UpdateProductCatalog grava só os dados de cardápio vindos do PDV (nome, descrição, tipo, categoria,
+// status, código e preço), sem sobrescrever as demais colunas alteradas em paralelo
+func (r *resourceProduct) UpdateProductCatalog(product *models.Product) error {
+	return r.db.Model(&models.Product{}).Where("id = ?", product.Id).
+		Updates(map[string]interface{}{
+			"name":         product.Name,
+			"description":  product.Description,
+			"type":         product.Type,
+			"category_id":  product.CategoryId,
+			"active":       product.Active,
+			"pdv_code":     product.PDVCode,
+			"price_normal": product.PriceNormal,
+			"updated_at":   product.UpdatedAt,
+		}).Error
+}
+
 // GetProductsByType retorna produtos filtrados por tipo (prato, bebida, vinho)
 func (r *resourceProduct) GetProductsByType(organizationId, projectId uuid.UUID, productType string) ([]models.Product, error) {
 	var products []models.Product
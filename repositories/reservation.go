@@ -1,14 +1,21 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
 	"lep/repositories/models"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// activeReservationStatuses status em que a reserva ocupa a mesa
+var activeReservationStatuses = []string{"confirmed", "pending"}
+
 type IReservationRepository interface {
 	CreateReservation(reservation *models.Reservation) error
 	GetReservationById(id uuid.UUID) (*models.Reservation, error)
@@ -18,10 +25,18 @@ type IReservationRepository interface {
 	IsReservationTableAvailable(tableId uuid.UUID, dt time.Time, durationMinutes int) (bool, error)
 	GetReservationsByProject(orgId, projectId uuid.UUID) ([]models.Reservation, error)
 	GetReservationsByTableAndDateRange(tableId uuid.UUID, startDate, endDate time.Time) ([]models.Reservation, error)
+	GetReservationsInRange(orgId, projectId uuid.UUID, start, end time.Time) ([]models.Reservation, error)
 	DeleteReservation(id uuid.UUID) error
 	GetPendingConfirmationReservation(orgId, projectId, customerId uuid.UUID) (*models.Reservation, error)
+	UpdateReservationTables(reservations []models.Reservation, defaultDurationMinutes int) error
+	CreateReservationExclusive(reservation *models.Reservation, defaultDurationMinutes int, pacing PacingCheck) error
+	UpdateReservationExclusive(reservation *models.Reservation, defaultDurationMinutes int, pacing PacingCheck) error
 }
 
+// PacingCheck confere os limites de ritmo da reserva como ficará (current nil na criação).
+// Roda dentro da transação da reserva e lê as reservas por reservations, ligado a ela.
+type PacingCheck func(reservations IReservationRepository, current *models.Reservation, reservation models.Reservation) error
+
 type ReservationRepository struct {
 	db *gorm.DB
 }
@@ -60,43 +75,37 @@ func (r *ReservationRepository) SoftDeleteReservation(id uuid.UUID) error {
 	return r.db.Model(&models.Reservation{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error
 }
 
-// IsReservationTableAvailable verifica se uma mesa está disponível para uma nova reserva em dt.
-// Considera dois cenários de bloqueio:
-// 1. Reservas futuras: existe reserva no intervalo [dt, dt+diningDurationMinutes] → outra pessoa chega logo depois
-// 2. Reservas em andamento: existe reserva ativa iniciada hoje antes de dt → grupo ainda pode estar na mesa
+// IsReservationTableAvailable verifica se uma mesa está livre para uma reserva de diningDurationMinutes a partir de dt:
+// nenhuma reserva ativa na mesa pode ter permanência sobreposta a [dt, dt+diningDurationMinutes).
+// Reservas sem duração gravada usam diningDurationMinutes.
 func (r *ReservationRepository) IsReservationTableAvailable(tableId uuid.UUID, dt time.Time, diningDurationMinutes int) (bool, error) {
-	dtStr := dt.Format(time.RFC3339)
-	futureEnd := dt.Add(time.Duration(diningDurationMinutes) * time.Minute).Format(time.RFC3339)
-	// Início do dia de dt para verificar reservas em andamento do mesmo dia
-	dayStart := time.Date(dt.Year(), dt.Month(), dt.Day(), 0, 0, 0, 0, dt.Location()).Format(time.RFC3339)
-
-	var count int64
-	err := r.db.Model(&models.Reservation{}).
-		Where(`table_id = ? AND deleted_at IS NULL AND status IN ? AND (
-			(datetime >= ? AND datetime <= ?) OR
-			(datetime >= ? AND datetime < ?)
-		)`,
-			tableId, []string{"confirmed", "pending"},
-			dtStr, futureEnd,
-			dayStart, dtStr,
-		).Count(&count).Error
-	return count == 0, err
+	end := dt.Add(time.Duration(diningDurationMinutes) * time.Minute)
+	conflict, err := tablesConflict(r.db, []string{tableId.String()}, dt, end, diningDurationMinutes, []uuid.UUID{uuid.Nil})
+	return !conflict, err
 }
 
 func (r *ReservationRepository) GetReservationsByProject(orgId, projectId uuid.UUID) ([]models.Reservation, error) {
 	var reservations []models.Reservation
 	err := r.db.Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId).
-		Order("datetime ASC").Find(&reservations).Error
+		Order("starts_at ASC").Find(&reservations).Error
 	return reservations, err
 }
 
 func (r *ReservationRepository) GetReservationsByTableAndDateRange(tableId uuid.UUID, startDate, endDate time.Time) ([]models.Reservation, error) {
 	var reservations []models.Reservation
-	err := r.db.Where("table_id = ? AND datetime BETWEEN ? AND ? AND deleted_at IS NULL", tableId, startDate, endDate).
+	err := r.db.Where("(table_id = ? OR ? = ANY(table_ids)) AND starts_at BETWEEN ? AND ? AND deleted_at IS NULL", tableId, tableId.String(), startDate, endDate).
 		Find(&reservations).Error
 	return reservations, err
 }
 
+// GetReservationsInRange reservas do projeto que começam em [start, end)
+func (r *ReservationRepository) GetReservationsInRange(orgId, projectId uuid.UUID, start, end time.Time) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := r.db.Where("organization_id = ? AND project_id = ? AND starts_at >= ? AND starts_at < ? AND deleted_at IS NULL", orgId, projectId, start, end).
+		Order("starts_at ASC").Find(&reservations).Error
+	return reservations, err
+}
+
 func (r *ReservationRepository) DeleteReservation(id uuid.UUID) error {
 	return r.db.Delete(&models.Reservation{}, id).Error
 }
@@ -109,12 +118,205 @@ func (r *ReservationRepository) GetPendingConfirmationReservation(orgId, project
 
 	// Busca a reserva futura mais próxima para este cliente
 	err := r.db.Where(
-		"organization_id = ? AND project_id = ? AND customer_id = ? AND datetime > ? AND status IN (?, ?) AND deleted_at IS NULL",
+		"organization_id = ? AND project_id = ? AND customer_id = ? AND starts_at > ? AND status IN (?, ?) AND deleted_at IS NULL",
 		orgId, projectId, customerId, now, "confirmed", "awaiting_confirmation",
-	).Order("datetime ASC").First(&reservation).Error
+	).Order("starts_at ASC").First(&reservation).Error
 
 	if err != nil {
 		return nil, err
 	}
 	return &reservation, nil
 }
+
+// UpdateReservationTables grava mesa principal, mesas da montagem e origem da escolha de várias reservas na mesma transação.
+// As mesas novas são bloqueadas e conferidas contra as demais reservas (as do lote não conflitam entre si).
+func (r *ReservationRepository) UpdateReservationTables(reservations []models.Reservation, defaultDurationMinutes int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var tableIds []string
+		batch := make([]uuid.UUID, 0, len(reservations))
+		for _, reservation := range reservations {
+			tableIds = append(tableIds, reservationTables(reservation)...)
+			batch = append(batch, reservation.Id)
+		}
+		if err := lockTables(tx, tableIds); err != nil {
+			return err
+		}
+		for _, reservation := range reservations {
+			if err := checkTables(tx, reservation, defaultDurationMinutes, batch); err != nil {
+				return err
+			}
+		}
+
+		for _, reservation := range reservations {
+			err := tx.Model(&models.Reservation{}).Where("id = ?", reservation.Id).Updates(map[string]interface{}{
+				"table_id":      reservation.TableId,
+				"table_ids":     reservation.TableIds,
+				"auto_assigned": reservation.AutoAssigned,
+				"updated_at":    time.Now(),
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CreateReservationExclusive grava a reserva com as mesas bloqueadas (pg_advisory_xact_lock), garantindo que
+// duas reservas simultâneas não ocupem a mesma mesa no mesmo horário. pacing (opcional) confere os limites
+// de ritmo na mesma transação, para que duas reservas simultâneas não passem juntas do limite.
+func (r *ReservationRepository) CreateReservationExclusive(reservation *models.Reservation, defaultDurationMinutes int, pacing PacingCheck) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkPacing(tx, pacing, nil, *reservation); err != nil {
+			return err
+		}
+		if err := lockTables(tx, reservationTables(*reservation)); err != nil {
+			return err
+		}
+		if err := checkTables(tx, *reservation, defaultDurationMinutes, []uuid.UUID{reservation.Id}); err != nil {
+			return err
+		}
+		return tx.Create(reservation).Error
+	})
+}
+
+// UpdateReservationExclusive atualização parcial com a mesma garantia: confere mesas, horário e status
+// resultantes antes de gravar. Mesa trocada pela equipe (table_id sem table_ids) substitui a escolha do
+// alocador e a montagem na mesma transação.
+func (r *ReservationRepository) UpdateReservationExclusive(reservation *models.Reservation, defaultDurationMinutes int, pacing PacingCheck) error {
+	if reservation.Id == uuid.Nil {
+		return fmt.Errorf("reservation ID cannot be empty")
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.Reservation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, "id = ? AND deleted_at IS NULL", reservation.Id).Error; err != nil {
+			return err
+		}
+
+		// Reserva como ficará após a atualização (campos vazios mantêm o valor atual)
+		merged := current
+		manualTable := reservation.TableId != nil && len(reservation.TableIds) == 0 &&
+			(current.TableId == nil || *current.TableId != *reservation.TableId || len(current.TableIds) > 0)
+		if reservation.TableId != nil {
+			merged.TableId = reservation.TableId
+			merged.TableIds = reservation.TableIds
+		}
+		if reservation.StartsAt != nil {
+			merged.StartsAt = reservation.StartsAt
+		}
+		if reservation.DurationMinutes > 0 {
+			merged.DurationMinutes = reservation.DurationMinutes
+		}
+		if reservation.PartySize > 0 {
+			merged.PartySize = reservation.PartySize
+		}
+		if reservation.Status != "" {
+			merged.Status = reservation.Status
+		}
+		merged.PacingOverrideReason = reservation.PacingOverrideReason
+
+		if err := checkPacing(tx, pacing, &current, merged); err != nil {
+			return err
+		}
+		if err := lockTables(tx, reservationTables(merged)); err != nil {
+			return err
+		}
+		if err := checkTables(tx, merged, defaultDurationMinutes, []uuid.UUID{merged.Id}); err != nil {
+			return err
+		}
+		if err := tx.Model(reservation).Where("id = ?", reservation.Id).Updates(reservation).Error; err != nil {
+			return err
+		}
+		if !manualTable {
+			return nil
+		}
+
+		// Updates com struct ignora valores vazios: limpa a montagem e a origem explicitamente
+		reservation.TableIds = nil
+		reservation.AutoAssigned = false
+		return tx.Model(&models.Reservation{}).Where("id = ?", reservation.Id).Updates(map[string]interface{}{
+			"table_ids":     nil,
+			"auto_assigned": false,
+		}).Error
+	})
+}
+
+// reservationTables mesas ocupadas pela reserva (todas as da montagem ou a mesa principal)
+func reservationTables(reservation models.Reservation) []string {
+	if len(reservation.TableIds) > 0 {
+		return reservation.TableIds
+	}
+	if reservation.TableId != nil {
+		return []string{reservation.TableId.String()}
+	}
+	return nil
+}
+
+// lockTables bloqueia as mesas até o fim da transação, sempre na mesma ordem para evitar deadlock
+func lockTables(tx *gorm.DB, tableIds []string) error {
+	sorted := append([]string{}, tableIds...)
+	sort.Strings(sorted)
+	for i, tableId := range sorted {
+		if i > 0 && tableId == sorted[i-1] {
+			continue
+		}
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "reservation_table:"+tableId).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPacing bloqueia o ritmo do projeto até o fim da transação e confere a reserva com as reservas lidas nela.
+// A trava é por projeto porque as janelas móveis cruzam horários; é tomada antes das mesas, sempre na mesma ordem.
+func checkPacing(tx *gorm.DB, pacing PacingCheck, current *models.Reservation, reservation models.Reservation) error {
+	if pacing == nil {
+		return nil
+	}
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "reservation_pacing:"+reservation.ProjectId.String()).Error; err != nil {
+		return err
+	}
+	return pacing(&ReservationRepository{db: tx}, current, reservation)
+}
+
+// checkTables recusa a reserva (reservation_conflict) se outra reserva ativa ocupa alguma das mesas durante a permanência
+func checkTables(tx *gorm.DB, reservation models.Reservation, defaultDurationMinutes int, skip []uuid.UUID) error {
+	tableIds := reservationTables(reservation)
+	if len(tableIds) == 0 || reservation.StartsAt == nil || !isActiveReservation(reservation.Status) {
+		return nil
+	}
+	duration := reservation.DurationMinutes
+	if duration <= 0 {
+		duration = defaultDurationMinutes
+	}
+	start := *reservation.StartsAt
+	conflict, err := tablesConflict(tx, tableIds, start, start.Add(time.Duration(duration)*time.Minute), defaultDurationMinutes, skip)
+	if err != nil {
+		return err
+	}
+	if conflict {
+		return errors.New("reservation_conflict: table already booked for this time")
+	}
+	return nil
+}
+
+// tablesConflict indica reserva ativa nas mesas com permanência sobreposta a [start, end), exceto as de skip
+func tablesConflict(db *gorm.DB, tableIds []string, start, end time.Time, defaultDurationMinutes int, skip []uuid.UUID) (bool, error) {
+	var count int64
+	err := db.Model(&models.Reservation{}).
+		Where("(table_id IN ? OR table_ids && ?) AND status IN ? AND deleted_at IS NULL AND id NOT IN ?",
+			tableIds, pq.StringArray(tableIds), activeReservationStatuses, skip).
+		Where("starts_at < ? AND starts_at + COALESCE(NULLIF(duration_minutes, 0), ?) * interval '1 minute' > ?",
+			end, defaultDurationMinutes, start).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func isActiveReservation(status string) bool {
+	for _, active := range activeReservationStatuses {
+		if status == active {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ServiceRequestRepository struct {
+	db *gorm.DB
+}
+
+type IServiceRequestRepository interface {
+	CreateRequest(request *models.ServiceRequest) error
+	GetRequestById(id uuid.UUID) (*models.ServiceRequest, error)
+	UpdateRequest(request *models.ServiceRequest) error
+	ListOpenByTable(tableId uuid.UUID) ([]models.ServiceRequest, error)
+	CountByTableSince(tableId uuid.UUID, since time.Time) (int64, error)
+	ListRequests(orgId, projectId uuid.UUID, status, environmentId, tableId string, limit int) ([]models.ServiceRequest, error)
+	ListRequestsInPeriod(orgId, projectId uuid.UUID, from, to time.Time) ([]models.ServiceRequest, error)
+}
+
+func NewServiceRequestRepository(db *gorm.DB) IServiceRequestRepository {
+	return &ServiceRequestRepository{db: db}
+}
+
+// CreateRequest registra chamado da mesa
+func (r *ServiceRequestRepository) CreateRequest(request *models.ServiceRequest) error {
+	return r.db.Create(request).Error
+}
+
+// GetRequestById busca chamado por ID
+func (r *ServiceRequestRepository) GetRequestById(id uuid.UUID) (*models.ServiceRequest, error) {
+	var request models.ServiceRequest
+	err := r.db.First(&request, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// UpdateRequest atualiza chamado
+func (r *ServiceRequestRepository) UpdateRequest(request *models.ServiceRequest) error {
+	request.UpdatedAt = time.Now()
+	return r.db.Save(request).Error
+}
+
+// ListOpenByTable chamados ainda não resolvidos da mesa
+func (r *ServiceRequestRepository) ListOpenByTable(tableId uuid.UUID) ([]models.ServiceRequest, error) {
+	var requests []models.ServiceRequest
+	err := r.db.Where("table_id = ? AND status <> ?", tableId, models.ServiceRequestStatusResolved).
+		Order("created_at").Find(&requests).Error
+	return requests, err
+}
+
+// CountByTableSince quantidade de chamados da mesa desde o horário informado
+func (r *ServiceRequestRepository) CountByTableSince(tableId uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ServiceRequest{}).
+		Where("table_id = ? AND created_at >= ?", tableId, since).Count(&count).Error
+	return count, err
+}
+
+// ListRequests lista chamados do projeto (mais antigos primeiro) por status, setor ou mesa.
+// Sem status lista os não resolvidos; "all" lista todos.
+func (r *ServiceRequestRepository) ListRequests(orgId, projectId uuid.UUID, status, environmentId, tableId string, limit int) ([]models.ServiceRequest, error) {
+	var requests []models.ServiceRequest
+	query := r.db.Where("organization_id = ? AND project_id = ?", orgId, projectId)
+	switch status {
+	case "":
+		query = query.Where("status <> ?", models.ServiceRequestStatusResolved)
+	case "all":
+	default:
+		query = query.Where("status = ?", status)
+	}
+	if environmentId != "" {
+		query = query.Where("environment_id = ?", environmentId)
+	}
+	if tableId != "" {
+		query = query.Where("table_id = ?", tableId)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Order("created_at").Find(&requests).Error
+	return requests, err
+}
+
+// ListRequestsInPeriod chamados criados no período (métricas de resposta)
+func (r *ServiceRequestRepository) ListRequestsInPeriod(orgId, projectId uuid.UUID, from, to time.Time) ([]models.ServiceRequest, error) {
+	var requests []models.ServiceRequest
+	err := r.db.Where("organization_id = ? AND project_id = ? AND created_at >= ? AND created_at < ?", orgId, projectId, from, to).
+		Find(&requests).Error
+	return requests, err
+}
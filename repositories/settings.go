@@ -69,6 +69,20 @@ func (r *SettingsRepository) GetOrCreateSettings(orgId, projectId uuid.UUID) (*m
 			EnableSms:      true,
 			EnableEmail:    false,
 			EnableWhatsapp: false,
+			ServiceChargePercent: 10,
+			LowStockThreshold:    5,
+			SlaGraceMinutes:        5,
+			SlaPendingMaxMinutes:   15,
+			SlaPreparingMaxMinutes: 45,
+			SlaReadyMaxMinutes:     10,
+			EnableTakeout:            true,
+			OrderSlotIntervalMinutes: 15,
+			OrderSlotCapacity:        10,
+			OrderLeadMinutes:         30,
+			NfceEnvironment: 2,
+			NfceSeries:      1,
+			NfceStartNumber: 1,
+			NfceProvider:    "mock",
 			CreatedAt:      time.Now(),
 			UpdatedAt:      time.Now(),
 		}
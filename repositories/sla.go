@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SLARepository struct {
+	db *gorm.DB
+}
+
+type ISLARepository interface {
+	ListActiveOrders() ([]models.Order, error)
+	CreateBreach(breach *models.OrderSLABreach) (bool, error)
+	UpdateBreach(breach *models.OrderSLABreach) error
+	ListOpenBreaches() ([]models.OrderSLABreach, error)
+	ResolveBreaches(ids []uuid.UUID, resolvedAt time.Time) error
+	ListBreaches(orgId, projectId uuid.UUID, breachType string, open bool, limit int) ([]models.OrderSLABreach, error)
+	CountBreaches(orgId, projectId uuid.UUID, from, to time.Time) (map[string]int, error)
+}
+
+func NewSLARepository(db *gorm.DB) ISLARepository {
+	return &SLARepository{db: db}
+}
+
+// ListActiveOrders lista os pedidos na cozinha de todos os projetos
+func (r *SLARepository) ListActiveOrders() ([]models.Order, error) {
+	var orders []models.Order
+	err := r.db.Where("status IN ? AND deleted_at IS NULL",
+		[]string{models.OrderStatusPending, models.OrderStatusPreparing, models.OrderStatusReady}).
+		Order("created_at ASC").
+		Find(&orders).Error
+	return orders, err
+}
+
+// CreateBreach registra a violação. Retorna false se ela já tinha sido registrada.
+func (r *SLARepository) CreateBreach(breach *models.OrderSLABreach) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(breach)
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdateBreach atualiza a violação
+func (r *SLARepository) UpdateBreach(breach *models.OrderSLABreach) error {
+	return r.db.Save(breach).Error
+}
+
+// ListOpenBreaches lista violações ainda não resolvidas de todos os projetos
+func (r *SLARepository) ListOpenBreaches() ([]models.OrderSLABreach, error) {
+	var breaches []models.OrderSLABreach
+	err := r.db.Where("resolved_at IS NULL").Find(&breaches).Error
+	return breaches, err
+}
+
+// ResolveBreaches marca as violações como resolvidas
+func (r *SLARepository) ResolveBreaches(ids []uuid.UUID, resolvedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.OrderSLABreach{}).Where("id IN ?", ids).Update("resolved_at", resolvedAt).Error
+}
+
+// ListBreaches lista violações do projeto, mais recentes primeiro
+func (r *SLARepository) ListBreaches(orgId, projectId uuid.UUID, breachType string, open bool, limit int) ([]models.OrderSLABreach, error) {
+	var breaches []models.OrderSLABreach
+	query := r.db.Where("organization_id = ? AND project_id = ?", orgId, projectId)
+	if breachType != "" {
+		query = query.Where("type = ?", breachType)
+	}
+	if open {
+		query = query.Where("resolved_at IS NULL")
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Order("detected_at DESC").Find(&breaches).Error
+	return breaches, err
+}
+
+// CountBreaches conta violações detectadas no período por tipo
+func (r *SLARepository) CountBreaches(orgId, projectId uuid.UUID, from, to time.Time) (map[string]int, error) {
+	var rows []struct {
+		Type  string
+		Total int
+	}
+	err := r.db.Model(&models.OrderSLABreach{}).
+		Select("type, COUNT(*) AS total").
+		Where("organization_id = ? AND project_id = ? AND detected_at >= ? AND detected_at < ?", orgId, projectId, from, to).
+		Group("type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{models.SLABreachTypeLate: 0, models.SLABreachTypeStuck: 0}
+	for _, row := range rows {
+		counts[row.Type] = row.Total
+	}
+	return counts, nil
+}
@@ -0,0 +1,263 @@
+package repositories
+
+import (
+	"fmt"
+	"lep/repositories/models"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StockRepository struct {
+	db *gorm.DB
+}
+
+type IStockRepository interface {
+	UpdateOrderWithStock(orderId uuid.UUID, createdBy *uuid.UUID, apply func(order *models.Order) ([]models.OrderStatusHistory, error)) (*models.Order, []models.StockMovement, error)
+	AdjustStock(productId uuid.UUID, quantity int, movementType, reason string, createdBy *uuid.UUID) (*models.StockMovement, error)
+	RecordMovement(movement *models.StockMovement) error
+	ListMovements(orgId, projectId uuid.UUID, productId *uuid.UUID, limit int) ([]models.StockMovement, error)
+	ListLowStock(orgId, projectId uuid.UUID, defaultThreshold int) ([]models.Product, error)
+}
+
+func NewStockRepository(db *gorm.DB) IStockRepository {
+	return &StockRepository{db: db}
+}
+
+// UpdateOrderWithStock bloqueia o pedido (FOR UPDATE), aplica apply sobre a versão atual e grava
+// pedido, transições e estoque na mesma transação. apply valida status e itens já sob o bloqueio,
+// então duas transições simultâneas do mesmo pedido não baixam o estoque duas vezes nem
+// sobrescrevem uma à outra.
+func (r *StockRepository) UpdateOrderWithStock(orderId uuid.UUID, createdBy *uuid.UUID, apply func(order *models.Order) ([]models.OrderStatusHistory, error)) (*models.Order, []models.StockMovement, error) {
+	var order models.Order
+	var movements []models.StockMovement
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&order, "id = ? AND deleted_at IS NULL", orderId).Error; err != nil {
+			return err
+		}
+
+		entries, err := apply(&order)
+		if err != nil {
+			return err
+		}
+
+		movements, err = saveOrderWithStock(tx, &order, entries, createdBy)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &order, movements, nil
+}
+
+// saveOrderWithStock grava o pedido (e transições) ajustando o estoque na transação informada.
+// Pedidos em preparo, prontos ou entregues mantêm seus itens baixados; nos demais status o
+// que já foi baixado é estornado. A diferença é calculada pelo livro de movimentações, então
+// chamadas repetidas não baixam duas vezes. Produtos são bloqueados (FOR UPDATE) para evitar venda acima do estoque.
+func saveOrderWithStock(tx *gorm.DB, order *models.Order, entries []models.OrderStatusHistory, createdBy *uuid.UUID) ([]models.StockMovement, error) {
+	var movements []models.StockMovement
+
+	var deducted []struct {
+		ProductId uuid.UUID
+		Deducted  int
+	}
+	if err := tx.Model(&models.StockMovement{}).
+		Select("product_id, -SUM(quantity) AS deducted").
+		Where("order_id = ?", order.Id).
+		Group("product_id").
+		Scan(&deducted).Error; err != nil {
+		return nil, err
+	}
+
+	// delta positivo = baixar, negativo = estornar
+	deltas := orderStockTarget(order)
+	for _, row := range deducted {
+		deltas[row.ProductId] -= row.Deducted
+	}
+
+	productIds := make([]uuid.UUID, 0, len(deltas))
+	for productId, delta := range deltas {
+		if delta != 0 {
+			productIds = append(productIds, productId)
+		}
+	}
+	// Ordem fixa de bloqueio evita deadlock entre pedidos concorrentes
+	sort.Slice(productIds, func(i, j int) bool { return productIds[i].String() < productIds[j].String() })
+
+	for _, productId := range productIds {
+		delta := deltas[productId]
+
+		var product models.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", productId).First(&product).Error; err != nil {
+			return nil, err
+		}
+		if product.Stock == nil {
+			continue // estoque não controlado
+		}
+
+		before := *product.Stock
+		after := before - delta
+		if after < 0 {
+			return nil, fmt.Errorf("insufficient_stock: %s has %d unit(s) left", product.Name, before)
+		}
+
+		movementType := models.StockMovementOrderDeduction
+		if delta < 0 {
+			movementType = models.StockMovementOrderRestore
+		}
+
+		orderId := order.Id
+		movement := models.StockMovement{
+			Id:             uuid.New(),
+			OrganizationId: product.OrganizationId,
+			ProjectId:      product.ProjectId,
+			ProductId:      product.Id,
+			OrderId:        &orderId,
+			Type:           movementType,
+			Quantity:       -delta,
+			StockBefore:    before,
+			StockAfter:     after,
+			CreatedBy:      createdBy,
+			CreatedAt:      time.Now(),
+		}
+
+		if err := applyStockLevel(tx, &product, after); err != nil {
+			return nil, err
+		}
+		if err := tx.Create(&movement).Error; err != nil {
+			return nil, err
+		}
+		movements = append(movements, movement)
+	}
+
+	if err := tx.Save(order).Error; err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if err := tx.Create(&entries[i]).Error; err != nil {
+			return nil, err
+		}
+	}
+	return movements, nil
+}
+
+// AdjustStock aplica ajuste manual (entrada, perda ou correção) e registra no livro
+func (r *StockRepository) AdjustStock(productId uuid.UUID, quantity int, movementType, reason string, createdBy *uuid.UUID) (*models.StockMovement, error) {
+	var movement models.StockMovement
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var product models.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted_at IS NULL", productId).
+			First(&product).Error; err != nil {
+			return err
+		}
+
+		// Produto sem controle passa a ser controlado a partir de zero
+		before := 0
+		if product.Stock != nil {
+			before = *product.Stock
+		}
+		after := before + quantity
+		if after < 0 {
+			return fmt.Errorf("invalid_stock: adjustment would leave %s with negative stock (%d)", product.Name, after)
+		}
+
+		movement = models.StockMovement{
+			Id:             uuid.New(),
+			OrganizationId: product.OrganizationId,
+			ProjectId:      product.ProjectId,
+			ProductId:      product.Id,
+			Type:           movementType,
+			Quantity:       quantity,
+			StockBefore:    before,
+			StockAfter:     after,
+			Reason:         reason,
+			CreatedBy:      createdBy,
+			CreatedAt:      time.Now(),
+		}
+
+		if err := applyStockLevel(tx, &product, after); err != nil {
+			return err
+		}
+		return tx.Create(&movement).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &movement, nil
+}
+
+// RecordMovement registra movimentação já aplicada ao produto (ex.: estoque alterado no cadastro)
+func (r *StockRepository) RecordMovement(movement *models.StockMovement) error {
+	return r.db.Create(movement).Error
+}
+
+// ListMovements lista o livro de movimentações, mais recentes primeiro
+func (r *StockRepository) ListMovements(orgId, projectId uuid.UUID, productId *uuid.UUID, limit int) ([]models.StockMovement, error) {
+	var movements []models.StockMovement
+	query := r.db.Where("organization_id = ? AND project_id = ?", orgId, projectId)
+	if productId != nil {
+		query = query.Where("product_id = ?", *productId)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Order("created_at DESC").Find(&movements).Error
+	return movements, err
+}
+
+// ListLowStock lista produtos controlados no limite de estoque baixo (ou zerados)
+func (r *StockRepository) ListLowStock(orgId, projectId uuid.UUID, defaultThreshold int) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId).
+		Where("stock IS NOT NULL AND stock <= COALESCE(low_stock_threshold, ?)", defaultThreshold).
+		Order("stock ASC, name ASC").
+		Find(&products).Error
+	return products, err
+}
+
+// orderStockTarget quantidade por produto que deve estar baixada para o status atual do pedido
+func orderStockTarget(order *models.Order) map[uuid.UUID]int {
+	target := make(map[uuid.UUID]int)
+	switch order.Status {
+	case models.OrderStatusPreparing, models.OrderStatusReady, models.OrderStatusDelivered:
+		for _, item := range order.Items {
+			target[item.ProductId] += item.Quantity
+		}
+	}
+	return target
+}
+
+// applyStockLevel grava o novo estoque, desativando o produto ao zerar e reativando na reposição
+func applyStockLevel(tx *gorm.DB, product *models.Product, stock int) error {
+	updates := map[string]interface{}{
+		"stock":      stock,
+		"updated_at": time.Now(),
+	}
+	if stock == 0 && product.Active {
+		updates["active"] = false
+		updates["out_of_stock"] = true
+	} else if stock > 0 && product.OutOfStock {
+		updates["active"] = true
+		updates["out_of_stock"] = false
+	}
+
+	if err := tx.Model(&models.Product{}).Where("id = ?", product.Id).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	product.Stock = &stock
+	if active, changed := updates["active"]; changed {
+		product.Active = active.(bool)
+		product.OutOfStock = updates["out_of_stock"].(bool)
+	}
+	return nil
+}
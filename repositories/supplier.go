@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"errors"
+	"lep/repositories/models"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SupplierRepository struct {
+	db *gorm.DB
+}
+
+type ISupplierRepository interface {
+	CreateSupplier(supplier *models.Supplier) error
+	GetSupplierById(id uuid.UUID) (*models.Supplier, error)
+	ListSuppliers(orgId, projectId uuid.UUID) ([]models.Supplier, error)
+	UpdateSupplier(supplier *models.Supplier) error
+	SoftDeleteSupplier(id uuid.UUID) error
+	CreatePurchaseOrder(order *models.PurchaseOrder) error
+	GetPurchaseOrderById(id uuid.UUID) (*models.PurchaseOrder, error)
+	ListPurchaseOrders(orgId, projectId uuid.UUID, status string) ([]models.PurchaseOrder, error)
+	UpdatePurchaseOrder(order *models.PurchaseOrder) error
+	UpdatePurchaseOrderStatus(id uuid.UUID, from, to string) (bool, error)
+	ReceivePurchaseOrder(order *models.PurchaseOrder, received map[uuid.UUID]float64) error
+}
+
+func NewSupplierRepository(db *gorm.DB) ISupplierRepository {
+	return &SupplierRepository{db: db}
+}
+
+// CreateSupplier cria fornecedor
+func (r *SupplierRepository) CreateSupplier(supplier *models.Supplier) error {
+	return r.db.Create(supplier).Error
+}
+
+// GetSupplierById busca fornecedor por ID
+func (r *SupplierRepository) GetSupplierById(id uuid.UUID) (*models.Supplier, error) {
+	var supplier models.Supplier
+	err := r.db.First(&supplier, "id = ? AND deleted_at IS NULL", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+// ListSuppliers lista fornecedores do projeto
+func (r *SupplierRepository) ListSuppliers(orgId, projectId uuid.UUID) ([]models.Supplier, error) {
+	var suppliers []models.Supplier
+	err := r.db.Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId).
+		Order("name ASC").
+		Find(&suppliers).Error
+	return suppliers, err
+}
+
+// UpdateSupplier atualiza fornecedor
+func (r *SupplierRepository) UpdateSupplier(supplier *models.Supplier) error {
+	return r.db.Save(supplier).Error
+}
+
+// SoftDeleteSupplier exclui fornecedor
+func (r *SupplierRepository) SoftDeleteSupplier(id uuid.UUID) error {
+	return r.db.Model(&models.Supplier{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error
+}
+
+// CreatePurchaseOrder cria pedido de compra com seus itens
+func (r *SupplierRepository) CreatePurchaseOrder(order *models.PurchaseOrder) error {
+	return r.db.Create(order).Error
+}
+
+// GetPurchaseOrderById busca pedido de compra com itens
+func (r *SupplierRepository) GetPurchaseOrderById(id uuid.UUID) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := r.db.Preload("Items").First(&order, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// ListPurchaseOrders lista pedidos de compra do projeto (opcionalmente por status)
+func (r *SupplierRepository) ListPurchaseOrders(orgId, projectId uuid.UUID, status string) ([]models.PurchaseOrder, error) {
+	var orders []models.PurchaseOrder
+	query := r.db.Preload("Items").Where("organization_id = ? AND project_id = ?", orgId, projectId)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").Find(&orders).Error
+	return orders, err
+}
+
+// UpdatePurchaseOrder atualiza rascunho substituindo os itens
+func (r *SupplierRepository) UpdatePurchaseOrder(order *models.PurchaseOrder) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("purchase_order_id = ?", order.Id).Delete(&models.PurchaseOrderItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("Items").Save(order).Error; err != nil {
+			return err
+		}
+		if len(order.Items) == 0 {
+			return nil
+		}
+		return tx.Create(&order.Items).Error
+	})
+}
+
+// UpdatePurchaseOrderStatus muda o status apenas se ainda estiver em from.
+// Retorna false quando outro processo já alterou o pedido.
+func (r *SupplierRepository) UpdatePurchaseOrderStatus(id uuid.UUID, from, to string) (bool, error) {
+	now := time.Now()
+	updates := map[string]interface{}{"status": to, "updated_at": now}
+	switch to {
+	case models.PurchaseOrderStatusSent:
+		updates["sent_at"] = now
+	case models.PurchaseOrderStatusCancelled:
+		updates["cancelled_at"] = now
+	}
+
+	result := r.db.Model(&models.PurchaseOrder{}).Where("id = ? AND status = ?", id, from).Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReceivePurchaseOrder marca o pedido como recebido e dá entrada no estoque dos ingredientes,
+// recalculando o custo médio. received traz a quantidade recebida por item.
+func (r *SupplierRepository) ReceivePurchaseOrder(order *models.PurchaseOrder, received map[uuid.UUID]float64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.PurchaseOrder{}).
+			Where("id = ? AND status = ?", order.Id, models.PurchaseOrderStatusSent).
+			Updates(map[string]interface{}{"status": models.PurchaseOrderStatusReceived, "received_at": now, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("invalid_transition: purchase order is no longer awaiting receipt")
+		}
+
+		// Ordem fixa de bloqueio evita deadlock com recebimentos concorrentes
+		items := append([]models.PurchaseOrderItem(nil), order.Items...)
+		sort.Slice(items, func(i, j int) bool { return items[i].IngredientId.String() < items[j].IngredientId.String() })
+
+		for _, item := range items {
+			quantity := received[item.Id]
+			if err := tx.Model(&models.PurchaseOrderItem{}).Where("id = ?", item.Id).
+				Update("received_quantity", quantity).Error; err != nil {
+				return err
+			}
+			if quantity <= 0 {
+				continue
+			}
+
+			var ingredient models.Ingredient
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", item.IngredientId).First(&ingredient).Error; err != nil {
+				return err
+			}
+
+			// Custo médio ponderado; estoque negativo ou zerado assume o custo da compra
+			unitCost := item.UnitCost
+			if ingredient.Stock > 0 {
+				unitCost = (ingredient.Stock*ingredient.UnitCost + quantity*item.UnitCost) / (ingredient.Stock + quantity)
+			}
+
+			if err := tx.Model(&models.Ingredient{}).Where("id = ?", ingredient.Id).
+				Updates(map[string]interface{}{
+					"stock":      ingredient.Stock + quantity,
+					"unit_cost":  unitCost,
+					"updated_at": now,
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		order.Status = models.PurchaseOrderStatusReceived
+		order.ReceivedAt = &now
+		for i := range order.Items {
+			order.Items[i].ReceivedQuantity = received[order.Items[i].Id]
+		}
+		return nil
+	})
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"errors"
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TabRepository struct {
+	db *gorm.DB
+}
+
+type ITabRepository interface {
+	CreateTab(tab *models.Tab) error
+	GetTabById(id uuid.UUID) (*models.Tab, error)
+	GetOpenTabByTable(tableId uuid.UUID) (*models.Tab, error)
+	ListTabs(orgId, projectId uuid.UUID, status string) ([]models.Tab, error)
+	UpdateTabTotals(tab *models.Tab) error
+	ListTabOrders(tabId uuid.UUID) ([]models.Order, error)
+	AttachOrder(orderId, tabId uuid.UUID) error
+	DetachOrder(orderId uuid.UUID) error
+	CreatePayment(payment *models.TabPayment) error
+	ListPayments(tabId uuid.UUID) ([]models.TabPayment, error)
+	CloseTab(tab *models.Tab) error
+	WithTabLock(tabId uuid.UUID, fn func(tabs ITabRepository, tab *models.Tab) error) error
+}
+
+func NewTabRepository(db *gorm.DB) ITabRepository {
+	return &TabRepository{db: db}
+}
+
+// CreateTab abre comanda e marca a mesa como ocupada
+func (r *TabRepository) CreateTab(tab *models.Tab) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(tab).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Table{}).Where("id = ?", tab.TableId).
+			Updates(map[string]interface{}{"status": "ocupada", "updated_at": time.Now()}).Error
+	})
+}
+
+// GetTabById busca comanda por ID
+func (r *TabRepository) GetTabById(id uuid.UUID) (*models.Tab, error) {
+	var tab models.Tab
+	err := r.db.First(&tab, "id = ? AND deleted_at IS NULL", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tab, nil
+}
+
+// GetOpenTabByTable busca a comanda aberta da mesa (nil se não houver)
+func (r *TabRepository) GetOpenTabByTable(tableId uuid.UUID) (*models.Tab, error) {
+	var tabs []models.Tab
+	err := r.db.Where("table_id = ? AND status = ? AND deleted_at IS NULL", tableId, models.TabStatusOpen).
+		Limit(1).Find(&tabs).Error
+	if err != nil || len(tabs) == 0 {
+		return nil, err
+	}
+	return &tabs[0], nil
+}
+
+// ListTabs lista comandas do projeto, opcionalmente filtrando por status
+func (r *TabRepository) ListTabs(orgId, projectId uuid.UUID, status string) ([]models.Tab, error) {
+	var tabs []models.Tab
+	query := r.db.Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("opened_at DESC").Find(&tabs).Error
+	return tabs, err
+}
+
+// UpdateTabTotals grava só a taxa de serviço e os totais da comanda, se ela ainda estiver aberta
+func (r *TabRepository) UpdateTabTotals(tab *models.Tab) error {
+	tab.UpdatedAt = time.Now()
+	result := r.db.Model(&models.Tab{}).Where("id = ? AND status = ?", tab.Id, models.TabStatusOpen).
+		Updates(tabTotals(tab))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errTabClosed
+	}
+	return nil
+}
+
+// errTabClosed comanda fechada entre a leitura e a gravação
+var errTabClosed = errors.New("tab_closed: tab is already closed")
+
+// tabTotals colunas recalculadas da comanda
+func tabTotals(tab *models.Tab) map[string]interface{} {
+	return map[string]interface{}{
+		"service_charge_percent": tab.ServiceChargePercent,
+		"subtotal":               tab.Subtotal,
+		"service_charge":         tab.ServiceCharge,
+		"total":                  tab.Total,
+		"paid_total":             tab.PaidTotal,
+		"tips_total":             tab.TipsTotal,
+		"updated_at":             tab.UpdatedAt,
+	}
+}
+
+// ListTabOrders lista pedidos vinculados à comanda
+func (r *TabRepository) ListTabOrders(tabId uuid.UUID) ([]models.Order, error) {
+	var orders []models.Order
+	err := r.db.Where("tab_id = ? AND deleted_at IS NULL", tabId).Order("created_at ASC").Find(&orders).Error
+	return orders, err
+}
+
+// AttachOrder vincula pedido à comanda
+func (r *TabRepository) AttachOrder(orderId, tabId uuid.UUID) error {
+	return r.db.Model(&models.Order{}).Where("id = ?", orderId).
+		Updates(map[string]interface{}{"tab_id": tabId, "updated_at": time.Now()}).Error
+}
+
+// DetachOrder remove o vínculo do pedido com a comanda
+func (r *TabRepository) DetachOrder(orderId uuid.UUID) error {
+	return r.db.Model(&models.Order{}).Where("id = ?", orderId).
+		Updates(map[string]interface{}{"tab_id": nil, "updated_at": time.Now()}).Error
+}
+
+// CreatePayment registra pagamento da comanda
+func (r *TabRepository) CreatePayment(payment *models.TabPayment) error {
+	return r.db.Create(payment).Error
+}
+
+// ListPayments lista pagamentos da comanda
+func (r *TabRepository) ListPayments(tabId uuid.UUID) ([]models.TabPayment, error) {
+	var payments []models.TabPayment
+	err := r.db.Where("tab_id = ?", tabId).Order("paid_at ASC").Find(&payments).Error
+	return payments, err
+}
+
+// CloseTab fecha a comanda e libera a mesa (e as mesas unidas a ela) na mesma transação
+func (r *TabRepository) CloseTab(tab *models.Tab) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		tab.UpdatedAt = time.Now()
+		columns := tabTotals(tab)
+		columns["status"] = tab.Status
+		columns["closed_at"] = tab.ClosedAt
+		result := tx.Model(&models.Tab{}).Where("id = ? AND status = ?", tab.Id, models.TabStatusOpen).Updates(columns)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errTabClosed
+		}
+		if err := tx.Model(&models.Table{}).Where("merged_into_id = ?", tab.TableId).
+			Updates(map[string]interface{}{"status": "livre", "merged_into_id": nil, "updated_at": time.Now()}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Table{}).Where("id = ?", tab.TableId).
+			Updates(map[string]interface{}{"status": "livre", "updated_at": time.Now()}).Error
+	})
+}
+
+// WithTabLock executa fn numa transação com a linha da comanda travada (SELECT ... FOR UPDATE).
+// fn recebe um repositório ligado à mesma transação e a comanda lida já sob o lock.
+func (r *TabRepository) WithTabLock(tabId uuid.UUID, fn func(tabs ITabRepository, tab *models.Tab) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var tab models.Tab
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&tab, "id = ? AND deleted_at IS NULL", tabId).Error; err != nil {
+			return err
+		}
+		return fn(&TabRepository{db: tx}, &tab)
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Interface para TableRepository
@@ -17,8 +18,12 @@ type ITableRepository interface {
 	ListTablesByProject(OrganizationId, projectId uuid.UUID) ([]models.Table, error)
 	GetTablesByProject(orgId, projectId uuid.UUID) ([]models.Table, error)
 	CheckTableNumberExists(orgId, projectId uuid.UUID, number int, excludeId *uuid.UUID) (bool, error)
+	GetTableByNumber(orgId, projectId uuid.UUID, number int) (*models.Table, error)
 	UpdateTable(table *models.Table) error
 	SoftDeleteTable(id uuid.UUID) error
+	ListMergedTables(tableId uuid.UUID) ([]models.Table, error)
+	ListOpenOrdersByTable(tableId uuid.UUID) ([]models.Order, error)
+	WithServiceLock(tableIds []uuid.UUID, plan func(tables ITableRepository, tabs ITabRepository) (*models.TableServiceChange, error)) error
 }
 
 type TableRepository struct {
@@ -77,6 +82,17 @@ func (r *TableRepository) CheckTableNumberExists(orgId, projectId uuid.UUID, num
 	return count > 0, nil
 }
 
+// GetTableByNumber busca mesa pelo número dentro do projeto (QR Code da mesa)
+func (r *TableRepository) GetTableByNumber(orgId, projectId uuid.UUID, number int) (*models.Table, error) {
+	var table models.Table
+	err := r.db.Where("organization_id = ? AND project_id = ? AND number = ? AND deleted_at IS NULL", orgId, projectId, number).
+		First(&table).Error
+	if err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
 func (r *TableRepository) UpdateTable(table *models.Table) error {
 	// Garante que deleted_at não seja alterado
 	return r.db.Model(table).Omit("deleted_at").Save(table).Error
@@ -94,3 +110,194 @@ func (r *TableRepository) GetTablesByProject(orgId, projectId uuid.UUID) ([]mode
 		Order("number ASC").Find(&tables).Error
 	return tables, err
 }
+
+// ListMergedTables lista as mesas unidas à mesa principal
+func (r *TableRepository) ListMergedTables(tableId uuid.UUID) ([]models.Table, error) {
+	var tables []models.Table
+	err := r.db.Where("merged_into_id = ? AND deleted_at IS NULL", tableId).
+		Order("number ASC").Find(&tables).Error
+	return tables, err
+}
+
+// ListOpenOrdersByTable lista os pedidos em atendimento na mesa:
+// ainda na cozinha/salão ou lançados em comanda aberta
+func (r *TableRepository) ListOpenOrdersByTable(tableId uuid.UUID) ([]models.Order, error) {
+	var orders []models.Order
+	err := r.openOrders([]uuid.UUID{tableId}).Order("created_at ASC").Find(&orders).Error
+	return orders, err
+}
+
+func (r *TableRepository) openOrders(tableIds []uuid.UUID) *gorm.DB {
+	return r.db.Where("table_id IN ? AND deleted_at IS NULL", tableIds).
+		Where("(status NOT IN (?, ?) OR tab_id IN (?))", models.OrderStatusDelivered, models.OrderStatusCancelled,
+			r.db.Model(&models.Tab{}).Select("id").Where("status = ? AND deleted_at IS NULL", models.TabStatusOpen))
+}
+
+// WithServiceLock trava as mesas, suas comandas abertas e seus pedidos em atendimento (SELECT ... FOR UPDATE)
+// e chama plan com repositórios ligados à mesma transação, para que a troca, união ou separação seja
+// decidida sobre dados lidos já sob o lock. A mudança devolvida é gravada antes do commit:
+// status das mesas, comandas, pedidos, histórico e a baixa de estoque dos itens transferidos.
+func (r *TableRepository) WithServiceLock(tableIds []uuid.UUID, plan func(tables ITableRepository, tabs ITabRepository) (*models.TableServiceChange, error)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		locking := clause.Locking{Strength: "UPDATE"}
+		var tables []models.Table
+		if err := tx.Clauses(locking).Where("id IN ?", tableIds).Order("id").Find(&tables).Error; err != nil {
+			return err
+		}
+		var tabs []models.Tab
+		if err := tx.Clauses(locking).Where("table_id IN ? AND status = ? AND deleted_at IS NULL", tableIds, models.TabStatusOpen).
+			Order("id").Find(&tabs).Error; err != nil {
+			return err
+		}
+		var orders []models.Order
+		if err := (&TableRepository{db: tx}).openOrders(tableIds).Clauses(locking).Order("id").Find(&orders).Error; err != nil {
+			return err
+		}
+
+		change, err := plan(&TableRepository{db: tx}, &TabRepository{db: tx})
+		if err != nil || change == nil {
+			return err
+		}
+		return applyServiceChange(tx, change)
+	})
+}
+
+// applyServiceChange grava só as colunas que a troca, união ou separação altera
+func applyServiceChange(tx *gorm.DB, change *models.TableServiceChange) error {
+	now := time.Now()
+
+	for i := range change.NewTabs {
+		if err := tx.Create(&change.NewTabs[i]).Error; err != nil {
+			return err
+		}
+	}
+	for _, tab := range change.Tabs {
+		if err := tx.Model(&models.Tab{}).Where("id = ?", tab.Id).
+			Updates(map[string]interface{}{
+				"table_id":   tab.TableId,
+				"guests":     tab.Guests,
+				"updated_at": now,
+			}).Error; err != nil {
+			return err
+		}
+	}
+	for _, merge := range change.TabMerges {
+		if err := tx.Model(&models.Order{}).Where("tab_id = ?", merge.FromTabId).
+			Updates(map[string]interface{}{"tab_id": merge.ToTabId, "updated_at": now}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.TabPayment{}).Where("tab_id = ?", merge.FromTabId).
+			Update("tab_id", merge.ToTabId).Error; err != nil {
+			return err
+		}
+		// Comanda absorvida sai de cena (pedidos e pagamentos seguem na outra)
+		if err := tx.Model(&models.Tab{}).Where("id = ?", merge.FromTabId).
+			Updates(map[string]interface{}{"deleted_at": now, "updated_at": now}).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, order := range change.Orders {
+		if err := tx.Model(&models.Order{}).Where("id = ?", order.Id).
+			Updates(map[string]interface{}{
+				"table_id":     order.TableId,
+				"table_number": order.TableNumber,
+				"tab_id":       order.TabId,
+				"updated_at":   now,
+			}).Error; err != nil {
+			return err
+		}
+	}
+	for _, order := range change.SplitOrders {
+		if err := tx.Model(&models.Order{}).Where("id = ?", order.Id).
+			Updates(map[string]interface{}{
+				"items":        order.Items,
+				"courses":      order.Courses,
+				"total_amount": order.TotalAmount,
+				"status":       order.Status,
+				"started_at":   order.StartedAt,
+				"ready_at":     order.ReadyAt,
+				"delivered_at": order.DeliveredAt,
+				"updated_at":   now,
+			}).Error; err != nil {
+			return err
+		}
+	}
+	for i := range change.NewOrders {
+		if err := tx.Create(&change.NewOrders[i]).Error; err != nil {
+			return err
+		}
+	}
+	for i := range change.History {
+		if err := tx.Create(&change.History[i]).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, transfer := range change.StockTransfers {
+		if err := transferOrderStock(tx, transfer, now); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range change.Tables {
+		if err := tx.Model(&models.Table{}).Where("id = ?", table.Id).
+			Updates(map[string]interface{}{
+				"status":         table.Status,
+				"merged_into_id": table.MergedIntoId,
+				"updated_at":     now,
+			}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transferOrderStock move para o pedido novo a baixa já feita no pedido de origem,
+// sem alterar o estoque (o pedido de origem não estorna e o novo não baixa de novo)
+func transferOrderStock(tx *gorm.DB, transfer models.OrderStockTransfer, now time.Time) error {
+	var deducted int
+	if err := tx.Model(&models.StockMovement{}).
+		Select("COALESCE(-SUM(quantity), 0)").
+		Where("order_id = ? AND product_id = ?", transfer.FromOrderId, transfer.ProductId).
+		Scan(&deducted).Error; err != nil {
+		return err
+	}
+
+	quantity := transfer.Quantity
+	if deducted < quantity {
+		quantity = deducted
+	}
+	if quantity <= 0 {
+		return nil // pedido ainda não baixou estoque ou produto sem controle
+	}
+
+	var product models.Product
+	if err := tx.Where("id = ?", transfer.ProductId).First(&product).Error; err != nil {
+		return err
+	}
+	stock := 0
+	if product.Stock != nil {
+		stock = *product.Stock
+	}
+
+	fromOrderId, toOrderId := transfer.FromOrderId, transfer.ToOrderId
+	movements := []models.StockMovement{
+		{OrderId: &fromOrderId, Quantity: quantity},
+		{OrderId: &toOrderId, Quantity: -quantity},
+	}
+	for i := range movements {
+		movements[i].Id = uuid.New()
+		movements[i].OrganizationId = product.OrganizationId
+		movements[i].ProjectId = product.ProjectId
+		movements[i].ProductId = product.Id
+		movements[i].Type = models.StockMovementOrderTransfer
+		movements[i].StockBefore = stock
+		movements[i].StockAfter = stock
+		movements[i].CreatedAt = now
+		if err := tx.Create(&movements[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
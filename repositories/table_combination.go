@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ITableCombinationRepository interface {
+	CreateCombination(combination *models.TableCombination) error
+	GetCombinationById(id uuid.UUID) (*models.TableCombination, error)
+	ListCombinations(orgId, projectId uuid.UUID) ([]models.TableCombination, error)
+	ListActiveCombinations(orgId, projectId uuid.UUID) ([]models.TableCombination, error)
+	UpdateCombination(combination *models.TableCombination) error
+	SoftDeleteCombination(id uuid.UUID) error
+}
+
+type TableCombinationRepository struct {
+	db *gorm.DB
+}
+
+func NewTableCombinationRepository(db *gorm.DB) ITableCombinationRepository {
+	return &TableCombinationRepository{db: db}
+}
+
+func (r *TableCombinationRepository) CreateCombination(combination *models.TableCombination) error {
+	return r.db.Create(combination).Error
+}
+
+func (r *TableCombinationRepository) GetCombinationById(id uuid.UUID) (*models.TableCombination, error) {
+	var combination models.TableCombination
+	err := r.db.First(&combination, "id = ? AND deleted_at IS NULL", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &combination, nil
+}
+
+func (r *TableCombinationRepository) ListCombinations(orgId, projectId uuid.UUID) ([]models.TableCombination, error) {
+	var combinations []models.TableCombination
+	err := r.db.Where("organization_id = ? AND project_id = ? AND deleted_at IS NULL", orgId, projectId).
+		Order("capacity ASC").Find(&combinations).Error
+	return combinations, err
+}
+
+func (r *TableCombinationRepository) ListActiveCombinations(orgId, projectId uuid.UUID) ([]models.TableCombination, error) {
+	var combinations []models.TableCombination
+	err := r.db.Where("organization_id = ? AND project_id = ? AND active = ? AND deleted_at IS NULL", orgId, projectId, true).
+		Order("capacity ASC").Find(&combinations).Error
+	return combinations, err
+}
+
+func (r *TableCombinationRepository) UpdateCombination(combination *models.TableCombination) error {
+	combination.UpdatedAt = time.Now()
+	return r.db.Save(combination).Error
+}
+
+func (r *TableCombinationRepository) SoftDeleteCombination(id uuid.UUID) error {
+	return r.db.Model(&models.TableCombination{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error
+}
@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"errors"
+	"lep/repositories/models"
+	"time"
+
+	"github.com/invopop/validation"
+	"github.com/invopop/validation/is"
+)
+
+// BlockedPeriodValidation valida dados de cadastro/atualização de período bloqueado
+func BlockedPeriodValidation(period *models.BlockedPeriod) error {
+	recurring := period.RecurringType != models.BlockedPeriodRecurringNone
+	return validation.ValidateStruct(period,
+		validation.Field(&period.OrganizationId, validation.Required, is.UUID),
+		validation.Field(&period.ProjectId, validation.Required, is.UUID),
+		validation.Field(&period.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&period.StartDateTime, validation.Required),
+		validation.Field(&period.EndDateTime, validation.Required, validation.By(func(interface{}) error {
+			if !period.EndDateTime.After(period.StartDateTime) {
+				return errors.New("must be after start_datetime")
+			}
+			if recurring && period.EndDateTime.Sub(period.StartDateTime) > blockedPeriodRecurrenceSpan(period) {
+				return errors.New("must be shorter than the recurrence interval")
+			}
+			return nil
+		})),
+		validation.Field(&period.RecurringType, validation.Required,
+			validation.In(models.BlockedPeriodRecurringNone, models.BlockedPeriodRecurringDaily, models.BlockedPeriodRecurringWeekly,
+				models.BlockedPeriodRecurringMonthly, models.BlockedPeriodRecurringYearly).
+				Error("Invalid recurring_type. Allowed: none, daily, weekly, monthly, yearly")),
+		validation.Field(&period.RecurringInterval, validation.Min(1), validation.Max(99)),
+		validation.Field(&period.RecurringWeekdays,
+			validation.When(period.RecurringType != models.BlockedPeriodRecurringWeekly, validation.Empty.Error("only allowed for weekly recurrence")),
+			validation.Each(validation.Min(int64(0)), validation.Max(int64(6)))),
+		validation.Field(&period.RecurringUntil, validation.When(period.RecurringUntil != nil, validation.By(func(interface{}) error {
+			if !recurring {
+				return errors.New("only allowed for recurring periods")
+			}
+			if period.RecurringUntil.Before(period.StartDateTime) {
+				return errors.New("must not be before start_datetime")
+			}
+			return nil
+		}))),
+		validation.Field(&period.ExceptionDates,
+			validation.When(!recurring, validation.Empty.Error("only allowed for recurring periods")),
+			validation.Each(validation.Date("2006-01-02"))),
+		validation.Field(&period.TableIds, validation.Each(is.UUID)),
+	)
+}
+
+// blockedPeriodRecurrenceSpan menor distância entre duas ocorrências (a duração não pode sobrepor a seguinte)
+func blockedPeriodRecurrenceSpan(period *models.BlockedPeriod) time.Duration {
+	interval := period.RecurringInterval
+	if interval < 1 {
+		interval = 1
+	}
+	day := 24 * time.Hour
+	switch period.RecurringType {
+	case models.BlockedPeriodRecurringDaily:
+		return time.Duration(interval) * day
+	case models.BlockedPeriodRecurringWeekly:
+		if len(period.RecurringWeekdays) > 1 {
+			return day
+		}
+		return time.Duration(interval) * 7 * day
+	case models.BlockedPeriodRecurringMonthly:
+		return time.Duration(interval) * 28 * day
+	default:
+		return time.Duration(interval) * 365 * day
+	}
+}
@@ -0,0 +1,25 @@
+package validation
+
+import (
+	"lep/repositories/models"
+
+	"github.com/invopop/validation"
+	"github.com/invopop/validation/is"
+)
+
+// DeliveryZoneValidation valida dados de cadastro/atualização de zona de entrega
+func DeliveryZoneValidation(zone *models.DeliveryZone) error {
+	return validation.ValidateStruct(zone,
+		validation.Field(&zone.OrganizationId, validation.Required, is.UUID),
+		validation.Field(&zone.ProjectId, validation.Required, is.UUID),
+		validation.Field(&zone.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&zone.Type, validation.Required,
+			validation.In(models.DeliveryZoneTypeRadius, models.DeliveryZoneTypePolygon).Error("Invalid type. Allowed: radius, polygon")),
+		validation.Field(&zone.RadiusKm, validation.When(zone.Type == models.DeliveryZoneTypeRadius, validation.Required, validation.Min(0.0))),
+		validation.Field(&zone.Polygon, validation.When(zone.Type == models.DeliveryZoneTypePolygon,
+			validation.Required, validation.Length(3, 0).Error("polygon needs at least 3 points"))),
+		validation.Field(&zone.Fee, validation.Min(0.0)),
+		validation.Field(&zone.MinOrderValue, validation.Min(0.0)),
+		validation.Field(&zone.EstimatedMinutes, validation.Min(0)),
+	)
+}
@@ -0,0 +1,61 @@
+package validation
+
+import (
+	"lep/repositories/models"
+
+	"github.com/invopop/validation"
+	"github.com/invopop/validation/is"
+)
+
+var ingredientUnitRule = validation.In(
+	models.IngredientUnitGram, models.IngredientUnitKilogram, models.IngredientUnitMilliliter,
+	models.IngredientUnitLiter, models.IngredientUnitPiece,
+).Error("Invalid unit. Allowed: g, kg, ml, l, un")
+
+// CreateIngredientValidation valida dados para criação de ingrediente
+func CreateIngredientValidation(ingredient *models.Ingredient) error {
+	return validation.ValidateStruct(ingredient,
+		validation.Field(&ingredient.OrganizationId, validation.Required, is.UUID),
+		validation.Field(&ingredient.ProjectId, validation.Required, is.UUID),
+		validation.Field(&ingredient.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&ingredient.Unit, validation.Required, ingredientUnitRule),
+		validation.Field(&ingredient.Stock, validation.Min(0.0)),
+		validation.Field(&ingredient.UnitCost, validation.Min(0.0)),
+		validation.Field(&ingredient.MinStock, validation.Min(0.0)),
+	)
+}
+
+// UpdateIngredientValidation valida dados para atualização de ingrediente
+func UpdateIngredientValidation(ingredient *models.Ingredient) error {
+	return validation.ValidateStruct(ingredient,
+		validation.Field(&ingredient.Id, validation.Required, is.UUID),
+		validation.Field(&ingredient.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&ingredient.Unit, validation.Required, ingredientUnitRule),
+		validation.Field(&ingredient.MinStock, validation.Min(0.0)),
+	)
+}
+
+// CreateSupplierValidation valida dados para criação de fornecedor
+func CreateSupplierValidation(supplier *models.Supplier) error {
+	return validation.ValidateStruct(supplier,
+		validation.Field(&supplier.OrganizationId, validation.Required, is.UUID),
+		validation.Field(&supplier.ProjectId, validation.Required, is.UUID),
+		validation.Field(&supplier.Name, validation.Required, validation.Length(1, 150)),
+		validation.Field(&supplier.Document, validation.Length(0, 20)),
+		validation.Field(&supplier.Email, is.EmailFormat),
+		validation.Field(&supplier.Phone, validation.Length(0, 20)),
+		validation.Field(&supplier.Notes, validation.Length(0, 500)),
+	)
+}
+
+// UpdateSupplierValidation valida dados para atualização de fornecedor
+func UpdateSupplierValidation(supplier *models.Supplier) error {
+	return validation.ValidateStruct(supplier,
+		validation.Field(&supplier.Id, validation.Required, is.UUID),
+		validation.Field(&supplier.Name, validation.Required, validation.Length(1, 150)),
+		validation.Field(&supplier.Document, validation.Length(0, 20)),
+		validation.Field(&supplier.Email, is.EmailFormat),
+		validation.Field(&supplier.Phone, validation.Length(0, 20)),
+		validation.Field(&supplier.Notes, validation.Length(0, 500)),
+	)
+}
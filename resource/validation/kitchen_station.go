@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"lep/repositories/models"
+
+	"github.com/invopop/validation"
+	"github.com/invopop/validation/is"
+)
+
+// CreateKitchenStationValidation valida dados para criação de estação da cozinha
+func CreateKitchenStationValidation(station *models.KitchenStation) error {
+	return validation.ValidateStruct(station,
+		validation.Field(&station.OrganizationId, validation.Required, is.UUID),
+		validation.Field(&station.ProjectId, validation.Required, is.UUID),
+		validation.Field(&station.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&station.Code, validation.Required, validation.Length(1, 50)),
+		validation.Field(&station.ProductTypes, validation.Each(validation.In("prato", "bebida", "vinho"))),
+		validation.Field(&station.CategoryIds, validation.Each(is.UUID)),
+	)
+}
+
+// UpdateKitchenStationValidation valida dados para atualização de estação da cozinha
+func UpdateKitchenStationValidation(station *models.KitchenStation) error {
+	return validation.ValidateStruct(station,
+		validation.Field(&station.Id, validation.Required, is.UUID),
+		validation.Field(&station.OrganizationId, validation.Required, is.UUID),
+		validation.Field(&station.ProjectId, validation.Required, is.UUID),
+		validation.Field(&station.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&station.Code, validation.Required, validation.Length(1, 50)),
+		validation.Field(&station.ProductTypes, validation.Each(validation.In("prato", "bebida", "vinho"))),
+		validation.Field(&station.CategoryIds, validation.Each(is.UUID)),
+	)
+}
+
+// OrderItemStatusValidation valida o status de item enviado pela estação
+func OrderItemStatusValidation(status string) error {
+	return validation.Validate(status,
+		validation.Required.Error("status is required"),
+		validation.In(models.OrderItemStatusQueued, models.OrderItemStatusPreparing, models.OrderItemStatusReady).
+			Error("Invalid status. Allowed: queued, preparing, ready"))
+}
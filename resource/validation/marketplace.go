@@ -0,0 +1,20 @@
+package validation
+
+import (
+	"lep/repositories/models"
+
+	"github.com/invopop/validation"
+	"github.com/invopop/validation/is"
+)
+
+// MarketplaceIntegrationValidation valida dados de cadastro/atualização de integração com marketplace
+func MarketplaceIntegrationValidation(integration *models.MarketplaceIntegration) error {
+	return validation.ValidateStruct(integration,
+		validation.Field(&integration.OrganizationId, validation.Required, is.UUID),
+		validation.Field(&integration.ProjectId, validation.Required, is.UUID),
+		validation.Field(&integration.Marketplace, validation.Required, validation.Length(1, 50)),
+		validation.Field(&integration.MerchantId, validation.Required, validation.Length(1, 100)),
+		validation.Field(&integration.WebhookSecret, validation.Length(16, 200)),
+		validation.Field(&integration.ApiBaseUrl, validation.Required, is.URL),
+	)
+}
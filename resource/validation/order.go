@@ -13,7 +13,9 @@ func CreateOrderValidation(e *models.Order) error {
 		validation.Field(&e.ProjectId, validation.Required, is.UUID),
 		validation.Field(&e.CustomerId, validation.Required, is.UUID),
 		validation.Field(&e.Items, validation.Required),
-		validation.Field(&e.TableId, validation.Required, is.UUID),
+		validation.Field(&e.TableId, validation.When(isDineIn(e), validation.Required), is.UUID),
+		validation.Field(&e.Type, validation.In(models.OrderTypeDineIn, models.OrderTypeTakeout, models.OrderTypeDelivery).
+			Error("Invalid type. Allowed: dine_in, takeout, delivery")),
 	)
 }
 
@@ -26,6 +28,13 @@ func UpdateOrderValidation(e *models.Order) error {
 		validation.Field(&e.CustomerId, validation.Required, is.UUID),
 		validation.Field(&e.Items, validation.Required),
 		validation.Field(&e.Status, validation.Required, validation.In("draft", "pending", "completed", "canceled")),
-		validation.Field(&e.TableId, validation.Required, is.UUID),
+		validation.Field(&e.TableId, validation.When(isDineIn(e), validation.Required), is.UUID),
+		validation.Field(&e.Type, validation.In(models.OrderTypeDineIn, models.OrderTypeTakeout, models.OrderTypeDelivery).
+			Error("Invalid type. Allowed: dine_in, takeout, delivery")),
 	)
 }
+
+// isDineIn pedido na mesa (tipo vazio = dine_in); retirada e entrega não têm mesa
+func isDineIn(e *models.Order) bool {
+	return e.Type == "" || e.Type == models.OrderTypeDineIn
+}
@@ -0,0 +1,34 @@
+package validation
+
+import (
+	"errors"
+	"lep/repositories/models"
+
+	"github.com/invopop/validation"
+	"github.com/invopop/validation/is"
+)
+
+// PacingRuleValidation valida dados de cadastro/atualização de regra de ritmo das reservas
+func PacingRuleValidation(rule *models.PacingRule) error {
+	rolling := rule.RollingMaxCovers > 0 || rule.RollingMaxReservations > 0
+	return validation.ValidateStruct(rule,
+		validation.Field(&rule.OrganizationId, validation.Required, is.UUID),
+		validation.Field(&rule.ProjectId, validation.Required, is.UUID),
+		validation.Field(&rule.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&rule.Service, validation.In(models.PacingServiceLunch, models.PacingServiceDinner).
+			Error("Invalid service. Allowed: lunch, dinner or empty")),
+		validation.Field(&rule.Weekdays, validation.Each(validation.Min(int64(0)), validation.Max(int64(6)))),
+		validation.Field(&rule.MaxCoversPerSlot, validation.Min(0), validation.Max(10000),
+			validation.By(func(interface{}) error {
+				if rule.MaxCoversPerSlot == 0 && rule.MaxReservationsPerSlot == 0 && !rolling {
+					return errors.New("at least one limit is required")
+				}
+				return nil
+			})),
+		validation.Field(&rule.MaxReservationsPerSlot, validation.Min(0), validation.Max(10000)),
+		validation.Field(&rule.RollingWindowMinutes, validation.Min(0), validation.Max(1440),
+			validation.When(rolling, validation.Required.Error("is required for rolling limits"))),
+		validation.Field(&rule.RollingMaxCovers, validation.Min(0), validation.Max(10000)),
+		validation.Field(&rule.RollingMaxReservations, validation.Min(0), validation.Max(10000)),
+	)
+}
@@ -0,0 +1,28 @@
+package validation
+
+import (
+	"lep/repositories/models"
+	"lep/utils"
+
+	"github.com/invopop/validation"
+	"github.com/invopop/validation/is"
+)
+
+// PrinterValidation valida dados de cadastro/atualização de impressora
+func PrinterValidation(printer *models.Printer) error {
+	return validation.ValidateStruct(printer,
+		validation.Field(&printer.OrganizationId, validation.Required, is.UUID),
+		validation.Field(&printer.ProjectId, validation.Required, is.UUID),
+		validation.Field(&printer.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&printer.Host, validation.Required, validation.By(func(interface{}) error {
+			return utils.ValidatePrinterHost(printer.Host)
+		})),
+		validation.Field(&printer.Port, validation.Required, validation.Min(1), validation.Max(65535)),
+		validation.Field(&printer.PaperWidth, validation.Required,
+			validation.In(utils.PaperWidth58, utils.PaperWidth80).Error("Invalid paper_width. Allowed: 58, 80")),
+		validation.Field(&printer.Purpose, validation.Required,
+			validation.In(models.PrinterPurposeKitchen, models.PrinterPurposeReceipt).Error("Invalid purpose. Allowed: kitchen, receipt")),
+		validation.Field(&printer.Delivery, validation.Required,
+			validation.In(models.PrinterDeliveryAgent, models.PrinterDeliveryDirect).Error("Invalid delivery. Allowed: agent, direct")),
+	)
+}
@@ -2,6 +2,7 @@ package validation
 
 import (
 	"errors"
+	"fmt"
 	"lep/repositories/models"
 
 	"github.com/invopop/validation"
@@ -20,6 +21,10 @@ func CreateProductValidation(product *models.Product) error {
 		return err
 	}
 
+	if err := validateProductOptionGroups(product); err != nil {
+		return err
+	}
+
 	// Validação de preço condicional por tipo
 	return validateProductPrice(product)
 }
@@ -37,10 +42,41 @@ func UpdateProductValidation(product *models.Product) error {
 		return err
 	}
 
+	if err := validateProductOptionGroups(product); err != nil {
+		return err
+	}
+
 	// Validação de preço condicional por tipo
 	return validateProductPrice(product)
 }
 
+// validateProductOptionGroups valida grupos de opções (limites de seleção e opções)
+func validateProductOptionGroups(product *models.Product) error {
+	for _, group := range product.OptionGroups {
+		if group.Name == "" {
+			return errors.New("option group name is required")
+		}
+		if len(group.Options) == 0 {
+			return fmt.Errorf("option group %s must have at least one option", group.Name)
+		}
+		if group.MinSelections < 0 || group.MaxSelections < 0 {
+			return fmt.Errorf("option group %s has negative selection limits", group.Name)
+		}
+		if group.MaxSelections > 0 && group.MinSelections > group.MaxSelections {
+			return fmt.Errorf("option group %s has min_selections greater than max_selections", group.Name)
+		}
+		if group.MaxSelections > 0 && group.MinSelections > len(group.Options) {
+			return fmt.Errorf("option group %s requires more selections than available options", group.Name)
+		}
+		for _, option := range group.Options {
+			if option.Name == "" {
+				return fmt.Errorf("option name is required in group %s", group.Name)
+			}
+		}
+	}
+	return nil
+}
+
 // validateProductPrice valida preço baseado no tipo do produto
 // Vinhos podem ter price_normal=0 se tiverem price_bottle ou price_glass
 func validateProductPrice(product *models.Product) error {
@@ -0,0 +1,31 @@
+package validation
+
+import (
+	"lep/repositories/models"
+
+	"github.com/invopop/validation"
+	"github.com/invopop/validation/is"
+)
+
+// CreateTabValidation valida dados para abertura de comanda
+func CreateTabValidation(tab *models.Tab) error {
+	return validation.ValidateStruct(tab,
+		validation.Field(&tab.OrganizationId, validation.Required, is.UUID),
+		validation.Field(&tab.ProjectId, validation.Required, is.UUID),
+		validation.Field(&tab.TableId, validation.Required, is.UUID),
+		validation.Field(&tab.Guests, validation.Min(0)),
+		validation.Field(&tab.Note, validation.Length(0, 500)),
+	)
+}
+
+// TabPaymentValidation valida pagamento de comanda
+func TabPaymentValidation(payment *models.TabPayment) error {
+	return validation.ValidateStruct(payment,
+		validation.Field(&payment.Method, validation.Required,
+			validation.In(models.PaymentMethodCash, models.PaymentMethodCard, models.PaymentMethodPix).
+				Error("Invalid method. Allowed: cash, card, pix")),
+		validation.Field(&payment.Amount, validation.Required, validation.Min(0.01)),
+		validation.Field(&payment.Tip, validation.Min(0.0)),
+		validation.Field(&payment.Reference, validation.Length(0, 100)),
+	)
+}
@@ -0,0 +1,31 @@
+package validation
+
+import (
+	"errors"
+	"lep/repositories/models"
+
+	"github.com/invopop/validation"
+	"github.com/invopop/validation/is"
+)
+
+// TableCombinationValidation valida dados de cadastro/atualização de montagem de mesas
+func TableCombinationValidation(combination *models.TableCombination) error {
+	return validation.ValidateStruct(combination,
+		validation.Field(&combination.OrganizationId, validation.Required, is.UUID),
+		validation.Field(&combination.ProjectId, validation.Required, is.UUID),
+		validation.Field(&combination.Name, validation.Length(0, 100)),
+		validation.Field(&combination.TableIds, validation.Required, validation.Length(2, 10), validation.Each(is.UUID),
+			validation.By(func(interface{}) error {
+				seen := make(map[string]bool, len(combination.TableIds))
+				for _, id := range combination.TableIds {
+					if seen[id] {
+						return errors.New("must not repeat tables")
+					}
+					seen[id] = true
+				}
+				return nil
+			})),
+		validation.Field(&combination.Capacity, validation.Required, validation.Min(2), validation.Max(100)),
+		validation.Field(&combination.MinPartySize, validation.Min(0), validation.Max(combination.Capacity)),
+	)
+}
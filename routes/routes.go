@@ -4,6 +4,7 @@ import (
 	"lep/handler"
 	"lep/middleware"
 	"lep/resource"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -44,6 +45,8 @@ func SetupRoutes(r *gin.Engine) {
 	webhook := r.Group("/webhook")
 	webhook.POST("/twilio/status", resource.ServersControllers.SourceNotification.TwilioWebhookStatus)
 	webhook.POST("/twilio/inbound/:orgId/:projectId", resource.ServersControllers.SourceNotification.TwilioWebhookInbound)
+	webhook.POST("/pix/:provider", resource.ServersControllers.SourcePix.ServiceWebhook)
+	webhook.POST("/marketplace/:marketplace", resource.ServersControllers.SourceMarketplace.ServiceWebhook)
 
 	// Rotas públicas de menu/reserva
 	publicRoutes := r.Group("/public")
@@ -71,6 +74,30 @@ func SetupRoutes(r *gin.Engine) {
 	publicRoutes.GET("/waitlist/org/:orgSlug", resource.ServersControllers.SourcePublic.ServiceGetPublicWaitlistBySlug)
 	publicRoutes.GET("/waitlist/org/:orgSlug/:projectSlug", resource.ServersControllers.SourcePublic.ServiceGetPublicWaitlistBySlug)
 
+	// Pedido pela mesa (QR Code) - limitado por IP
+	publicRoutes.POST("/order/org/:orgSlug/:projectSlug/table/:number", middleware.RateLimitMiddleware(10, time.Minute), resource.ServersControllers.SourcePublic.ServiceCreatePublicOrderBySlug)
+	publicRoutes.GET("/order/track/:token", middleware.RateLimitMiddleware(60, time.Minute), resource.ServersControllers.SourcePublic.ServiceGetPublicOrderTracking)
+	// Retirada e entrega pelo site (horários, taxa da zona e pedido)
+	publicRoutes.POST("/order/org/:orgSlug/:projectSlug", middleware.RateLimitMiddleware(10, time.Minute), resource.ServersControllers.SourcePublic.ServiceCreatePublicTakeoutOrderBySlug)
+	publicRoutes.GET("/order/org/:orgSlug/:projectSlug/slots", resource.ServersControllers.SourcePublic.ServiceGetPublicOrderSlotsBySlug)
+	publicRoutes.POST("/order/org/:orgSlug/:projectSlug/delivery-quote", middleware.RateLimitMiddleware(30, time.Minute), resource.ServersControllers.SourcePublic.ServiceQuotePublicDeliveryBySlug)
+	// Chamados da mesa (garçom, conta, água, talheres) - limitado por IP e mesa só contra rajadas;
+	// o limite real de chamados é o da mesa (5 abertos, 10 em 10 minutos) e toques repetidos devolvem o chamado aberto
+	publicRoutes.POST("/service-request/org/:orgSlug/:projectSlug/table/:number", middleware.RateLimitMiddleware(20, time.Minute), resource.ServersControllers.SourcePublic.ServiceCreatePublicServiceRequestBySlug)
+	publicRoutes.GET("/service-request/org/:orgSlug/:projectSlug/table/:number", middleware.RateLimitMiddleware(60, time.Minute), resource.ServersControllers.SourcePublic.ServiceListPublicServiceRequestsBySlug)
+
+	// Streams em tempo real autenticados por ticket curto (?ticket=), emitido em POST /realtime/ticket
+	realtime := r.Group("/realtime")
+	realtime.Use(middleware.RealtimeTicketMiddleware())
+	realtime.GET("/stream", resource.ServersControllers.SourceRealtime.ServiceStreamEvents)
+	realtime.GET("/ws", resource.ServersControllers.SourceRealtime.ServiceWebSocketEvents)
+
+	// Agente de impressão na rede do restaurante: busca os trabalhos e devolve o resultado (X-Print-Agent-Token)
+	printAgent := r.Group("/print/agent")
+	printAgent.Use(middleware.PrintAgentMiddleware(resource.Handlers.HandlerPrint))
+	printAgent.GET("/jobs", resource.ServersControllers.SourcePrint.ServiceAgentClaimJobs)
+	printAgent.POST("/jobs/:id/result", resource.ServersControllers.SourcePrint.ServiceAgentJobResult)
+
 	// =============================================================================
 	// 2. ROTAS PROTEGIDAS (auth + headers obrigatórios)
 	// =============================================================================
@@ -431,6 +458,25 @@ func SetupRoutes(r *gin.Engine) {
 	table.POST("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_create", 1), middleware.PackageLimitMiddleware(resource.Handlers.HandlerLimits, handler.LimitTables), resource.ServersControllers.SourceTables.ServiceCreateTable)
 	table.PUT("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_edit", 1), resource.ServersControllers.SourceTables.ServiceUpdateTable)
 	table.DELETE("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_delete", 1), resource.ServersControllers.SourceTables.ServiceDeleteTable)
+	table.POST("/:id/transfer", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_edit", 1), resource.ServersControllers.SourceTables.ServiceTransferTable)
+	table.POST("/:id/merge", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_edit", 1), resource.ServersControllers.SourceTables.ServiceMergeTables)
+	table.POST("/:id/split", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_edit", 1), resource.ServersControllers.SourceTables.ServiceSplitTables)
+
+	// Montagens de mesas (mesas juntadas para grupos maiores)
+	tableCombination := protected.Group("/table-combination")
+	tableCombination.GET("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_view", 1), resource.ServersControllers.SourceTableAllocation.ServiceListCombinations)
+	tableCombination.GET("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_view", 1), resource.ServersControllers.SourceTableAllocation.ServiceGetCombination)
+	tableCombination.POST("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_create", 1), resource.ServersControllers.SourceTableAllocation.ServiceCreateCombination)
+	tableCombination.PUT("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_edit", 1), resource.ServersControllers.SourceTableAllocation.ServiceUpdateCombination)
+	tableCombination.DELETE("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_delete", 1), resource.ServersControllers.SourceTableAllocation.ServiceDeleteCombination)
+
+	// Chamados das mesas (garçom, conta, água, talheres e mensagens)
+	serviceRequest := protected.Group("/service-request")
+	serviceRequest.GET("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_view", 1), resource.ServersControllers.SourceServiceRequest.ServiceListRequests)
+	serviceRequest.GET("/report", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_view", 1), resource.ServersControllers.SourceServiceRequest.ServiceGetReport)
+	serviceRequest.GET("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_view", 1), resource.ServersControllers.SourceServiceRequest.ServiceGetRequest)
+	serviceRequest.POST("/:id/acknowledge", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_edit", 1), resource.ServersControllers.SourceServiceRequest.ServiceAcknowledgeRequest)
+	serviceRequest.POST("/:id/resolve", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_tables_edit", 1), resource.ServersControllers.SourceServiceRequest.ServiceResolveRequest)
 
 	// Reservation (requer módulo)
 	reservation := protected.Group("/reservation")
@@ -440,6 +486,26 @@ func SetupRoutes(r *gin.Engine) {
 	reservation.POST("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_create", 1), middleware.PackageLimitMiddleware(resource.Handlers.HandlerLimits, handler.LimitReservationsDay), resource.ServersControllers.SourceReservation.ServiceCreateReservation)
 	reservation.PUT("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_edit", 1), resource.ServersControllers.SourceReservation.ServiceUpdateReservation)
 	reservation.DELETE("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_delete", 1), resource.ServersControllers.SourceReservation.ServiceDeleteReservation)
+	reservation.POST("/reoptimize", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_edit", 1), resource.ServersControllers.SourceTableAllocation.ServiceReoptimize)
+
+	// Períodos bloqueados para reservas (avulsos ou recorrentes, do projeto, ambiente ou mesas)
+	blockedPeriod := protected.Group("/blocked-period")
+	blockedPeriod.Use(middleware.ModuleRequiredMiddleware(resource.Handlers.HandlerLimits, "client_reservations"))
+	blockedPeriod.GET("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_view", 1), resource.ServersControllers.SourceBlockedPeriod.ServiceListBlockedPeriods)
+	blockedPeriod.GET("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_view", 1), resource.ServersControllers.SourceBlockedPeriod.ServiceGetBlockedPeriod)
+	blockedPeriod.POST("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_create", 1), resource.ServersControllers.SourceBlockedPeriod.ServiceCreateBlockedPeriod)
+	blockedPeriod.PUT("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_edit", 1), resource.ServersControllers.SourceBlockedPeriod.ServiceUpdateBlockedPeriod)
+	blockedPeriod.DELETE("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_delete", 1), resource.ServersControllers.SourceBlockedPeriod.ServiceDeleteBlockedPeriod)
+
+	// Ritmo das reservas (limite de pessoas e reservas por horário)
+	pacingRule := protected.Group("/pacing-rule")
+	pacingRule.Use(middleware.ModuleRequiredMiddleware(resource.Handlers.HandlerLimits, "client_reservations"))
+	pacingRule.GET("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_view", 1), resource.ServersControllers.SourcePacing.ServiceListRules)
+	pacingRule.GET("/report", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_view", 1), resource.ServersControllers.SourcePacing.ServiceGetReport)
+	pacingRule.GET("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_view", 1), resource.ServersControllers.SourcePacing.ServiceGetRule)
+	pacingRule.POST("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_create", 1), resource.ServersControllers.SourcePacing.ServiceCreateRule)
+	pacingRule.PUT("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_edit", 1), resource.ServersControllers.SourcePacing.ServiceUpdateRule)
+	pacingRule.DELETE("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_reservations_delete", 1), resource.ServersControllers.SourcePacing.ServiceDeleteRule)
 
 	// Customer
 	customer := protected.Group("/customer")
@@ -457,11 +523,143 @@ func SetupRoutes(r *gin.Engine) {
 	order.POST("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_create", 1), resource.ServersControllers.SourceOrders.CreateOrder)
 	order.PUT("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceOrders.UpdateOrder)
 	order.PUT("/:id/status", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceOrders.UpdateOrderStatus)
+	order.POST("/:id/approve", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceOrders.ApproveOrder)
+	order.POST("/:id/fire", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceOrders.FireCourse)
 	order.DELETE("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_delete", 1), resource.ServersControllers.SourceOrders.SoftDeleteOrder)
 
 	// Kitchen
 	kitchen := protected.Group("/kitchen")
 	kitchen.GET("/queue", resource.ServersControllers.SourceOrders.GetKitchenQueue)
+	kitchen.GET("/station", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceKitchenStation.ServiceListStations)
+	kitchen.GET("/station/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceKitchenStation.ServiceGetStation)
+	kitchen.GET("/station/:id/queue", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceOrders.GetStationQueue)
+	kitchen.POST("/station", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceKitchenStation.ServiceCreateStation)
+	kitchen.POST("/station/defaults", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceKitchenStation.ServiceCreateDefaultStations)
+	kitchen.PUT("/station/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceKitchenStation.ServiceUpdateStation)
+	kitchen.DELETE("/station/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_delete", 1), resource.ServersControllers.SourceKitchenStation.ServiceDeleteStation)
+	kitchen.PUT("/order/:id/item/:itemId/status", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceOrders.UpdateOrderItemStatus)
+	kitchen.GET("/prep-time", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourcePrepTime.ServiceGetStats)
+	kitchen.POST("/prep-time/recompute", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourcePrepTime.ServiceRecompute)
+	kitchen.GET("/prep-time/accuracy", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourcePrepTime.ServiceGetAccuracy)
+	kitchen.GET("/sla/report", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceSLA.ServiceGetReport)
+	kitchen.GET("/sla/breach", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceSLA.ServiceListBreaches)
+
+	// Tab (comanda da mesa: pedidos, taxa de serviço, divisão e pagamentos)
+	tab := protected.Group("/tab")
+	tab.GET("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceTab.ServiceListTabs)
+	tab.GET("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceTab.ServiceGetTab)
+	tab.POST("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_create", 1), resource.ServersControllers.SourceTab.ServiceOpenTab)
+	tab.POST("/:id/order/:orderId", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceTab.ServiceAttachOrder)
+	tab.DELETE("/:id/order/:orderId", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceTab.ServiceDetachOrder)
+	tab.PUT("/:id/service-charge", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceTab.ServiceSetServiceCharge)
+	tab.POST("/:id/split", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceTab.ServiceSplitTab)
+	tab.POST("/:id/payment", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceTab.ServiceAddPayment)
+	tab.POST("/:id/close", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceTab.ServiceCloseTab)
+
+	// Inventory (estoque: livro de movimentações, ajustes e estoque baixo)
+	inventory := protected.Group("/inventory")
+	inventory.GET("/movements", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourceInventory.ServiceListMovements)
+	inventory.GET("/low-stock", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourceInventory.ServiceListLowStock)
+	inventory.POST("/product/:id/adjust", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_edit", 1), resource.ServersControllers.SourceInventory.ServiceAdjustStock)
+	inventory.GET("/consumption", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourceIngredient.ServiceGetConsumption)
+	inventory.GET("/food-cost", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourceIngredient.ServiceListFoodCost)
+	inventory.GET("/count", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourceIngredient.ServiceListCounts)
+	inventory.GET("/count/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourceIngredient.ServiceGetCount)
+	inventory.POST("/count", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_edit", 1), resource.ServersControllers.SourceIngredient.ServiceCreateCount)
+
+	// Ingredients (insumos e fichas técnicas)
+	ingredient := protected.Group("/ingredient")
+	ingredient.GET("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourceIngredient.ServiceListIngredients)
+	ingredient.GET("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourceIngredient.ServiceGetIngredient)
+	ingredient.POST("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_create", 1), resource.ServersControllers.SourceIngredient.ServiceCreateIngredient)
+	ingredient.PUT("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_edit", 1), resource.ServersControllers.SourceIngredient.ServiceUpdateIngredient)
+	ingredient.DELETE("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_delete", 1), resource.ServersControllers.SourceIngredient.ServiceDeleteIngredient)
+	ingredient.GET("/recipe/product/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourceIngredient.ServiceGetRecipe)
+	ingredient.PUT("/recipe/product/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_edit", 1), resource.ServersControllers.SourceIngredient.ServiceSetRecipe)
+
+	// Suppliers (fornecedores e pedidos de compra)
+	supplier := protected.Group("/supplier")
+	supplier.GET("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourceSupplier.ServiceListSuppliers)
+	supplier.GET("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourceSupplier.ServiceGetSupplier)
+	supplier.POST("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_create", 1), resource.ServersControllers.SourceSupplier.ServiceCreateSupplier)
+	supplier.PUT("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_edit", 1), resource.ServersControllers.SourceSupplier.ServiceUpdateSupplier)
+	supplier.DELETE("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_delete", 1), resource.ServersControllers.SourceSupplier.ServiceDeleteSupplier)
+
+	purchaseOrder := protected.Group("/purchase-order")
+	purchaseOrder.GET("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourceSupplier.ServiceListPurchaseOrders)
+	purchaseOrder.GET("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourceSupplier.ServiceGetPurchaseOrder)
+	purchaseOrder.POST("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_create", 1), resource.ServersControllers.SourceSupplier.ServiceCreatePurchaseOrder)
+	purchaseOrder.PUT("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_edit", 1), resource.ServersControllers.SourceSupplier.ServiceUpdatePurchaseOrder)
+	purchaseOrder.POST("/:id/send", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_edit", 1), resource.ServersControllers.SourceSupplier.ServiceSendPurchaseOrder)
+	purchaseOrder.POST("/:id/receive", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_edit", 1), resource.ServersControllers.SourceSupplier.ServiceReceivePurchaseOrder)
+	purchaseOrder.POST("/:id/cancel", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_edit", 1), resource.ServersControllers.SourceSupplier.ServiceCancelPurchaseOrder)
+
+	// Printing (impressoras ESC/POS, fila de impressão e reimpressão)
+	printer := protected.Group("/printer")
+	printer.GET("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourcePrint.ServiceListPrinters)
+	printer.GET("/agent", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourcePrint.ServiceGetAgent)
+	printer.POST("/agent/token", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourcePrint.ServiceGenerateAgentToken)
+	printer.GET("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourcePrint.ServiceGetPrinter)
+	printer.POST("", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_create", 1), resource.ServersControllers.SourcePrint.ServiceCreatePrinter)
+	printer.PUT("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourcePrint.ServiceUpdatePrinter)
+	printer.DELETE("/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_delete", 1), resource.ServersControllers.SourcePrint.ServiceDeletePrinter)
+	printer.POST("/:id/test", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourcePrint.ServiceTestPrinter)
+
+	print := protected.Group("/print")
+	print.POST("/order/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourcePrint.ServicePrintOrder)
+	print.POST("/table/:id/receipt", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourcePrint.ServicePrintTableReceipt)
+	print.GET("/job", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourcePrint.ServiceListJobs)
+	print.GET("/job/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourcePrint.ServiceGetJob)
+	print.POST("/job/:id/retry", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourcePrint.ServiceRetryJob)
+
+	// Delivery (zonas de entrega, cotação de taxa e horários de retirada/entrega)
+	delivery := protected.Group("/delivery")
+	delivery.GET("/zone", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceDelivery.ServiceListZones)
+	delivery.GET("/zone/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceDelivery.ServiceGetZone)
+	delivery.POST("/zone", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_create", 1), resource.ServersControllers.SourceDelivery.ServiceCreateZone)
+	delivery.PUT("/zone/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceDelivery.ServiceUpdateZone)
+	delivery.DELETE("/zone/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_delete", 1), resource.ServersControllers.SourceDelivery.ServiceDeleteZone)
+	delivery.POST("/quote", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceDelivery.ServiceQuoteDelivery)
+	delivery.GET("/slots", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceDelivery.ServiceListOrderSlots)
+
+	// Marketplaces de delivery (lojas integradas, webhooks recebidos e status enviados)
+	marketplace := protected.Group("/marketplace")
+	marketplace.GET("/integration", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceMarketplace.ServiceListIntegrations)
+	marketplace.GET("/integration/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceMarketplace.ServiceGetIntegration)
+	marketplace.POST("/integration", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_create", 1), resource.ServersControllers.SourceMarketplace.ServiceCreateIntegration)
+	marketplace.PUT("/integration/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceMarketplace.ServiceUpdateIntegration)
+	marketplace.DELETE("/integration/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_delete", 1), resource.ServersControllers.SourceMarketplace.ServiceDeleteIntegration)
+	marketplace.GET("/event", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceMarketplace.ServiceListEvents)
+	marketplace.POST("/event/:id/retry", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceMarketplace.ServiceRetryEvent)
+
+	// PDV (importação de cardápio e exportação de vendas por código PDV)
+	pos := protected.Group("/pos")
+	pos.POST("/catalog/import", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_edit", 1), resource.ServersControllers.SourcePos.ServiceImportCatalog)
+	pos.GET("/sales/export", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourcePos.ServiceExportSales)
+	pos.GET("/run", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourcePos.ServiceListRuns)
+	pos.GET("/run/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_products_view", 1), resource.ServersControllers.SourcePos.ServiceGetRun)
+
+	// Fiscal: NFC-e dos pedidos entregues
+	fiscal := protected.Group("/fiscal")
+	fiscal.GET("/nfce", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceFiscal.ServiceListDocuments)
+	fiscal.GET("/nfce/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceFiscal.ServiceGetDocument)
+	fiscal.GET("/nfce/:id/xml", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceFiscal.ServiceGetDocumentXML)
+	fiscal.GET("/nfce/:id/qrcode.png", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourceFiscal.ServiceGetDocumentQRCode)
+	fiscal.POST("/nfce", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceFiscal.ServiceIssueNfce)
+	fiscal.POST("/nfce/:id/transmit", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceFiscal.ServiceTransmitNfce)
+	fiscal.POST("/nfce/:id/cancel", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourceFiscal.ServiceCancelNfce)
+
+	// Pix (BR Code "copia e cola", QR Code e confirmação de pagamento)
+	pix := protected.Group("/pix")
+	pix.GET("/charge", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourcePix.ServiceListCharges)
+	pix.GET("/charge/:id", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourcePix.ServiceGetCharge)
+	pix.GET("/charge/:id/qrcode.png", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_view", 1), resource.ServersControllers.SourcePix.ServiceGetChargeQRCode)
+	pix.POST("/charge", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourcePix.ServiceCreateCharge)
+	pix.POST("/charge/:id/simulate-payment", middleware.RolePermissionMiddleware(resource.Handlers.HandlerRole, "client_orders_edit", 1), resource.ServersControllers.SourcePix.ServiceSimulatePayment)
+
+	// Realtime (SSE/WebSocket) - substitui polling das telas de cozinha, salão e fila.
+	// O ticket é emitido com auth + headers; os streams usam só o ticket (navegador não envia headers)
+	protected.POST("/realtime/ticket", resource.ServersControllers.SourceRealtime.ServiceCreateTicket)
 
 	// Waitlist (requer módulo)
 	waitlist := protected.Group("/waitlist")
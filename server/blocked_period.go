@@ -0,0 +1,176 @@
+package server
+
+import (
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ResourceBlockedPeriod struct {
+	handler *handler.Handlers
+}
+
+type IServerBlockedPeriod interface {
+	ServiceGetBlockedPeriod(c *gin.Context)
+	ServiceListBlockedPeriods(c *gin.Context)
+	ServiceCreateBlockedPeriod(c *gin.Context)
+	ServiceUpdateBlockedPeriod(c *gin.Context)
+	ServiceDeleteBlockedPeriod(c *gin.Context)
+}
+
+func (r *ResourceBlockedPeriod) ServiceGetBlockedPeriod(c *gin.Context) {
+	period, ok := r.loadBlockedPeriod(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, period)
+}
+
+func (r *ResourceBlockedPeriod) ServiceListBlockedPeriods(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	periods, err := r.handler.HandlerBlockedPeriod.ListBlockedPeriods(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing blocked periods", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, periods)
+}
+
+func (r *ResourceBlockedPeriod) ServiceCreateBlockedPeriod(c *gin.Context) {
+	var newPeriod models.BlockedPeriod
+	if err := c.BindJSON(&newPeriod); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	var err error
+	newPeriod.OrganizationId, err = uuid.Parse(c.GetString("organization_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid organization ID", err)
+		return
+	}
+	newPeriod.ProjectId, err = uuid.Parse(c.GetString("project_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid project ID", err)
+		return
+	}
+	// Novo bloqueio nasce ativo
+	newPeriod.Active = true
+	applyBlockedPeriodDefaults(&newPeriod)
+
+	if err := validation.BlockedPeriodValidation(&newPeriod); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerBlockedPeriod.CreateBlockedPeriod(&newPeriod); err != nil {
+		sendBlockedPeriodError(c, "Error creating blocked period", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Blocked period created successfully", newPeriod)
+}
+
+func (r *ResourceBlockedPeriod) ServiceUpdateBlockedPeriod(c *gin.Context) {
+	existing, ok := r.loadBlockedPeriod(c)
+	if !ok {
+		return
+	}
+
+	var updatedPeriod models.BlockedPeriod
+	if err := c.BindJSON(&updatedPeriod); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	updatedPeriod.Id = existing.Id
+	updatedPeriod.OrganizationId = existing.OrganizationId
+	updatedPeriod.ProjectId = existing.ProjectId
+	updatedPeriod.CreatedAt = existing.CreatedAt
+	updatedPeriod.DeletedAt = nil
+	applyBlockedPeriodDefaults(&updatedPeriod)
+
+	if err := validation.BlockedPeriodValidation(&updatedPeriod); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerBlockedPeriod.UpdateBlockedPeriod(&updatedPeriod); err != nil {
+		sendBlockedPeriodError(c, "Error updating blocked period", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Blocked period updated successfully", updatedPeriod)
+}
+
+func (r *ResourceBlockedPeriod) ServiceDeleteBlockedPeriod(c *gin.Context) {
+	existing, ok := r.loadBlockedPeriod(c)
+	if !ok {
+		return
+	}
+
+	if err := r.handler.HandlerBlockedPeriod.DeleteBlockedPeriod(existing.Id.String()); err != nil {
+		utils.SendInternalServerError(c, "Error deleting blocked period", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Blocked period deleted successfully", nil)
+}
+
+// loadBlockedPeriod busca o período da rota e valida que pertence ao projeto
+func (r *ResourceBlockedPeriod) loadBlockedPeriod(c *gin.Context) (*models.BlockedPeriod, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "blocked period")
+	if !ok {
+		return nil, false
+	}
+
+	period, err := r.handler.HandlerBlockedPeriod.GetBlockedPeriod(id.String())
+	if err != nil || period == nil {
+		utils.SendNotFoundError(c, "Blocked period")
+		return nil, false
+	}
+
+	if period.OrganizationId.String() != c.GetString("organization_id") ||
+		period.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return period, true
+}
+
+// applyBlockedPeriodDefaults sem recorrência informada o bloqueio é avulso
+func applyBlockedPeriodDefaults(period *models.BlockedPeriod) {
+	if period.RecurringType == "" {
+		period.RecurringType = models.BlockedPeriodRecurringNone
+	}
+	if period.RecurringInterval == 0 {
+		period.RecurringInterval = 1
+	}
+}
+
+// sendBlockedPeriodError traduz erros de negócio dos períodos bloqueados
+func sendBlockedPeriodError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "invalid_blocked_period"):
+		utils.SendBadRequestError(c, message, err)
+	default:
+		utils.SendInternalServerError(c, message, err)
+	}
+}
+
+func NewSourceServerBlockedPeriod(handler *handler.Handlers) IServerBlockedPeriod {
+	return &ResourceBlockedPeriod{handler: handler}
+}
@@ -0,0 +1,242 @@
+package server
+
+import (
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ResourceDelivery struct {
+	handler *handler.Handlers
+}
+
+type IServerDelivery interface {
+	ServiceGetZone(c *gin.Context)
+	ServiceListZones(c *gin.Context)
+	ServiceCreateZone(c *gin.Context)
+	ServiceUpdateZone(c *gin.Context)
+	ServiceDeleteZone(c *gin.Context)
+	ServiceQuoteDelivery(c *gin.Context)
+	ServiceListOrderSlots(c *gin.Context)
+}
+
+func (r *ResourceDelivery) ServiceGetZone(c *gin.Context) {
+	zone, ok := r.loadZone(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, zone)
+}
+
+func (r *ResourceDelivery) ServiceListZones(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	zones, err := r.handler.HandlerDelivery.ListZones(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing delivery zones", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, zones)
+}
+
+func (r *ResourceDelivery) ServiceCreateZone(c *gin.Context) {
+	var newZone models.DeliveryZone
+	if err := c.BindJSON(&newZone); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	var err error
+	newZone.OrganizationId, err = uuid.Parse(c.GetString("organization_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid organization ID", err)
+		return
+	}
+	newZone.ProjectId, err = uuid.Parse(c.GetString("project_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid project ID", err)
+		return
+	}
+
+	if err := validation.DeliveryZoneValidation(&newZone); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerDelivery.CreateZone(&newZone); err != nil {
+		utils.SendInternalServerError(c, "Error creating delivery zone", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Delivery zone created successfully", newZone)
+}
+
+func (r *ResourceDelivery) ServiceUpdateZone(c *gin.Context) {
+	existing, ok := r.loadZone(c)
+	if !ok {
+		return
+	}
+
+	var updatedZone models.DeliveryZone
+	if err := c.BindJSON(&updatedZone); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Manter dados imutáveis
+	updatedZone.Id = existing.Id
+	updatedZone.OrganizationId = existing.OrganizationId
+	updatedZone.ProjectId = existing.ProjectId
+	updatedZone.CreatedAt = existing.CreatedAt
+	updatedZone.DeletedAt = nil
+
+	if err := validation.DeliveryZoneValidation(&updatedZone); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerDelivery.UpdateZone(&updatedZone); err != nil {
+		utils.SendInternalServerError(c, "Error updating delivery zone", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Delivery zone updated successfully", updatedZone)
+}
+
+func (r *ResourceDelivery) ServiceDeleteZone(c *gin.Context) {
+	existing, ok := r.loadZone(c)
+	if !ok {
+		return
+	}
+
+	if err := r.handler.HandlerDelivery.DeleteZone(existing.Id.String()); err != nil {
+		utils.SendInternalServerError(c, "Error deleting delivery zone", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Delivery zone deleted successfully", nil)
+}
+
+// ServiceQuoteDelivery retorna zona, taxa e pedido mínimo para o endereço informado
+func (r *ResourceDelivery) ServiceQuoteDelivery(c *gin.Context) {
+	orgId, projectId, ok := projectFromContext(c)
+	if !ok {
+		return
+	}
+
+	var address models.Address
+	if err := c.ShouldBindJSON(&address); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	quote, err := r.handler.HandlerDelivery.QuoteDelivery(orgId, projectId, &address)
+	if err != nil {
+		sendDeliveryError(c, "Error quoting delivery", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+// ServiceListOrderSlots lista horários de retirada/entrega do dia (?type=takeout|delivery&date=YYYY-MM-DD)
+func (r *ResourceDelivery) ServiceListOrderSlots(c *gin.Context) {
+	orgId, projectId, ok := projectFromContext(c)
+	if !ok {
+		return
+	}
+
+	date, ok := parseSlotDate(c, r.handler.HandlerProject.Clock(projectId.String()))
+	if !ok {
+		return
+	}
+
+	slots, err := r.handler.HandlerDelivery.ListOrderSlots(orgId, projectId, c.DefaultQuery("type", models.OrderTypeTakeout), date)
+	if err != nil {
+		sendDeliveryError(c, "Error listing order slots", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, slots)
+}
+
+// loadZone busca a zona de entrega da rota e valida que pertence ao projeto
+func (r *ResourceDelivery) loadZone(c *gin.Context) (*models.DeliveryZone, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "delivery zone")
+	if !ok {
+		return nil, false
+	}
+
+	zone, err := r.handler.HandlerDelivery.GetZone(id.String())
+	if err != nil || zone == nil {
+		utils.SendNotFoundError(c, "Delivery zone")
+		return nil, false
+	}
+
+	if zone.OrganizationId.String() != c.GetString("organization_id") ||
+		zone.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return zone, true
+}
+
+// projectFromContext organização e projeto validados pelo middleware
+func projectFromContext(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	orgId, err := uuid.Parse(c.GetString("organization_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid organization ID", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	projectId, err := uuid.Parse(c.GetString("project_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid project ID", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return orgId, projectId, true
+}
+
+// parseSlotDate lê a data dos slots (?date=YYYY-MM-DD; padrão hoje no fuso do projeto)
+func parseSlotDate(c *gin.Context, clock *utils.Clock) (time.Time, bool) {
+	dateStr := c.Query("date")
+	if dateStr == "" {
+		return clock.Today(), true
+	}
+	date, err := clock.ParseDate(dateStr)
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid date format. Use YYYY-MM-DD", err)
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// sendDeliveryError mapeia erros de tipo de pedido, entrega e horário agendado
+func sendDeliveryError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "invalid_slot"):
+		utils.SendConflictError(c, message, err)
+	case strings.Contains(err.Error(), "invalid_delivery"):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	case strings.Contains(err.Error(), "invalid_order_type"):
+		utils.SendBadRequestError(c, message, err)
+	default:
+		utils.SendInternalServerError(c, message, err)
+	}
+}
+
+func NewSourceServerDelivery(handler *handler.Handlers) IServerDelivery {
+	return &ResourceDelivery{handler: handler}
+}
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"fmt"
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResourceFiscal struct {
+	handler *handler.Handlers
+}
+
+type IServerFiscal interface {
+	ServiceIssueNfce(c *gin.Context)
+	ServiceListDocuments(c *gin.Context)
+	ServiceGetDocument(c *gin.Context)
+	ServiceGetDocumentXML(c *gin.Context)
+	ServiceGetDocumentQRCode(c *gin.Context)
+	ServiceTransmitNfce(c *gin.Context)
+	ServiceCancelNfce(c *gin.Context)
+}
+
+// ServiceIssueNfce emite NFC-e para pedidos entregues
+func (r *ResourceFiscal) ServiceIssueNfce(c *gin.Context) {
+	var request models.NfceIssueRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	document, err := r.handler.HandlerFiscal.IssueNfce(organizationId, projectId, request, c.GetString("user_id"))
+	if err != nil {
+		sendFiscalError(c, "Error issuing NFC-e", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "NFC-e issued", document)
+}
+
+// ServiceListDocuments lista notas do projeto (?status=&order_id=&limit=100)
+func (r *ResourceFiscal) ServiceListDocuments(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	documents, err := r.handler.HandlerFiscal.ListDocuments(organizationId, projectId, c.Query("status"), c.Query("order_id"), limit)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing fiscal documents", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, documents)
+}
+
+func (r *ResourceFiscal) ServiceGetDocument(c *gin.Context) {
+	document, ok := r.getOwnedDocument(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
+
+// ServiceGetDocumentXML baixa o XML da nota (nfeProc quando autorizada)
+func (r *ResourceFiscal) ServiceGetDocumentXML(c *gin.Context) {
+	document, ok := r.getOwnedDocument(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", document.AccessKey+"-nfce.xml"))
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", []byte(document.XML))
+}
+
+// ServiceGetDocumentQRCode PNG do QR Code do DANFE NFC-e (?size=256)
+func (r *ResourceFiscal) ServiceGetDocumentQRCode(c *gin.Context) {
+	document, ok := r.getOwnedDocument(c)
+	if !ok {
+		return
+	}
+
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "256"))
+	if size < 128 || size > 1024 {
+		utils.SendBadRequestError(c, "Invalid size. Allowed: 128 to 1024", nil)
+		return
+	}
+
+	png, err := r.handler.HandlerFiscal.GetDocumentQRCode(document.Id.String(), size)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error generating QR code", err)
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// ServiceTransmitNfce reenvia à SEFAZ a nota que ficou sem resposta
+func (r *ResourceFiscal) ServiceTransmitNfce(c *gin.Context) {
+	document, ok := r.getOwnedDocument(c)
+	if !ok {
+		return
+	}
+
+	transmitted, err := r.handler.HandlerFiscal.TransmitNfce(document.Id.String())
+	if err != nil {
+		sendFiscalError(c, "Error transmitting NFC-e", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "NFC-e transmitted", transmitted)
+}
+
+// ServiceCancelNfce cancela a nota autorizada ({"reason": "..."})
+func (r *ResourceFiscal) ServiceCancelNfce(c *gin.Context) {
+	document, ok := r.getOwnedDocument(c)
+	if !ok {
+		return
+	}
+
+	var request models.NfceCancelRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	cancelled, err := r.handler.HandlerFiscal.CancelNfce(document.Id.String(), request)
+	if err != nil {
+		sendFiscalError(c, "Error cancelling NFC-e", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "NFC-e cancelled", cancelled)
+}
+
+// getOwnedDocument busca a nota do parâmetro :id e verifica se pertence à organização/projeto
+func (r *ResourceFiscal) getOwnedDocument(c *gin.Context) (*models.FiscalDocument, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "fiscal document")
+	if !ok {
+		return nil, false
+	}
+
+	document, err := r.handler.HandlerFiscal.GetDocument(id.String())
+	if err != nil || document == nil {
+		utils.SendNotFoundError(c, "Fiscal document")
+		return nil, false
+	}
+
+	if document.OrganizationId.String() != c.GetString("organization_id") ||
+		document.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return document, true
+}
+
+// sendFiscalError converte erros do módulo fiscal em respostas HTTP
+func sendFiscalError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "nfce_not_configured"),
+		strings.Contains(err.Error(), "invalid_certificate"),
+		strings.Contains(err.Error(), "nfce_rejected"):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	case strings.Contains(err.Error(), "invalid_nfce"):
+		utils.SendBadRequestError(c, message, err)
+	case strings.Contains(err.Error(), "fiscal_conflict"):
+		utils.SendConflictError(c, message, err)
+	case strings.Contains(err.Error(), "nfce_unavailable"):
+		utils.SendError(c, http.StatusBadGateway, message, err)
+	case strings.Contains(err.Error(), "not found"):
+		utils.SendError(c, http.StatusNotFound, message, err)
+	default:
+		utils.SendInternalServerError(c, message, err)
+	}
+}
+
+func NewSourceServerFiscal(handler *handler.Handlers) IServerFiscal {
+	return &ResourceFiscal{handler: handler}
+}
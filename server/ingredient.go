@@ -0,0 +1,338 @@
+package server
+
+import (
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ResourceIngredient struct {
+	handler *handler.Handlers
+}
+
+type IServerIngredient interface {
+	ServiceGetIngredient(c *gin.Context)
+	ServiceListIngredients(c *gin.Context)
+	ServiceCreateIngredient(c *gin.Context)
+	ServiceUpdateIngredient(c *gin.Context)
+	ServiceDeleteIngredient(c *gin.Context)
+	ServiceGetRecipe(c *gin.Context)
+	ServiceSetRecipe(c *gin.Context)
+	ServiceListFoodCost(c *gin.Context)
+	ServiceGetConsumption(c *gin.Context)
+	ServiceCreateCount(c *gin.Context)
+	ServiceGetCount(c *gin.Context)
+	ServiceListCounts(c *gin.Context)
+}
+
+func (r *ResourceIngredient) ServiceGetIngredient(c *gin.Context) {
+	ingredient, ok := r.loadIngredient(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, ingredient)
+}
+
+func (r *ResourceIngredient) ServiceListIngredients(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	ingredients, err := r.handler.HandlerIngredient.ListIngredients(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing ingredients", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ingredients)
+}
+
+func (r *ResourceIngredient) ServiceCreateIngredient(c *gin.Context) {
+	var newIngredient models.Ingredient
+	if err := c.BindJSON(&newIngredient); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	var err error
+	newIngredient.OrganizationId, err = uuid.Parse(c.GetString("organization_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid organization ID", err)
+		return
+	}
+	newIngredient.ProjectId, err = uuid.Parse(c.GetString("project_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid project ID", err)
+		return
+	}
+
+	if err := validation.CreateIngredientValidation(&newIngredient); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerIngredient.CreateIngredient(&newIngredient); err != nil {
+		sendIngredientError(c, "Error creating ingredient", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Ingredient created successfully", newIngredient)
+}
+
+func (r *ResourceIngredient) ServiceUpdateIngredient(c *gin.Context) {
+	existing, ok := r.loadIngredient(c)
+	if !ok {
+		return
+	}
+
+	var updatedIngredient models.Ingredient
+	if err := c.BindJSON(&updatedIngredient); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Estoque e custo só mudam por recebimento de compra ou contagem
+	updatedIngredient.Id = existing.Id
+	updatedIngredient.OrganizationId = existing.OrganizationId
+	updatedIngredient.ProjectId = existing.ProjectId
+	updatedIngredient.Stock = existing.Stock
+	updatedIngredient.UnitCost = existing.UnitCost
+	updatedIngredient.LastCountedAt = existing.LastCountedAt
+	updatedIngredient.CreatedAt = existing.CreatedAt
+
+	if err := validation.UpdateIngredientValidation(&updatedIngredient); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerIngredient.UpdateIngredient(&updatedIngredient); err != nil {
+		utils.SendInternalServerError(c, "Error updating ingredient", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Ingredient updated successfully", updatedIngredient)
+}
+
+func (r *ResourceIngredient) ServiceDeleteIngredient(c *gin.Context) {
+	existing, ok := r.loadIngredient(c)
+	if !ok {
+		return
+	}
+
+	if err := r.handler.HandlerIngredient.DeleteIngredient(existing.Id.String()); err != nil {
+		utils.SendInternalServerError(c, "Error deleting ingredient", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Ingredient deleted successfully", nil)
+}
+
+func (r *ResourceIngredient) ServiceGetRecipe(c *gin.Context) {
+	product, ok := r.loadProduct(c)
+	if !ok {
+		return
+	}
+
+	recipe, err := r.handler.HandlerIngredient.GetRecipe(product)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error getting recipe", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recipe)
+}
+
+func (r *ResourceIngredient) ServiceSetRecipe(c *gin.Context) {
+	product, ok := r.loadProduct(c)
+	if !ok {
+		return
+	}
+
+	var request models.RecipeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	recipe, err := r.handler.HandlerIngredient.SetRecipe(product, request.Items)
+	if err != nil {
+		sendIngredientError(c, "Error saving recipe", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Recipe saved successfully", recipe)
+}
+
+func (r *ResourceIngredient) ServiceListFoodCost(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	foodCost, err := r.handler.HandlerIngredient.ListFoodCost(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error calculating food cost", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, foodCost)
+}
+
+func (r *ResourceIngredient) ServiceGetConsumption(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	startDateStr := c.DefaultQuery("start_date", time.Now().AddDate(0, 0, -7).Format("2006-01-02"))
+	endDateStr := c.DefaultQuery("end_date", time.Now().Format("2006-01-02"))
+
+	startDate, err := time.ParseInLocation("2006-01-02", startDateStr, time.Local)
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid start_date format", err)
+		return
+	}
+	endDate, err := time.ParseInLocation("2006-01-02", endDateStr, time.Local)
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid end_date format", err)
+		return
+	}
+	if endDate.Before(startDate) {
+		utils.SendBadRequestError(c, "end_date must not be before start_date", nil)
+		return
+	}
+
+	// end_date inclusivo
+	report, err := r.handler.HandlerIngredient.GetConsumption(organizationId, projectId, startDate, endDate.AddDate(0, 0, 1))
+	if err != nil {
+		utils.SendInternalServerError(c, "Error calculating consumption", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (r *ResourceIngredient) ServiceCreateCount(c *gin.Context) {
+	var request models.InventoryCountRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	count, err := r.handler.HandlerIngredient.CreateCount(c.GetString("organization_id"), c.GetString("project_id"), request, c.GetString("user_id"))
+	if err != nil {
+		sendIngredientError(c, "Error registering count", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Count registered successfully", count)
+}
+
+func (r *ResourceIngredient) ServiceGetCount(c *gin.Context) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "count")
+	if !ok {
+		return
+	}
+
+	count, err := r.handler.HandlerIngredient.GetCount(id.String())
+	if err != nil || count == nil {
+		utils.SendNotFoundError(c, "Count")
+		return
+	}
+
+	if count.OrganizationId.String() != c.GetString("organization_id") ||
+		count.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return
+	}
+
+	c.JSON(http.StatusOK, count)
+}
+
+func (r *ResourceIngredient) ServiceListCounts(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		utils.SendBadRequestError(c, "Invalid limit. Allowed: 1 to 100", err)
+		return
+	}
+
+	counts, err := r.handler.HandlerIngredient.ListCounts(organizationId, projectId, limit)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing counts", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
+// loadIngredient busca o ingrediente da rota e valida que pertence ao projeto
+func (r *ResourceIngredient) loadIngredient(c *gin.Context) (*models.Ingredient, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "ingredient")
+	if !ok {
+		return nil, false
+	}
+
+	ingredient, err := r.handler.HandlerIngredient.GetIngredient(id.String())
+	if err != nil || ingredient == nil {
+		utils.SendNotFoundError(c, "Ingredient")
+		return nil, false
+	}
+
+	if ingredient.OrganizationId.String() != c.GetString("organization_id") ||
+		ingredient.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return ingredient, true
+}
+
+// loadProduct busca o produto da rota e valida que pertence ao projeto
+func (r *ResourceIngredient) loadProduct(c *gin.Context) (*models.Product, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "product")
+	if !ok {
+		return nil, false
+	}
+
+	product, err := r.handler.HandlerProducts.GetProduct(id.String())
+	if err != nil || product == nil {
+		utils.SendNotFoundError(c, "Product")
+		return nil, false
+	}
+
+	if product.OrganizationId.String() != c.GetString("organization_id") ||
+		product.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return product, true
+}
+
+// sendIngredientError traduz erros de negócio de ingredientes, fichas técnicas e contagens
+func sendIngredientError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "invalid_ingredient"),
+		strings.Contains(err.Error(), "invalid_recipe"),
+		strings.Contains(err.Error(), "invalid_count"):
+		utils.SendBadRequestError(c, message, err)
+	default:
+		utils.SendInternalServerError(c, message, err)
+	}
+}
+
+func NewSourceServerIngredient(handler *handler.Handlers) IServerIngredient {
+	return &ResourceIngredient{handler: handler}
+}
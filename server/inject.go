@@ -12,10 +12,28 @@ type ServerController struct {
 	SourceProducts           IServerProducts
 	SourceAuth               IServerAuth
 	SourceOrders             IOrderServer
+	SourceKitchenStation     IServerKitchenStation
+	SourceRealtime           IServerRealtime
+	SourceTab                IServerTab
+	SourceInventory          IServerInventory
+	SourceIngredient         IServerIngredient
+	SourceSupplier           IServerSupplier
+	SourcePix                IServerPix
+	SourcePrint              IServerPrint
+	SourceDelivery           IServerDelivery
+	SourceMarketplace        IServerMarketplace
+	SourcePos                IServerPos
+	SourceFiscal             IServerFiscal
+	SourceServiceRequest     IServerServiceRequest
+	SourcePrepTime           IServerPrepTime
+	SourceSLA                IServerSLA
 	SourceOrganization       IServerOrganization
 	SourceTables             IServerTables
 	SourceWaitlist           IServerWaitlist
 	SourceReservation        IServerReservation
+	SourceBlockedPeriod      IServerBlockedPeriod
+	SourceTableAllocation    IServerTableAllocation
+	SourcePacing             IServerPacing
 	SourceCustomer           IServerCustomer
 	SourceProject            IProjectServer
 	SourceSettings           ISettingsServer
@@ -101,10 +119,28 @@ func (h *ServerController) Inject(handler *handler.Handlers) {
 	h.SourceProducts = NewSourceServerProducts(handler)
 	h.SourceAuth = NewSourceServerAuth(handler)
 	h.SourceOrders = NewOrderServer(handler.HandlerOrder)
+	h.SourceKitchenStation = NewSourceServerKitchenStation(handler)
+	h.SourceRealtime = NewSourceServerRealtime(handler)
+	h.SourceTab = NewSourceServerTab(handler)
+	h.SourceInventory = NewSourceServerInventory(handler)
+	h.SourceIngredient = NewSourceServerIngredient(handler)
+	h.SourceSupplier = NewSourceServerSupplier(handler)
+	h.SourcePix = NewSourceServerPix(handler)
+	h.SourcePrint = NewSourceServerPrint(handler)
+	h.SourceDelivery = NewSourceServerDelivery(handler)
+	h.SourceMarketplace = NewSourceServerMarketplace(handler)
+	h.SourcePos = NewSourceServerPos(handler)
+	h.SourceFiscal = NewSourceServerFiscal(handler)
+	h.SourceServiceRequest = NewSourceServerServiceRequest(handler)
+	h.SourcePrepTime = NewSourceServerPrepTime(handler)
+	h.SourceSLA = NewSourceServerSLA(handler)
 	h.SourceOrganization = NewSourceServerOrganization(handler)
 	h.SourceTables = NewSourceServerTables(handler)
 	h.SourceWaitlist = NewSourceServerWaitlist(handler)
 	h.SourceReservation = NewSourceServerReservation(handler)
+	h.SourceBlockedPeriod = NewSourceServerBlockedPeriod(handler)
+	h.SourceTableAllocation = NewSourceServerTableAllocation(handler)
+	h.SourcePacing = NewSourceServerPacing(handler)
 	h.SourceCustomer = NewSourceServerCustomer(handler)
 	h.SourceProject = NewProjectServer(handler.HandlerProject)
 	h.SourceSettings = NewSettingsServer(handler.HandlerSettings)
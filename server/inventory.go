@@ -0,0 +1,106 @@
+package server
+
+import (
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResourceInventory struct {
+	handler *handler.Handlers
+}
+
+type IServerInventory interface {
+	ServiceListMovements(c *gin.Context)
+	ServiceListLowStock(c *gin.Context)
+	ServiceAdjustStock(c *gin.Context)
+}
+
+func (r *ResourceInventory) ServiceListMovements(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	productId := c.Query("product_id")
+	if productId != "" {
+		if _, ok := validation.ParseAndValidateUUID(c, productId, "product"); !ok {
+			return
+		}
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		utils.SendBadRequestError(c, "Invalid limit. Allowed: 1 to 1000", err)
+		return
+	}
+
+	movements, err := r.handler.HandlerInventory.ListMovements(organizationId, projectId, productId, limit)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing stock movements", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, movements)
+}
+
+func (r *ResourceInventory) ServiceListLowStock(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	products, err := r.handler.HandlerInventory.ListLowStock(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing low stock products", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, products)
+}
+
+func (r *ResourceInventory) ServiceAdjustStock(c *gin.Context) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "product")
+	if !ok {
+		return
+	}
+
+	product, err := r.handler.HandlerProducts.GetProduct(id.String())
+	if err != nil || product == nil {
+		utils.SendNotFoundError(c, "Product")
+		return
+	}
+
+	if product.OrganizationId.String() != c.GetString("organization_id") ||
+		product.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return
+	}
+
+	var request models.StockAdjustmentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+	request.Reason = strings.TrimSpace(request.Reason)
+
+	movement, err := r.handler.HandlerInventory.AdjustStock(id.String(), request, c.GetString("user_id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid_stock") {
+			utils.SendBadRequestError(c, "Error adjusting stock", err)
+			return
+		}
+		utils.SendInternalServerError(c, "Error adjusting stock", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Stock adjusted successfully", movement)
+}
+
+func NewSourceServerInventory(handler *handler.Handlers) IServerInventory {
+	return &ResourceInventory{handler: handler}
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ResourceKitchenStation struct {
+	handler *handler.Handlers
+}
+
+type IServerKitchenStation interface {
+	ServiceGetStation(c *gin.Context)
+	ServiceListStations(c *gin.Context)
+	ServiceCreateStation(c *gin.Context)
+	ServiceUpdateStation(c *gin.Context)
+	ServiceDeleteStation(c *gin.Context)
+	ServiceCreateDefaultStations(c *gin.Context)
+}
+
+func (r *ResourceKitchenStation) ServiceGetStation(c *gin.Context) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "station")
+	if !ok {
+		return
+	}
+
+	station, err := r.handler.HandlerKitchenStation.GetStationById(id.String())
+	if err != nil || station == nil {
+		utils.SendNotFoundError(c, "Station")
+		return
+	}
+
+	// Verificar se estação pertence à organização/projeto
+	if station.OrganizationId.String() != c.GetString("organization_id") ||
+		station.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return
+	}
+
+	c.JSON(http.StatusOK, station)
+}
+
+func (r *ResourceKitchenStation) ServiceListStations(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	stations, err := r.handler.HandlerKitchenStation.ListStations(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing stations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stations)
+}
+
+func (r *ResourceKitchenStation) ServiceCreateStation(c *gin.Context) {
+	var newStation models.KitchenStation
+	if err := c.BindJSON(&newStation); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	var err error
+	newStation.OrganizationId, err = uuid.Parse(c.GetString("organization_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid organization ID", err)
+		return
+	}
+	newStation.ProjectId, err = uuid.Parse(c.GetString("project_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid project ID", err)
+		return
+	}
+
+	if err := validation.CreateKitchenStationValidation(&newStation); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerKitchenStation.CreateStation(&newStation); err != nil {
+		if strings.Contains(err.Error(), "already_exists") {
+			utils.SendConflictError(c, "Station with this code already exists", err)
+			return
+		}
+		utils.SendInternalServerError(c, "Error creating station", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Station created successfully", newStation)
+}
+
+func (r *ResourceKitchenStation) ServiceUpdateStation(c *gin.Context) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "station")
+	if !ok {
+		return
+	}
+
+	existing, err := r.handler.HandlerKitchenStation.GetStationById(id.String())
+	if err != nil || existing == nil {
+		utils.SendNotFoundError(c, "Station")
+		return
+	}
+
+	if existing.OrganizationId.String() != c.GetString("organization_id") ||
+		existing.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return
+	}
+
+	var updatedStation models.KitchenStation
+	if err := c.BindJSON(&updatedStation); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Manter dados imutáveis
+	updatedStation.Id = existing.Id
+	updatedStation.OrganizationId = existing.OrganizationId
+	updatedStation.ProjectId = existing.ProjectId
+	updatedStation.CreatedAt = existing.CreatedAt
+
+	if err := validation.UpdateKitchenStationValidation(&updatedStation); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerKitchenStation.UpdateStation(&updatedStation); err != nil {
+		if strings.Contains(err.Error(), "already_exists") {
+			utils.SendConflictError(c, "Station with this code already exists", err)
+			return
+		}
+		utils.SendInternalServerError(c, "Error updating station", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Station updated successfully", updatedStation)
+}
+
+func (r *ResourceKitchenStation) ServiceDeleteStation(c *gin.Context) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "station")
+	if !ok {
+		return
+	}
+
+	existing, err := r.handler.HandlerKitchenStation.GetStationById(id.String())
+	if err != nil || existing == nil {
+		utils.SendNotFoundError(c, "Station")
+		return
+	}
+
+	if existing.OrganizationId.String() != c.GetString("organization_id") ||
+		existing.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return
+	}
+
+	if err := r.handler.HandlerKitchenStation.SoftDeleteStation(id.String()); err != nil {
+		utils.SendInternalServerError(c, "Error deleting station", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Station deleted successfully", nil)
+}
+
+// ServiceCreateDefaultStations cria as estações padrão (grill, cold, bar, wine) do projeto
+func (r *ResourceKitchenStation) ServiceCreateDefaultStations(c *gin.Context) {
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	created, err := r.handler.HandlerKitchenStation.CreateDefaultStations(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error creating default stations", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Default stations created successfully", created)
+}
+
+func NewSourceServerKitchenStation(handler *handler.Handlers) IServerKitchenStation {
+	return &ResourceKitchenStation{handler: handler}
+}
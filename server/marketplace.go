@@ -0,0 +1,234 @@
+package server
+
+import (
+	"io"
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ResourceMarketplace struct {
+	handler *handler.Handlers
+}
+
+type IServerMarketplace interface {
+	ServiceGetIntegration(c *gin.Context)
+	ServiceListIntegrations(c *gin.Context)
+	ServiceCreateIntegration(c *gin.Context)
+	ServiceUpdateIntegration(c *gin.Context)
+	ServiceDeleteIntegration(c *gin.Context)
+	ServiceListEvents(c *gin.Context)
+	ServiceRetryEvent(c *gin.Context)
+	ServiceWebhook(c *gin.Context)
+}
+
+func (r *ResourceMarketplace) ServiceGetIntegration(c *gin.Context) {
+	integration, ok := r.loadIntegration(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, integration)
+}
+
+func (r *ResourceMarketplace) ServiceListIntegrations(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	integrations, err := r.handler.HandlerMarketplace.ListIntegrations(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing marketplace integrations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, integrations)
+}
+
+func (r *ResourceMarketplace) ServiceCreateIntegration(c *gin.Context) {
+	var newIntegration models.MarketplaceIntegration
+	if err := c.BindJSON(&newIntegration); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	var err error
+	newIntegration.OrganizationId, err = uuid.Parse(c.GetString("organization_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid organization ID", err)
+		return
+	}
+	newIntegration.ProjectId, err = uuid.Parse(c.GetString("project_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid project ID", err)
+		return
+	}
+
+	if err := validation.MarketplaceIntegrationValidation(&newIntegration); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerMarketplace.CreateIntegration(&newIntegration); err != nil {
+		sendMarketplaceError(c, "Error creating marketplace integration", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Marketplace integration created successfully", newIntegration)
+}
+
+func (r *ResourceMarketplace) ServiceUpdateIntegration(c *gin.Context) {
+	existing, ok := r.loadIntegration(c)
+	if !ok {
+		return
+	}
+
+	var updatedIntegration models.MarketplaceIntegration
+	if err := c.BindJSON(&updatedIntegration); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Manter dados imutáveis
+	updatedIntegration.Id = existing.Id
+	updatedIntegration.OrganizationId = existing.OrganizationId
+	updatedIntegration.ProjectId = existing.ProjectId
+	updatedIntegration.DeletedAt = nil
+
+	if err := validation.MarketplaceIntegrationValidation(&updatedIntegration); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerMarketplace.UpdateIntegration(&updatedIntegration); err != nil {
+		sendMarketplaceError(c, "Error updating marketplace integration", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Marketplace integration updated successfully", updatedIntegration)
+}
+
+func (r *ResourceMarketplace) ServiceDeleteIntegration(c *gin.Context) {
+	existing, ok := r.loadIntegration(c)
+	if !ok {
+		return
+	}
+
+	if err := r.handler.HandlerMarketplace.DeleteIntegration(existing.Id.String()); err != nil {
+		utils.SendInternalServerError(c, "Error deleting marketplace integration", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Marketplace integration deleted successfully", nil)
+}
+
+// ServiceListEvents lista webhooks recebidos e status enviados (?direction=inbound|outbound&status=failed&limit=100)
+func (r *ResourceMarketplace) ServiceListEvents(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	events, err := r.handler.HandlerMarketplace.ListEvents(organizationId, projectId, c.Query("direction"), c.Query("status"), limit)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing marketplace events", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// ServiceRetryEvent reprocessa webhook com falha ou reenvia status não entregue ao marketplace
+func (r *ResourceMarketplace) ServiceRetryEvent(c *gin.Context) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "marketplace event")
+	if !ok {
+		return
+	}
+
+	event, err := r.handler.HandlerMarketplace.GetEvent(id.String())
+	if err != nil || event == nil {
+		utils.SendNotFoundError(c, "Marketplace event")
+		return
+	}
+	if event.OrganizationId.String() != c.GetString("organization_id") ||
+		event.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return
+	}
+
+	retried, err := r.handler.HandlerMarketplace.RetryEvent(event)
+	if err != nil {
+		sendMarketplaceError(c, "Error retrying marketplace event", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Marketplace event retried", retried)
+}
+
+// ServiceWebhook recebe pedidos e cancelamentos do marketplace (rota pública, assinada com HMAC)
+func (r *ResourceMarketplace) ServiceWebhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	result, err := r.handler.HandlerMarketplace.HandleWebhook(c.Param("marketplace"), body, c.Request.Header)
+	if err != nil {
+		sendMarketplaceError(c, "Error processing marketplace webhook", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// loadIntegration busca a integração da rota e valida que pertence ao projeto
+func (r *ResourceMarketplace) loadIntegration(c *gin.Context) (*models.MarketplaceIntegration, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "marketplace integration")
+	if !ok {
+		return nil, false
+	}
+
+	integration, err := r.handler.HandlerMarketplace.GetIntegration(id.String())
+	if err != nil || integration == nil {
+		utils.SendNotFoundError(c, "Marketplace integration")
+		return nil, false
+	}
+
+	if integration.OrganizationId.String() != c.GetString("organization_id") ||
+		integration.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return integration, true
+}
+
+// sendMarketplaceError converte erros do handler de marketplace em respostas HTTP
+func sendMarketplaceError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "invalid_signature"):
+		utils.SendError(c, http.StatusUnauthorized, message, err)
+	case strings.Contains(err.Error(), "already exists"):
+		utils.SendConflictError(c, message, err)
+	case strings.Contains(err.Error(), "invalid_marketplace"):
+		utils.SendBadRequestError(c, message, err)
+	case strings.Contains(err.Error(), "not found"),
+		strings.Contains(err.Error(), "not registered"):
+		utils.SendError(c, http.StatusNotFound, message, err)
+	default:
+		utils.SendInternalServerError(c, message, err)
+	}
+}
+
+func NewSourceServerMarketplace(handler *handler.Handlers) IServerMarketplace {
+	return &ResourceMarketplace{handler: handler}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"lep/repositories/models"
 	"lep/utils"
@@ -21,8 +22,12 @@ type IOrderServer interface {
 	UpdateOrder(c *gin.Context)
 	SoftDeleteOrder(c *gin.Context)
 	UpdateOrderStatus(c *gin.Context)
+	ApproveOrder(c *gin.Context)
+	FireCourse(c *gin.Context)
 	GetKitchenQueue(c *gin.Context)
 	GetOrderProgress(c *gin.Context)
+	GetStationQueue(c *gin.Context)
+	UpdateOrderItemStatus(c *gin.Context)
 }
 
 type OrderServer struct {
@@ -59,6 +64,11 @@ func (s *OrderServer) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	// Pedidos de marketplace só entram pelo webhook
+	createOrderPOST.ExternalOrderId = ""
+	// Vínculo com a NFC-e é feito apenas na emissão
+	createOrderPOST.FiscalDocumentId = nil
+
 	if err := validation.CreateOrderValidation(createOrderPOST); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -66,6 +76,9 @@ func (s *OrderServer) CreateOrder(c *gin.Context) {
 
 	err = s.handler.CreateOrder(createOrderPOST)
 	if err != nil {
+		if respondOrderItemsError(c, err) || respondOrderTypeError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -153,12 +166,17 @@ func (s *OrderServer) UpdateOrder(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
 		return
 	}
+	// Total só é conferido quando enviado pelo cliente; o valor gravado é recalculado no handler
+	order.TotalAmount = 0
 	if err := c.ShouldBindJSON(order); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
 	order.UpdatedAt = time.Now()
 	if err := s.handler.UpdateOrder(order); err != nil {
+		if respondOrderItemsError(c, err) || respondOrderStatusError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating order"})
 		return
 	}
@@ -218,6 +236,7 @@ func (s *OrderServer) UpdateOrderStatus(c *gin.Context) {
 
 	var statusUpdate struct {
 		Status string `json:"status" binding:"required"`
+		Reason string `json:"reason"` // obrigatório para cancelar
 	}
 
 	if err := c.ShouldBindJSON(&statusUpdate); err != nil {
@@ -225,29 +244,124 @@ func (s *OrderServer) UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
-	// Validar status permitidos
-	allowedStatuses := []string{"pending", "preparing", "ready", "delivered", "cancelled"}
-	validStatus := false
-	for _, status := range allowedStatuses {
-		if statusUpdate.Status == status {
-			validStatus = true
-			break
+	// Transições validadas pela máquina de estados (utils.ValidateOrderStatusTransition)
+	if err := s.handler.UpdateOrderStatus(id.String(), statusUpdate.Status, strings.TrimSpace(statusUpdate.Reason), c.GetString("user_id")); err != nil {
+		if respondOrderStatusError(c, err) {
+			return
 		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating order status"})
+		return
 	}
 
-	if !validStatus {
+	c.JSON(http.StatusOK, gin.H{"message": "Order status updated successfully"})
+}
+
+// ApproveOrder libera para a cozinha um pedido público retido para aprovação
+func (s *OrderServer) ApproveOrder(c *gin.Context) {
+	organizationId := c.GetHeader("X-Lpe-Organization-Id")
+	if strings.TrimSpace(organizationId) == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid status. Allowed: pending, preparing, ready, delivered, cancelled",
+			"error": "the header param 'X-Lpe-Organization-Id' cannot be empty",
 		})
 		return
 	}
 
-	if err := s.handler.UpdateOrderStatus(id.String(), statusUpdate.Status); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating order status"})
+	projectId := c.GetHeader("X-Lpe-Project-Id")
+	if strings.TrimSpace(projectId) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "the header param 'X-Lpe-Project-Id' cannot be empty",
+		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Order status updated successfully"})
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "order")
+	if !ok {
+		return
+	}
+
+	order, err := s.handler.GetOrderById(id.String())
+	if err != nil || order == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		return
+	}
+	if order.OrganizationId.String() != organizationId || order.ProjectId.String() != projectId {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	approved, err := s.handler.ApproveOrder(id.String(), c.GetString("user_id"))
+	if err != nil {
+		if respondOrderStatusError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error approving order"})
+		return
+	}
+
+	c.JSON(http.StatusOK, approved)
+}
+
+// FireCourse dispara para a cozinha um tempo retido do pedido (entrada, principal, sobremesa)
+func (s *OrderServer) FireCourse(c *gin.Context) {
+	organizationId := c.GetHeader("X-Lpe-Organization-Id")
+	if strings.TrimSpace(organizationId) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "the header param 'X-Lpe-Organization-Id' cannot be empty",
+		})
+		return
+	}
+
+	projectId := c.GetHeader("X-Lpe-Project-Id")
+	if strings.TrimSpace(projectId) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "the header param 'X-Lpe-Project-Id' cannot be empty",
+		})
+		return
+	}
+
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "order")
+	if !ok {
+		return
+	}
+
+	// Corpo opcional: sem tempo informado dispara o próximo retido
+	var request models.FireCourseRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+	request.Course = strings.ToLower(strings.TrimSpace(request.Course))
+	if !utils.IsValidOrderCourse(request.Course) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course. Allowed: entrada, principal, sobremesa"})
+		return
+	}
+
+	order, err := s.handler.GetOrderById(id.String())
+	if err != nil || order == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		return
+	}
+	if order.OrganizationId.String() != organizationId || order.ProjectId.String() != projectId {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	fired, err := s.handler.FireCourse(id.String(), request.Course, c.GetString("user_id"))
+	if err != nil {
+		if respondOrderStatusError(c, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "invalid_course") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error firing course"})
+		return
+	}
+
+	c.JSON(http.StatusOK, fired)
 }
 
 // GetKitchenQueue retorna a fila da cozinha
@@ -306,8 +420,13 @@ func (s *OrderServer) GetOrderProgress(c *gin.Context) {
 		return
 	}
 
-	progress := utils.GetOrderProgress(*order)
-	remainingTime := utils.GetRemainingTime(*order)
+	history, err := s.handler.GetStatusHistory(order.Id.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error getting order status history"})
+		return
+	}
+
+	progress, remainingTime := utils.GetOrderProgressFromHistory(*order, history)
 
 	c.JSON(http.StatusOK, gin.H{
 		"order_id":           order.Id,
@@ -315,5 +434,165 @@ func (s *OrderServer) GetOrderProgress(c *gin.Context) {
 		"progress_percent":   progress,
 		"remaining_minutes":  remainingTime,
 		"estimated_delivery": order.EstimatedDeliveryTime,
+		"preparing_since":    utils.StatusEnteredAt(history, models.OrderStatusPreparing),
+		"history":            history,
 	})
 }
+
+// GetStationQueue retorna a fila de uma estação da cozinha (apenas os itens roteados para ela)
+func (s *OrderServer) GetStationQueue(c *gin.Context) {
+	organizationId := c.GetHeader("X-Lpe-Organization-Id")
+	if strings.TrimSpace(organizationId) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "the header param 'X-Lpe-Organization-Id' cannot be empty",
+		})
+		return
+	}
+
+	projectId := c.GetHeader("X-Lpe-Project-Id")
+	if strings.TrimSpace(projectId) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "the header param 'X-Lpe-Project-Id' cannot be empty",
+		})
+		return
+	}
+
+	stationId, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "station")
+	if !ok {
+		return
+	}
+
+	tickets, err := s.handler.GetStationQueue(organizationId, projectId, stationId.String())
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Station not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching station queue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tickets)
+}
+
+// UpdateOrderItemStatus atualiza o status de um item do pedido (queued, preparing, ready)
+func (s *OrderServer) UpdateOrderItemStatus(c *gin.Context) {
+	organizationId := c.GetHeader("X-Lpe-Organization-Id")
+	if strings.TrimSpace(organizationId) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "the header param 'X-Lpe-Organization-Id' cannot be empty",
+		})
+		return
+	}
+
+	projectId := c.GetHeader("X-Lpe-Project-Id")
+	if strings.TrimSpace(projectId) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "the header param 'X-Lpe-Project-Id' cannot be empty",
+		})
+		return
+	}
+
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "order")
+	if !ok {
+		return
+	}
+
+	itemId, ok := validation.ParseAndValidateUUID(c, c.Param("itemId"), "item")
+	if !ok {
+		return
+	}
+
+	var statusUpdate struct {
+		Status string `json:"status" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&statusUpdate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := validation.OrderItemStatusValidation(statusUpdate.Status); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Pedido de outro projeto é tratado como inexistente
+	existing, err := s.handler.GetOrderById(id.String())
+	if err != nil || existing == nil ||
+		existing.OrganizationId.String() != organizationId || existing.ProjectId.String() != projectId {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		return
+	}
+
+	order, err := s.handler.UpdateOrderItemStatus(id.String(), itemId.String(), statusUpdate.Status, c.GetString("user_id"))
+	if err != nil {
+		if respondOrderStatusError(c, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating item status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// respondOrderItemsError trata erros de precificação/validação de itens.
+// Retorna true se a resposta já foi enviada.
+func respondOrderItemsError(c *gin.Context, err error) bool {
+	var mismatch *utils.PriceMismatchError
+	if errors.As(err, &mismatch) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":        mismatch.Error(),
+			"client_total": mismatch.ClientTotal,
+			"server_total": mismatch.ServerTotal,
+			"mismatches":   mismatch.Items,
+		})
+		return true
+	}
+
+	if strings.Contains(err.Error(), "invalid_items") || strings.Contains(err.Error(), "invalid_modifiers") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return true
+	}
+
+	return false
+}
+
+// respondOrderTypeError responde erros de retirada/entrega: tipo inválido, endereço fora da área
+// ou abaixo do pedido mínimo e horário agendado indisponível. Retorna false para outros erros.
+func respondOrderTypeError(c *gin.Context, err error) bool {
+	switch {
+	case strings.Contains(err.Error(), "invalid_slot"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "invalid_delivery"):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "invalid_order_type"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
+
+// respondOrderStatusError responde erros da máquina de estados do pedido e de estoque insuficiente.
+// Retorna false se o erro não for de transição de status.
+func respondOrderStatusError(c *gin.Context, err error) bool {
+	var transition *utils.OrderTransitionError
+	if errors.As(err, &transition) || strings.Contains(err.Error(), "invalid_transition") ||
+		strings.Contains(err.Error(), "insufficient_stock") {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return true
+	}
+
+	if strings.Contains(err.Error(), "invalid_status") || strings.Contains(err.Error(), "reason_required") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return true
+	}
+
+	return false
+}
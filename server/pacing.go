@@ -0,0 +1,210 @@
+package server
+
+import (
+	"errors"
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ResourcePacing struct {
+	handler *handler.Handlers
+}
+
+type IServerPacing interface {
+	ServiceGetRule(c *gin.Context)
+	ServiceListRules(c *gin.Context)
+	ServiceCreateRule(c *gin.Context)
+	ServiceUpdateRule(c *gin.Context)
+	ServiceDeleteRule(c *gin.Context)
+	ServiceGetReport(c *gin.Context)
+}
+
+func (r *ResourcePacing) ServiceGetRule(c *gin.Context) {
+	rule, ok := r.loadPacingRule(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func (r *ResourcePacing) ServiceListRules(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	rules, err := r.handler.HandlerPacing.ListRules(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing pacing rules", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+func (r *ResourcePacing) ServiceCreateRule(c *gin.Context) {
+	var newRule models.PacingRule
+	if err := c.BindJSON(&newRule); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	var err error
+	newRule.OrganizationId, err = uuid.Parse(c.GetString("organization_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid organization ID", err)
+		return
+	}
+	newRule.ProjectId, err = uuid.Parse(c.GetString("project_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid project ID", err)
+		return
+	}
+	// Nova regra nasce ativa
+	newRule.Active = true
+
+	if err := validation.PacingRuleValidation(&newRule); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerPacing.CreateRule(&newRule); err != nil {
+		sendPacingError(c, "Error creating pacing rule", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Pacing rule created successfully", newRule)
+}
+
+func (r *ResourcePacing) ServiceUpdateRule(c *gin.Context) {
+	existing, ok := r.loadPacingRule(c)
+	if !ok {
+		return
+	}
+
+	var updatedRule models.PacingRule
+	if err := c.BindJSON(&updatedRule); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	updatedRule.Id = existing.Id
+	updatedRule.OrganizationId = existing.OrganizationId
+	updatedRule.ProjectId = existing.ProjectId
+	updatedRule.CreatedAt = existing.CreatedAt
+	updatedRule.DeletedAt = nil
+
+	if err := validation.PacingRuleValidation(&updatedRule); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerPacing.UpdateRule(&updatedRule); err != nil {
+		sendPacingError(c, "Error updating pacing rule", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Pacing rule updated successfully", updatedRule)
+}
+
+func (r *ResourcePacing) ServiceDeleteRule(c *gin.Context) {
+	existing, ok := r.loadPacingRule(c)
+	if !ok {
+		return
+	}
+
+	if err := r.handler.HandlerPacing.DeleteRule(existing.Id.String()); err != nil {
+		utils.SendInternalServerError(c, "Error deleting pacing rule", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Pacing rule deleted successfully", nil)
+}
+
+// ServiceGetReport ocupação de cada horário frente aos limites de ritmo (?date=YYYY-MM-DD&service=lunch|dinner)
+func (r *ResourcePacing) ServiceGetReport(c *gin.Context) {
+	orgId, projectId, ok := projectFromContext(c)
+	if !ok {
+		return
+	}
+
+	report, err := r.handler.HandlerPacing.Report(orgId, projectId, c.Query("date"), c.Query("service"))
+	if err != nil {
+		sendPacingError(c, "Error building pacing report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// loadPacingRule busca a regra da rota e valida que pertence ao projeto
+func (r *ResourcePacing) loadPacingRule(c *gin.Context) (*models.PacingRule, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "pacing rule")
+	if !ok {
+		return nil, false
+	}
+
+	rule, err := r.handler.HandlerPacing.GetRule(id.String())
+	if err != nil || rule == nil {
+		utils.SendNotFoundError(c, "Pacing rule")
+		return nil, false
+	}
+
+	if rule.OrganizationId.String() != c.GetString("organization_id") ||
+		rule.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return rule, true
+}
+
+// sendPacingError traduz erros das regras e do relatório de ritmo
+func sendPacingError(c *gin.Context, message string, err error) {
+	if strings.Contains(err.Error(), "invalid_pacing") {
+		utils.SendBadRequestError(c, message, err)
+		return
+	}
+	utils.SendInternalServerError(c, message, err)
+}
+
+// sendPacingLimit responde com status quando a reserva ultrapassa um limite de ritmo, sugerindo horários próximos
+// dentro dos limites. Devolve false se err não for de ritmo.
+func sendPacingLimit(c *gin.Context, h *handler.Handlers, reservation *models.Reservation, environmentId *uuid.UUID, status int, err error) bool {
+	var violation *models.PacingViolation
+	if !errors.As(err, &violation) {
+		return false
+	}
+
+	suggestions := []models.ReservationTimeSuggestion{}
+	if reservation.StartsAt != nil {
+		found, suggestErr := h.HandlerTableAllocation.SuggestTimes(reservation.OrganizationId, reservation.ProjectId, *reservation.StartsAt, reservation.PartySize, environmentId)
+		if suggestErr == nil {
+			suggestions = found
+		}
+	}
+
+	message := "Este horário já está com o limite de chegadas. Por favor, escolha um dos horários sugeridos."
+	if status == http.StatusConflict {
+		message = "Este horário ultrapassa o limite de chegadas. Escolha outro horário ou informe pacing_override_reason para aceitar mesmo assim."
+	}
+	c.JSON(status, gin.H{
+		"error":       "pacing_limit",
+		"message":     message,
+		"pacing":      violation,
+		"suggestions": suggestions,
+	})
+	return true
+}
+
+func NewSourceServerPacing(handler *handler.Handlers) IServerPacing {
+	return &ResourcePacing{handler: handler}
+}
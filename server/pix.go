@@ -0,0 +1,160 @@
+package server
+
+import (
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResourcePix struct {
+	handler *handler.Handlers
+}
+
+type IServerPix interface {
+	ServiceCreateCharge(c *gin.Context)
+	ServiceListCharges(c *gin.Context)
+	ServiceGetCharge(c *gin.Context)
+	ServiceGetChargeQRCode(c *gin.Context)
+	ServiceSimulatePayment(c *gin.Context)
+	ServiceWebhook(c *gin.Context)
+}
+
+func (r *ResourcePix) ServiceCreateCharge(c *gin.Context) {
+	var request models.PixChargeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	charge, err := r.handler.HandlerPix.CreateCharge(organizationId, projectId, request)
+	if err != nil {
+		sendPixError(c, "Error creating pix charge", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Pix charge created successfully", charge)
+}
+
+func (r *ResourcePix) ServiceListCharges(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	charges, err := r.handler.HandlerPix.ListCharges(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing pix charges", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, charges)
+}
+
+func (r *ResourcePix) ServiceGetCharge(c *gin.Context) {
+	charge, ok := r.getOwnedCharge(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, charge)
+}
+
+func (r *ResourcePix) ServiceGetChargeQRCode(c *gin.Context) {
+	charge, ok := r.getOwnedCharge(c)
+	if !ok {
+		return
+	}
+
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "256"))
+	if size < 128 || size > 1024 {
+		utils.SendBadRequestError(c, "Invalid size. Allowed: 128 to 1024", nil)
+		return
+	}
+
+	png, err := r.handler.HandlerPix.GetChargeQRCode(charge.Id.String(), size)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error generating QR code", err)
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+func (r *ResourcePix) ServiceSimulatePayment(c *gin.Context) {
+	charge, ok := r.getOwnedCharge(c)
+	if !ok {
+		return
+	}
+
+	paid, err := r.handler.HandlerPix.SimulatePayment(charge.Id.String())
+	if err != nil {
+		sendPixError(c, "Error simulating payment", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Pix payment confirmed", paid)
+}
+
+// ServiceWebhook recebe confirmações de pagamento do provedor Pix (rota pública)
+func (r *ResourcePix) ServiceWebhook(c *gin.Context) {
+	// Provedor recebe a requisição inteira (headers inclusos) para validar a assinatura do PSP
+	confirmed, err := r.handler.HandlerPix.HandleWebhook(c.Param("provider"), c.Request)
+	if err != nil {
+		sendPixError(c, "Error processing pix webhook", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"confirmed": len(confirmed)})
+}
+
+// getOwnedCharge busca a cobrança do parâmetro :id e verifica se pertence à organização/projeto
+func (r *ResourcePix) getOwnedCharge(c *gin.Context) (*models.PixCharge, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "pix charge")
+	if !ok {
+		return nil, false
+	}
+
+	charge, err := r.handler.HandlerPix.GetCharge(id.String())
+	if err != nil || charge == nil {
+		utils.SendNotFoundError(c, "Pix charge")
+		return nil, false
+	}
+
+	if charge.OrganizationId.String() != c.GetString("organization_id") ||
+		charge.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return charge, true
+}
+
+// sendPixError converte erros do handler Pix em respostas HTTP
+func sendPixError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "invalid_signature"):
+		utils.SendError(c, http.StatusUnauthorized, message, err)
+	case strings.Contains(err.Error(), "pix_not_configured"):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	case strings.Contains(err.Error(), "invalid_pix"):
+		utils.SendBadRequestError(c, message, err)
+	case strings.Contains(err.Error(), "not found"),
+		strings.Contains(err.Error(), "not registered"):
+		utils.SendError(c, http.StatusNotFound, message, err)
+	default:
+		utils.SendInternalServerError(c, message, err)
+	}
+}
+
+func NewSourceServerPix(handler *handler.Handlers) IServerPix {
+	return &ResourcePix{handler: handler}
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"lep/handler"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// posMaxFileSize limite do arquivo de cardápio importado
+const posMaxFileSize = 5 << 20
+
+type ResourcePos struct {
+	handler *handler.Handlers
+}
+
+type IServerPos interface {
+	ServiceImportCatalog(c *gin.Context)
+	ServiceExportSales(c *gin.Context)
+	ServiceListRuns(c *gin.Context)
+	ServiceGetRun(c *gin.Context)
+}
+
+// ServiceImportCatalog importa o cardápio do PDV (?format=csv|json&dry_run=true).
+// Aceita o arquivo no corpo da requisição ou no campo "file" de um multipart.
+func (r *ResourcePos) ServiceImportCatalog(c *gin.Context) {
+	data, fileName, err := readPosFile(c)
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid catalog file", err)
+		return
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		format = posFormatFor(c.ContentType(), fileName)
+	}
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	run, err := r.handler.HandlerPos.ImportCatalog(organizationId, projectId, format, data, dryRun, c.GetString("user_id"))
+	if err != nil {
+		sendPosError(c, "Error importing POS catalog", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "POS catalog imported", run)
+}
+
+// ServiceExportSales baixa as vendas dos pedidos entregues (?format=csv|json&from=&to=, RFC3339 ou YYYY-MM-DD).
+// Sem "from", exporta a partir do fim da última exportação.
+func (r *ResourcePos) ServiceExportSales(c *gin.Context) {
+	from, err := parsePosTime(c.Query("from"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid from. Use RFC3339 or YYYY-MM-DD", err)
+		return
+	}
+	to, err := parsePosTime(c.Query("to"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid to. Use RFC3339 or YYYY-MM-DD", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	export, err := r.handler.HandlerPos.ExportSales(organizationId, projectId, c.DefaultQuery("format", "csv"), from, to, c.GetString("user_id"))
+	if err != nil {
+		sendPosError(c, "Error exporting POS sales", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.FileName))
+	c.Header("X-Pos-Sync-Run", export.Run.Id.String())
+	c.Data(http.StatusOK, export.ContentType, export.Content)
+}
+
+// ServiceListRuns histórico de importações e exportações (?direction=catalog_import|sales_export&limit=50)
+func (r *ResourcePos) ServiceListRuns(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	runs, err := r.handler.HandlerPos.ListRuns(organizationId, projectId, c.Query("direction"), limit)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing POS sync runs", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+// ServiceGetRun detalhe da execução com os itens criados, alterados e rejeitados
+func (r *ResourcePos) ServiceGetRun(c *gin.Context) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "POS sync run")
+	if !ok {
+		return
+	}
+
+	run, err := r.handler.HandlerPos.GetRun(id.String())
+	if err != nil || run == nil {
+		utils.SendNotFoundError(c, "POS sync run")
+		return
+	}
+	if run.OrganizationId.String() != c.GetString("organization_id") ||
+		run.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// readPosFile lê o arquivo do multipart (campo "file") ou o corpo inteiro
+func readPosFile(c *gin.Context) ([]byte, string, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, posMaxFileSize)
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", err
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		return data, header.Filename, err
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err == nil && len(data) == 0 {
+		err = fmt.Errorf("empty file")
+	}
+	return data, "", err
+}
+
+// posFormatFor deduz o formato pelo nome do arquivo ou Content-Type (padrão csv)
+func posFormatFor(contentType, fileName string) string {
+	if ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), "."); ext != "" {
+		return ext
+	}
+	if strings.Contains(contentType, "json") {
+		return "json"
+	}
+	return "csv"
+}
+
+// parsePosTime aceita RFC3339 ou YYYY-MM-DD (vazio = nil)
+func parsePosTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// sendPosError converte erros de sincronização com o PDV em respostas HTTP
+func sendPosError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "invalid_pos_file"),
+		strings.Contains(err.Error(), "invalid_pos_period"):
+		utils.SendBadRequestError(c, message, err)
+	default:
+		utils.SendInternalServerError(c, message, err)
+	}
+}
+
+func NewSourceServerPos(handler *handler.Handlers) IServerPos {
+	return &ResourcePos{handler: handler}
+}
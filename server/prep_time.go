@@ -0,0 +1,85 @@
+package server
+
+import (
+	"lep/handler"
+	"lep/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResourcePrepTime struct {
+	handler *handler.Handlers
+}
+
+type IServerPrepTime interface {
+	ServiceGetStats(c *gin.Context)
+	ServiceRecompute(c *gin.Context)
+	ServiceGetAccuracy(c *gin.Context)
+}
+
+func (r *ResourcePrepTime) ServiceGetStats(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	stats, err := r.handler.HandlerPrepTime.GetStats(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error getting prep time stats", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+func (r *ResourcePrepTime) ServiceRecompute(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	stats, err := r.handler.HandlerPrepTime.Recompute(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error computing prep time stats", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Prep time stats recomputed", stats)
+}
+
+func (r *ResourcePrepTime) ServiceGetAccuracy(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	startDateStr := c.DefaultQuery("start_date", time.Now().AddDate(0, 0, -7).Format("2006-01-02"))
+	endDateStr := c.DefaultQuery("end_date", time.Now().Format("2006-01-02"))
+
+	startDate, err := time.ParseInLocation("2006-01-02", startDateStr, time.Local)
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid start_date format", err)
+		return
+	}
+	endDate, err := time.ParseInLocation("2006-01-02", endDateStr, time.Local)
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid end_date format", err)
+		return
+	}
+	if endDate.Before(startDate) {
+		utils.SendBadRequestError(c, "end_date must not be before start_date", nil)
+		return
+	}
+
+	// end_date inclusivo
+	report, err := r.handler.HandlerPrepTime.GetAccuracy(organizationId, projectId, startDate, endDate.AddDate(0, 0, 1))
+	if err != nil {
+		utils.SendInternalServerError(c, "Error calculating prep time accuracy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func NewSourceServerPrepTime(handler *handler.Handlers) IServerPrepTime {
+	return &ResourcePrepTime{handler: handler}
+}
@@ -0,0 +1,423 @@
+package server
+
+import (
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ResourcePrint struct {
+	handler *handler.Handlers
+}
+
+type IServerPrint interface {
+	ServiceGetPrinter(c *gin.Context)
+	ServiceListPrinters(c *gin.Context)
+	ServiceCreatePrinter(c *gin.Context)
+	ServiceUpdatePrinter(c *gin.Context)
+	ServiceDeletePrinter(c *gin.Context)
+	ServiceTestPrinter(c *gin.Context)
+	ServicePrintOrder(c *gin.Context)
+	ServicePrintTableReceipt(c *gin.Context)
+	ServiceListJobs(c *gin.Context)
+	ServiceGetJob(c *gin.Context)
+	ServiceRetryJob(c *gin.Context)
+	ServiceGetAgent(c *gin.Context)
+	ServiceGenerateAgentToken(c *gin.Context)
+	ServiceAgentClaimJobs(c *gin.Context)
+	ServiceAgentJobResult(c *gin.Context)
+}
+
+func (r *ResourcePrint) ServiceGetPrinter(c *gin.Context) {
+	printer, ok := r.loadPrinter(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, printer)
+}
+
+func (r *ResourcePrint) ServiceListPrinters(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	printers, err := r.handler.HandlerPrint.ListPrinters(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing printers", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, printers)
+}
+
+func (r *ResourcePrint) ServiceCreatePrinter(c *gin.Context) {
+	var newPrinter models.Printer
+	if err := c.BindJSON(&newPrinter); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	var err error
+	newPrinter.OrganizationId, err = uuid.Parse(c.GetString("organization_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid organization ID", err)
+		return
+	}
+	newPrinter.ProjectId, err = uuid.Parse(c.GetString("project_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid project ID", err)
+		return
+	}
+	if newPrinter.Port == 0 {
+		newPrinter.Port = 9100
+	}
+	if newPrinter.PaperWidth == 0 {
+		newPrinter.PaperWidth = utils.PaperWidth80
+	}
+	if newPrinter.Delivery == "" {
+		newPrinter.Delivery = models.PrinterDeliveryAgent
+	}
+
+	if err := validation.PrinterValidation(&newPrinter); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerPrint.CreatePrinter(&newPrinter); err != nil {
+		sendPrintError(c, "Error creating printer", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Printer created successfully", newPrinter)
+}
+
+func (r *ResourcePrint) ServiceUpdatePrinter(c *gin.Context) {
+	existing, ok := r.loadPrinter(c)
+	if !ok {
+		return
+	}
+
+	var updatedPrinter models.Printer
+	if err := c.BindJSON(&updatedPrinter); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Manter dados imutáveis
+	updatedPrinter.Id = existing.Id
+	updatedPrinter.OrganizationId = existing.OrganizationId
+	updatedPrinter.ProjectId = existing.ProjectId
+	updatedPrinter.CreatedAt = existing.CreatedAt
+	updatedPrinter.DeletedAt = nil
+	if updatedPrinter.Delivery == "" {
+		updatedPrinter.Delivery = existing.Delivery
+	}
+
+	if err := validation.PrinterValidation(&updatedPrinter); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerPrint.UpdatePrinter(&updatedPrinter); err != nil {
+		sendPrintError(c, "Error updating printer", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Printer updated successfully", updatedPrinter)
+}
+
+func (r *ResourcePrint) ServiceDeletePrinter(c *gin.Context) {
+	existing, ok := r.loadPrinter(c)
+	if !ok {
+		return
+	}
+
+	if err := r.handler.HandlerPrint.DeletePrinter(existing.Id.String()); err != nil {
+		utils.SendInternalServerError(c, "Error deleting printer", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Printer deleted successfully", nil)
+}
+
+func (r *ResourcePrint) ServiceTestPrinter(c *gin.Context) {
+	printer, ok := r.loadPrinter(c)
+	if !ok {
+		return
+	}
+
+	job, err := r.handler.HandlerPrint.PrintTestPage(printer, c.GetString("user_id"))
+	if err != nil {
+		sendPrintError(c, "Error printing test page", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Test page queued", job)
+}
+
+// ServicePrintOrder reimprime as comandas de cozinha do pedido
+func (r *ResourcePrint) ServicePrintOrder(c *gin.Context) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "order")
+	if !ok {
+		return
+	}
+
+	order, err := r.handler.HandlerOrder.GetOrderById(id.String())
+	if err != nil || order == nil {
+		utils.SendNotFoundError(c, "Order")
+		return
+	}
+
+	if order.OrganizationId.String() != c.GetString("organization_id") ||
+		order.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return
+	}
+
+	request, ok := bindPrintRequest(c)
+	if !ok {
+		return
+	}
+
+	jobs, err := r.handler.HandlerPrint.PrintOrder(order, request.PrinterId, c.GetString("user_id"))
+	if err != nil {
+		sendPrintError(c, "Error printing order", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Order queued for printing", jobs)
+}
+
+// ServicePrintTableReceipt imprime a conferência de conta da mesa
+func (r *ResourcePrint) ServicePrintTableReceipt(c *gin.Context) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "table")
+	if !ok {
+		return
+	}
+
+	table, err := r.handler.HandlerTables.GetTable(id.String())
+	if err != nil || table == nil {
+		utils.SendNotFoundError(c, "Table")
+		return
+	}
+
+	if table.OrganizationId.String() != c.GetString("organization_id") ||
+		table.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return
+	}
+
+	request, ok := bindPrintRequest(c)
+	if !ok {
+		return
+	}
+
+	job, err := r.handler.HandlerPrint.PrintTableReceipt(table, request.PrinterId, c.GetString("user_id"))
+	if err != nil {
+		sendPrintError(c, "Error printing receipt", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Receipt queued for printing", job)
+}
+
+func (r *ResourcePrint) ServiceListJobs(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	status := c.Query("status")
+	switch status {
+	case "", models.PrintJobStatusPending, models.PrintJobStatusPrinting,
+		models.PrintJobStatusPrinted, models.PrintJobStatusFailed:
+	default:
+		utils.SendBadRequestError(c, "Invalid status. Allowed: pending, printing, printed, failed", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 500 {
+		utils.SendBadRequestError(c, "Invalid limit. Allowed: 1 to 500", err)
+		return
+	}
+
+	jobs, err := r.handler.HandlerPrint.ListJobs(organizationId, projectId, status, limit)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing print jobs", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+func (r *ResourcePrint) ServiceGetJob(c *gin.Context) {
+	job, ok := r.loadJob(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func (r *ResourcePrint) ServiceRetryJob(c *gin.Context) {
+	job, ok := r.loadJob(c)
+	if !ok {
+		return
+	}
+
+	if err := r.handler.HandlerPrint.RetryJob(job); err != nil {
+		sendPrintError(c, "Error retrying print job", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Print job queued again", job)
+}
+
+// ServiceGetAgent situação do agente de impressão do projeto (última consulta)
+func (r *ResourcePrint) ServiceGetAgent(c *gin.Context) {
+	agent, err := r.handler.HandlerPrint.GetAgent(c.GetString("organization_id"), c.GetString("project_id"))
+	if err != nil || agent == nil {
+		utils.SendNotFoundError(c, "Print agent")
+		return
+	}
+
+	c.JSON(http.StatusOK, agent)
+}
+
+// ServiceGenerateAgentToken gera o token do agente de impressão (mostrado só nesta resposta)
+func (r *ResourcePrint) ServiceGenerateAgentToken(c *gin.Context) {
+	token, err := r.handler.HandlerPrint.GenerateAgentToken(c.GetString("organization_id"), c.GetString("project_id"))
+	if err != nil {
+		utils.SendInternalServerError(c, "Error generating print agent token", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Print agent token generated", token)
+}
+
+// ServiceAgentClaimJobs entrega ao agente os trabalhos prontos das impressoras "agent" (?limit=, padrão 10)
+func (r *ResourcePrint) ServiceAgentClaimJobs(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 || limit > 50 {
+		utils.SendBadRequestError(c, "Invalid limit. Allowed: 1 to 50", err)
+		return
+	}
+
+	agent, ok := c.Get("print_agent")
+	if !ok {
+		utils.SendUnauthorizedError(c, "Print agent not authenticated")
+		return
+	}
+
+	jobs, err := r.handler.HandlerPrint.ClaimAgentJobs(agent.(*models.PrintAgent), limit)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error claiming print jobs", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// ServiceAgentJobResult recebe do agente o resultado do envio à impressora
+func (r *ResourcePrint) ServiceAgentJobResult(c *gin.Context) {
+	job, ok := r.loadJob(c)
+	if !ok {
+		return
+	}
+
+	var result models.PrintAgentResult
+	if err := c.BindJSON(&result); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	if err := r.handler.HandlerPrint.CompleteAgentJob(job, result); err != nil {
+		sendPrintError(c, "Error saving print job result", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Print job result saved", job)
+}
+
+// loadPrinter busca a impressora da rota e valida que pertence ao projeto
+func (r *ResourcePrint) loadPrinter(c *gin.Context) (*models.Printer, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "printer")
+	if !ok {
+		return nil, false
+	}
+
+	printer, err := r.handler.HandlerPrint.GetPrinter(id.String())
+	if err != nil || printer == nil {
+		utils.SendNotFoundError(c, "Printer")
+		return nil, false
+	}
+
+	if printer.OrganizationId.String() != c.GetString("organization_id") ||
+		printer.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return printer, true
+}
+
+// loadJob busca o trabalho de impressão da rota e valida que pertence ao projeto
+func (r *ResourcePrint) loadJob(c *gin.Context) (*models.PrintJob, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "print job")
+	if !ok {
+		return nil, false
+	}
+
+	job, err := r.handler.HandlerPrint.GetJob(id.String())
+	if err != nil || job == nil {
+		utils.SendNotFoundError(c, "Print job")
+		return nil, false
+	}
+
+	if job.OrganizationId.String() != c.GetString("organization_id") ||
+		job.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return job, true
+}
+
+// bindPrintRequest lê o corpo opcional com a impressora escolhida
+func bindPrintRequest(c *gin.Context) (models.PrintRequest, bool) {
+	var request models.PrintRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			utils.SendBadRequestError(c, "Invalid request body", err)
+			return request, false
+		}
+	}
+	return request, true
+}
+
+// sendPrintError traduz erros de negócio da impressão
+func sendPrintError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "printer_not_configured"):
+		utils.SendError(c, http.StatusUnprocessableEntity, message, err)
+	case strings.Contains(err.Error(), "invalid_print_job"):
+		utils.SendConflictError(c, message, err)
+	case strings.Contains(err.Error(), "invalid_print"):
+		utils.SendBadRequestError(c, message, err)
+	default:
+		utils.SendInternalServerError(c, message, err)
+	}
+}
+
+func NewSourceServerPrint(handler *handler.Handlers) IServerPrint {
+	return &ResourcePrint{handler: handler}
+}
@@ -5,6 +5,7 @@ import (
 	"lep/repositories/models"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -127,6 +128,10 @@ func (s *ProjectServer) CreateProject(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Project name is required"})
 		return
 	}
+	if !validTimezone(project.TimeZone) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone (use an IANA name, ex: America/Manaus)"})
+		return
+	}
 
 	err = s.handler.CreateProject(&project)
 	if err != nil {
@@ -181,6 +186,11 @@ func (s *ProjectServer) UpdateProject(c *gin.Context) {
 		return
 	}
 
+	if !validTimezone(updateData.TimeZone) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone (use an IANA name, ex: America/Manaus)"})
+		return
+	}
+
 	// Manter dados imutáveis
 	updateData.Id = projectId
 	updateData.OrganizationId = existingProject.OrganizationId
@@ -195,6 +205,15 @@ func (s *ProjectServer) UpdateProject(c *gin.Context) {
 	c.JSON(http.StatusOK, updateData)
 }
 
+// validTimezone fuso vazio usa o padrão; preenchido precisa ser um nome IANA conhecido
+func validTimezone(timezone string) bool {
+	if timezone == "" {
+		return true
+	}
+	_, err := time.LoadLocation(timezone)
+	return err == nil
+}
+
 // SoftDeleteProject remove projeto logicamente
 func (s *ProjectServer) SoftDeleteProject(c *gin.Context) {
 	organizationId := c.GetHeader("X-Lpe-Organization-Id")
@@ -1,12 +1,12 @@
 package server
 
 import (
-	"encoding/json"
 	"lep/handler"
 	"lep/repositories/models"
 	"lep/utils"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -35,6 +35,16 @@ type IServerPublic interface {
 	// Fila de espera pública
 	ServiceGetPublicWaitlist(c *gin.Context)
 	ServiceGetPublicWaitlistBySlug(c *gin.Context)
+	// Pedido pela mesa (QR Code)
+	ServiceCreatePublicOrderBySlug(c *gin.Context)
+	ServiceGetPublicOrderTracking(c *gin.Context)
+	// Retirada e entrega
+	ServiceCreatePublicTakeoutOrderBySlug(c *gin.Context)
+	ServiceGetPublicOrderSlotsBySlug(c *gin.Context)
+	ServiceQuotePublicDeliveryBySlug(c *gin.Context)
+	// Chamados da mesa (garçom, conta, água, talheres)
+	ServiceCreatePublicServiceRequestBySlug(c *gin.Context)
+	ServiceListPublicServiceRequestsBySlug(c *gin.Context)
 }
 
 // ServiceGetPublicMenu retorna produtos do cardápio sem autenticação
@@ -195,8 +205,13 @@ func (r *ResourcePublic) ServiceGetAvailableTimes(c *gin.Context) {
 		return
 	}
 
-	// Gerar horários disponíveis (lógica simplificada)
-	availableTimes := generateAvailableTimeSlots(date, partySize, orgIdStr, projIdStr, r.handler)
+	environmentId, ok := parseEnvironmentPreference(c)
+	if !ok {
+		return
+	}
+
+	// Gerar horários disponíveis
+	availableTimes := generateAvailableTimeSlots(date, partySize, environmentId, orgIdStr, projIdStr, r.handler)
 
 	c.JSON(http.StatusOK, availableTimes)
 }
@@ -231,6 +246,8 @@ func (r *ResourcePublic) ServiceCreatePublicReservation(c *gin.Context) {
 			PartySize int    `json:"party_size" binding:"required,min=1"`
 			Note      string `json:"note"`
 			Source    string `json:"source"`
+			// Ambiente preferido (opcional); o alocador usa outro quando não houver mesa livre nele
+			EnvironmentId *uuid.UUID `json:"environment_id"`
 		} `json:"reservation" binding:"required"`
 	}
 
@@ -273,76 +290,54 @@ func (r *ResourcePublic) ServiceCreatePublicReservation(c *gin.Context) {
 		customer = newCustomer
 	}
 
-	// Parse datetime
-	datetime, err := time.Parse(time.RFC3339, requestData.Reservation.Datetime)
+	// Parse datetime (sem offset = horário local do projeto)
+	clock := r.handler.HandlerProject.Clock(projIdStr)
+	datetime, err := clock.ParseDatetime(requestData.Reservation.Datetime)
 	if err != nil {
-		// Tentar formato alternativo
-		datetime, err = time.Parse("2006-01-02T15:04", requestData.Reservation.Datetime)
-		if err != nil {
-			utils.SendBadRequestError(c, "Invalid datetime format", err)
-			return
-		}
+		utils.SendBadRequestError(c, "Invalid datetime format", err)
+		return
 	}
 
-	// Carregar configurações do projeto (usadas para diningDuration e AutoConfirm)
+	// Configurações do projeto (usadas para AutoConfirm)
 	settings, _ := r.handler.HandlerSettings.GetOrCreateSettings(orgIdStr, projIdStr)
-	diningDuration := 120
-	if settings != nil && settings.DiningDurationMinutes > 0 {
-		diningDuration = settings.DiningDurationMinutes
-	}
 
-	// Buscar mesa disponível (pegar primeira mesa com capacidade suficiente e sem conflito de reserva)
-	tables, err := r.handler.HandlerTables.ListTables(orgIdStr, projIdStr, nil)
+	// Mesas, montagens, reservas ativas e períodos bloqueados do horário
+	allocator, err := r.handler.HandlerTableAllocation.NewAllocator(orgId, projId, datetime, datetime)
 	if err != nil {
 		utils.SendInternalServerError(c, "Error finding available tables", err)
 		return
 	}
 
-	// isPendingBySize: excede threshold configurado OU nenhuma mesa comporta o grupo sozinha
-	exceedsThreshold := settings != nil && settings.AutoConfirmMaxPartySize > 0 && requestData.Reservation.PartySize > settings.AutoConfirmMaxPartySize
-	noTableFits := true
-	for _, t := range tables {
-		if t.Capacity >= requestData.Reservation.PartySize {
-			noTableFits = false
-			break
-		}
+	// Bloqueio do projeto inteiro recusa o horário; os de ambiente/mesas tiram as mesas da escolha
+	if allocator.ProjectBlocked(datetime) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "blocked_period",
+			"message": "Não aceitamos reservas neste horário. Por favor, escolha outro horário.",
+		})
+		return
 	}
-	isPendingBySize := exceedsThreshold || noTableFits
 
-	var selectedTable *models.Table
-	for _, table := range tables {
-		if table.Capacity < requestData.Reservation.PartySize {
-			continue
-		}
-		available, availErr := r.handler.HandlerReservation.IsTableAvailable(table.Id, datetime, diningDuration)
-		if availErr != nil || !available {
-			continue
-		}
-		t := table
-		selectedTable = &t
-		break
-	}
+	// isPendingBySize: excede threshold configurado OU nenhuma mesa ou montagem comporta o grupo
+	partySize := requestData.Reservation.PartySize
+	exceedsThreshold := settings != nil && settings.AutoConfirmMaxPartySize > 0 && partySize > settings.AutoConfirmMaxPartySize
+	isPendingBySize := exceedsThreshold || !allocator.FitsParty(partySize)
 
-	// Para grupos que exigem intervenção manual, tenta qualquer mesa disponível como placeholder
-	if selectedTable == nil && isPendingBySize {
-		for _, table := range tables {
-			available, availErr := r.handler.HandlerReservation.IsTableAvailable(table.Id, datetime, diningDuration)
-			if availErr != nil || !available {
-				continue
-			}
-			t := table
-			selectedTable = &t
-			break
-		}
+	// Melhor mesa ou montagem livre: ambiente preferido primeiro, depois menor sobra de lugares
+	allocation := allocator.Allocate(datetime, partySize, requestData.Reservation.EnvironmentId, uuid.Nil)
+
+	// Para grupos que exigem intervenção manual, usa a maior opção livre como placeholder
+	if allocation == nil && isPendingBySize {
+		allocation = allocator.Placeholder(datetime, partySize, uuid.Nil)
 	}
 
-	if selectedTable == nil {
+	if allocation == nil {
 		c.JSON(http.StatusUnprocessableEntity, gin.H{
 			"error":   "no_availability",
 			"message": "Estamos sem disponibilidade para o horário e quantidade de pessoas selecionados. Por favor, escolha outro horário ou entre em contato conosco.",
 		})
 		return
 	}
+	selectedTable := allocator.Table(allocation.TableIds[0])
 
 	// Determinar status
 	reservationStatus := "confirmed"
@@ -351,24 +346,29 @@ func (r *ResourcePublic) ServiceCreatePublicReservation(c *gin.Context) {
 	}
 
 	// Criar reserva
-	var tableId *uuid.UUID
-	if selectedTable != nil {
-		tableId = &selectedTable.Id
-	}
 	newReservation := models.Reservation{
 		Id:             uuid.New(),
 		OrganizationId: orgId,
 		ProjectId:      projId,
 		CustomerId:     customer.Id,
-		TableId:        tableId,
-		Datetime:       datetime.Format(time.RFC3339),
+		Datetime:       clock.Format(datetime),
 		PartySize:      requestData.Reservation.PartySize,
 		Status:         reservationStatus,
 		Note:           requestData.Reservation.Note,
 	}
+	utils.ApplyTableAllocation(&newReservation, allocation)
 
 	err = r.handler.HandlerReservation.CreateReservation(&newReservation)
 	if err != nil {
+		// Outra reserva ocupou a mesa entre a escolha e a gravação
+		if strings.Contains(err.Error(), "reservation_conflict") {
+			sendReservationConflict(c, r.handler, &newReservation, requestData.Reservation.EnvironmentId)
+			return
+		}
+		// Horário com chegadas demais para a cozinha
+		if sendPacingLimit(c, r.handler, &newReservation, requestData.Reservation.EnvironmentId, http.StatusUnprocessableEntity, err) {
+			return
+		}
 		utils.SendInternalServerError(c, "Error creating reservation", err)
 		return
 	}
@@ -383,112 +383,70 @@ func (r *ResourcePublic) ServiceCreatePublicReservation(c *gin.Context) {
 		"customer":    customer,
 		"reservation": newReservation,
 		"table":       selectedTable,
+		"allocation":  allocation,
 	}
 
 	utils.SendCreatedSuccess(c, "Reservation created successfully", response)
 }
 
 // generateAvailableTimeSlots gera horários disponíveis verificando disponibilidade real no banco.
-// Os horários de funcionamento são carregados das configurações do projeto.
-func generateAvailableTimeSlots(date time.Time, partySize int, orgId, projId string, h *handler.Handlers) []gin.H {
-	// Defaults caso as configurações não sejam encontradas
-	lunchStart, lunchEnd := "12:00", "14:30"
-	dinnerStart, dinnerEnd := "19:00", "22:00"
-	slotInterval := 30
-	enableLunch, enableDinner := true, true
-	diningDuration := 120
-
+// Os horários de funcionamento são carregados das configurações do projeto; environmentId é só preferência.
+func generateAvailableTimeSlots(date time.Time, partySize int, environmentId *uuid.UUID, orgId, projId string, h *handler.Handlers) []gin.H {
 	settings, err := h.HandlerSettings.GetOrCreateSettings(orgId, projId)
-	if err == nil && settings != nil {
-		if settings.LunchStart != "" {
-			lunchStart = settings.LunchStart
-		}
-		if settings.LunchEnd != "" {
-			lunchEnd = settings.LunchEnd
-		}
-		if settings.DinnerStart != "" {
-			dinnerStart = settings.DinnerStart
-		}
-		if settings.DinnerEnd != "" {
-			dinnerEnd = settings.DinnerEnd
-		}
-		if settings.SlotIntervalMinutes > 0 {
-			slotInterval = settings.SlotIntervalMinutes
-		}
-		enableLunch = settings.EnableLunch
-		enableDinner = settings.EnableDinner
-		if settings.DiningDurationMinutes > 0 {
-			diningDuration = settings.DiningDurationMinutes
-		}
-
-		// Agenda semanal: sobrescreve enableLunch/enableDinner para o dia da semana solicitado
-		if settings.OperatingScheduleJson != "" {
-			type dayConfig struct {
-				Enabled      bool `json:"enabled"`
-				EnableLunch  bool `json:"enable_lunch"`
-				EnableDinner bool `json:"enable_dinner"`
-			}
-			var schedule map[string]dayConfig
-			if jsonErr := json.Unmarshal([]byte(settings.OperatingScheduleJson), &schedule); jsonErr == nil {
-				dayKey := strconv.Itoa(int(date.Weekday())) // 0=Domingo ... 6=Sábado
-				if dc, ok := schedule[dayKey]; ok {
-					if !dc.Enabled {
-						return []gin.H{} // restaurante fechado neste dia
-					}
-					enableLunch = dc.EnableLunch
-					enableDinner = dc.EnableDinner
-				}
-			}
-		}
+	if err != nil {
+		settings = nil // horários padrão
 	}
 
-	var timeSlots []string
-	if enableLunch {
-		timeSlots = append(timeSlots, buildTimeSlots(lunchStart, lunchEnd, slotInterval)...)
-	}
-	if enableDinner {
-		timeSlots = append(timeSlots, buildTimeSlots(dinnerStart, dinnerEnd, slotInterval)...)
-	}
+	// Dia e horários no relógio do projeto
+	clock := h.HandlerProject.Clock(projId)
+	dayStart := clock.Date(date)
+	now := clock.Now()
 
-	tables, tablesErr := h.HandlerTables.ListTables(orgId, projId, nil)
+	timeSlots := utils.ServiceTimeSlots(dayStart, settings, 0)
+	if len(timeSlots) == 0 {
+		return []gin.H{} // restaurante fechado neste dia
+	}
 
-	// Se nenhuma mesa tem capacidade para o grupo, aceita qualquer mesa disponível (reserva ficará pending)
-	noTableFitsParty := true
-	if tablesErr == nil {
-		for _, t := range tables {
-			if t.Capacity >= partySize {
-				noTableFitsParty = false
-				break
-			}
+	// Mesas, montagens, reservas e bloqueios do dia, e limites de ritmo (erro = nenhum horário disponível)
+	var allocator *utils.TableAllocator
+	var pacing *utils.PacingChecker
+	orgUUID, orgErr := uuid.Parse(orgId)
+	projUUID, projErr := uuid.Parse(projId)
+	if orgErr == nil && projErr == nil {
+		allocator, err = h.HandlerTableAllocation.NewAllocator(orgUUID, projUUID, dayStart, dayStart.AddDate(0, 0, 1))
+		if err != nil {
+			allocator = nil
+		}
+		pacing, err = h.HandlerPacing.NewChecker(orgUUID, projUUID, dayStart, dayStart.AddDate(0, 0, 1))
+		if err != nil {
+			allocator = nil
 		}
 	}
 
+	// Se nenhuma mesa ou montagem comporta o grupo, aceita qualquer opção livre (reserva ficará pending)
+	noTableFitsParty := allocator != nil && !allocator.FitsParty(partySize)
+
 	availableTimes := make([]gin.H, 0)
 	for _, slot := range timeSlots {
-		slotTime, parseErr := time.Parse("15:04", slot)
+		dt, parseErr := utils.SlotDateTime(dayStart, slot, clock.Location())
 		if parseErr != nil {
 			continue
 		}
-		dt := time.Date(date.Year(), date.Month(), date.Day(),
-			slotTime.Hour(), slotTime.Minute(), 0, 0, time.UTC)
+		// Horário bloqueado para o projeto inteiro não é oferecido
+		if allocator != nil && allocator.ProjectBlocked(dt) {
+			continue
+		}
 
+		// Horários que já passaram ou com chegadas no limite de ritmo não ficam disponíveis
 		hasAvailableTable := false
-		if tablesErr == nil {
-			for _, table := range tables {
-				// Se nenhuma mesa comporta o grupo, verifica disponibilidade sem filtrar capacidade
-				if !noTableFitsParty && table.Capacity < partySize {
-					continue
-				}
-				ok, checkErr := h.HandlerReservation.IsTableAvailable(table.Id, dt, diningDuration)
-				if checkErr == nil && ok {
-					hasAvailableTable = true
-					break
-				}
-			}
+		if allocator != nil && dt.After(now) && pacing.Check(dt, partySize, uuid.Nil) == nil {
+			hasAvailableTable = allocator.Allocate(dt, partySize, environmentId, uuid.Nil) != nil ||
+				(noTableFitsParty && allocator.Placeholder(dt, partySize, uuid.Nil) != nil)
 		}
 
 		availableTimes = append(availableTimes, gin.H{
 			"time":      slot,
+			"datetime":  clock.Format(dt),
 			"available": hasAvailableTable,
 		})
 	}
@@ -496,23 +454,18 @@ func generateAvailableTimeSlots(date time.Time, partySize int, orgId, projId str
 	return availableTimes
 }
 
-// buildTimeSlots gera uma lista de horários (HH:MM) entre start e end com o intervalo dado em minutos.
-func buildTimeSlots(start, end string, intervalMinutes int) []string {
-	startTime, err := time.Parse("15:04", start)
-	if err != nil {
-		return nil
+// parseEnvironmentPreference lê o ambiente preferido da query (?environment_id=; opcional)
+func parseEnvironmentPreference(c *gin.Context) (*uuid.UUID, bool) {
+	value := c.Query("environment_id")
+	if value == "" {
+		return nil, true
 	}
-	endTime, err := time.Parse("15:04", end)
+	environmentId, err := uuid.Parse(value)
 	if err != nil {
-		return nil
-	}
-	var slots []string
-	cur := startTime
-	for !cur.After(endTime) {
-		slots = append(slots, cur.Format("15:04"))
-		cur = cur.Add(time.Duration(intervalMinutes) * time.Minute)
+		utils.SendBadRequestError(c, "Invalid environment_id", err)
+		return nil, false
 	}
-	return slots
+	return &environmentId, true
 }
 
 // ServiceGetPublicCategories retorna categorias ativas sem autenticação
@@ -787,7 +740,12 @@ func (r *ResourcePublic) ServiceGetAvailableTimesBySlug(c *gin.Context) {
 		return
 	}
 
-	availableTimes := generateAvailableTimeSlots(date, partySize, orgId, projId, r.handler)
+	environmentId, ok := parseEnvironmentPreference(c)
+	if !ok {
+		return
+	}
+
+	availableTimes := generateAvailableTimeSlots(date, partySize, environmentId, orgId, projId, r.handler)
 	c.JSON(http.StatusOK, availableTimes)
 }
 
@@ -824,6 +782,8 @@ func (r *ResourcePublic) ServiceCreatePublicReservationBySlug(c *gin.Context) {
 			PartySize int    `json:"party_size" binding:"required,min=1"`
 			Note      string `json:"note"`
 			Source    string `json:"source"`
+			// Ambiente preferido (opcional); o alocador usa outro quando não houver mesa livre nele
+			EnvironmentId *uuid.UUID `json:"environment_id"`
 		} `json:"reservation" binding:"required"`
 	}
 
@@ -866,73 +826,53 @@ func (r *ResourcePublic) ServiceCreatePublicReservationBySlug(c *gin.Context) {
 		customer = newCustomer
 	}
 
-	datetime, err := time.Parse(time.RFC3339, requestData.Reservation.Datetime)
+	clock := r.handler.HandlerProject.Clock(projId.String())
+	datetime, err := clock.ParseDatetime(requestData.Reservation.Datetime)
 	if err != nil {
-		datetime, err = time.Parse("2006-01-02T15:04", requestData.Reservation.Datetime)
-		if err != nil {
-			utils.SendBadRequestError(c, "Invalid datetime format", err)
-			return
-		}
+		utils.SendBadRequestError(c, "Invalid datetime format", err)
+		return
 	}
 
-	// Carregar configurações do projeto (usadas para diningDuration e AutoConfirm)
+	// Configurações do projeto (usadas para AutoConfirm)
 	settings, _ := r.handler.HandlerSettings.GetOrCreateSettings(orgId.String(), projId.String())
-	diningDuration := 120
-	if settings != nil && settings.DiningDurationMinutes > 0 {
-		diningDuration = settings.DiningDurationMinutes
-	}
 
-	tables, err := r.handler.HandlerTables.ListTables(orgId.String(), projId.String(), nil)
+	// Mesas, montagens, reservas ativas e períodos bloqueados do horário
+	allocator, err := r.handler.HandlerTableAllocation.NewAllocator(orgId, projId, datetime, datetime)
 	if err != nil {
 		utils.SendInternalServerError(c, "Error finding available tables", err)
 		return
 	}
 
-	// isPendingBySize: excede threshold configurado OU nenhuma mesa comporta o grupo sozinha
-	exceedsThresholdSlug := settings != nil && settings.AutoConfirmMaxPartySize > 0 && requestData.Reservation.PartySize > settings.AutoConfirmMaxPartySize
-	noTableFitsSlug := true
-	for _, t := range tables {
-		if t.Capacity >= requestData.Reservation.PartySize {
-			noTableFitsSlug = false
-			break
-		}
+	// Bloqueio do projeto inteiro recusa o horário; os de ambiente/mesas tiram as mesas da escolha
+	if allocator.ProjectBlocked(datetime) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "blocked_period",
+			"message": "Não aceitamos reservas neste horário. Por favor, escolha outro horário.",
+		})
+		return
 	}
-	isPendingBySize := exceedsThresholdSlug || noTableFitsSlug
 
-	var selectedTable *models.Table
-	for _, table := range tables {
-		if table.Capacity < requestData.Reservation.PartySize {
-			continue
-		}
-		available, availErr := r.handler.HandlerReservation.IsTableAvailable(table.Id, datetime, diningDuration)
-		if availErr != nil || !available {
-			continue
-		}
-		t := table
-		selectedTable = &t
-		break
-	}
+	// isPendingBySize: excede threshold configurado OU nenhuma mesa ou montagem comporta o grupo
+	partySize := requestData.Reservation.PartySize
+	exceedsThreshold := settings != nil && settings.AutoConfirmMaxPartySize > 0 && partySize > settings.AutoConfirmMaxPartySize
+	isPendingBySize := exceedsThreshold || !allocator.FitsParty(partySize)
 
-	// Para grupos que exigem intervenção manual, tenta qualquer mesa disponível como placeholder
-	if selectedTable == nil && isPendingBySize {
-		for _, table := range tables {
-			available, availErr := r.handler.HandlerReservation.IsTableAvailable(table.Id, datetime, diningDuration)
-			if availErr != nil || !available {
-				continue
-			}
-			t := table
-			selectedTable = &t
-			break
-		}
+	// Melhor mesa ou montagem livre: ambiente preferido primeiro, depois menor sobra de lugares
+	allocation := allocator.Allocate(datetime, partySize, requestData.Reservation.EnvironmentId, uuid.Nil)
+
+	// Para grupos que exigem intervenção manual, usa a maior opção livre como placeholder
+	if allocation == nil && isPendingBySize {
+		allocation = allocator.Placeholder(datetime, partySize, uuid.Nil)
 	}
 
-	if selectedTable == nil {
+	if allocation == nil {
 		c.JSON(http.StatusUnprocessableEntity, gin.H{
 			"error":   "no_availability",
 			"message": "Estamos sem disponibilidade para o horário e quantidade de pessoas selecionados. Por favor, escolha outro horário ou entre em contato conosco.",
 		})
 		return
 	}
+	selectedTable := allocator.Table(allocation.TableIds[0])
 
 	// Determinar status
 	reservationStatus := "confirmed"
@@ -940,24 +880,29 @@ func (r *ResourcePublic) ServiceCreatePublicReservationBySlug(c *gin.Context) {
 		reservationStatus = "pending"
 	}
 
-	var tableIdSlug *uuid.UUID
-	if selectedTable != nil {
-		tableIdSlug = &selectedTable.Id
-	}
 	newReservation := models.Reservation{
 		Id:             uuid.New(),
 		OrganizationId: orgId,
 		ProjectId:      projId,
 		CustomerId:     customer.Id,
-		TableId:        tableIdSlug,
-		Datetime:       datetime.Format(time.RFC3339),
+		Datetime:       clock.Format(datetime),
 		PartySize:      requestData.Reservation.PartySize,
 		Status:         reservationStatus,
 		Note:           requestData.Reservation.Note,
 	}
+	utils.ApplyTableAllocation(&newReservation, allocation)
 
 	err = r.handler.HandlerReservation.CreateReservation(&newReservation)
 	if err != nil {
+		// Outra reserva ocupou a mesa entre a escolha e a gravação
+		if strings.Contains(err.Error(), "reservation_conflict") {
+			sendReservationConflict(c, r.handler, &newReservation, requestData.Reservation.EnvironmentId)
+			return
+		}
+		// Horário com chegadas demais para a cozinha
+		if sendPacingLimit(c, r.handler, &newReservation, requestData.Reservation.EnvironmentId, http.StatusUnprocessableEntity, err) {
+			return
+		}
 		utils.SendInternalServerError(c, "Error creating reservation", err)
 		return
 	}
@@ -971,6 +916,7 @@ func (r *ResourcePublic) ServiceCreatePublicReservationBySlug(c *gin.Context) {
 		"customer":    customer,
 		"reservation": newReservation,
 		"table":       selectedTable,
+		"allocation":  allocation,
 	}
 
 	utils.SendCreatedSuccess(c, "Reservation created successfully", response)
@@ -1065,6 +1011,229 @@ func (r *ResourcePublic) ServiceGetPublicWaitlistBySlug(c *gin.Context) {
 	})
 }
 
+// ServiceCreatePublicOrderBySlug cria pedido feito pelo cliente no QR Code da mesa
+func (r *ResourcePublic) ServiceCreatePublicOrderBySlug(c *gin.Context) {
+	orgSlug := c.Param("orgSlug")
+	projectSlug := c.Param("projectSlug")
+
+	tableNumber, err := strconv.Atoi(c.Param("number"))
+	if err != nil || tableNumber <= 0 {
+		utils.SendBadRequestError(c, "Invalid table number", err)
+		return
+	}
+
+	orgId, projId, err := r.resolveOrgAndProject(orgSlug, projectSlug)
+	if err != nil {
+		utils.SendNotFoundError(c, "Organization or project not found")
+		return
+	}
+
+	var request models.PublicOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	order, err := r.handler.HandlerPublicOrder.CreatePublicOrder(orgId, projId, tableNumber, request)
+	if err != nil {
+		if respondOrderItemsError(c, err) {
+			return
+		}
+		switch {
+		case strings.Contains(err.Error(), "menu_unavailable"):
+			utils.SendConflictError(c, "Menu is not available right now", err)
+		case strings.Contains(err.Error(), "not found"):
+			utils.SendNotFoundError(c, "Table")
+		default:
+			utils.SendInternalServerError(c, "Error creating order", err)
+		}
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Order placed successfully", gin.H{
+		"order_id":           order.Id,
+		"tracking_token":     order.TrackingToken,
+		"status":             order.Status,
+		"table_number":       order.TableNumber,
+		"items":              order.Items,
+		"total_amount":       order.TotalAmount,
+		"estimated_delivery": order.EstimatedDeliveryTime,
+	})
+}
+
+// ServiceGetPublicOrderTracking retorna o andamento do pedido pelo token de acompanhamento
+func (r *ResourcePublic) ServiceGetPublicOrderTracking(c *gin.Context) {
+	tracking, err := r.handler.HandlerPublicOrder.GetOrderTracking(c.Param("token"))
+	if err != nil {
+		utils.SendNotFoundError(c, "Order")
+		return
+	}
+
+	c.JSON(http.StatusOK, tracking)
+}
+
+// ServiceCreatePublicTakeoutOrderBySlug cria pedido de retirada ou entrega feito pelo cliente no site
+func (r *ResourcePublic) ServiceCreatePublicTakeoutOrderBySlug(c *gin.Context) {
+	orgId, projId, err := r.resolveOrgAndProject(c.Param("orgSlug"), c.Param("projectSlug"))
+	if err != nil {
+		utils.SendNotFoundError(c, "Organization or project not found")
+		return
+	}
+
+	var request models.PublicTakeoutOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	order, err := r.handler.HandlerPublicOrder.CreatePublicTakeoutOrder(orgId, projId, request)
+	if err != nil {
+		if respondOrderItemsError(c, err) || respondOrderTypeError(c, err) {
+			return
+		}
+		switch {
+		case strings.Contains(err.Error(), "invalid_customer"):
+			utils.SendBadRequestError(c, "Customer name and phone are required", err)
+		case strings.Contains(err.Error(), "menu_unavailable"):
+			utils.SendConflictError(c, "Menu is not available right now", err)
+		default:
+			utils.SendInternalServerError(c, "Error creating order", err)
+		}
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Order placed successfully", gin.H{
+		"order_id":           order.Id,
+		"tracking_token":     order.TrackingToken,
+		"status":             order.Status,
+		"type":               order.Type,
+		"scheduled_for":      order.ScheduledFor,
+		"items":              order.Items,
+		"total_amount":       order.AmountDue(),
+		"delivery_fee":       order.DeliveryFee,
+		"estimated_delivery": order.EstimatedDeliveryTime,
+	})
+}
+
+// ServiceGetPublicOrderSlotsBySlug lista horários de retirada/entrega (?type=takeout|delivery&date=YYYY-MM-DD)
+func (r *ResourcePublic) ServiceGetPublicOrderSlotsBySlug(c *gin.Context) {
+	orgId, projId, ok := r.resolveProjectIds(c)
+	if !ok {
+		return
+	}
+
+	date, ok := parseSlotDate(c, r.handler.HandlerProject.Clock(projId.String()))
+	if !ok {
+		return
+	}
+
+	slots, err := r.handler.HandlerDelivery.ListOrderSlots(orgId, projId, c.DefaultQuery("type", models.OrderTypeTakeout), date)
+	if err != nil {
+		sendDeliveryError(c, "Error listing order slots", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"date":  date.Format("2006-01-02"),
+		"slots": slots,
+	})
+}
+
+// ServiceQuotePublicDeliveryBySlug informa taxa e pedido mínimo para o endereço do cliente
+func (r *ResourcePublic) ServiceQuotePublicDeliveryBySlug(c *gin.Context) {
+	orgId, projId, ok := r.resolveProjectIds(c)
+	if !ok {
+		return
+	}
+
+	var address models.Address
+	if err := c.ShouldBindJSON(&address); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	quote, err := r.handler.HandlerDelivery.QuoteDelivery(orgId, projId, &address)
+	if err != nil {
+		sendDeliveryError(c, "Error quoting delivery", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+// ServiceCreatePublicServiceRequestBySlug registra chamado feito pelo cliente no QR Code da mesa
+func (r *ResourcePublic) ServiceCreatePublicServiceRequestBySlug(c *gin.Context) {
+	tableNumber, err := strconv.Atoi(c.Param("number"))
+	if err != nil || tableNumber <= 0 {
+		utils.SendBadRequestError(c, "Invalid table number", err)
+		return
+	}
+
+	orgId, projId, ok := r.resolveProjectIds(c)
+	if !ok {
+		return
+	}
+
+	var request models.PublicServiceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	serviceRequest, created, err := r.handler.HandlerServiceRequest.CreatePublicRequest(orgId.String(), projId.String(), tableNumber, request, c.ClientIP())
+	if err != nil {
+		sendServiceRequestError(c, "Error creating service request", err)
+		return
+	}
+
+	status := models.PublicServiceRequestStatus{
+		Id:             serviceRequest.Id,
+		Type:           serviceRequest.Type,
+		Status:         serviceRequest.Status,
+		CreatedAt:      serviceRequest.CreatedAt,
+		AcknowledgedAt: serviceRequest.AcknowledgedAt,
+	}
+	// Chamado igual ainda aberto: devolve o existente sem notificar de novo
+	if !created {
+		utils.SendOKSuccess(c, "Request already sent", status)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Request sent", status)
+}
+
+// ServiceListPublicServiceRequestsBySlug chamados em aberto da mesa
+func (r *ResourcePublic) ServiceListPublicServiceRequestsBySlug(c *gin.Context) {
+	tableNumber, err := strconv.Atoi(c.Param("number"))
+	if err != nil || tableNumber <= 0 {
+		utils.SendBadRequestError(c, "Invalid table number", err)
+		return
+	}
+
+	orgId, projId, ok := r.resolveProjectIds(c)
+	if !ok {
+		return
+	}
+
+	requests, err := r.handler.HandlerServiceRequest.ListPublicRequests(orgId.String(), projId.String(), tableNumber)
+	if err != nil {
+		sendServiceRequestError(c, "Error listing service requests", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// resolveProjectIds resolve os slugs da rota para os IDs de organização e projeto
+func (r *ResourcePublic) resolveProjectIds(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	orgId, projId, err := r.resolveOrgAndProject(c.Param("orgSlug"), c.Param("projectSlug"))
+	if err != nil {
+		utils.SendNotFoundError(c, "Organization or project not found")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return uuid.MustParse(orgId), uuid.MustParse(projId), true
+}
+
 func NewSourceServerPublic(handler *handler.Handlers) IServerPublic {
 	return &ResourcePublic{handler: handler}
 }
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lep/config"
+	"lep/handler"
+	"lep/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const realtimeHeartbeatInterval = 25 * time.Second
+
+type ResourceRealtime struct {
+	handler *handler.Handlers
+}
+
+type IServerRealtime interface {
+	ServiceCreateTicket(c *gin.Context)
+	ServiceStreamEvents(c *gin.Context)
+	ServiceWebSocketEvents(c *gin.Context)
+}
+
+// ServiceCreateTicket emite o ticket curto que o navegador envia em ?ticket= ao abrir o stream ou o WebSocket
+func (r *ResourceRealtime) ServiceCreateTicket(c *gin.Context) {
+	// Stream sempre escopado por organização/projeto (inclusive para admins)
+	if c.GetString("organization_id") == "" || c.GetString("project_id") == "" {
+		utils.SendBadRequestError(c, "X-Lpe-Organization-Id and X-Lpe-Project-Id headers are required", nil)
+		return
+	}
+
+	ticket, expiresAt, err := utils.NewRealtimeTicket(utils.RealtimeTicket{
+		UserId:         c.GetString("user_id"),
+		UserType:       c.GetString("user_type"),
+		OrganizationId: c.GetString("organization_id"),
+		ProjectId:      c.GetString("project_id"),
+	})
+	if err != nil {
+		utils.SendInternalServerError(c, "Error creating realtime ticket", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ticket": ticket, "expires_at": expiresAt})
+}
+
+// ServiceStreamEvents abre um stream SSE com os eventos da organização/projeto.
+// Query: topics=kitchen,floor,waitlist (padrão: todos). Retomada via header Last-Event-ID ou query last_event_id.
+func (r *ResourceRealtime) ServiceStreamEvents(c *gin.Context) {
+	topics, lastEventId, ok := parseRealtimeParams(c)
+	if !ok {
+		return
+	}
+
+	bus := utils.GetRealtimeBus()
+	sub, missed, complete := bus.Subscribe(c.GetString("organization_id"), c.GetString("project_id"), topics, lastEventId)
+	defer bus.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if !complete {
+		// Eventos perdidos: cliente deve recarregar o estado via REST
+		fmt.Fprint(c.Writer, "event: resync\ndata: {}\n\n")
+	}
+	for _, event := range missed {
+		writeSSEEvent(c, event)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(realtimeHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, open := <-sub.Events:
+			if !open {
+				return
+			}
+			writeSSEEvent(c, event)
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// ServiceWebSocketEvents entrega os mesmos eventos do stream SSE via WebSocket (mensagens JSON)
+func (r *ResourceRealtime) ServiceWebSocketEvents(c *gin.Context) {
+	topics, lastEventId, ok := parseRealtimeParams(c)
+	if !ok {
+		return
+	}
+
+	orgId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	wsServer := websocket.Server{
+		// Navegadores não aplicam CORS ao WebSocket: origem conferida com a mesma lista
+		Handshake: func(_ *websocket.Config, req *http.Request) error {
+			if origin := req.Header.Get("Origin"); origin != "" && !config.IsAllowedOrigin(origin) {
+				return errors.New("origin not allowed")
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			bus := utils.GetRealtimeBus()
+			sub, missed, complete := bus.Subscribe(orgId, projectId, topics, lastEventId)
+			defer bus.Unsubscribe(sub)
+
+			// Leitura apenas para detectar fechamento pelo cliente
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var discard string
+				for websocket.Message.Receive(ws, &discard) == nil {
+				}
+			}()
+
+			if !complete {
+				if err := websocket.JSON.Send(ws, gin.H{"type": "resync"}); err != nil {
+					return
+				}
+			}
+			for _, event := range missed {
+				if err := websocket.JSON.Send(ws, event); err != nil {
+					return
+				}
+			}
+
+			heartbeat := time.NewTicker(realtimeHeartbeatInterval)
+			defer heartbeat.Stop()
+
+			for {
+				select {
+				case <-closed:
+					return
+				case event, open := <-sub.Events:
+					if !open {
+						return
+					}
+					if err := websocket.JSON.Send(ws, event); err != nil {
+						return
+					}
+				case <-heartbeat.C:
+					if err := websocket.JSON.Send(ws, gin.H{"type": "ping"}); err != nil {
+						return
+					}
+				}
+			}
+		},
+	}
+	wsServer.ServeHTTP(c.Writer, c.Request)
+}
+
+// parseRealtimeParams lê tópicos e last-event id da requisição
+func parseRealtimeParams(c *gin.Context) ([]string, uint64, bool) {
+	// Stream sempre escopado por organização/projeto (inclusive para admins)
+	if c.GetString("organization_id") == "" || c.GetString("project_id") == "" {
+		utils.SendBadRequestError(c, "X-Lpe-Organization-Id and X-Lpe-Project-Id headers are required", nil)
+		return nil, 0, false
+	}
+
+	topics := utils.RealtimeTopics
+	if raw := strings.TrimSpace(c.Query("topics")); raw != "" {
+		topics = make([]string, 0)
+		for _, topic := range strings.Split(raw, ",") {
+			topic = strings.TrimSpace(topic)
+			valid := false
+			for _, allowed := range utils.RealtimeTopics {
+				if topic == allowed {
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				utils.SendBadRequestError(c, "Invalid topic. Allowed: kitchen, floor, waitlist", nil)
+				return nil, 0, false
+			}
+			topics = append(topics, topic)
+		}
+	}
+
+	rawLastId := c.GetHeader("Last-Event-ID")
+	if rawLastId == "" {
+		rawLastId = c.Query("last_event_id")
+	}
+	var lastEventId uint64
+	if rawLastId != "" {
+		parsed, err := strconv.ParseUint(rawLastId, 10, 64)
+		if err != nil {
+			utils.SendBadRequestError(c, "Invalid last event id", err)
+			return nil, 0, false
+		}
+		lastEventId = parsed
+	}
+
+	return topics, lastEventId, true
+}
+
+func writeSSEEvent(c *gin.Context, event utils.RealtimeEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Topic, payload)
+}
+
+func NewSourceServerRealtime(handler *handler.Handlers) IServerRealtime {
+	return &ResourceRealtime{handler: handler}
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"lep/handler"
 	"lep/repositories/models"
+	"lep/utils"
 	"net/http"
 	"strings"
 
@@ -95,12 +96,33 @@ func (r *ResourceReservation) ServiceCreateReservation(c *gin.Context) {
 		newReservation.Id = uuid.New()
 	}
 
+	// Sem mesa informada, o alocador escolhe a melhor mesa ou montagem livre
+	if newReservation.TableId == nil {
+		r.autoAssignTables(&newReservation)
+	}
+
+	// Exceção ao limite de ritmo registra quem aceitou
+	setPacingOverrideBy(c, &newReservation)
+
 	err = r.handler.HandlerReservation.CreateReservation(&newReservation)
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid_datetime") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "reservation_conflict") {
+			sendReservationConflict(c, r.handler, &newReservation, nil)
+			return
+		}
+		if sendPacingLimit(c, r.handler, &newReservation, nil, http.StatusConflict, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	r.logPacingOverride(c, &newReservation)
+
 	c.JSON(http.StatusCreated, newReservation)
 }
 
@@ -143,13 +165,34 @@ func (r *ResourceReservation) ServiceUpdateReservation(c *gin.Context) {
 	updatedReservation.Id = reservationId
 	updatedReservation.OrganizationId, _ = uuid.Parse(organizationId)
 	updatedReservation.ProjectId, _ = uuid.Parse(projectId)
+	setPacingOverrideBy(c, &updatedReservation)
 
 	err = r.handler.HandlerReservation.UpdateReservation(&updatedReservation)
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid_datetime") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// Sugestões usam o grupo e o horário atuais quando a atualização não os altera
+		if updatedReservation.PartySize == 0 && currentReservation != nil {
+			updatedReservation.PartySize = currentReservation.PartySize
+		}
+		if updatedReservation.StartsAt == nil && currentReservation != nil {
+			updatedReservation.StartsAt = currentReservation.StartsAt
+		}
+		if strings.Contains(err.Error(), "reservation_conflict") {
+			sendReservationConflict(c, r.handler, &updatedReservation, nil)
+			return
+		}
+		if sendPacingLimit(c, r.handler, &updatedReservation, nil, http.StatusConflict, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	r.logPacingOverride(c, &updatedReservation)
+
 	// Disparar notificação se status mudou para confirmed, not_approved ou pending
 	if currentReservation != nil && currentReservation.Status != updatedReservation.Status && r.handler.EventService != nil {
 		newStatus := updatedReservation.Status
@@ -228,6 +271,42 @@ func (r *ResourceReservation) ServiceListReservations(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// autoAssignTables escolhe mesas para reserva criada sem mesa; sem opção livre ela segue sem mesa para a equipe definir
+func (r *ResourceReservation) autoAssignTables(reservation *models.Reservation) {
+	datetime, err := r.handler.HandlerProject.Clock(reservation.ProjectId.String()).ParseDatetime(reservation.Datetime)
+	if err != nil {
+		return // CreateReservation devolve o erro de datetime
+	}
+	allocator, err := r.handler.HandlerTableAllocation.NewAllocator(reservation.OrganizationId, reservation.ProjectId, datetime, datetime)
+	if err != nil || allocator.ProjectBlocked(datetime) {
+		return
+	}
+	utils.ApplyTableAllocation(reservation, allocator.Allocate(datetime, reservation.PartySize, nil, reservation.Id))
+}
+
 func NewSourceServerReservation(handler *handler.Handlers) IServerReservation {
 	return &ResourceReservation{handler: handler}
-}
\ No newline at end of file
+}
+
+// setPacingOverrideBy grava o usuário que informou o motivo de exceção ao limite de ritmo
+func setPacingOverrideBy(c *gin.Context, reservation *models.Reservation) {
+	reservation.PacingOverrideReason = strings.TrimSpace(reservation.PacingOverrideReason)
+	reservation.PacingOverrideBy = nil
+	if reservation.PacingOverrideReason == "" {
+		return
+	}
+	if userId, err := uuid.Parse(c.GetString("user_id")); err == nil {
+		reservation.PacingOverrideBy = &userId
+	}
+}
+
+// logPacingOverride registra na auditoria a reserva aceita pela equipe com exceção ao limite de ritmo
+func (r *ResourceReservation) logPacingOverride(c *gin.Context, reservation *models.Reservation) {
+	if reservation.PacingOverrideReason == "" {
+		return
+	}
+	_ = r.handler.HandlerClientAuditLog.LogAction(reservation.OrganizationId, reservation.ProjectId, reservation.PacingOverrideBy, c.GetString("user_email"),
+		models.ClientAuditActionPacingOverride, models.ClientAuditEntityReservation, reservation.Id, models.ClientAuditModuleReservations, nil,
+		map[string]interface{}{"pacing_override_reason": reservation.PacingOverrideReason, "datetime": reservation.Datetime, "party_size": reservation.PartySize},
+		[]string{"pacing_override_reason"}, "Reserva aceita acima do limite de ritmo: "+reservation.PacingOverrideReason, c.ClientIP())
+}
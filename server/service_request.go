@@ -0,0 +1,157 @@
+package server
+
+import (
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResourceServiceRequest struct {
+	handler *handler.Handlers
+}
+
+type IServerServiceRequest interface {
+	ServiceListRequests(c *gin.Context)
+	ServiceGetRequest(c *gin.Context)
+	ServiceAcknowledgeRequest(c *gin.Context)
+	ServiceResolveRequest(c *gin.Context)
+	ServiceGetReport(c *gin.Context)
+}
+
+// ServiceListRequests lista chamados das mesas (?status=open|acknowledged|resolved|all&environment_id=&table_id=&limit=100)
+func (r *ResourceServiceRequest) ServiceListRequests(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	requests, err := r.handler.HandlerServiceRequest.ListRequests(organizationId, projectId, c.Query("status"), c.Query("environment_id"), c.Query("table_id"), limit)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing service requests", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+func (r *ResourceServiceRequest) ServiceGetRequest(c *gin.Context) {
+	request, ok := r.getOwnedRequest(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+// ServiceAcknowledgeRequest marca o chamado como visto
+func (r *ResourceServiceRequest) ServiceAcknowledgeRequest(c *gin.Context) {
+	request, ok := r.getOwnedRequest(c)
+	if !ok {
+		return
+	}
+
+	acknowledged, err := r.handler.HandlerServiceRequest.AcknowledgeRequest(request.Id.String(), c.GetString("user_id"))
+	if err != nil {
+		sendServiceRequestError(c, "Error acknowledging service request", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Service request acknowledged", acknowledged)
+}
+
+// ServiceResolveRequest encerra o chamado
+func (r *ResourceServiceRequest) ServiceResolveRequest(c *gin.Context) {
+	request, ok := r.getOwnedRequest(c)
+	if !ok {
+		return
+	}
+
+	resolved, err := r.handler.HandlerServiceRequest.ResolveRequest(request.Id.String(), c.GetString("user_id"))
+	if err != nil {
+		sendServiceRequestError(c, "Error resolving service request", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Service request resolved", resolved)
+}
+
+// ServiceGetReport tempos de resposta por setor (?start_date=&end_date=)
+func (r *ResourceServiceRequest) ServiceGetReport(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	startDateStr := c.DefaultQuery("start_date", time.Now().AddDate(0, 0, -7).Format("2006-01-02"))
+	endDateStr := c.DefaultQuery("end_date", time.Now().Format("2006-01-02"))
+
+	startDate, err := time.ParseInLocation("2006-01-02", startDateStr, time.Local)
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid start_date format", err)
+		return
+	}
+	endDate, err := time.ParseInLocation("2006-01-02", endDateStr, time.Local)
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid end_date format", err)
+		return
+	}
+	if endDate.Before(startDate) {
+		utils.SendBadRequestError(c, "end_date must not be before start_date", nil)
+		return
+	}
+
+	// end_date inclusivo
+	report, err := r.handler.HandlerServiceRequest.GetReport(organizationId, projectId, startDate, endDate.AddDate(0, 0, 1))
+	if err != nil {
+		utils.SendInternalServerError(c, "Error generating service request report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// getOwnedRequest busca o chamado do parâmetro :id e verifica se pertence à organização/projeto
+func (r *ResourceServiceRequest) getOwnedRequest(c *gin.Context) (*models.ServiceRequest, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "service request")
+	if !ok {
+		return nil, false
+	}
+
+	request, err := r.handler.HandlerServiceRequest.GetRequest(id.String())
+	if err != nil || request == nil {
+		utils.SendNotFoundError(c, "Service request")
+		return nil, false
+	}
+
+	if request.OrganizationId.String() != c.GetString("organization_id") ||
+		request.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return request, true
+}
+
+// sendServiceRequestError converte erros dos chamados em respostas HTTP
+func sendServiceRequestError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "invalid_service_request"):
+		utils.SendBadRequestError(c, message, err)
+	case strings.Contains(err.Error(), "too_many_requests"):
+		utils.SendError(c, http.StatusTooManyRequests, message, err)
+	case strings.Contains(err.Error(), "not found"):
+		utils.SendNotFoundError(c, "Table")
+	default:
+		utils.SendInternalServerError(c, message, err)
+	}
+}
+
+func NewSourceServerServiceRequest(handler *handler.Handlers) IServerServiceRequest {
+	return &ResourceServiceRequest{handler: handler}
+}
@@ -46,6 +46,7 @@ func (s *SettingsServer) GetSettingsByProject(c *gin.Context) {
 		return
 	}
 
+	hideFiscalSecrets(settings)
 	c.JSON(http.StatusOK, settings)
 }
 
@@ -102,11 +103,26 @@ func (s *SettingsServer) UpdateSettings(c *gin.Context) {
 	updateData.ProjectId = existingSettings.ProjectId
 	updateData.CreatedAt = existingSettings.CreatedAt
 
+	// Segredos da NFC-e não voltam nas consultas: vazio mantém o valor gravado
+	if updateData.NfceCsc == "" {
+		updateData.NfceCsc = existingSettings.NfceCsc
+	}
+	if updateData.NfceCertificatePassword == "" {
+		updateData.NfceCertificatePassword = existingSettings.NfceCertificatePassword
+	}
+
 	err = s.handler.UpdateSettings(&updateData)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating settings"})
 		return
 	}
 
+	hideFiscalSecrets(&updateData)
 	c.JSON(http.StatusOK, updateData)
+}
+
+// hideFiscalSecrets remove o CSC e a senha do certificado da resposta
+func hideFiscalSecrets(settings *models.Settings) {
+	settings.NfceCsc = ""
+	settings.NfceCertificatePassword = ""
 }
\ No newline at end of file
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResourceSLA struct {
+	handler *handler.Handlers
+}
+
+type IServerSLA interface {
+	ServiceGetReport(c *gin.Context)
+	ServiceListBreaches(c *gin.Context)
+}
+
+func (r *ResourceSLA) ServiceGetReport(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	startDateStr := c.DefaultQuery("start_date", time.Now().AddDate(0, 0, -7).Format("2006-01-02"))
+	endDateStr := c.DefaultQuery("end_date", time.Now().Format("2006-01-02"))
+
+	startDate, err := time.ParseInLocation("2006-01-02", startDateStr, time.Local)
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid start_date format", err)
+		return
+	}
+	endDate, err := time.ParseInLocation("2006-01-02", endDateStr, time.Local)
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid end_date format", err)
+		return
+	}
+	if endDate.Before(startDate) {
+		utils.SendBadRequestError(c, "end_date must not be before start_date", nil)
+		return
+	}
+
+	// end_date inclusivo
+	report, err := r.handler.HandlerSLA.GetReport(organizationId, projectId, startDate, endDate.AddDate(0, 0, 1))
+	if err != nil {
+		utils.SendInternalServerError(c, "Error generating SLA report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (r *ResourceSLA) ServiceListBreaches(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	breachType := c.Query("type")
+	if breachType != "" && breachType != models.SLABreachTypeLate && breachType != models.SLABreachTypeStuck {
+		utils.SendBadRequestError(c, "Invalid type. Allowed: late, stuck", nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 500 {
+		utils.SendBadRequestError(c, "Invalid limit. Allowed: 1 to 500", err)
+		return
+	}
+
+	breaches, err := r.handler.HandlerSLA.ListBreaches(organizationId, projectId, breachType, c.Query("open") == "true", limit)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing SLA breaches", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, breaches)
+}
+
+func NewSourceServerSLA(handler *handler.Handlers) IServerSLA {
+	return &ResourceSLA{handler: handler}
+}
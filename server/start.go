@@ -27,8 +27,35 @@ func Start(db *gorm.DB) {
 		&models.Table{},
 		&models.Product{},
 		&models.Reservation{},
+		&models.TableCombination{},
+		&models.PacingRule{},
 		&models.Waitlist{},
 		&models.Order{},
+		&models.OrderStatusHistory{}, // Histórico de transições de status do pedido
+		&models.StockMovement{},      // Livro de movimentações de estoque
+		&models.Ingredient{},         // Insumos da cozinha
+		&models.RecipeItem{},         // Fichas técnicas (produto -> ingredientes)
+		&models.InventoryCount{},     // Contagens físicas de estoque
+		&models.InventoryCountItem{}, // Linhas da contagem (esperado x contado)
+		&models.Supplier{},           // Fornecedores
+		&models.PurchaseOrder{},      // Pedidos de compra
+		&models.PurchaseOrderItem{},  // Itens dos pedidos de compra
+		&models.KitchenStation{}, // Estações da cozinha (roteamento de itens)
+		&models.Tab{},            // Comandas (conta da mesa)
+		&models.TabPayment{},     // Pagamentos de comanda
+		&models.PixCharge{},      // Cobranças Pix
+		&models.Printer{},        // Impressoras ESC/POS
+		&models.DeliveryZone{},   // Zonas de entrega (raio/polígono, taxa e pedido mínimo)
+		&models.MarketplaceIntegration{}, // Lojas integradas a marketplaces de delivery
+		&models.MarketplaceEvent{},       // Webhooks recebidos e status enviados aos marketplaces
+		&models.PosSyncRun{},             // Importações de cardápio e exportações de vendas do PDV
+		&models.FiscalDocument{},         // NFC-e emitidas para pedidos entregues
+		&models.ServiceRequest{},         // Chamados da mesa (garçom, conta, água, talheres)
+		&models.PrintJob{},       // Fila de impressão
+		&models.PrintAgent{},     // Agentes de impressão na rede local
+		&models.PrepTimeStat{},   // Tempos de preparo aprendidos
+		&models.QueueTimeStat{},  // Espera na fila aprendida
+		&models.OrderSLABreach{}, // Violações de SLA da cozinha
 		&models.AuditLog{},
 		&models.AccessLog{}, // User access/login logs
 
@@ -101,4 +128,5 @@ func Start(db *gorm.DB) {
 	// Usar migrate customizado para lidar com alterações no Product
 	migrator := migrate.NewConnMigrate(db)
 	migrator.MigrateRun(modelsToMigrate...)
+	migrator.BackfillReservationStartsAt()
 }
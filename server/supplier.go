@@ -0,0 +1,325 @@
+package server
+
+import (
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ResourceSupplier struct {
+	handler *handler.Handlers
+}
+
+type IServerSupplier interface {
+	ServiceGetSupplier(c *gin.Context)
+	ServiceListSuppliers(c *gin.Context)
+	ServiceCreateSupplier(c *gin.Context)
+	ServiceUpdateSupplier(c *gin.Context)
+	ServiceDeleteSupplier(c *gin.Context)
+	ServiceGetPurchaseOrder(c *gin.Context)
+	ServiceListPurchaseOrders(c *gin.Context)
+	ServiceCreatePurchaseOrder(c *gin.Context)
+	ServiceUpdatePurchaseOrder(c *gin.Context)
+	ServiceSendPurchaseOrder(c *gin.Context)
+	ServiceReceivePurchaseOrder(c *gin.Context)
+	ServiceCancelPurchaseOrder(c *gin.Context)
+}
+
+func (r *ResourceSupplier) ServiceGetSupplier(c *gin.Context) {
+	supplier, ok := r.loadSupplier(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, supplier)
+}
+
+func (r *ResourceSupplier) ServiceListSuppliers(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	suppliers, err := r.handler.HandlerSupplier.ListSuppliers(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing suppliers", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, suppliers)
+}
+
+func (r *ResourceSupplier) ServiceCreateSupplier(c *gin.Context) {
+	var newSupplier models.Supplier
+	if err := c.BindJSON(&newSupplier); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	var err error
+	newSupplier.OrganizationId, err = uuid.Parse(c.GetString("organization_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid organization ID", err)
+		return
+	}
+	newSupplier.ProjectId, err = uuid.Parse(c.GetString("project_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid project ID", err)
+		return
+	}
+
+	if err := validation.CreateSupplierValidation(&newSupplier); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerSupplier.CreateSupplier(&newSupplier); err != nil {
+		utils.SendInternalServerError(c, "Error creating supplier", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Supplier created successfully", newSupplier)
+}
+
+func (r *ResourceSupplier) ServiceUpdateSupplier(c *gin.Context) {
+	existing, ok := r.loadSupplier(c)
+	if !ok {
+		return
+	}
+
+	var updatedSupplier models.Supplier
+	if err := c.BindJSON(&updatedSupplier); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Manter dados imutáveis
+	updatedSupplier.Id = existing.Id
+	updatedSupplier.OrganizationId = existing.OrganizationId
+	updatedSupplier.ProjectId = existing.ProjectId
+	updatedSupplier.CreatedAt = existing.CreatedAt
+	updatedSupplier.DeletedAt = nil
+
+	if err := validation.UpdateSupplierValidation(&updatedSupplier); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerSupplier.UpdateSupplier(&updatedSupplier); err != nil {
+		utils.SendInternalServerError(c, "Error updating supplier", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Supplier updated successfully", updatedSupplier)
+}
+
+func (r *ResourceSupplier) ServiceDeleteSupplier(c *gin.Context) {
+	existing, ok := r.loadSupplier(c)
+	if !ok {
+		return
+	}
+
+	if err := r.handler.HandlerSupplier.DeleteSupplier(existing.Id.String()); err != nil {
+		utils.SendInternalServerError(c, "Error deleting supplier", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Supplier deleted successfully", nil)
+}
+
+func (r *ResourceSupplier) ServiceGetPurchaseOrder(c *gin.Context) {
+	order, ok := r.loadPurchaseOrder(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (r *ResourceSupplier) ServiceListPurchaseOrders(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	status := c.Query("status")
+	switch status {
+	case "", models.PurchaseOrderStatusDraft, models.PurchaseOrderStatusSent,
+		models.PurchaseOrderStatusReceived, models.PurchaseOrderStatusCancelled:
+	default:
+		utils.SendBadRequestError(c, "Invalid status. Allowed: draft, sent, received, cancelled", nil)
+		return
+	}
+
+	orders, err := r.handler.HandlerSupplier.ListPurchaseOrders(organizationId, projectId, status)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing purchase orders", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+func (r *ResourceSupplier) ServiceCreatePurchaseOrder(c *gin.Context) {
+	var newOrder models.PurchaseOrder
+	if err := c.BindJSON(&newOrder); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	var err error
+	newOrder.OrganizationId, err = uuid.Parse(c.GetString("organization_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid organization ID", err)
+		return
+	}
+	newOrder.ProjectId, err = uuid.Parse(c.GetString("project_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid project ID", err)
+		return
+	}
+
+	if err := r.handler.HandlerSupplier.CreatePurchaseOrder(&newOrder, c.GetString("user_id")); err != nil {
+		sendPurchaseOrderError(c, "Error creating purchase order", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Purchase order created successfully", newOrder)
+}
+
+func (r *ResourceSupplier) ServiceUpdatePurchaseOrder(c *gin.Context) {
+	existing, ok := r.loadPurchaseOrder(c)
+	if !ok {
+		return
+	}
+
+	var update models.PurchaseOrder
+	if err := c.BindJSON(&update); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	if err := r.handler.HandlerSupplier.UpdatePurchaseOrder(existing, &update); err != nil {
+		sendPurchaseOrderError(c, "Error updating purchase order", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Purchase order updated successfully", existing)
+}
+
+func (r *ResourceSupplier) ServiceSendPurchaseOrder(c *gin.Context) {
+	order, ok := r.loadPurchaseOrder(c)
+	if !ok {
+		return
+	}
+
+	if err := r.handler.HandlerSupplier.SendPurchaseOrder(order); err != nil {
+		sendPurchaseOrderError(c, "Error sending purchase order", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Purchase order sent successfully", order)
+}
+
+func (r *ResourceSupplier) ServiceReceivePurchaseOrder(c *gin.Context) {
+	order, ok := r.loadPurchaseOrder(c)
+	if !ok {
+		return
+	}
+
+	// Corpo opcional: sem itens, recebe exatamente o que foi pedido
+	var request models.PurchaseOrderReceiveRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			utils.SendBadRequestError(c, "Invalid request body", err)
+			return
+		}
+	}
+
+	if err := r.handler.HandlerSupplier.ReceivePurchaseOrder(order, request); err != nil {
+		sendPurchaseOrderError(c, "Error receiving purchase order", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Purchase order received successfully", order)
+}
+
+func (r *ResourceSupplier) ServiceCancelPurchaseOrder(c *gin.Context) {
+	order, ok := r.loadPurchaseOrder(c)
+	if !ok {
+		return
+	}
+
+	if err := r.handler.HandlerSupplier.CancelPurchaseOrder(order); err != nil {
+		sendPurchaseOrderError(c, "Error cancelling purchase order", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Purchase order cancelled successfully", order)
+}
+
+// loadSupplier busca o fornecedor da rota e valida que pertence ao projeto
+func (r *ResourceSupplier) loadSupplier(c *gin.Context) (*models.Supplier, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "supplier")
+	if !ok {
+		return nil, false
+	}
+
+	supplier, err := r.handler.HandlerSupplier.GetSupplier(id.String())
+	if err != nil || supplier == nil {
+		utils.SendNotFoundError(c, "Supplier")
+		return nil, false
+	}
+
+	if supplier.OrganizationId.String() != c.GetString("organization_id") ||
+		supplier.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return supplier, true
+}
+
+// loadPurchaseOrder busca o pedido de compra da rota e valida que pertence ao projeto
+func (r *ResourceSupplier) loadPurchaseOrder(c *gin.Context) (*models.PurchaseOrder, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "purchase order")
+	if !ok {
+		return nil, false
+	}
+
+	order, err := r.handler.HandlerSupplier.GetPurchaseOrder(id.String())
+	if err != nil || order == nil {
+		utils.SendNotFoundError(c, "Purchase order")
+		return nil, false
+	}
+
+	if order.OrganizationId.String() != c.GetString("organization_id") ||
+		order.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return order, true
+}
+
+// sendPurchaseOrderError traduz erros de negócio dos pedidos de compra
+func sendPurchaseOrderError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "invalid_transition"):
+		utils.SendConflictError(c, message, err)
+	case strings.Contains(err.Error(), "invalid_purchase_order"):
+		utils.SendBadRequestError(c, message, err)
+	default:
+		utils.SendInternalServerError(c, message, err)
+	}
+}
+
+func NewSourceServerSupplier(handler *handler.Handlers) IServerSupplier {
+	return &ResourceSupplier{handler: handler}
+}
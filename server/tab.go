@@ -0,0 +1,264 @@
+package server
+
+import (
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ResourceTab struct {
+	handler *handler.Handlers
+}
+
+type IServerTab interface {
+	ServiceListTabs(c *gin.Context)
+	ServiceGetTab(c *gin.Context)
+	ServiceOpenTab(c *gin.Context)
+	ServiceAttachOrder(c *gin.Context)
+	ServiceDetachOrder(c *gin.Context)
+	ServiceSetServiceCharge(c *gin.Context)
+	ServiceSplitTab(c *gin.Context)
+	ServiceAddPayment(c *gin.Context)
+	ServiceCloseTab(c *gin.Context)
+}
+
+func (r *ResourceTab) ServiceListTabs(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	status := c.Query("status")
+	if status != "" && status != models.TabStatusOpen && status != models.TabStatusClosed {
+		utils.SendBadRequestError(c, "Invalid status. Allowed: open, closed", nil)
+		return
+	}
+
+	tabs, err := r.handler.HandlerTab.ListTabs(organizationId, projectId, status)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing tabs", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tabs)
+}
+
+func (r *ResourceTab) ServiceGetTab(c *gin.Context) {
+	tab, ok := r.getOwnedTab(c)
+	if !ok {
+		return
+	}
+
+	summary, err := r.handler.HandlerTab.GetTabSummary(tab.Id.String())
+	if err != nil {
+		utils.SendInternalServerError(c, "Error fetching tab", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+func (r *ResourceTab) ServiceOpenTab(c *gin.Context) {
+	var newTab models.Tab
+	if err := c.BindJSON(&newTab); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	var err error
+	newTab.OrganizationId, err = uuid.Parse(c.GetString("organization_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid organization ID", err)
+		return
+	}
+	newTab.ProjectId, err = uuid.Parse(c.GetString("project_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid project ID", err)
+		return
+	}
+
+	if err := validation.CreateTabValidation(&newTab); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerTab.OpenTab(&newTab); err != nil {
+		sendTabError(c, "Error opening tab", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Tab opened successfully", newTab)
+}
+
+func (r *ResourceTab) ServiceAttachOrder(c *gin.Context) {
+	tab, ok := r.getOwnedTab(c)
+	if !ok {
+		return
+	}
+
+	orderId, ok := validation.ParseAndValidateUUID(c, c.Param("orderId"), "order")
+	if !ok {
+		return
+	}
+
+	updated, err := r.handler.HandlerTab.AttachOrder(tab.Id.String(), orderId.String())
+	if err != nil {
+		sendTabError(c, "Error attaching order", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Order attached to tab", updated)
+}
+
+func (r *ResourceTab) ServiceDetachOrder(c *gin.Context) {
+	tab, ok := r.getOwnedTab(c)
+	if !ok {
+		return
+	}
+
+	orderId, ok := validation.ParseAndValidateUUID(c, c.Param("orderId"), "order")
+	if !ok {
+		return
+	}
+
+	updated, err := r.handler.HandlerTab.DetachOrder(tab.Id.String(), orderId.String())
+	if err != nil {
+		sendTabError(c, "Error detaching order", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Order detached from tab", updated)
+}
+
+func (r *ResourceTab) ServiceSetServiceCharge(c *gin.Context) {
+	tab, ok := r.getOwnedTab(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Percent *float64 `json:"percent"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil || request.Percent == nil {
+		utils.SendBadRequestError(c, "Invalid request body: percent is required", err)
+		return
+	}
+
+	updated, err := r.handler.HandlerTab.SetServiceCharge(tab.Id.String(), *request.Percent)
+	if err != nil {
+		sendTabError(c, "Error updating service charge", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Service charge updated", updated)
+}
+
+func (r *ResourceTab) ServiceSplitTab(c *gin.Context) {
+	tab, ok := r.getOwnedTab(c)
+	if !ok {
+		return
+	}
+
+	var request models.TabSplitRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	shares, err := r.handler.HandlerTab.SplitTab(tab.Id.String(), request)
+	if err != nil {
+		sendTabError(c, "Error splitting tab", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shares)
+}
+
+func (r *ResourceTab) ServiceAddPayment(c *gin.Context) {
+	tab, ok := r.getOwnedTab(c)
+	if !ok {
+		return
+	}
+
+	var payment models.TabPayment
+	if err := c.ShouldBindJSON(&payment); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	if err := validation.TabPaymentValidation(&payment); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	created, err := r.handler.HandlerTab.AddPayment(tab.Id.String(), &payment)
+	if err != nil {
+		sendTabError(c, "Error registering payment", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Payment registered successfully", created)
+}
+
+func (r *ResourceTab) ServiceCloseTab(c *gin.Context) {
+	tab, ok := r.getOwnedTab(c)
+	if !ok {
+		return
+	}
+
+	closed, err := r.handler.HandlerTab.CloseTab(tab.Id.String())
+	if err != nil {
+		sendTabError(c, "Error closing tab", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Tab closed successfully", closed)
+}
+
+// getOwnedTab busca a comanda do parâmetro :id e verifica se pertence à organização/projeto
+func (r *ResourceTab) getOwnedTab(c *gin.Context) (*models.Tab, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "tab")
+	if !ok {
+		return nil, false
+	}
+
+	tab, err := r.handler.HandlerTab.GetTab(id.String())
+	if err != nil || tab == nil {
+		utils.SendNotFoundError(c, "Tab")
+		return nil, false
+	}
+
+	if tab.OrganizationId.String() != c.GetString("organization_id") ||
+		tab.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return tab, true
+}
+
+// sendTabError converte erros do handler de comandas em respostas HTTP
+func sendTabError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "already_exists"),
+		strings.Contains(err.Error(), "tab_closed"),
+		strings.Contains(err.Error(), "tab_not_paid"):
+		utils.SendConflictError(c, message, err)
+	case strings.Contains(err.Error(), "invalid_tab"):
+		utils.SendBadRequestError(c, message, err)
+	case strings.Contains(err.Error(), "not found"):
+		utils.SendError(c, http.StatusNotFound, message, err)
+	default:
+		utils.SendInternalServerError(c, message, err)
+	}
+}
+
+func NewSourceServerTab(handler *handler.Handlers) IServerTab {
+	return &ResourceTab{handler: handler}
+}
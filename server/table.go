@@ -1,11 +1,13 @@
 package server
 
 import (
+	"fmt"
 	"lep/handler"
 	"lep/repositories/models"
 	"lep/resource/validation"
 	"lep/utils"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -21,6 +23,9 @@ type IServerTables interface {
 	ServiceUpdateTable(c *gin.Context)
 	ServiceDeleteTable(c *gin.Context)
 	ServiceListTables(c *gin.Context)
+	ServiceTransferTable(c *gin.Context)
+	ServiceMergeTables(c *gin.Context)
+	ServiceSplitTables(c *gin.Context)
 }
 
 func (r *ResourceTables) ServiceGetTable(c *gin.Context) {
@@ -163,6 +168,152 @@ func (r *ResourceTables) ServiceListTables(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// ServiceTransferTable move pedidos, itens ou todo o atendimento para outra mesa
+func (r *ResourceTables) ServiceTransferTable(c *gin.Context) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "table")
+	if !ok {
+		return
+	}
+
+	var request models.TableTransferRequest
+	if err := c.BindJSON(&request); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+	if request.ToTableId == uuid.Nil {
+		utils.SendBadRequestError(c, "to_table_id is required", nil)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	result, err := r.handler.HandlerTables.TransferTable(organizationId, projectId, id.String(), request, c.GetString("user_id"))
+	if err != nil {
+		sendTableServiceError(c, "Error transferring table", err)
+		return
+	}
+
+	for _, move := range result.Moves {
+		entityId := move.ToTableId
+		entityType := models.ClientAuditEntityTable
+		if move.OrderId != nil {
+			entityId = *move.OrderId
+			entityType = models.ClientAuditEntityOrder
+		}
+		description := fmt.Sprintf("Pedido transferido da mesa %d para a mesa %d", move.FromTableNumber, move.ToTableNumber)
+		if move.NewOrderId != nil {
+			description = fmt.Sprintf("%d item(ns) transferido(s) da mesa %d para a mesa %d", len(move.ItemIds), move.FromTableNumber, move.ToTableNumber)
+		}
+		r.logTableMove(c, models.ClientAuditActionTransfer, entityType, entityId,
+			gin.H{"table_id": move.FromTableId}, move, []string{"table_id", "tab_id"}, description)
+	}
+
+	utils.SendOKSuccess(c, "Table transferred successfully", result)
+}
+
+// ServiceMergeTables une mesas à mesa principal (uma conta só)
+func (r *ResourceTables) ServiceMergeTables(c *gin.Context) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "table")
+	if !ok {
+		return
+	}
+
+	var request models.TableMergeRequest
+	if err := c.BindJSON(&request); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	result, err := r.handler.HandlerTables.MergeTables(organizationId, projectId, id.String(), request, c.GetString("user_id"))
+	if err != nil {
+		sendTableServiceError(c, "Error merging tables", err)
+		return
+	}
+
+	for _, move := range result.Moves {
+		r.logTableMove(c, models.ClientAuditActionMerge, models.ClientAuditEntityTable, move.FromTableId,
+			gin.H{"merged_into_id": nil}, gin.H{"merged_into_id": move.ToTableId}, []string{"merged_into_id", "status"},
+			fmt.Sprintf("Mesa %d unida à mesa %d", move.FromTableNumber, move.ToTableNumber))
+	}
+
+	utils.SendOKSuccess(c, "Tables merged successfully", result)
+}
+
+// ServiceSplitTables separa as mesas unidas à mesa principal
+func (r *ResourceTables) ServiceSplitTables(c *gin.Context) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "table")
+	if !ok {
+		return
+	}
+
+	// Corpo opcional: sem mesas informadas separa todas
+	var request models.TableSplitRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.BindJSON(&request); err != nil {
+			utils.SendBadRequestError(c, "Invalid request body", err)
+			return
+		}
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	result, err := r.handler.HandlerTables.SplitTables(organizationId, projectId, id.String(), request)
+	if err != nil {
+		sendTableServiceError(c, "Error splitting tables", err)
+		return
+	}
+
+	for _, move := range result.Moves {
+		r.logTableMove(c, models.ClientAuditActionSplit, models.ClientAuditEntityTable, move.ToTableId,
+			gin.H{"merged_into_id": move.FromTableId}, gin.H{"merged_into_id": nil}, []string{"merged_into_id", "status"},
+			fmt.Sprintf("Mesa %d separada da mesa %d", move.ToTableNumber, move.FromTableNumber))
+	}
+
+	utils.SendOKSuccess(c, "Tables split successfully", result)
+}
+
+// logTableMove registra a movimentação no log de auditoria de cliente (módulo de mesas)
+func (r *ResourceTables) logTableMove(c *gin.Context, action, entityType string, entityId uuid.UUID, oldValues, newValues interface{}, changedFields []string, description string) {
+	orgId, err := uuid.Parse(c.GetString("organization_id"))
+	if err != nil {
+		return
+	}
+	projectId, err := uuid.Parse(c.GetString("project_id"))
+	if err != nil {
+		return
+	}
+	var userId *uuid.UUID
+	if parsed, err := uuid.Parse(c.GetString("user_id")); err == nil {
+		userId = &parsed
+	}
+
+	_ = r.handler.HandlerClientAuditLog.LogAction(orgId, projectId, userId, c.GetString("user_email"),
+		action, entityType, entityId, models.ClientAuditModuleTables, oldValues, newValues,
+		changedFields, description, c.ClientIP())
+}
+
+// sendTableServiceError converte erros de troca/união/separação de mesas em respostas HTTP
+func sendTableServiceError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "invalid_transfer"),
+		strings.Contains(err.Error(), "invalid_merge"),
+		strings.Contains(err.Error(), "invalid_split"):
+		utils.SendConflictError(c, message, err)
+	case strings.Contains(err.Error(), "not found"):
+		utils.SendError(c, http.StatusNotFound, message, err)
+	default:
+		utils.SendInternalServerError(c, message, err)
+	}
+}
+
 func NewSourceServerTables(handler *handler.Handlers) IServerTables {
 	return &ResourceTables{handler: handler}
 }
\ No newline at end of file
@@ -0,0 +1,209 @@
+package server
+
+import (
+	"lep/handler"
+	"lep/repositories/models"
+	"lep/resource/validation"
+	"lep/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ResourceTableAllocation struct {
+	handler *handler.Handlers
+}
+
+type IServerTableAllocation interface {
+	ServiceGetCombination(c *gin.Context)
+	ServiceListCombinations(c *gin.Context)
+	ServiceCreateCombination(c *gin.Context)
+	ServiceUpdateCombination(c *gin.Context)
+	ServiceDeleteCombination(c *gin.Context)
+	ServiceReoptimize(c *gin.Context)
+}
+
+func (r *ResourceTableAllocation) ServiceGetCombination(c *gin.Context) {
+	combination, ok := r.loadCombination(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, combination)
+}
+
+func (r *ResourceTableAllocation) ServiceListCombinations(c *gin.Context) {
+	// Headers validados pelo middleware - acessar via context
+	organizationId := c.GetString("organization_id")
+	projectId := c.GetString("project_id")
+
+	combinations, err := r.handler.HandlerTableAllocation.ListCombinations(organizationId, projectId)
+	if err != nil {
+		utils.SendInternalServerError(c, "Error listing table combinations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, combinations)
+}
+
+func (r *ResourceTableAllocation) ServiceCreateCombination(c *gin.Context) {
+	var newCombination models.TableCombination
+	if err := c.BindJSON(&newCombination); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	// Headers validados pelo middleware - acessar via context
+	var err error
+	newCombination.OrganizationId, err = uuid.Parse(c.GetString("organization_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid organization ID", err)
+		return
+	}
+	newCombination.ProjectId, err = uuid.Parse(c.GetString("project_id"))
+	if err != nil {
+		utils.SendBadRequestError(c, "Invalid project ID", err)
+		return
+	}
+	// Nova montagem nasce ativa
+	newCombination.Active = true
+
+	if err := validation.TableCombinationValidation(&newCombination); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerTableAllocation.CreateCombination(&newCombination); err != nil {
+		sendTableAllocationError(c, "Error creating table combination", err)
+		return
+	}
+
+	utils.SendCreatedSuccess(c, "Table combination created successfully", newCombination)
+}
+
+func (r *ResourceTableAllocation) ServiceUpdateCombination(c *gin.Context) {
+	existing, ok := r.loadCombination(c)
+	if !ok {
+		return
+	}
+
+	var updatedCombination models.TableCombination
+	if err := c.BindJSON(&updatedCombination); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	updatedCombination.Id = existing.Id
+	updatedCombination.OrganizationId = existing.OrganizationId
+	updatedCombination.ProjectId = existing.ProjectId
+	updatedCombination.CreatedAt = existing.CreatedAt
+	updatedCombination.DeletedAt = nil
+
+	if err := validation.TableCombinationValidation(&updatedCombination); err != nil {
+		utils.SendValidationError(c, "Validation failed", err)
+		return
+	}
+
+	if err := r.handler.HandlerTableAllocation.UpdateCombination(&updatedCombination); err != nil {
+		sendTableAllocationError(c, "Error updating table combination", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Table combination updated successfully", updatedCombination)
+}
+
+func (r *ResourceTableAllocation) ServiceDeleteCombination(c *gin.Context) {
+	existing, ok := r.loadCombination(c)
+	if !ok {
+		return
+	}
+
+	if err := r.handler.HandlerTableAllocation.DeleteCombination(existing.Id.String()); err != nil {
+		utils.SendInternalServerError(c, "Error deleting table combination", err)
+		return
+	}
+
+	utils.SendOKSuccess(c, "Table combination deleted successfully", nil)
+}
+
+// ServiceReoptimize redistribui as mesas das reservas de um serviço ({"date", "service"?, "dry_run"?})
+func (r *ResourceTableAllocation) ServiceReoptimize(c *gin.Context) {
+	var request models.TableReoptimizeRequest
+	if err := c.BindJSON(&request); err != nil {
+		utils.SendBadRequestError(c, "Invalid request body", err)
+		return
+	}
+
+	orgId, projectId, ok := projectFromContext(c)
+	if !ok {
+		return
+	}
+
+	result, err := r.handler.HandlerTableAllocation.Reoptimize(orgId, projectId, request)
+	if err != nil {
+		if result != nil && strings.Contains(err.Error(), "reoptimize_conflict") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "result": result})
+			return
+		}
+		sendTableAllocationError(c, "Error reoptimizing tables", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// loadCombination busca a montagem da rota e valida que pertence ao projeto
+func (r *ResourceTableAllocation) loadCombination(c *gin.Context) (*models.TableCombination, bool) {
+	id, ok := validation.ParseAndValidateUUID(c, c.Param("id"), "table combination")
+	if !ok {
+		return nil, false
+	}
+
+	combination, err := r.handler.HandlerTableAllocation.GetCombination(id.String())
+	if err != nil || combination == nil {
+		utils.SendNotFoundError(c, "Table combination")
+		return nil, false
+	}
+
+	if combination.OrganizationId.String() != c.GetString("organization_id") ||
+		combination.ProjectId.String() != c.GetString("project_id") {
+		utils.SendForbiddenError(c, "Access denied")
+		return nil, false
+	}
+
+	return combination, true
+}
+
+// sendTableAllocationError traduz erros das montagens e da redistribuição de mesas
+func sendTableAllocationError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "invalid_table_combination"), strings.Contains(err.Error(), "invalid_reoptimize"):
+		utils.SendBadRequestError(c, message, err)
+	case strings.Contains(err.Error(), "reservation_conflict"):
+		utils.SendConflictError(c, message, err)
+	default:
+		utils.SendInternalServerError(c, message, err)
+	}
+}
+
+// sendReservationConflict responde 409 quando outra reserva ocupou a mesa no horário, sugerindo horários próximos livres
+func sendReservationConflict(c *gin.Context, h *handler.Handlers, reservation *models.Reservation, environmentId *uuid.UUID) {
+	suggestions := []models.ReservationTimeSuggestion{}
+	if reservation.StartsAt != nil {
+		found, err := h.HandlerTableAllocation.SuggestTimes(reservation.OrganizationId, reservation.ProjectId, *reservation.StartsAt, reservation.PartySize, environmentId)
+		if err == nil {
+			suggestions = found
+		}
+	}
+	c.JSON(http.StatusConflict, gin.H{
+		"error":       "reservation_conflict",
+		"message":     "A mesa acabou de ser reservada para este horário. Por favor, escolha um dos horários sugeridos.",
+		"suggestions": suggestions,
+	})
+}
+
+func NewSourceServerTableAllocation(handler *handler.Handlers) IServerTableAllocation {
+	return &ResourceTableAllocation{handler: handler}
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"fmt"
+	"lep/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Clock relógio no fuso horário do projeto.
+// Horários de funcionamento, slots, cardápios por horário e o "hoje" das regras são sempre
+// calculados no relógio local do projeto, independente do fuso do servidor (Cloud Run roda em UTC).
+type Clock struct {
+	loc *time.Location
+}
+
+// NewClock relógio no fuso informado (nil usa o fuso padrão do projeto)
+func NewClock(loc *time.Location) *Clock {
+	if loc == nil {
+		loc = ProjectLocation("")
+	}
+	return &Clock{loc: loc}
+}
+
+// ProjectClock relógio do projeto (projeto não encontrado usa o fuso padrão)
+func ProjectClock(projects repositories.IProjectRepository, projectId uuid.UUID) *Clock {
+	project, err := projects.GetProjectById(projectId)
+	if err != nil || project == nil {
+		return NewClock(nil)
+	}
+	return NewClock(ProjectLocation(project.TimeZone))
+}
+
+// Location fuso horário do relógio
+func (c *Clock) Location() *time.Location {
+	return c.loc
+}
+
+// Now horário atual no fuso do projeto
+func (c *Clock) Now() time.Time {
+	return time.Now().In(c.loc)
+}
+
+// Today meia-noite local do dia atual
+func (c *Clock) Today() time.Time {
+	return c.StartOfDay(c.Now())
+}
+
+// StartOfDay meia-noite local do dia (no fuso do projeto) em que t cai
+func (c *Clock) StartOfDay(t time.Time) time.Time {
+	t = t.In(c.loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.loc)
+}
+
+// Date meia-noite local da data civil de date (ano/mês/dia lidos como estão, sem conversão de fuso)
+func (c *Clock) Date(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, c.loc)
+}
+
+// ParseDate interpreta YYYY-MM-DD como meia-noite local
+func (c *Clock) ParseDate(value string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", value, c.loc)
+}
+
+// At horário HH:MM na data civil de date, no fuso do projeto
+func (c *Clock) At(date time.Time, hhmm string) (time.Time, error) {
+	clockTime, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), clockTime.Hour(), clockTime.Minute(), 0, 0, c.loc), nil
+}
+
+// ParseDatetime interpreta data/hora enviada pelo cliente.
+// Com offset (RFC3339) vale o instante informado; sem offset ("2006-01-02T15:04[:05]") é horário local do projeto.
+func (c *Clock) ParseDatetime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(c.loc), nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, value, c.loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid datetime %q (use RFC3339 or YYYY-MM-DDTHH:MM)", value)
+}
+
+// Format horário local com offset (RFC3339), ex: 2026-03-10T19:30:00-04:00
+func (c *Clock) Format(t time.Time) string {
+	return t.In(c.loc).Format(time.RFC3339)
+}
+
+// ClockTime horário local HH:MM
+func (c *Clock) ClockTime(t time.Time) string {
+	return t.In(c.loc).Format("15:04")
+}
@@ -14,6 +14,10 @@ type CronService struct {
 	eventService     *EventService
 	scheduleService  *NotificationScheduleService
 	inboundProcessor *InboundProcessorService
+	printService     *PrintService
+	prepTimeService  *PrepTimeService
+	slaMonitor       *SLAMonitorService
+	marketplaceSync  *MarketplaceSyncService
 }
 
 func NewCronService(repo *repositories.DBconn) *CronService {
@@ -39,6 +43,10 @@ func NewCronService(repo *repositories.DBconn) *CronService {
 		eventService:     eventService,
 		scheduleService:  scheduleService,
 		inboundProcessor: inboundProcessor,
+		printService:     NewPrintService(repo.Printers),
+		prepTimeService:  NewPrepTimeService(repo.PrepTimes, repo.Projects),
+		slaMonitor:       NewSLAMonitorService(repo, eventService),
+		marketplaceSync:  NewMarketplaceSyncService(repo.Marketplace),
 	}
 }
 
@@ -71,27 +79,8 @@ func (c *CronService) processProjectConfirmations(orgId, projectId uuid.UUID) er
 		return nil
 	}
 
-	// Buscar projeto para pegar timezone
-	project, err := c.repo.Projects.GetProjectById(projectId)
-	if err != nil {
-		log.Printf("Project not found %s: %v", projectId, err)
-		return err
-	}
-
-	// Carregar timezone do projeto (padrão: America/Sao_Paulo)
-	timezone := project.TimeZone
-	if timezone == "" {
-		timezone = "America/Sao_Paulo"
-	}
-
-	loc, err := time.LoadLocation(timezone)
-	if err != nil {
-		log.Printf("Invalid timezone %s, using UTC: %v", timezone, err)
-		loc = time.UTC
-	}
-
-	// Calcular janela de tempo no timezone do projeto: reservas que acontecem entre 23h e 25h a partir de agora
-	now := time.Now().In(loc)
+	// Janela no relógio do projeto: reservas que acontecem entre 23h e 25h a partir de agora
+	now := ProjectClock(c.repo.Projects, projectId).Now()
 	start24h := now.Add(23 * time.Hour)
 	end24h := now.Add(25 * time.Hour)
 
@@ -198,8 +187,7 @@ func (c *CronService) getAllActiveProjects() ([]models.Project, error) {
 
 // getReservationsInTimeRange - Busca reservas em um período específico
 func (c *CronService) getReservationsInTimeRange(orgId, projectId uuid.UUID, start, end time.Time) ([]models.Reservation, error) {
-	// Usar o método existente no repository
-	return c.repo.Reservations.GetReservationsByTableAndDateRange(uuid.Nil, start, end)
+	return c.repo.Reservations.GetReservationsInRange(orgId, projectId, start, end)
 }
 
 // hasRecentConfirmationLog - Verifica se já foi enviada confirmação recentemente
@@ -270,6 +258,33 @@ func (c *CronService) ProcessUnprocessedInbound() error {
 	return nil
 }
 
+// ProcessPrintQueue - Reenvia trabalhos de impressão pendentes (impressora estava fora do ar)
+func (c *CronService) ProcessPrintQueue() error {
+	return c.printService.ProcessDueJobs()
+}
+
+// ProcessMarketplaceSync - Reenvia status de pedidos que o marketplace não recebeu
+func (c *CronService) ProcessMarketplaceSync() error {
+	return c.marketplaceSync.ProcessDueEvents()
+}
+
+// ProcessPrepTimeStats - Recalcula os tempos de preparo aprendidos a partir do histórico de pedidos
+func (c *CronService) ProcessPrepTimeStats() error {
+	log.Println("Starting prep time stats job...")
+
+	if err := c.prepTimeService.RecomputeAll(); err != nil {
+		return err
+	}
+
+	log.Println("Prep time stats job completed")
+	return nil
+}
+
+// ProcessKitchenSLA - Verifica pedidos atrasados ou parados e alerta a equipe
+func (c *CronService) ProcessKitchenSLA() error {
+	return c.slaMonitor.CheckOrders(time.Now())
+}
+
 // StartCronJobs - Inicia jobs automáticos (seria chamado no main)
 func (c *CronService) StartCronJobs() {
 	log.Println("Starting cron jobs...")
@@ -304,6 +319,66 @@ func (c *CronService) StartCronJobs() {
 		}
 	}()
 
+	// Job de SLA da cozinha - executa a cada minuto
+	go func() {
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.ProcessKitchenSLA(); err != nil {
+					log.Printf("Error in kitchen SLA job: %v", err)
+				}
+			}
+		}
+	}()
+
+	// Job da fila de impressão - executa a cada 30 segundos
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.ProcessPrintQueue(); err != nil {
+					log.Printf("Error in print queue job: %v", err)
+				}
+			}
+		}
+	}()
+
+	// Job de sincronização com marketplaces - executa a cada 30 segundos
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.ProcessMarketplaceSync(); err != nil {
+					log.Printf("Error in marketplace sync job: %v", err)
+				}
+			}
+		}
+	}()
+
+	// Job de tempos de preparo aprendidos - executa a cada hora
+	go func() {
+		ticker := time.NewTicker(1 * time.Hour)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.ProcessPrepTimeStats(); err != nil {
+					log.Printf("Error in prep time stats job: %v", err)
+				}
+			}
+		}
+	}()
+
 	// Job de eventos pendentes - executa a cada 5 minutos
 	go func() {
 		ticker := time.NewTicker(5 * time.Minute)
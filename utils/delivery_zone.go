@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"lep/repositories/models"
+	"math"
+	"sort"
+	"time"
+)
+
+const earthRadiusKm = 6371.0
+
+// HaversineKm distância em linha reta (km) entre dois pontos
+func HaversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// PointInPolygon verifica se o ponto está dentro do polígono (ray casting; vértices [lat, lng])
+func PointInPolygon(lat, lng float64, polygon models.GeoPolygon) bool {
+	if len(polygon) < 3 {
+		return false
+	}
+	inside := false
+	j := len(polygon) - 1
+	for i := range polygon {
+		latI, lngI := polygon[i][0], polygon[i][1]
+		latJ, lngJ := polygon[j][0], polygon[j][1]
+		if (latI > lat) != (latJ > lat) &&
+			lng < (lngJ-lngI)*(lat-latI)/(latJ-latI)+lngI {
+			inside = !inside
+		}
+		j = i
+	}
+	return inside
+}
+
+// DeliveryZoneContains verifica se o endereço cai na zona.
+// Zonas por raio precisam da localização do projeto (origin); sem ela nunca atendem.
+func DeliveryZoneContains(zone models.DeliveryZone, lat, lng float64, originLat, originLng *float64) bool {
+	switch zone.Type {
+	case models.DeliveryZoneTypeRadius:
+		if originLat == nil || originLng == nil || zone.RadiusKm <= 0 {
+			return false
+		}
+		return HaversineKm(*originLat, *originLng, lat, lng) <= zone.RadiusKm
+	case models.DeliveryZoneTypePolygon:
+		return PointInPolygon(lat, lng, zone.Polygon)
+	}
+	return false
+}
+
+// FindDeliveryZone zona ativa que atende o endereço; com zonas sobrepostas vale a de menor taxa
+func FindDeliveryZone(zones []models.DeliveryZone, lat, lng float64, originLat, originLng *float64) *models.DeliveryZone {
+	var match *models.DeliveryZone
+	for i := range zones {
+		zone := &zones[i]
+		if !zone.Active || !DeliveryZoneContains(*zone, lat, lng, originLat, originLng) {
+			continue
+		}
+		if match == nil || zone.Fee < match.Fee {
+			match = zone
+		}
+	}
+	return match
+}
+
+// BuildOrderSlots monta os horários de retirada/entrega do dia com a ocupação da cozinha.
+// scheduled são os horários dos pedidos já agendados; cada pedido ocupa o slot em que cai.
+// Horários antes de earliest (agora + antecedência) ou lotados ficam indisponíveis.
+// date é a meia-noite local do projeto; os horários são montados no fuso de date.
+func BuildOrderSlots(date time.Time, times []string, intervalMinutes int, scheduled []time.Time, capacity int, earliest time.Time) []models.OrderSlot {
+	slots := make([]models.OrderSlot, 0, len(times))
+	for _, slot := range times {
+		startsAt, err := SlotDateTime(date, slot, date.Location())
+		if err != nil {
+			continue
+		}
+		endsAt := startsAt.Add(time.Duration(intervalMinutes) * time.Minute)
+
+		orders := 0
+		for _, at := range scheduled {
+			if !at.Before(startsAt) && at.Before(endsAt) {
+				orders++
+			}
+		}
+
+		slots = append(slots, models.OrderSlot{
+			Time:      slot,
+			StartsAt:  startsAt,
+			Orders:    orders,
+			Capacity:  capacity,
+			Available: !startsAt.Before(earliest) && (capacity <= 0 || orders < capacity),
+		})
+	}
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].StartsAt.Before(slots[j].StartsAt) })
+	return slots
+}
+
+// FindOrderSlot slot que contém o horário pedido (nil se fora do funcionamento)
+func FindOrderSlot(slots []models.OrderSlot, at time.Time, intervalMinutes int) *models.OrderSlot {
+	for i := range slots {
+		endsAt := slots[i].StartsAt.Add(time.Duration(intervalMinutes) * time.Minute)
+		if !at.Before(slots[i].StartsAt) && at.Before(endsAt) {
+			return &slots[i]
+		}
+	}
+	return nil
+}
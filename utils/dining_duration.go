@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"lep/repositories/models"
+	"time"
+)
+
+// DefaultDiningDurationMinutes permanência na mesa quando o projeto não configura
+const DefaultDiningDurationMinutes = 120
+
+// DiningDurationMinutes permanência prevista de um grupo: a de grupos grandes a partir de
+// LargePartySize pessoas, senão DiningDurationMinutes
+func DiningDurationMinutes(settings *models.Settings, partySize int) int {
+	if settings == nil {
+		return DefaultDiningDurationMinutes
+	}
+	if settings.LargePartySize > 0 && settings.LargePartyDiningDurationMinutes > 0 && partySize >= settings.LargePartySize {
+		return settings.LargePartyDiningDurationMinutes
+	}
+	if settings.DiningDurationMinutes > 0 {
+		return settings.DiningDurationMinutes
+	}
+	return DefaultDiningDurationMinutes
+}
+
+// DiningDuration mesma permanência como duração
+func DiningDuration(settings *models.Settings, partySize int) time.Duration {
+	return time.Duration(DiningDurationMinutes(settings, partySize)) * time.Minute
+}
+
+// MaxDiningDuration maior permanência configurada (janela das reservas que ainda podem ocupar mesas)
+func MaxDiningDuration(settings *models.Settings) time.Duration {
+	duration := DiningDuration(settings, 0)
+	if settings != nil {
+		if large := DiningDuration(settings, settings.LargePartySize); large > duration {
+			duration = large
+		}
+	}
+	return duration
+}
+
+// ReservationDuration permanência de uma reserva: a gravada nela ou a das configurações para o grupo
+func ReservationDuration(settings *models.Settings, reservation models.Reservation) time.Duration {
+	if reservation.DurationMinutes > 0 {
+		return time.Duration(reservation.DurationMinutes) * time.Minute
+	}
+	return DiningDuration(settings, reservation.PartySize)
+}
@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Larguras de papel suportadas (mm)
+const (
+	PaperWidth58 = 58
+	PaperWidth80 = 80
+)
+
+// Alinhamentos ESC a
+const (
+	EscPosAlignLeft   byte = 0
+	EscPosAlignCenter byte = 1
+	EscPosAlignRight  byte = 2
+)
+
+// EscPosColumns colunas de texto na fonte padrão para a largura do papel
+func EscPosColumns(paperWidth int) int {
+	if paperWidth == PaperWidth58 {
+		return 32
+	}
+	return 48
+}
+
+// EscPosBuilder monta o fluxo de bytes ESC/POS de um cupom.
+// Texto é convertido para ASCII (sem acentos) para não depender da página de código da impressora.
+type EscPosBuilder struct {
+	buf     bytes.Buffer
+	columns int
+	double  bool
+}
+
+// NewEscPosBuilder inicia o cupom (ESC @) para a largura de papel informada
+func NewEscPosBuilder(paperWidth int) *EscPosBuilder {
+	b := &EscPosBuilder{columns: EscPosColumns(paperWidth)}
+	b.buf.Write([]byte{0x1b, '@'})
+	return b
+}
+
+// Align define o alinhamento das próximas linhas
+func (b *EscPosBuilder) Align(align byte) *EscPosBuilder {
+	b.buf.Write([]byte{0x1b, 'a', align})
+	return b
+}
+
+// Bold liga/desliga negrito
+func (b *EscPosBuilder) Bold(on bool) *EscPosBuilder {
+	b.buf.Write([]byte{0x1b, 'E', boolByte(on)})
+	return b
+}
+
+// DoubleSize liga/desliga altura e largura dupla (metade das colunas)
+func (b *EscPosBuilder) DoubleSize(on bool) *EscPosBuilder {
+	size := byte(0x00)
+	if on {
+		size = 0x11
+	}
+	b.buf.Write([]byte{0x1d, '!', size})
+	b.double = on
+	return b
+}
+
+// Line escreve o texto quebrando em palavras pela largura disponível
+func (b *EscPosBuilder) Line(text string) *EscPosBuilder {
+	return b.Indented(text, "")
+}
+
+// Indented escreve o texto quebrado em linhas, todas com o prefixo informado
+func (b *EscPosBuilder) Indented(text, indent string) *EscPosBuilder {
+	width := b.columns
+	if b.double {
+		width /= 2
+	}
+	indent = EscPosText(indent)
+	for _, line := range wrapWords(EscPosText(text), width-len(indent)) {
+		b.buf.WriteString(indent + line + "\n")
+	}
+	return b
+}
+
+// Columns escreve texto à esquerda e valor alinhado à direita na mesma linha
+func (b *EscPosBuilder) Columns(left, right string) *EscPosBuilder {
+	left, right = EscPosText(left), EscPosText(right)
+	width := b.columns
+	if b.double {
+		width /= 2
+	}
+
+	lines := wrapWords(left, width-len(right)-1)
+	for i, line := range lines {
+		if i < len(lines)-1 {
+			b.buf.WriteString(line + "\n")
+			continue
+		}
+		padding := width - len(line) - len(right)
+		if padding < 1 {
+			padding = 1
+		}
+		b.buf.WriteString(line + strings.Repeat(" ", padding) + right + "\n")
+	}
+	return b
+}
+
+// Separator linha tracejada na largura do papel
+func (b *EscPosBuilder) Separator() *EscPosBuilder {
+	b.buf.WriteString(strings.Repeat("-", b.columns) + "\n")
+	return b
+}
+
+// Feed avança n linhas (ESC d)
+func (b *EscPosBuilder) Feed(lines byte) *EscPosBuilder {
+	b.buf.Write([]byte{0x1b, 'd', lines})
+	return b
+}
+
+// QRCode imprime QR Code nativo (GS ( k): modelo 2, correção M, módulo de tamanho size (1-16)
+func (b *EscPosBuilder) QRCode(data string, size byte) *EscPosBuilder {
+	length := len(data) + 3
+	b.buf.Write([]byte{0x1d, '(', 'k', 4, 0, 49, 65, 50, 0})
+	b.buf.Write([]byte{0x1d, '(', 'k', 3, 0, 49, 67, size})
+	b.buf.Write([]byte{0x1d, '(', 'k', 3, 0, 49, 69, 49})
+	b.buf.Write([]byte{0x1d, '(', 'k', byte(length % 256), byte(length / 256), 49, 80, 48})
+	b.buf.WriteString(data)
+	b.buf.Write([]byte{0x1d, '(', 'k', 3, 0, 49, 81, 48})
+	b.buf.WriteString("\n")
+	return b
+}
+
+// Cut avança o papel e faz corte parcial (GS V B)
+func (b *EscPosBuilder) Cut() *EscPosBuilder {
+	b.buf.Write([]byte{0x1d, 'V', 66, 3})
+	return b
+}
+
+// Bytes retorna o fluxo ESC/POS montado
+func (b *EscPosBuilder) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+// EscPosText remove acentos e troca caracteres de controle/não ASCII, evitando que
+// textos digitados pelo cliente (observações) injetem comandos na impressora
+func EscPosText(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, _ := transform.String(t, text)
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			return ' '
+		case r < 0x20 || r == 0x7f:
+			return -1
+		case r > 0x7e:
+			return '?'
+		}
+		return r
+	}, result)
+}
+
+// wrapWords quebra o texto em linhas de até width colunas
+func wrapWords(text string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		for len(word) > width {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			lines = append(lines, word[:width])
+			word = word[width:]
+		}
+		switch {
+		case current == "":
+			current = word
+		case len(current)+1+len(word) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" || len(lines) == 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+func boolByte(on bool) byte {
+	if on {
+		return 1
+	}
+	return 0
+}
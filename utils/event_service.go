@@ -30,6 +30,18 @@ type EventData struct {
 	Status        string     `json:"status,omitempty"`
 	EstimatedWait int        `json:"estimated_wait,omitempty"` // em minutos
 	Environment   string     `json:"environment,omitempty"`
+	// Alertas para a equipe (enviados ao telefone responsável do projeto)
+	ProductName   string `json:"product_name,omitempty"`
+	StockQuantity *int   `json:"stock_quantity,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
+// staffEventTypes eventos destinados à equipe e não ao cliente
+var staffEventTypes = map[string]bool{
+	"stock_low":   true,
+	"stock_out":   true,
+	"order_late":  true,
+	"order_stuck": true,
 }
 
 func NewEventService(notificationRepo repositories.INotificationRepository, projectRepo repositories.IProjectRepository, settingsRepo repositories.ISettingsRepository) *EventService {
@@ -195,6 +207,54 @@ func (e *EventService) TriggerConfirmation24h(orgId, projectId uuid.UUID, reserv
 	return e.createAndProcessEvent(orgId, projectId, "confirmation_24h", "reservation", reservation.Id, eventData)
 }
 
+// TriggerLowStock - Alerta a equipe quando o produto atinge o limite de estoque (ou zera)
+func (e *EventService) TriggerLowStock(orgId, projectId uuid.UUID, product *models.Product, threshold int) error {
+	if product.Stock == nil {
+		return nil
+	}
+
+	eventType := "stock_low"
+	message := fmt.Sprintf("Estoque baixo: %s com %d unidade(s) (limite %d)", product.Name, *product.Stock, threshold)
+	if *product.Stock == 0 {
+		eventType = "stock_out"
+		message = fmt.Sprintf("Estoque esgotado: %s foi desativado do cardápio", product.Name)
+	}
+
+	eventData := EventData{
+		ProductName:   product.Name,
+		StockQuantity: product.Stock,
+		Message:       message,
+	}
+
+	return e.createAndProcessEvent(orgId, projectId, eventType, "product", product.Id, eventData)
+}
+
+// TriggerOrderSLABreach - Alerta a equipe quando o pedido passa do horário estimado ou fica parado em um status
+func (e *EventService) TriggerOrderSLABreach(order *models.Order, breach *models.OrderSLABreach, tableNumber *int) error {
+	label := "balcão"
+	if tableNumber != nil {
+		label = fmt.Sprintf("mesa %d", *tableNumber)
+	}
+
+	eventType := "order_stuck"
+	message := fmt.Sprintf("Pedido %s (%s) parado em %s, %d min além do limite", order.Id.String()[:8], label, breach.OrderStatus, breach.DelayMinutes)
+	if breach.Type == models.SLABreachTypeLate {
+		eventType = "order_late"
+		message = fmt.Sprintf("Pedido %s (%s) atrasado %d min além do previsto", order.Id.String()[:8], label, breach.DelayMinutes)
+	}
+
+	eventData := EventData{
+		TableId: order.TableId,
+		Status:  order.Status,
+		Message: message,
+	}
+	if tableNumber != nil {
+		eventData.TableNumber = *tableNumber
+	}
+
+	return e.createAndProcessEvent(order.OrganizationId, order.ProjectId, eventType, "order", order.Id, eventData)
+}
+
 func parseTime(datetimeStr string) *time.Time {
 	if datetimeStr == "" {
 		return nil
@@ -263,6 +323,8 @@ func (e *EventService) ProcessEvent(event *models.NotificationEvent) error {
 	// Criar service de notificação
 	notificationService := NewNotificationService()
 
+	staffEvent := staffEventTypes[event.EventType]
+
 	// Enviar notificação para cada canal habilitado
 	for _, channel := range config.Channels {
 		// Determinar destinatário baseado no canal
@@ -270,8 +332,13 @@ func (e *EventService) ProcessEvent(event *models.NotificationEvent) error {
 		switch channel {
 		case "sms", "whatsapp":
 			recipient = eventData.CustomerPhone
+			if staffEvent && project.NotificationResponsiblePhone != nil {
+				recipient = *project.NotificationResponsiblePhone
+			}
 		case "email":
-			recipient = eventData.CustomerEmail
+			if !staffEvent {
+				recipient = eventData.CustomerEmail
+			}
 		}
 
 		if recipient == "" {
@@ -279,11 +346,14 @@ func (e *EventService) ProcessEvent(event *models.NotificationEvent) error {
 			continue
 		}
 
-		// Buscar template para o canal
-		template, err := e.notificationRepo.GetNotificationTemplateByChannel(event.OrganizationId, event.ProjectId, channel)
-		if err != nil {
-			log.Printf("No template found for channel %s: %v", channel, err)
-			continue
+		// Buscar template para o canal (alertas da equipe usam a mensagem do evento)
+		template := &models.NotificationTemplate{Body: eventData.Message}
+		if !staffEvent {
+			template, err = e.notificationRepo.GetNotificationTemplateByChannel(event.OrganizationId, event.ProjectId, channel)
+			if err != nil {
+				log.Printf("No template found for channel %s: %v", channel, err)
+				continue
+			}
 		}
 
 		// Preparar variáveis do template
@@ -404,6 +474,14 @@ func (e *EventService) buildTemplateVariables(eventData EventData) map[string]st
 		variables["status"] = eventData.Status
 	}
 
+	if eventData.ProductName != "" {
+		variables["produto"] = eventData.ProductName
+	}
+
+	if eventData.StockQuantity != nil {
+		variables["estoque"] = fmt.Sprintf("%d", *eventData.StockQuantity)
+	}
+
 	return variables
 }
 
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GroupRecipes agrupa as linhas de ficha técnica por produto
+func GroupRecipes(items []models.RecipeItem) map[uuid.UUID][]models.RecipeItem {
+	recipes := make(map[uuid.UUID][]models.RecipeItem)
+	for _, item := range items {
+		recipes[item.ProductId] = append(recipes[item.ProductId], item)
+	}
+	return recipes
+}
+
+// TheoreticalConsumption soma o consumo de cada ingrediente pelos itens dos pedidos e suas fichas técnicas.
+// Se since tiver data para o ingrediente, pedidos iniciados antes dela são ignorados (já refletidos na última contagem).
+func TheoreticalConsumption(orders []models.Order, recipes map[uuid.UUID][]models.RecipeItem, since map[uuid.UUID]time.Time) map[uuid.UUID]float64 {
+	consumption := make(map[uuid.UUID]float64)
+	for _, order := range orders {
+		for _, item := range order.Items {
+			for _, line := range recipes[item.ProductId] {
+				if start, ok := since[line.IngredientId]; ok && (order.StartedAt == nil || order.StartedAt.Before(start)) {
+					continue
+				}
+				consumption[line.IngredientId] += line.Quantity * float64(item.Quantity)
+			}
+		}
+	}
+	return consumption
+}
+
+// CalculateFoodCost custo da ficha técnica e percentual sobre o preço de venda do produto
+func CalculateFoodCost(product models.Product, recipe []models.RecipeItem, ingredients map[uuid.UUID]models.Ingredient) models.ProductFoodCost {
+	result := models.ProductFoodCost{
+		ProductId:   product.Id,
+		ProductName: product.Name,
+		Items:       make([]models.RecipeItemCost, 0, len(recipe)),
+	}
+
+	for _, line := range recipe {
+		ingredient := ingredients[line.IngredientId]
+		cost := RoundMoney(line.Quantity * ingredient.UnitCost)
+		result.Items = append(result.Items, models.RecipeItemCost{
+			IngredientId:   line.IngredientId,
+			IngredientName: ingredient.Name,
+			Unit:           ingredient.Unit,
+			Quantity:       line.Quantity,
+			UnitCost:       ingredient.UnitCost,
+			Cost:           cost,
+		})
+		result.RecipeCost += cost
+	}
+	result.RecipeCost = RoundMoney(result.RecipeCost)
+
+	// Vinhos vendidos só por variante não têm preço único
+	if price, err := ResolveProductPrice(product, ""); err == nil && price > 0 {
+		result.Price = price
+		result.FoodCostPercent = RoundMoney(result.RecipeCost / price * 100)
+	}
+	return result
+}
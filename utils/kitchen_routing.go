@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"lep/repositories/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FindStationForProduct escolhe a estação responsável pelo produto.
+// Ordem de prioridade: categoria do produto, tipo do produto e, por fim, a estação padrão.
+func FindStationForProduct(product models.Product, stations []models.KitchenStation) *models.KitchenStation {
+	if product.CategoryId != nil {
+		categoryId := product.CategoryId.String()
+		for i := range stations {
+			for _, id := range stations[i].CategoryIds {
+				if id == categoryId {
+					return &stations[i]
+				}
+			}
+		}
+	}
+
+	for i := range stations {
+		for _, productType := range stations[i].ProductTypes {
+			if strings.EqualFold(productType, product.Type) {
+				return &stations[i]
+			}
+		}
+	}
+
+	for i := range stations {
+		if stations[i].IsDefault {
+			return &stations[i]
+		}
+	}
+
+	return nil
+}
+
+// RouteOrderItems atribui id, estação e status inicial a cada item do pedido
+func RouteOrderItems(items models.OrderItems, products []models.Product, stations []models.KitchenStation) {
+	productMap := make(map[uuid.UUID]models.Product)
+	for _, product := range products {
+		productMap[product.Id] = product
+	}
+
+	for i := range items {
+		if items[i].Id == uuid.Nil {
+			items[i].Id = uuid.New()
+		}
+		if items[i].Status == "" {
+			items[i].Status = models.OrderItemStatusQueued
+		}
+		if items[i].StationId != nil {
+			continue // estação definida manualmente
+		}
+		product, exists := productMap[items[i].ProductId]
+		if !exists {
+			continue
+		}
+		if station := FindStationForProduct(product, stations); station != nil {
+			stationId := station.Id
+			items[i].StationId = &stationId
+		}
+	}
+}
+
+// DeriveOrderStatus calcula o status do pedido a partir do status dos itens.
+// Pedidos entregues ou cancelados não são alterados.
+func DeriveOrderStatus(currentStatus string, items models.OrderItems) string {
+	if currentStatus == "delivered" || currentStatus == "cancelled" || len(items) == 0 {
+		return currentStatus
+	}
+
+	readyCount := 0
+	started := false
+	for _, item := range items {
+		switch item.Status {
+		case models.OrderItemStatusReady:
+			readyCount++
+			started = true
+		case models.OrderItemStatusPreparing:
+			started = true
+		}
+	}
+
+	if readyCount == len(items) {
+		return "ready"
+	}
+	if started {
+		return "preparing"
+	}
+	return "pending"
+}
+
+// FilterItemsByStation retorna apenas os itens roteados para a estação
+func FilterItemsByStation(items models.OrderItems, stationId uuid.UUID) []models.OrderItem {
+	filtered := make([]models.OrderItem, 0)
+	for _, item := range items {
+		if item.StationId != nil && *item.StationId == stationId {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// UpdateOrderItemStatus atualiza o status de um item. O status do pedido é derivado pelo handler
+// (DeriveOrderStatus) para passar pela máquina de estados. Retorna false se o item não pertence ao pedido.
+func UpdateOrderItemStatus(order *models.Order, itemId uuid.UUID, newStatus string) bool {
+	found := false
+	now := time.Now()
+	for i := range order.Items {
+		if order.Items[i].Id != itemId {
+			continue
+		}
+		found = true
+		order.Items[i].Status = newStatus
+		switch newStatus {
+		case models.OrderItemStatusPreparing:
+			order.Items[i].StartedAt = &now
+		case models.OrderItemStatusReady:
+			if order.Items[i].StartedAt == nil {
+				order.Items[i].StartedAt = &now
+			}
+			order.Items[i].ReadyAt = &now
+		}
+		break
+	}
+	if !found {
+		return false
+	}
+
+	order.UpdatedAt = now
+	SyncOrderCourses(order)
+	return true
+}
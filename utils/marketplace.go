@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"lep/config"
+	"lep/repositories/models"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MarketplaceSignatureHeader cabeçalho com a assinatura HMAC-SHA256 do corpo ("sha256=<hex>")
+const MarketplaceSignatureHeader = "X-Marketplace-Signature"
+
+// ErrInvalidMarketplaceSignature webhook com assinatura ausente ou inválida
+var ErrInvalidMarketplaceSignature = errors.New("invalid_signature: marketplace webhook signature mismatch")
+
+// MarketplaceAdapter integração com um marketplace de delivery: valida e converte webhooks
+// para o formato canônico e envia as mudanças de status do pedido de volta
+type MarketplaceAdapter interface {
+	Name() string
+	// VerifySignature confere a assinatura do webhook com o segredo da loja
+	VerifySignature(body []byte, header http.Header, secret string) error
+	// ParseWebhook converte o corpo do webhook em eventos canônicos
+	ParseWebhook(body []byte) ([]models.MarketplaceOrderEvent, error)
+	// StatusFor converte o status do pedido para o marketplace ("" = não sincroniza)
+	StatusFor(orderStatus string) string
+	// SendStatus envia o status do pedido para a API do marketplace
+	SendStatus(integration models.MarketplaceIntegration, externalOrderId, status string) error
+}
+
+var (
+	marketplaceAdapters   = make(map[string]MarketplaceAdapter)
+	marketplaceAdaptersMu sync.RWMutex
+)
+
+// RegisterMarketplaceAdapter registra um adaptador de marketplace pelo nome
+func RegisterMarketplaceAdapter(adapter MarketplaceAdapter) {
+	marketplaceAdaptersMu.Lock()
+	defer marketplaceAdaptersMu.Unlock()
+	marketplaceAdapters[adapter.Name()] = adapter
+}
+
+// GetMarketplaceAdapter busca adaptador registrado
+func GetMarketplaceAdapter(name string) (MarketplaceAdapter, error) {
+	marketplaceAdaptersMu.RLock()
+	defer marketplaceAdaptersMu.RUnlock()
+	adapter, exists := marketplaceAdapters[name]
+	if !exists {
+		return nil, fmt.Errorf("marketplace %s not registered", name)
+	}
+	return adapter, nil
+}
+
+func init() {
+	// Marketplace fake fica disponível apenas fora de produção
+	if config.ENV != "prod" {
+		RegisterMarketplaceAdapter(NewFakeMarketplaceAdapter())
+	}
+}
+
+// SignMarketplacePayload assinatura HMAC-SHA256 do corpo no formato "sha256=<hex>"
+func SignMarketplacePayload(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyMarketplaceSignature compara a assinatura recebida em tempo constante
+func VerifyMarketplaceSignature(body []byte, signature, secret string) error {
+	if secret == "" || signature == "" {
+		return ErrInvalidMarketplaceSignature
+	}
+	if !hmac.Equal([]byte(strings.TrimSpace(signature)), []byte(SignMarketplacePayload(body, secret))) {
+		return ErrInvalidMarketplaceSignature
+	}
+	return nil
+}
+
+// FakeMarketplaceAdapter marketplace local para testes (ver cmd/fakemarketplace).
+// Webhook: {"events": [evento canônico]} ou um único evento, assinado com X-Marketplace-Signature.
+// Status: POST {api_base_url}/orders/{order_id}/status com {"status": "..."}.
+type FakeMarketplaceAdapter struct {
+	client *http.Client
+}
+
+func NewFakeMarketplaceAdapter() *FakeMarketplaceAdapter {
+	return &FakeMarketplaceAdapter{client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (a *FakeMarketplaceAdapter) Name() string {
+	return "fake"
+}
+
+func (a *FakeMarketplaceAdapter) VerifySignature(body []byte, header http.Header, secret string) error {
+	return VerifyMarketplaceSignature(body, header.Get(MarketplaceSignatureHeader), secret)
+}
+
+func (a *FakeMarketplaceAdapter) ParseWebhook(body []byte) ([]models.MarketplaceOrderEvent, error) {
+	var envelope struct {
+		Events []models.MarketplaceOrderEvent `json:"events"`
+	}
+	if err := json.Unmarshal(body, &envelope); err == nil && len(envelope.Events) > 0 {
+		return envelope.Events, nil
+	}
+
+	var single models.MarketplaceOrderEvent
+	if err := json.Unmarshal(body, &single); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	if single.EventId == "" {
+		return nil, errors.New("invalid webhook payload: event_id is required")
+	}
+	return []models.MarketplaceOrderEvent{single}, nil
+}
+
+// fakeMarketplaceStatuses status do pedido → status no marketplace
+var fakeMarketplaceStatuses = map[string]string{
+	models.OrderStatusPending:   "confirmed",
+	models.OrderStatusPreparing: "preparing",
+	models.OrderStatusReady:     "ready",
+	models.OrderStatusDelivered: "dispatched",
+	models.OrderStatusCancelled: "cancelled",
+}
+
+func (a *FakeMarketplaceAdapter) StatusFor(orderStatus string) string {
+	return fakeMarketplaceStatuses[orderStatus]
+}
+
+func (a *FakeMarketplaceAdapter) SendStatus(integration models.MarketplaceIntegration, externalOrderId, status string) error {
+	if integration.ApiBaseUrl == "" {
+		return errors.New("marketplace api_base_url not configured")
+	}
+
+	body, err := json.Marshal(map[string]string{"status": status})
+	if err != nil {
+		return err
+	}
+	endpoint := strings.TrimRight(integration.ApiBaseUrl, "/") + "/orders/" + url.PathEscape(externalOrderId) + "/status"
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if integration.ApiToken != "" {
+		req.Header.Set("Authorization", "Bearer "+integration.ApiToken)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("marketplace returned %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
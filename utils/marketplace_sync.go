@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	marketplaceSyncMaxAttempts = 5
+	marketplaceSyncRetryDelay  = 30 * time.Second // multiplicado pelo número de tentativas
+	marketplaceSyncStuckAfter  = 2 * time.Minute  // "processing" há mais tempo volta para a fila
+)
+
+// MarketplaceSyncService envia as mudanças de status dos pedidos de marketplace:
+// grava o envio, tenta na hora e reagenda com espera crescente quando a API não responde
+type MarketplaceSyncService struct {
+	repo repositories.IMarketplaceRepository
+}
+
+func NewMarketplaceSyncService(repo repositories.IMarketplaceRepository) *MarketplaceSyncService {
+	return &MarketplaceSyncService{repo: repo}
+}
+
+// SyncOrderStatus enfileira o status atual do pedido para o marketplace de origem.
+// Pedidos sem marketplace, status sem equivalente e cancelamentos feitos pelo próprio marketplace são ignorados.
+func (s *MarketplaceSyncService) SyncOrderStatus(order *models.Order) {
+	if order.ExternalOrderId == "" {
+		return
+	}
+	adapter, err := GetMarketplaceAdapter(order.Source)
+	if err != nil {
+		return
+	}
+	status := adapter.StatusFor(order.Status)
+	if status == "" {
+		return
+	}
+
+	if order.Status == models.OrderStatusCancelled {
+		cancelled, err := s.repo.GetInboundEvent(order.Id, models.MarketplaceEventOrderCancelled)
+		if err != nil || cancelled != nil {
+			return
+		}
+	}
+
+	integration, err := s.orderIntegration(order)
+	if err != nil {
+		log.Printf("⚠️ Marketplace %s: integração do pedido %s não encontrada: %v", order.Source, order.Id, err)
+		return
+	}
+
+	payload, _ := json.Marshal(map[string]string{"status": status, "order_status": order.Status})
+	now := time.Now()
+	event := &models.MarketplaceEvent{
+		Id:              uuid.New(),
+		OrganizationId:  order.OrganizationId,
+		ProjectId:       order.ProjectId,
+		IntegrationId:   integration.Id,
+		Marketplace:     order.Source,
+		EventId:         uuid.New().String(),
+		Direction:       models.MarketplaceDirectionOutbound,
+		Type:            models.MarketplaceEventOrderStatus,
+		ExternalOrderId: order.ExternalOrderId,
+		OrderId:         &order.Id,
+		Status:          models.MarketplaceEventStatusPending,
+		Payload:         string(payload),
+		NextAttemptAt:   now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if _, err := s.repo.CreateEventIfNew(event); err != nil {
+		log.Printf("⚠️ Marketplace %s: erro ao enfileirar status do pedido %s: %v", order.Source, order.Id, err)
+		return
+	}
+
+	go s.dispatch(event.Id)
+}
+
+// Retry devolve envio com falha para a fila com tentativas zeradas
+func (s *MarketplaceSyncService) Retry(event *models.MarketplaceEvent) error {
+	if event.Direction != models.MarketplaceDirectionOutbound || event.Status != models.MarketplaceEventStatusFailed {
+		return fmt.Errorf("invalid_marketplace_event: only failed outbound events can be retried (status %s)", event.Status)
+	}
+
+	event.Status = models.MarketplaceEventStatusPending
+	event.Attempts = 0
+	event.LastError = ""
+	event.NextAttemptAt = time.Now()
+	event.UpdatedAt = time.Now()
+	if err := s.repo.UpdateEvent(event); err != nil {
+		return err
+	}
+
+	go s.dispatch(event.Id)
+	return nil
+}
+
+// ProcessDueEvents tenta novamente os envios pendentes cujo horário chegou
+func (s *MarketplaceSyncService) ProcessDueEvents() error {
+	now := time.Now()
+	events, err := s.repo.ListDueOutbound(now, now.Add(-marketplaceSyncStuckAfter), 100)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		s.dispatch(event.Id)
+	}
+	return nil
+}
+
+// orderIntegration integração da loja que recebeu o pedido
+func (s *MarketplaceSyncService) orderIntegration(order *models.Order) (*models.MarketplaceIntegration, error) {
+	placed, err := s.repo.GetInboundEvent(order.Id, models.MarketplaceEventOrderPlaced)
+	if err != nil {
+		return nil, err
+	}
+	if placed == nil {
+		return nil, fmt.Errorf("no order.placed event for order %s", order.Id)
+	}
+	return s.repo.GetIntegrationById(placed.IntegrationId)
+}
+
+// dispatch faz uma tentativa de envio do status, se ninguém mais o pegou
+func (s *MarketplaceSyncService) dispatch(eventId uuid.UUID) {
+	now := time.Now()
+	claimed, err := s.repo.ClaimOutbound(eventId, now, now.Add(-marketplaceSyncStuckAfter))
+	if err != nil || !claimed {
+		return
+	}
+
+	event, err := s.repo.GetEventById(eventId)
+	if err != nil {
+		log.Printf("Marketplace event %s not found: %v", eventId, err)
+		return
+	}
+
+	event.Attempts++
+	event.UpdatedAt = time.Now()
+
+	var payload struct {
+		Status string `json:"status"`
+	}
+	err = json.Unmarshal([]byte(event.Payload), &payload)
+	if err == nil {
+		err = s.send(event, payload.Status)
+	}
+
+	if err == nil {
+		processedAt := time.Now()
+		event.Status = models.MarketplaceEventStatusProcessed
+		event.ProcessedAt = &processedAt
+		event.LastError = ""
+	} else {
+		event.LastError = err.Error()
+		if event.Attempts >= marketplaceSyncMaxAttempts {
+			event.Status = models.MarketplaceEventStatusFailed
+			GetRealtimeBus().Publish(event.OrganizationId, event.ProjectId, RealtimeTopicFloor, "marketplace.sync_failed", event)
+		} else {
+			event.Status = models.MarketplaceEventStatusPending
+			event.NextAttemptAt = time.Now().Add(time.Duration(event.Attempts) * marketplaceSyncRetryDelay)
+		}
+	}
+
+	if err := s.repo.UpdateEvent(event); err != nil {
+		log.Printf("Error saving marketplace event %s: %v", event.Id, err)
+	}
+}
+
+func (s *MarketplaceSyncService) send(event *models.MarketplaceEvent, status string) error {
+	adapter, err := GetMarketplaceAdapter(event.Marketplace)
+	if err != nil {
+		return err
+	}
+	integration, err := s.repo.GetIntegrationById(event.IntegrationId)
+	if err != nil {
+		return fmt.Errorf("integration not found: %w", err)
+	}
+	return adapter.SendStatus(*integration, event.ExternalOrderId, status)
+}
@@ -0,0 +1,996 @@
+package utils
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"lep/repositories/models"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Namespaces e algoritmos do leiaute 4.00 (assinatura XMLDSig RSA-SHA1 com C14N)
+const (
+	nfeNamespace     = "http://www.portalfiscal.inf.br/nfe"
+	xmldsigNamespace = "http://www.w3.org/2000/09/xmldsig#"
+	c14nAlgorithm    = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315"
+
+	nfceLayoutVersion = "4.00"
+	nfceQrCodeVersion = "2"
+	nfceAppVersion    = "LEP 1.0"
+
+	// Texto obrigatório no primeiro item e no destinatário em homologação
+	nfceHomologationText = "NOTA FISCAL EMITIDA EM AMBIENTE DE HOMOLOGACAO - SEM VALOR FISCAL"
+)
+
+// nfceStateCodes código IBGE das UFs (cUF)
+var nfceStateCodes = map[string]string{
+	"RO": "11", "AC": "12", "AM": "13", "RR": "14", "PA": "15", "AP": "16", "TO": "17",
+	"MA": "21", "PI": "22", "CE": "23", "RN": "24", "PB": "25", "PE": "26", "AL": "27", "SE": "28", "BA": "29",
+	"MG": "31", "ES": "32", "RJ": "33", "SP": "35",
+	"PR": "41", "SC": "42", "RS": "43",
+	"MS": "50", "MT": "51", "GO": "52", "DF": "53",
+}
+
+// nfceQrCodeUrls URLs de consulta da SEFAZ por UF: [produção, homologação] (demais UFs pelas configurações)
+var nfceQrCodeUrls = map[string][2]string{
+	"SP": {"https://www.nfce.fazenda.sp.gov.br/NFCeConsultaPublica/Paginas/ConsultaQRCode.aspx",
+		"https://www.homologacao.nfce.fazenda.sp.gov.br/NFCeConsultaPublica/Paginas/ConsultaQRCode.aspx"},
+}
+
+var nfceConsultUrls = map[string][2]string{
+	"SP": {"https://www.nfce.fazenda.sp.gov.br/NFCeConsultaPublica",
+		"https://www.homologacao.nfce.fazenda.sp.gov.br/NFCeConsultaPublica"},
+}
+
+// NfceItem item da nota: produto com os dados fiscais e valores já consolidados dos pedidos
+type NfceItem struct {
+	Product   models.Product
+	Name      string // descrição impressa (nome + variante)
+	Quantity  float64
+	UnitPrice float64
+}
+
+// NfceInput dados para montar a NFC-e
+type NfceInput struct {
+	Organization     *models.Organization
+	Environment      int
+	Series           int
+	Number           int
+	IssuedAt         time.Time
+	Items            []NfceItem
+	OtherCharges     float64 // taxa de entrega (vOutro)
+	Delivery         bool    // entrega em domicílio (indPres 4)
+	Payments         []models.FiscalPayment
+	CustomerDocument string
+	CustomerName     string
+	AdditionalInfo   string
+	CscId            string
+	Csc              string
+	QrCodeUrl        string // vazio = URL conhecida da UF
+	ConsultUrl       string
+}
+
+// NfceResult NFC-e assinada e dados impressos no DANFE
+type NfceResult struct {
+	AccessKey   string
+	XML         []byte
+	TotalAmount float64
+	Change      float64
+	QRCodeUrl   string
+	ConsultUrl  string
+}
+
+// BuildNfce monta, valida e assina a NFC-e
+func BuildNfce(input NfceInput, certificate *NfceCertificate) (*NfceResult, error) {
+	infNFe, err := buildNfceInfo(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateNfceInfo(infNFe); err != nil {
+		return nil, err
+	}
+
+	accessKey := strings.TrimPrefix(infNFe.Id, "NFe")
+	state := infNFe.Emit.EnderEmit.UF
+	qrCodeUrl, consultUrl, err := nfceUrls(input, state)
+	if err != nil {
+		return nil, err
+	}
+	qrCode := BuildNfceQrCode(qrCodeUrl, accessKey, input.Environment, input.CscId, input.Csc)
+
+	fragment, err := xml.Marshal(infNFe)
+	if err != nil {
+		return nil, err
+	}
+	signature, body, err := signNfceElement(fragment, infNFe.Id, certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	var document bytes.Buffer
+	document.WriteString(xml.Header[:len(xml.Header)-1])
+	document.WriteString(`<NFe xmlns="` + nfeNamespace + `">`)
+	document.Write(body)
+	document.WriteString("<infNFeSupl><qrCode>")
+	xml.EscapeText(&document, []byte(qrCode))
+	document.WriteString("</qrCode><urlChave>")
+	xml.EscapeText(&document, []byte(consultUrl))
+	document.WriteString("</urlChave></infNFeSupl>")
+	document.WriteString(signature)
+	document.WriteString("</NFe>")
+
+	if err := ValidateNfceSchema(document.Bytes()); err != nil {
+		return nil, err
+	}
+
+	total, _ := strconv.ParseFloat(infNFe.Total.ICMSTot.VNF, 64)
+	change := 0.0
+	if infNFe.Pag.VTroco != "" {
+		change, _ = strconv.ParseFloat(infNFe.Pag.VTroco, 64)
+	}
+	return &NfceResult{
+		AccessKey:   accessKey,
+		XML:         document.Bytes(),
+		TotalAmount: total,
+		Change:      change,
+		QRCodeUrl:   qrCode,
+		ConsultUrl:  consultUrl,
+	}, nil
+}
+
+// BuildNfceAccessKey chave de acesso: cUF, AAMM, CNPJ, modelo, série, número, tpEmis, cNF e dígito verificador
+func BuildNfceAccessKey(stateCode string, issuedAt time.Time, cnpj string, series, number int, code string) string {
+	key := fmt.Sprintf("%s%s%s%s%03d%09d%d%s", stateCode, issuedAt.Format("0601"), cnpj, models.FiscalModelNfce, series, number, 1, code)
+	return key + strconv.Itoa(nfceCheckDigit(key))
+}
+
+// nfceCheckDigit módulo 11 com pesos de 2 a 9 da direita para a esquerda
+func nfceCheckDigit(key string) int {
+	sum, weight := 0, 2
+	for i := len(key) - 1; i >= 0; i-- {
+		sum += int(key[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+	digit := 11 - sum%11
+	if digit >= 10 {
+		return 0
+	}
+	return digit
+}
+
+// BuildNfceQrCode URL do QR Code versão 2 (emissão online):
+// url?p=chave|2|tpAmb|idCSC|SHA1(chave|2|tpAmb|idCSC + CSC)
+func BuildNfceQrCode(baseUrl, accessKey string, environment int, cscId, csc string) string {
+	id := strings.TrimLeft(cscId, "0")
+	params := fmt.Sprintf("%s|%s|%d|%s", accessKey, nfceQrCodeVersion, environment, id)
+	hash := sha1.Sum([]byte(params + csc))
+	return baseUrl + "?p=" + params + "|" + strings.ToUpper(hex.EncodeToString(hash[:]))
+}
+
+// nfceUrls URLs do QR Code e da consulta pela chave (configuração do projeto ou padrão da UF)
+func nfceUrls(input NfceInput, state string) (string, string, error) {
+	index := 0
+	if input.Environment != models.FiscalEnvironmentProduction {
+		index = 1
+	}
+	qrCodeUrl, consultUrl := input.QrCodeUrl, input.ConsultUrl
+	if qrCodeUrl == "" {
+		qrCodeUrl = nfceQrCodeUrls[state][index]
+	}
+	if consultUrl == "" {
+		consultUrl = nfceConsultUrls[state][index]
+	}
+	if qrCodeUrl == "" || consultUrl == "" {
+		return "", "", fmt.Errorf("nfce_not_configured: qr code and consult urls must be set for %s", state)
+	}
+	return qrCodeUrl, consultUrl, nil
+}
+
+// --- Estrutura do infNFe (ordem dos elementos conforme o leiaute 4.00) ---
+
+type nfceInfNFe struct {
+	XMLName xml.Name     `xml:"infNFe"`
+	Versao  string       `xml:"versao,attr"`
+	Id      string       `xml:"Id,attr"`
+	Ide     nfceIde      `xml:"ide"`
+	Emit    nfceEmit     `xml:"emit"`
+	Dest    *nfceDest    `xml:"dest,omitempty"`
+	Det     []nfceDet    `xml:"det"`
+	Total   nfceTotal    `xml:"total"`
+	Transp  nfceTransp   `xml:"transp"`
+	Pag     nfcePag      `xml:"pag"`
+	InfAdic *nfceInfAdic `xml:"infAdic,omitempty"`
+}
+
+type nfceIde struct {
+	CUF         string `xml:"cUF"`
+	CNF         string `xml:"cNF"`
+	NatOp       string `xml:"natOp"`
+	Mod         string `xml:"mod"`
+	Serie       int    `xml:"serie"`
+	NNF         int    `xml:"nNF"`
+	DhEmi       string `xml:"dhEmi"`
+	TpNF        int    `xml:"tpNF"`
+	IdDest      int    `xml:"idDest"`
+	CMunFG      string `xml:"cMunFG"`
+	TpImp       int    `xml:"tpImp"`
+	TpEmis      int    `xml:"tpEmis"`
+	CDV         int    `xml:"cDV"`
+	TpAmb       int    `xml:"tpAmb"`
+	FinNFe      int    `xml:"finNFe"`
+	IndFinal    int    `xml:"indFinal"`
+	IndPres     int    `xml:"indPres"`
+	IndIntermed int    `xml:"indIntermed"`
+	ProcEmi     int    `xml:"procEmi"`
+	VerProc     string `xml:"verProc"`
+}
+
+type nfceEmit struct {
+	CNPJ      string       `xml:"CNPJ"`
+	XNome     string       `xml:"xNome"`
+	XFant     string       `xml:"xFant,omitempty"`
+	EnderEmit nfceEndereco `xml:"enderEmit"`
+	IE        string       `xml:"IE"`
+	CRT       int          `xml:"CRT"`
+}
+
+type nfceEndereco struct {
+	XLgr    string `xml:"xLgr"`
+	Nro     string `xml:"nro"`
+	XCpl    string `xml:"xCpl,omitempty"`
+	XBairro string `xml:"xBairro"`
+	CMun    string `xml:"cMun"`
+	XMun    string `xml:"xMun"`
+	UF      string `xml:"UF"`
+	CEP     string `xml:"CEP,omitempty"`
+	CPais   string `xml:"cPais"`
+	XPais   string `xml:"xPais"`
+	Fone    string `xml:"fone,omitempty"`
+}
+
+type nfceDest struct {
+	CNPJ      string `xml:"CNPJ,omitempty"`
+	CPF       string `xml:"CPF,omitempty"`
+	XNome     string `xml:"xNome,omitempty"`
+	IndIEDest int    `xml:"indIEDest"`
+}
+
+type nfceDet struct {
+	NItem   int         `xml:"nItem,attr"`
+	Prod    nfceProd    `xml:"prod"`
+	Imposto nfceImposto `xml:"imposto"`
+}
+
+type nfceProd struct {
+	CProd    string `xml:"cProd"`
+	CEAN     string `xml:"cEAN"`
+	XProd    string `xml:"xProd"`
+	NCM      string `xml:"NCM"`
+	CEST     string `xml:"CEST,omitempty"`
+	CFOP     string `xml:"CFOP"`
+	UCom     string `xml:"uCom"`
+	QCom     string `xml:"qCom"`
+	VUnCom   string `xml:"vUnCom"`
+	VProd    string `xml:"vProd"`
+	CEANTrib string `xml:"cEANTrib"`
+	UTrib    string `xml:"uTrib"`
+	QTrib    string `xml:"qTrib"`
+	VUnTrib  string `xml:"vUnTrib"`
+	VOutro   string `xml:"vOutro,omitempty"`
+	IndTot   int    `xml:"indTot"`
+}
+
+type nfceImposto struct {
+	ICMS   nfceICMS   `xml:"ICMS"`
+	PIS    nfcePIS    `xml:"PIS"`
+	COFINS nfceCOFINS `xml:"COFINS"`
+}
+
+type nfceICMS struct {
+	ICMS00    *nfceICMS00    `xml:"ICMS00,omitempty"`
+	ICMS40    *nfceICMSCST   `xml:"ICMS40,omitempty"`
+	ICMS60    *nfceICMSCST   `xml:"ICMS60,omitempty"`
+	ICMSSN102 *nfceICMSCSOSN `xml:"ICMSSN102,omitempty"`
+	ICMSSN500 *nfceICMSCSOSN `xml:"ICMSSN500,omitempty"`
+}
+
+type nfceICMS00 struct {
+	Orig  int    `xml:"orig"`
+	CST   string `xml:"CST"`
+	ModBC int    `xml:"modBC"`
+	VBC   string `xml:"vBC"`
+	PICMS string `xml:"pICMS"`
+	VICMS string `xml:"vICMS"`
+}
+
+type nfceICMSCST struct {
+	Orig int    `xml:"orig"`
+	CST  string `xml:"CST"`
+}
+
+type nfceICMSCSOSN struct {
+	Orig  int    `xml:"orig"`
+	CSOSN string `xml:"CSOSN"`
+}
+
+type nfcePIS struct {
+	PISNT   *nfceTaxCST  `xml:"PISNT,omitempty"`
+	PISOutr *nfcePISOutr `xml:"PISOutr,omitempty"`
+}
+
+type nfceCOFINS struct {
+	COFINSNT   *nfceTaxCST     `xml:"COFINSNT,omitempty"`
+	COFINSOutr *nfceCOFINSOutr `xml:"COFINSOutr,omitempty"`
+}
+
+type nfceTaxCST struct {
+	CST string `xml:"CST"`
+}
+
+type nfcePISOutr struct {
+	CST  string `xml:"CST"`
+	VBC  string `xml:"vBC"`
+	PPIS string `xml:"pPIS"`
+	VPIS string `xml:"vPIS"`
+}
+
+type nfceCOFINSOutr struct {
+	CST     string `xml:"CST"`
+	VBC     string `xml:"vBC"`
+	PCOFINS string `xml:"pCOFINS"`
+	VCOFINS string `xml:"vCOFINS"`
+}
+
+type nfceTotal struct {
+	ICMSTot nfceICMSTot `xml:"ICMSTot"`
+}
+
+type nfceICMSTot struct {
+	VBC        string `xml:"vBC"`
+	VICMS      string `xml:"vICMS"`
+	VICMSDeson string `xml:"vICMSDeson"`
+	VFCP       string `xml:"vFCP"`
+	VBCST      string `xml:"vBCST"`
+	VST        string `xml:"vST"`
+	VFCPST     string `xml:"vFCPST"`
+	VFCPSTRet  string `xml:"vFCPSTRet"`
+	VProd      string `xml:"vProd"`
+	VFrete     string `xml:"vFrete"`
+	VSeg       string `xml:"vSeg"`
+	VDesc      string `xml:"vDesc"`
+	VII        string `xml:"vII"`
+	VIPI       string `xml:"vIPI"`
+	VIPIDevol  string `xml:"vIPIDevol"`
+	VPIS       string `xml:"vPIS"`
+	VCOFINS    string `xml:"vCOFINS"`
+	VOutro     string `xml:"vOutro"`
+	VNF        string `xml:"vNF"`
+}
+
+type nfceTransp struct {
+	ModFrete int `xml:"modFrete"`
+}
+
+type nfcePag struct {
+	DetPag []nfceDetPag `xml:"detPag"`
+	VTroco string       `xml:"vTroco,omitempty"`
+}
+
+type nfceDetPag struct {
+	TPag string    `xml:"tPag"`
+	XPag string    `xml:"xPag,omitempty"`
+	VPag string    `xml:"vPag"`
+	Card *nfceCard `xml:"card,omitempty"`
+}
+
+type nfceCard struct {
+	TpIntegra int `xml:"tpIntegra"` // 2 = pagamento não integrado ao sistema (maquininha)
+}
+
+type nfceInfAdic struct {
+	InfCpl string `xml:"infCpl"`
+}
+
+// buildNfceInfo monta o grupo infNFe a partir dos pedidos e dados do emitente
+func buildNfceInfo(input NfceInput) (*nfceInfNFe, error) {
+	org := input.Organization
+	if org == nil || org.FiscalAddress == nil {
+		return nil, errors.New("nfce_not_configured: organization fiscal address is required")
+	}
+	address := org.FiscalAddress
+	state := strings.ToUpper(strings.TrimSpace(address.State))
+	stateCode, ok := nfceStateCodes[state]
+	if !ok {
+		return nil, fmt.Errorf("nfce_not_configured: invalid organization state %q", address.State)
+	}
+	cnpj := onlyDigits(org.Cnpj)
+	if len(cnpj) != 14 {
+		return nil, errors.New("nfce_not_configured: organization cnpj must have 14 digits")
+	}
+	if len(input.Items) == 0 {
+		return nil, errors.New("invalid_nfce: document has no items")
+	}
+
+	issuedAt := input.IssuedAt.In(nfceLocation())
+	code := nfceRandomCode(input.Number)
+	accessKey := BuildNfceAccessKey(stateCode, issuedAt, cnpj, input.Series, input.Number, code)
+	checkDigit, _ := strconv.Atoi(accessKey[43:])
+
+	presence := 1
+	if input.Delivery {
+		presence = 4
+	}
+	legalName := org.LegalName
+	if legalName == "" {
+		legalName = org.Name
+	}
+
+	info := &nfceInfNFe{
+		Versao: nfceLayoutVersion,
+		Id:     "NFe" + accessKey,
+		Ide: nfceIde{
+			CUF:      stateCode,
+			CNF:      code,
+			NatOp:    "VENDA",
+			Mod:      models.FiscalModelNfce,
+			Serie:    input.Series,
+			NNF:      input.Number,
+			DhEmi:    issuedAt.Format("2006-01-02T15:04:05-07:00"),
+			TpNF:     1,
+			IdDest:   1,
+			CMunFG:   org.CityCode,
+			TpImp:    4,
+			TpEmis:   1,
+			CDV:      checkDigit,
+			TpAmb:    input.Environment,
+			FinNFe:   1,
+			IndFinal: 1,
+			IndPres:  presence,
+			ProcEmi:  0,
+			VerProc:  nfceAppVersion,
+		},
+		Emit: nfceEmit{
+			CNPJ:  cnpj,
+			XNome: nfceText(legalName, 60),
+			XFant: nfceText(org.Name, 60),
+			EnderEmit: nfceEndereco{
+				XLgr:    nfceText(address.Street, 60),
+				Nro:     nfceText(address.Number, 60),
+				XCpl:    nfceText(address.Complement, 60),
+				XBairro: nfceText(address.Neighborhood, 60),
+				CMun:    org.CityCode,
+				XMun:    nfceText(address.City, 60),
+				UF:      state,
+				CEP:     onlyDigits(address.ZipCode),
+				CPais:   "1058",
+				XPais:   "Brasil",
+				Fone:    nfcePhone(org.Phone),
+			},
+			IE:  strings.ToUpper(onlyDigitsOr(org.StateRegistration, "ISENTO")),
+			CRT: org.TaxRegime,
+		},
+		Transp: nfceTransp{ModFrete: 9},
+	}
+
+	if document := onlyDigits(input.CustomerDocument); document != "" {
+		dest := &nfceDest{XNome: nfceText(input.CustomerName, 60), IndIEDest: 9}
+		if len(document) == 14 {
+			dest.CNPJ = document
+		} else {
+			dest.CPF = document
+		}
+		if input.Environment != models.FiscalEnvironmentProduction {
+			dest.XNome = nfceHomologationText
+		}
+		info.Dest = dest
+	}
+
+	// Itens: valores em centavos para os totais baterem com a soma das linhas
+	var totalProducts, totalICMSBase, totalICMS int64
+	for i, item := range input.Items {
+		product := item.Product
+		unitCents := nfceCents(item.UnitPrice)
+		lineCents := int64(math.Round(float64(unitCents) * item.Quantity))
+		totalProducts += lineCents
+
+		name := item.Name
+		if i == 0 && input.Environment != models.FiscalEnvironmentProduction {
+			name = nfceHomologationText
+		}
+		code := product.Id.String()
+		if product.PDVCode != nil && *product.PDVCode != "" {
+			code = *product.PDVCode
+		}
+		unit := strings.ToUpper(nfceDefault(product.FiscalUnit, "UN"))
+
+		det := nfceDet{
+			NItem: i + 1,
+			Prod: nfceProd{
+				CProd:    nfceText(code, 60),
+				CEAN:     "SEM GTIN",
+				XProd:    nfceText(name, 120),
+				NCM:      onlyDigits(product.NCM),
+				CEST:     onlyDigits(product.CEST),
+				CFOP:     nfceDefault(onlyDigits(product.CFOP), "5102"),
+				UCom:     unit,
+				QCom:     strconv.FormatFloat(item.Quantity, 'f', 4, 64),
+				VUnCom:   nfceMoney(unitCents),
+				VProd:    nfceMoney(lineCents),
+				CEANTrib: "SEM GTIN",
+				UTrib:    unit,
+				QTrib:    strconv.FormatFloat(item.Quantity, 'f', 4, 64),
+				VUnTrib:  nfceMoney(unitCents),
+				IndTot:   1,
+			},
+		}
+
+		base, tax, err := nfceItemTaxes(&det, product, org.TaxRegime, lineCents)
+		if err != nil {
+			return nil, err
+		}
+		totalICMSBase += base
+		totalICMS += tax
+		info.Det = append(info.Det, det)
+	}
+
+	// Taxa de entrega entra como outras despesas no último item
+	otherCents := nfceCents(input.OtherCharges)
+	if otherCents > 0 {
+		info.Det[len(info.Det)-1].Prod.VOutro = nfceMoney(otherCents)
+	}
+	totalCents := totalProducts + otherCents
+
+	zero := nfceMoney(0)
+	info.Total.ICMSTot = nfceICMSTot{
+		VBC: nfceMoney(totalICMSBase), VICMS: nfceMoney(totalICMS), VICMSDeson: zero, VFCP: zero,
+		VBCST: zero, VST: zero, VFCPST: zero, VFCPSTRet: zero,
+		VProd: nfceMoney(totalProducts), VFrete: zero, VSeg: zero, VDesc: zero,
+		VII: zero, VIPI: zero, VIPIDevol: zero, VPIS: zero, VCOFINS: zero,
+		VOutro: nfceMoney(otherCents), VNF: nfceMoney(totalCents),
+	}
+
+	payments := input.Payments
+	if len(payments) == 0 {
+		payments = []models.FiscalPayment{{Method: models.FiscalPaymentCash, Amount: float64(totalCents) / 100}}
+	}
+	var paid int64
+	for _, payment := range payments {
+		cents := nfceCents(payment.Amount)
+		paid += cents
+		detPag := nfceDetPag{TPag: payment.Method, VPag: nfceMoney(cents)}
+		if payment.Method == models.FiscalPaymentOther {
+			detPag.XPag = nfceText(payment.Description, 60)
+		}
+		if payment.Method == models.FiscalPaymentCredit || payment.Method == models.FiscalPaymentDebit {
+			detPag.Card = &nfceCard{TpIntegra: 2}
+		}
+		info.Pag.DetPag = append(info.Pag.DetPag, detPag)
+	}
+	if paid < totalCents {
+		return nil, fmt.Errorf("invalid_nfce: payments (%s) are less than the document total (%s)", nfceMoney(paid), nfceMoney(totalCents))
+	}
+	if paid > totalCents {
+		info.Pag.VTroco = nfceMoney(paid - totalCents)
+	}
+
+	if additional := strings.TrimSpace(input.AdditionalInfo); additional != "" {
+		info.InfAdic = &nfceInfAdic{InfCpl: nfceText(additional, 5000)}
+	}
+	return info, nil
+}
+
+// nfceItemTaxes preenche ICMS, PIS e COFINS do item; retorna base e valor do ICMS em centavos
+func nfceItemTaxes(det *nfceDet, product models.Product, regime int, lineCents int64) (int64, int64, error) {
+	var base, tax int64
+	cst := onlyDigits(product.TaxCST)
+	if regime == 1 || regime == 4 {
+		// Simples Nacional e MEI usam CSOSN; excesso de sublimite e regime normal usam CST
+		cst = nfceDefault(cst, "102")
+		group := &nfceICMSCSOSN{Orig: product.TaxOrigin, CSOSN: cst}
+		switch cst {
+		case "102", "103", "300", "400":
+			det.Imposto.ICMS.ICMSSN102 = group
+		case "500":
+			det.Imposto.ICMS.ICMSSN500 = group
+		default:
+			return 0, 0, fmt.Errorf("invalid_nfce: product %s has unsupported CSOSN %s", product.Name, cst)
+		}
+	} else {
+		cst = nfceDefault(cst, "00")
+		switch cst {
+		case "00":
+			base = lineCents
+			tax = int64(math.Round(float64(lineCents) * product.ICMSRate / 100))
+			det.Imposto.ICMS.ICMS00 = &nfceICMS00{
+				Orig: product.TaxOrigin, CST: cst, ModBC: 3,
+				VBC: nfceMoney(base), PICMS: strconv.FormatFloat(product.ICMSRate, 'f', 2, 64), VICMS: nfceMoney(tax),
+			}
+		case "40", "41":
+			det.Imposto.ICMS.ICMS40 = &nfceICMSCST{Orig: product.TaxOrigin, CST: cst}
+		case "60":
+			det.Imposto.ICMS.ICMS60 = &nfceICMSCST{Orig: product.TaxOrigin, CST: cst}
+		default:
+			return 0, 0, fmt.Errorf("invalid_nfce: product %s has unsupported ICMS CST %s", product.Name, cst)
+		}
+	}
+
+	pisCofins := nfceDefault(onlyDigits(product.PisCofinsCST), "49")
+	switch pisCofins {
+	case "04", "05", "06", "07", "08", "09":
+		det.Imposto.PIS.PISNT = &nfceTaxCST{CST: pisCofins}
+		det.Imposto.COFINS.COFINSNT = &nfceTaxCST{CST: pisCofins}
+	case "49", "99":
+		zero := nfceMoney(0)
+		det.Imposto.PIS.PISOutr = &nfcePISOutr{CST: pisCofins, VBC: zero, PPIS: "0.00", VPIS: zero}
+		det.Imposto.COFINS.COFINSOutr = &nfceCOFINSOutr{CST: pisCofins, VBC: zero, PCOFINS: "0.00", VCOFINS: zero}
+	default:
+		return 0, 0, fmt.Errorf("invalid_nfce: product %s has unsupported PIS/COFINS CST %s", product.Name, pisCofins)
+	}
+	return base, tax, nil
+}
+
+// --- Assinatura XMLDSig ---
+
+// signNfceElement assina o elemento pelo atributo Id (RSA-SHA1, C14N).
+// Retorna o bloco <Signature> e o elemento canônico para compor o documento.
+func signNfceElement(fragment []byte, id string, certificate *NfceCertificate) (string, []byte, error) {
+	if certificate == nil {
+		return "", nil, errors.New("invalid_certificate: certificate is required to sign the document")
+	}
+	canonical, err := canonicalizeNfceXML(fragment, nfeNamespace)
+	if err != nil {
+		return "", nil, err
+	}
+	body, err := canonicalizeNfceXML(fragment, "")
+	if err != nil {
+		return "", nil, err
+	}
+
+	digest := sha1.Sum(canonical)
+	signedInfo := nfceSignedInfo(id, base64.StdEncoding.EncodeToString(digest[:]))
+	hashed := sha1.Sum([]byte(`<SignedInfo xmlns="` + xmldsigNamespace + `">` + signedInfo + `</SignedInfo>`))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, certificate.PrivateKey, crypto.SHA1, hashed[:])
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid_certificate: %w", err)
+	}
+
+	block := `<Signature xmlns="` + xmldsigNamespace + `"><SignedInfo>` + signedInfo + `</SignedInfo>` +
+		`<SignatureValue>` + base64.StdEncoding.EncodeToString(signature) + `</SignatureValue>` +
+		`<KeyInfo><X509Data><X509Certificate>` + base64.StdEncoding.EncodeToString(certificate.Certificate.Raw) +
+		`</X509Certificate></X509Data></KeyInfo></Signature>`
+	return block, body, nil
+}
+
+// nfceSignedInfo conteúdo do SignedInfo já na forma canônica
+func nfceSignedInfo(id, digest string) string {
+	return `<CanonicalizationMethod Algorithm="` + c14nAlgorithm + `"></CanonicalizationMethod>` +
+		`<SignatureMethod Algorithm="` + xmldsigNamespace + `rsa-sha1"></SignatureMethod>` +
+		`<Reference URI="#` + id + `"><Transforms>` +
+		`<Transform Algorithm="` + xmldsigNamespace + `enveloped-signature"></Transform>` +
+		`<Transform Algorithm="` + c14nAlgorithm + `"></Transform>` +
+		`</Transforms><DigestMethod Algorithm="` + xmldsigNamespace + `sha1"></DigestMethod>` +
+		`<DigestValue>` + digest + `</DigestValue></Reference>`
+}
+
+type nfceSignatureBlock struct {
+	SignedInfo struct {
+		Reference struct {
+			URI         string `xml:"URI,attr"`
+			DigestValue string `xml:"DigestValue"`
+		} `xml:"Reference"`
+	} `xml:"SignedInfo"`
+	SignatureValue string `xml:"SignatureValue"`
+	Certificate    string `xml:"KeyInfo>X509Data>X509Certificate"`
+}
+
+// VerifyNfceSignature confere a assinatura do elemento (infNFe ou infEvento) e devolve o certificado e o digest
+func VerifyNfceSignature(document []byte, element string) (*x509.Certificate, string, error) {
+	fragment, err := nfceElement(document, element)
+	if err != nil {
+		return nil, "", err
+	}
+	signatureXML, err := nfceElement(document, "Signature")
+	if err != nil {
+		return nil, "", err
+	}
+	signedInfoXML, err := nfceElement(signatureXML, "SignedInfo")
+	if err != nil {
+		return nil, "", err
+	}
+
+	var signature nfceSignatureBlock
+	if err := xml.Unmarshal(signatureXML, &signature); err != nil {
+		return nil, "", err
+	}
+	var root struct {
+		Id string `xml:"Id,attr"`
+	}
+	if err := xml.Unmarshal(fragment, &root); err != nil {
+		return nil, "", err
+	}
+	if signature.SignedInfo.Reference.URI != "#"+root.Id {
+		return nil, "", errors.New("signature reference does not match the signed element")
+	}
+
+	canonical, err := canonicalizeNfceXML(fragment, nfeNamespace)
+	if err != nil {
+		return nil, "", err
+	}
+	digest := sha1.Sum(canonical)
+	digestValue := base64.StdEncoding.EncodeToString(digest[:])
+	if digestValue != strings.TrimSpace(signature.SignedInfo.Reference.DigestValue) {
+		return nil, "", errors.New("digest value differs from the signed content")
+	}
+
+	certificateDER, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(signature.Certificate), ""))
+	if err != nil {
+		return nil, "", err
+	}
+	certificate, err := x509.ParseCertificate(certificateDER)
+	if err != nil {
+		return nil, "", err
+	}
+	publicKey, ok := certificate.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, "", errors.New("certificate key is not RSA")
+	}
+	signatureValue, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(signature.SignatureValue), ""))
+	if err != nil {
+		return nil, "", err
+	}
+	canonicalSignedInfo, err := canonicalizeNfceXML(signedInfoXML, xmldsigNamespace)
+	if err != nil {
+		return nil, "", err
+	}
+	hashed := sha1.Sum(canonicalSignedInfo)
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA1, hashed[:], signatureValue); err != nil {
+		return nil, "", errors.New("signature value differs from the calculated")
+	}
+	return certificate, digestValue, nil
+}
+
+// canonicalizeNfceXML forma canônica (C14N sem comentários) de um elemento sem prefixos de namespace.
+// O namespace padrão herdado do documento é declarado no elemento raiz quando informado.
+func canonicalizeNfceXML(fragment []byte, namespace string) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(fragment))
+	var buf bytes.Buffer
+	depth := 0
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Space != "" {
+				return nil, fmt.Errorf("namespace prefix not allowed: %s:%s", t.Name.Space, t.Name.Local)
+			}
+			buf.WriteString("<" + t.Name.Local)
+			if depth == 0 && namespace != "" {
+				buf.WriteString(` xmlns="` + namespace + `"`)
+			}
+			attrs := make([]xml.Attr, 0, len(t.Attr))
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+					continue
+				}
+				attrs = append(attrs, attr)
+			}
+			sort.Slice(attrs, func(i, j int) bool { return attrs[i].Name.Local < attrs[j].Name.Local })
+			for _, attr := range attrs {
+				buf.WriteString(" " + attr.Name.Local + `="` + c14nEscape(attr.Value, true) + `"`)
+			}
+			buf.WriteString(">")
+			depth++
+		case xml.EndElement:
+			buf.WriteString("</" + t.Name.Local + ">")
+			depth--
+		case xml.CharData:
+			if depth > 0 {
+				buf.WriteString(c14nEscape(string(t), false))
+			}
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// c14nEscape escape de texto e atributos da C14N
+func c14nEscape(value string, attribute bool) string {
+	replacements := []string{"&", "&amp;", "<", "&lt;", "\r", "&#xD;"}
+	if attribute {
+		replacements = append(replacements, `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;")
+	} else {
+		replacements = append(replacements, ">", "&gt;")
+	}
+	return strings.NewReplacer(replacements...).Replace(value)
+}
+
+// nfceElement recorta o primeiro elemento com o nome informado (sem prefixo) do documento
+func nfceElement(document []byte, name string) ([]byte, error) {
+	start := -1
+	for _, opening := range []string{"<" + name + " ", "<" + name + ">"} {
+		if i := bytes.Index(document, []byte(opening)); i >= 0 && (start < 0 || i < start) {
+			start = i
+		}
+	}
+	end := bytes.Index(document, []byte("</"+name+">"))
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("element %s not found", name)
+	}
+	return document[start : end+len(name)+3], nil
+}
+
+// BuildNfeProc junta a NFC-e assinada e o protocolo de autorização (nfeProc distribuído ao consumidor)
+func BuildNfeProc(signed, protocol []byte) []byte {
+	nfe, err := nfceElement(signed, "NFe")
+	if err != nil {
+		nfe = signed
+	}
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header[:len(xml.Header)-1])
+	buf.WriteString(`<nfeProc xmlns="` + nfeNamespace + `" versao="` + nfceLayoutVersion + `">`)
+	buf.Write(nfe)
+	buf.Write(protocol)
+	buf.WriteString("</nfeProc>")
+	return buf.Bytes()
+}
+
+type nfceCancelEvent struct {
+	XMLName    xml.Name `xml:"infEvento"`
+	Id         string   `xml:"Id,attr"`
+	COrgao     string   `xml:"cOrgao"`
+	TpAmb      int      `xml:"tpAmb"`
+	CNPJ       string   `xml:"CNPJ"`
+	ChNFe      string   `xml:"chNFe"`
+	DhEvento   string   `xml:"dhEvento"`
+	TpEvento   string   `xml:"tpEvento"`
+	NSeqEvento int      `xml:"nSeqEvento"`
+	VerEvento  string   `xml:"verEvento"`
+	DetEvento  struct {
+		Versao     string `xml:"versao,attr"`
+		DescEvento string `xml:"descEvento"`
+		NProt      string `xml:"nProt"`
+		XJust      string `xml:"xJust"`
+	} `xml:"detEvento"`
+}
+
+// BuildNfceCancelEvent evento de cancelamento (110111) assinado
+func BuildNfceCancelEvent(accessKey string, environment int, protocol, reason string, at time.Time, certificate *NfceCertificate) ([]byte, error) {
+	if len(accessKey) != 44 {
+		return nil, errors.New("invalid_nfce: invalid access key")
+	}
+	reason = nfceText(reason, 255)
+	if len(reason) < 15 {
+		return nil, errors.New("invalid_nfce: cancel reason must have at least 15 characters")
+	}
+
+	event := nfceCancelEvent{
+		Id:         "ID110111" + accessKey + "01",
+		COrgao:     accessKey[:2],
+		TpAmb:      environment,
+		CNPJ:       accessKey[6:20],
+		ChNFe:      accessKey,
+		DhEvento:   at.In(nfceLocation()).Format("2006-01-02T15:04:05-07:00"),
+		TpEvento:   "110111",
+		NSeqEvento: 1,
+		VerEvento:  "1.00",
+	}
+	event.DetEvento.Versao = "1.00"
+	event.DetEvento.DescEvento = "Cancelamento"
+	event.DetEvento.NProt = protocol
+	event.DetEvento.XJust = reason
+
+	fragment, err := xml.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	signature, body, err := signNfceElement(fragment, event.Id, certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header[:len(xml.Header)-1])
+	buf.WriteString(`<evento xmlns="` + nfeNamespace + `" versao="1.00">`)
+	buf.Write(body)
+	buf.WriteString(signature)
+	buf.WriteString("</evento>")
+	return buf.Bytes(), nil
+}
+
+// --- Formatação ---
+
+// nfceLocation fuso de emissão (horário de Brasília)
+func nfceLocation() *time.Location {
+	location, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		return time.FixedZone("BRT", -3*60*60)
+	}
+	return location
+}
+
+// nfceRandomCode cNF: 8 dígitos aleatórios diferentes do número da nota
+func nfceRandomCode(number int) string {
+	for {
+		n, err := rand.Int(rand.Reader, big.NewInt(100000000))
+		if err != nil {
+			return fmt.Sprintf("%08d", time.Now().UnixNano()%100000000)
+		}
+		code := fmt.Sprintf("%08d", n.Int64())
+		if n.Int64() != int64(number) {
+			return code
+		}
+	}
+}
+
+func nfceCents(value float64) int64 {
+	return int64(math.Round(value * 100))
+}
+
+func nfceMoney(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+func nfceDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// nfceText remove espaços extras e quebras de linha e limita o tamanho
+func nfceText(value string, maxLen int) string {
+	value = strings.Join(strings.Fields(value), " ")
+	runes := []rune(value)
+	if len(runes) > maxLen {
+		value = strings.TrimSpace(string(runes[:maxLen]))
+	}
+	return value
+}
+
+// nfcePhone telefone com DDD, sem o código do país (6 a 14 dígitos)
+func nfcePhone(phone string) string {
+	digits := strings.TrimPrefix(onlyDigits(phone), "55")
+	if len(digits) < 6 || len(digits) > 14 {
+		return ""
+	}
+	return digits
+}
+
+func onlyDigits(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// onlyDigitsOr dígitos do valor ou o texto alternativo (ex: IE "ISENTO")
+func onlyDigitsOr(value, alternative string) string {
+	if strings.EqualFold(strings.TrimSpace(value), alternative) {
+		return alternative
+	}
+	return onlyDigits(value)
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/pkcs12"
+)
+
+// NfceCertificate certificado A1 (e-CNPJ) usado para assinar os documentos fiscais
+type NfceCertificate struct {
+	PrivateKey  *rsa.PrivateKey
+	Certificate *x509.Certificate
+}
+
+var (
+	nfceTestCertificate     *NfceCertificate
+	nfceTestCertificateErr  error
+	nfceTestCertificateOnce sync.Once
+)
+
+// LoadNfceCertificate lê o certificado A1 (.pfx/.p12) do arquivo.
+// A cadeia de certificação no arquivo é ignorada; vale o certificado do par da chave privada.
+func LoadNfceCertificate(path, password string) (*NfceCertificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid_certificate: %w", err)
+	}
+	blocks, err := pkcs12.ToPEM(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("invalid_certificate: %w", err)
+	}
+
+	var key *rsa.PrivateKey
+	var certificates []*x509.Certificate
+	for _, block := range blocks {
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid_certificate: private key must be RSA: %w", err)
+			}
+		case "CERTIFICATE":
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid_certificate: %w", err)
+			}
+			certificates = append(certificates, certificate)
+		}
+	}
+	if key == nil {
+		return nil, errors.New("invalid_certificate: file has no private key")
+	}
+
+	for _, certificate := range certificates {
+		publicKey, ok := certificate.PublicKey.(*rsa.PublicKey)
+		if !ok || !publicKey.Equal(&key.PublicKey) {
+			continue
+		}
+		if time.Now().After(certificate.NotAfter) {
+			return nil, fmt.Errorf("invalid_certificate: certificate expired at %s", certificate.NotAfter.Format("2006-01-02"))
+		}
+		return &NfceCertificate{PrivateKey: key, Certificate: certificate}, nil
+	}
+	return nil, errors.New("invalid_certificate: no certificate matches the private key")
+}
+
+// NfceTestCertificate certificado autoassinado gerado em memória para homologação local (provedor mock)
+func NfceTestCertificate() (*NfceCertificate, error) {
+	nfceTestCertificateOnce.Do(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			nfceTestCertificateErr = err
+			return
+		}
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(time.Now().UnixNano()),
+			Subject:      pkix.Name{CommonName: "LEP CERTIFICADO DE TESTE", Organization: []string{"LEP"}},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().AddDate(1, 0, 0),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		if err != nil {
+			nfceTestCertificateErr = err
+			return
+		}
+		certificate, err := x509.ParseCertificate(der)
+		if err != nil {
+			nfceTestCertificateErr = err
+			return
+		}
+		nfceTestCertificate = &NfceCertificate{PrivateKey: key, Certificate: certificate}
+	})
+	return nfceTestCertificate, nfceTestCertificateErr
+}
@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"encoding/xml"
+	"fmt"
+	"lep/config"
+	"sync"
+	"time"
+)
+
+// NfceTransmission documento assinado enviado à SEFAZ (NFC-e ou evento de cancelamento)
+type NfceTransmission struct {
+	AccessKey   string
+	Environment int
+	XML         []byte
+	Certificate *NfceCertificate // TLS mútuo com o webservice da SEFAZ
+}
+
+// NfceResponse retorno da SEFAZ
+type NfceResponse struct {
+	StatusCode    string    // cStat: 100 autorizado, 135 evento registrado; demais são rejeições
+	StatusMessage string    // xMotivo
+	Protocol      string    // nProt
+	ReceivedAt    time.Time // dhRecbto
+	ProtocolXML   []byte    // protNFe (autorização) ou retEvento (cancelamento)
+}
+
+// Authorized NFC-e autorizada ou evento registrado
+func (r *NfceResponse) Authorized() bool {
+	return r.StatusCode == "100" || r.StatusCode == "135" || r.StatusCode == "155"
+}
+
+// NfceProvider transmissão para a SEFAZ (webservice da UF, SVRS ou integrador fiscal).
+// Erros de comunicação voltam como error; rejeições voltam no NfceResponse.
+type NfceProvider interface {
+	Name() string
+	Authorize(transmission NfceTransmission) (*NfceResponse, error)
+	Cancel(transmission NfceTransmission) (*NfceResponse, error)
+}
+
+var (
+	nfceProviders   = make(map[string]NfceProvider)
+	nfceProvidersMu sync.RWMutex
+)
+
+// RegisterNfceProvider registra um provedor de transmissão pelo nome
+func RegisterNfceProvider(provider NfceProvider) {
+	nfceProvidersMu.Lock()
+	defer nfceProvidersMu.Unlock()
+	nfceProviders[provider.Name()] = provider
+}
+
+// GetNfceProvider busca provedor registrado
+func GetNfceProvider(name string) (NfceProvider, error) {
+	nfceProvidersMu.RLock()
+	defer nfceProvidersMu.RUnlock()
+	provider, exists := nfceProviders[name]
+	if !exists {
+		return nil, fmt.Errorf("nfce provider %s not registered", name)
+	}
+	return provider, nil
+}
+
+func init() {
+	// SEFAZ simulada fica disponível apenas fora de produção
+	if config.ENV != "prod" {
+		RegisterNfceProvider(NewMockSefaz())
+	}
+}
+
+// MockSefaz SEFAZ local para testes: confere a assinatura e responde como o webservice de autorização.
+// Rejeita assinatura inválida (297), CNPJ do emitente inválido (207), duplicidade (204)
+// e cancelamento fora do prazo de 30 minutos (501).
+type MockSefaz struct {
+	mu         sync.Mutex
+	sequence   int
+	authorized map[string]mockSefazNote
+}
+
+type mockSefazNote struct {
+	protocol     string
+	authorizedAt time.Time
+	cancelled    bool
+}
+
+// NewMockSefaz cria a SEFAZ simulada
+func NewMockSefaz() *MockSefaz {
+	return &MockSefaz{authorized: make(map[string]mockSefazNote)}
+}
+
+func (m *MockSefaz) Name() string {
+	return "mock"
+}
+
+func (m *MockSefaz) Authorize(transmission NfceTransmission) (*NfceResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	key := transmission.AccessKey
+	_, digest, err := VerifyNfceSignature(transmission.XML, "infNFe")
+	if err != nil {
+		return m.reply(key, transmission.Environment, "297", "Rejeição: Assinatura difere do calculado", "", digest, now), nil
+	}
+	if len(key) != 44 || !ValidCNPJ(key[6:20]) {
+		return m.reply(key, transmission.Environment, "207", "Rejeição: CNPJ do emitente inválido", "", digest, now), nil
+	}
+	if note, exists := m.authorized[key]; exists {
+		return m.reply(key, transmission.Environment, "204", "Rejeição: Duplicidade de NF-e [nProt:"+note.protocol+"]", "", digest, now), nil
+	}
+
+	protocol := m.nextProtocol(key, transmission.Environment, now)
+	m.authorized[key] = mockSefazNote{protocol: protocol, authorizedAt: now}
+	return m.reply(key, transmission.Environment, "100", "Autorizado o uso da NF-e", protocol, digest, now), nil
+}
+
+func (m *MockSefaz) Cancel(transmission NfceTransmission) (*NfceResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	key := transmission.AccessKey
+	if _, _, err := VerifyNfceSignature(transmission.XML, "infEvento"); err != nil {
+		return m.replyEvent(key, transmission.Environment, "297", "Rejeição: Assinatura difere do calculado", "", now), nil
+	}
+	note, exists := m.authorized[key]
+	if !exists {
+		return m.replyEvent(key, transmission.Environment, "217", "Rejeição: NF-e não consta na base de dados da SEFAZ", "", now), nil
+	}
+	if note.cancelled {
+		return m.replyEvent(key, transmission.Environment, "573", "Rejeição: Duplicidade de evento", "", now), nil
+	}
+	if now.Sub(note.authorizedAt) > 30*time.Minute {
+		return m.replyEvent(key, transmission.Environment, "501", "Rejeição: Prazo de cancelamento superior ao previsto na Legislação", "", now), nil
+	}
+
+	note.cancelled = true
+	m.authorized[key] = note
+	protocol := m.nextProtocol(key, transmission.Environment, now)
+	return m.replyEvent(key, transmission.Environment, "135", "Evento registrado e vinculado a NF-e", protocol, now), nil
+}
+
+// nextProtocol número de protocolo de 15 dígitos: ambiente, UF, ano e sequencial
+func (m *MockSefaz) nextProtocol(key string, environment int, now time.Time) string {
+	m.sequence++
+	return fmt.Sprintf("%d%s%s%010d", environment, key[:2], now.Format("06"), m.sequence)
+}
+
+type mockSefazProtocol struct {
+	XMLName  xml.Name `xml:"protNFe"`
+	Versao   string   `xml:"versao,attr"`
+	TpAmb    int      `xml:"infProt>tpAmb"`
+	VerAplic string   `xml:"infProt>verAplic"`
+	ChNFe    string   `xml:"infProt>chNFe"`
+	DhRecbto string   `xml:"infProt>dhRecbto"`
+	NProt    string   `xml:"infProt>nProt,omitempty"`
+	DigVal   string   `xml:"infProt>digVal,omitempty"`
+	CStat    string   `xml:"infProt>cStat"`
+	XMotivo  string   `xml:"infProt>xMotivo"`
+}
+
+type mockSefazEvent struct {
+	XMLName     xml.Name `xml:"retEvento"`
+	Versao      string   `xml:"versao,attr"`
+	TpAmb       int      `xml:"infEvento>tpAmb"`
+	VerAplic    string   `xml:"infEvento>verAplic"`
+	COrgao      string   `xml:"infEvento>cOrgao"`
+	CStat       string   `xml:"infEvento>cStat"`
+	XMotivo     string   `xml:"infEvento>xMotivo"`
+	ChNFe       string   `xml:"infEvento>chNFe"`
+	TpEvento    string   `xml:"infEvento>tpEvento"`
+	NSeqEvento  int      `xml:"infEvento>nSeqEvento"`
+	DhRegEvento string   `xml:"infEvento>dhRegEvento"`
+	NProt       string   `xml:"infEvento>nProt,omitempty"`
+}
+
+func (m *MockSefaz) reply(key string, environment int, status, message, protocol, digest string, now time.Time) *NfceResponse {
+	protocolXML, _ := xml.Marshal(mockSefazProtocol{
+		Versao: nfceLayoutVersion, TpAmb: environment, VerAplic: "MOCK-SEFAZ", ChNFe: key,
+		DhRecbto: now.In(nfceLocation()).Format("2006-01-02T15:04:05-07:00"),
+		NProt:    protocol, DigVal: digest, CStat: status, XMotivo: message,
+	})
+	return &NfceResponse{StatusCode: status, StatusMessage: message, Protocol: protocol, ReceivedAt: now, ProtocolXML: protocolXML}
+}
+
+func (m *MockSefaz) replyEvent(key string, environment int, status, message, protocol string, now time.Time) *NfceResponse {
+	organ := ""
+	if len(key) >= 2 {
+		organ = key[:2]
+	}
+	eventXML, _ := xml.Marshal(mockSefazEvent{
+		Versao: "1.00", TpAmb: environment, VerAplic: "MOCK-SEFAZ", COrgao: organ,
+		CStat: status, XMotivo: message, ChNFe: key, TpEvento: "110111", NSeqEvento: 1,
+		DhRegEvento: now.In(nfceLocation()).Format("2006-01-02T15:04:05-07:00"),
+		NProt:       protocol,
+	})
+	return &NfceResponse{StatusCode: status, StatusMessage: message, Protocol: protocol, ReceivedAt: now, ProtocolXML: eventXML}
+}
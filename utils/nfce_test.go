@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"bytes"
+	"lep/config"
+	"lep/repositories/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func testNfceInput() NfceInput {
+	code := "001"
+	return NfceInput{
+		Organization: &models.Organization{
+			Name:              "Cantina Teste",
+			LegalName:         "Cantina Teste Ltda",
+			Cnpj:              "11.222.333/0001-81",
+			StateRegistration: "123456789012",
+			TaxRegime:         1,
+			CityCode:          "3550308",
+			FiscalAddress: &models.Address{
+				Street:       "Rua das Flores",
+				Number:       "100",
+				Neighborhood: "Centro",
+				City:         "Sao Paulo",
+				State:        "SP",
+				ZipCode:      "01001-000",
+			},
+		},
+		Environment: models.FiscalEnvironmentHomologation,
+		Series:      1,
+		Number:      42,
+		IssuedAt:    time.Now(),
+		Items: []NfceItem{
+			{
+				Product:   models.Product{Id: uuid.New(), Name: "Lasanha", PDVCode: &code, NCM: "21069090"},
+				Name:      "Lasanha",
+				Quantity:  2,
+				UnitPrice: 39.9,
+			},
+		},
+		Payments: []models.FiscalPayment{{Method: models.FiscalPaymentPix, Amount: 79.8}},
+		CscId:    "1",
+		Csc:      "0123456789ABCDEF0123",
+	}
+}
+
+// Emissão completa: monta e assina, confere a assinatura e autoriza na SEFAZ simulada
+func TestNfceIssueSignAuthorize(t *testing.T) {
+	certificate, err := NfceTestCertificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := BuildNfce(testNfceInput(), certificate)
+	if err != nil {
+		t.Fatalf("BuildNfce: %v", err)
+	}
+	if len(result.AccessKey) != 44 {
+		t.Fatalf("access key %q must have 44 digits", result.AccessKey)
+	}
+
+	if _, _, err := VerifyNfceSignature(result.XML, "infNFe"); err != nil {
+		t.Fatalf("VerifyNfceSignature: %v", err)
+	}
+
+	sefaz := NewMockSefaz()
+	transmission := NfceTransmission{
+		AccessKey:   result.AccessKey,
+		Environment: models.FiscalEnvironmentHomologation,
+		XML:         result.XML,
+		Certificate: certificate,
+	}
+	response, err := sefaz.Authorize(transmission)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	if !response.Authorized() || response.Protocol == "" {
+		t.Fatalf("expected authorization, got %s %s", response.StatusCode, response.StatusMessage)
+	}
+
+	// Reenvio da mesma chave é duplicidade
+	response, err = sefaz.Authorize(transmission)
+	if err != nil {
+		t.Fatalf("Authorize again: %v", err)
+	}
+	if response.StatusCode != "204" {
+		t.Fatalf("expected 204 for duplicate, got %s", response.StatusCode)
+	}
+
+	// Conteúdo alterado depois da assinatura é rejeitado
+	tampered := bytes.Replace(result.XML, []byte("<vNF>79.80</vNF>"), []byte("<vNF>7.98</vNF>"), 1)
+	if bytes.Equal(tampered, result.XML) {
+		t.Fatal("total not found in the document")
+	}
+	if _, _, err := VerifyNfceSignature(tampered, "infNFe"); err == nil {
+		t.Fatal("expected signature error for tampered document")
+	}
+	response, err = NewMockSefaz().Authorize(NfceTransmission{AccessKey: result.AccessKey, Environment: 2, XML: tampered})
+	if err != nil {
+		t.Fatalf("Authorize tampered: %v", err)
+	}
+	if response.StatusCode != "297" {
+		t.Fatalf("expected 297 for tampered document, got %s", response.StatusCode)
+	}
+}
+
+// Fora de dev a emissão exige o schema oficial
+func TestNfceSchemaRequiredOutsideDev(t *testing.T) {
+	env, schemaPath := config.ENV, config.NFCE_SCHEMA_PATH
+	defer func() { config.ENV, config.NFCE_SCHEMA_PATH = env, schemaPath }()
+
+	config.ENV, config.NFCE_SCHEMA_PATH = "prod", ""
+	if err := ValidateNfceSchema([]byte("<NFe/>")); err == nil || !strings.Contains(err.Error(), "nfce_not_configured") {
+		t.Fatalf("expected nfce_not_configured without schema, got %v", err)
+	}
+
+	config.NFCE_SCHEMA_PATH = "testdata/missing/nfe_v4.00.xsd"
+	if err := ValidateNfceSchema([]byte("<NFe/>")); err == nil || !strings.Contains(err.Error(), "nfce_not_configured") {
+		t.Fatalf("expected nfce_not_configured with missing schema, got %v", err)
+	}
+
+	config.ENV, config.NFCE_SCHEMA_PATH = "dev", ""
+	if err := ValidateNfceSchema([]byte("<NFe/>")); err != nil {
+		t.Fatalf("dev without schema should use only the internal validations, got %v", err)
+	}
+}
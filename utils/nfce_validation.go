@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"lep/config"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// Padrões do schema oficial (tiposBasico_v4.00.xsd) para os campos preenchidos pelo emissor
+var (
+	nfceDigitsPattern = func(min, max int) *regexp.Regexp {
+		return regexp.MustCompile(fmt.Sprintf(`^[0-9]{%d,%d}$`, min, max))
+	}
+	nfceCnpjPattern     = nfceDigitsPattern(14, 14)
+	nfceCpfPattern      = nfceDigitsPattern(11, 11)
+	nfceIEPattern       = regexp.MustCompile(`^([0-9]{2,14}|ISENTO)$`)
+	nfceCityPattern     = nfceDigitsPattern(7, 7)
+	nfceZipPattern      = nfceDigitsPattern(8, 8)
+	nfceNCMPattern      = regexp.MustCompile(`^([0-9]{2}|[0-9]{8})$`)
+	nfceCESTPattern     = nfceDigitsPattern(7, 7)
+	nfceMoneyPattern    = regexp.MustCompile(`^(0|0\.[0-9]{2}|[1-9][0-9]{0,12}(\.[0-9]{2})?)$`)
+	nfceCscIdPattern    = nfceDigitsPattern(1, 6)
+	nfceUnitPattern     = regexp.MustCompile(`^\S(.{0,4}\S)?$`)
+	nfceAllowedCFOP     = map[string]bool{"5101": true, "5102": true, "5103": true, "5104": true, "5115": true, "5405": true, "5656": true, "5667": true, "5933": true}
+	nfceAllowedPayments = map[string]bool{"01": true, "02": true, "03": true, "04": true, "05": true, "10": true, "11": true, "12": true, "13": true, "15": true, "16": true, "17": true, "18": true, "19": true, "90": true, "99": true}
+)
+
+// validateNfceInfo confere o infNFe contra as regras do schema e as regras de validação da NFC-e
+// que dependem apenas do cadastro (os erros apontam o campo para corrigir no produto ou na organização)
+func validateNfceInfo(info *nfceInfNFe) error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+	length := func(value string, min, max int) bool {
+		size := len([]rune(value))
+		return size >= min && size <= max
+	}
+
+	emit := info.Emit
+	check(nfceCnpjPattern.MatchString(emit.CNPJ) && ValidCNPJ(emit.CNPJ), "emitter cnpj is invalid")
+	check(length(emit.XNome, 2, 60), "emitter legal name must have 2 to 60 characters")
+	check(nfceIEPattern.MatchString(emit.IE), "emitter state registration must have 2 to 14 digits")
+	check(emit.CRT >= 1 && emit.CRT <= 4, "emitter tax regime (CRT) must be 1, 2, 3 or 4")
+	address := emit.EnderEmit
+	check(length(address.XLgr, 2, 60), "emitter street must have 2 to 60 characters")
+	check(length(address.Nro, 1, 60), "emitter address number is required")
+	check(length(address.XBairro, 2, 60), "emitter neighborhood must have 2 to 60 characters")
+	check(length(address.XMun, 2, 60), "emitter city must have 2 to 60 characters")
+	check(nfceCityPattern.MatchString(address.CMun) && strings.HasPrefix(address.CMun, info.Ide.CUF), "emitter city code must be the 7-digit IBGE code of a city in %s", address.UF)
+	check(address.CEP == "" || nfceZipPattern.MatchString(address.CEP), "emitter zip code must have 8 digits")
+
+	check(info.Ide.Serie >= 0 && info.Ide.Serie <= 999, "series must be between 0 and 999")
+	check(info.Ide.NNF >= 1 && info.Ide.NNF <= 999999999, "number must be between 1 and 999999999")
+	check(info.Ide.TpAmb == 1 || info.Ide.TpAmb == 2, "environment must be 1 (production) or 2 (homologation)")
+
+	if dest := info.Dest; dest != nil {
+		if dest.CNPJ != "" {
+			check(ValidCNPJ(dest.CNPJ), "customer cnpj is invalid")
+		} else {
+			check(nfceCpfPattern.MatchString(dest.CPF) && ValidCPF(dest.CPF), "customer cpf is invalid")
+		}
+	}
+
+	check(len(info.Det) >= 1 && len(info.Det) <= 990, "document must have 1 to 990 items")
+	for _, det := range info.Det {
+		prod := det.Prod
+		label := fmt.Sprintf("item %d (%s)", det.NItem, prod.XProd)
+		check(length(prod.CProd, 1, 60), "%s: code is required", label)
+		check(length(prod.XProd, 1, 120), "%s: description must have 1 to 120 characters", label)
+		check(nfceNCMPattern.MatchString(prod.NCM), "%s: product ncm must have 8 digits", label)
+		check(prod.CEST == "" || nfceCESTPattern.MatchString(prod.CEST), "%s: product cest must have 7 digits", label)
+		check(nfceAllowedCFOP[prod.CFOP], "%s: cfop %s is not allowed in NFC-e", label, prod.CFOP)
+		check(nfceUnitPattern.MatchString(prod.UCom), "%s: fiscal unit must have 1 to 6 characters", label)
+		check(nfceMoneyPattern.MatchString(prod.VProd) && prod.VProd != "0.00", "%s: value must be greater than zero", label)
+	}
+
+	total := info.Total.ICMSTot
+	check(nfceMoneyPattern.MatchString(total.VNF), "document total is invalid")
+	check(len(info.Pag.DetPag) >= 1 && len(info.Pag.DetPag) <= 100, "document must have 1 to 100 payments")
+	for i, payment := range info.Pag.DetPag {
+		check(nfceAllowedPayments[payment.TPag], "payment %d: method %s is invalid", i+1, payment.TPag)
+		check(payment.TPag != "99" || length(payment.XPag, 2, 60), "payment %d: description is required for method 99", i+1)
+		check(nfceMoneyPattern.MatchString(payment.VPag), "payment %d: amount is invalid", i+1)
+	}
+	if info.InfAdic != nil {
+		check(length(info.InfAdic.InfCpl, 1, 5000), "additional info must have up to 5000 characters")
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid_nfce: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// ValidateNfceCsc confere o identificador e o código de segurança do contribuinte (QR Code)
+func ValidateNfceCsc(cscId, csc string) error {
+	if !nfceCscIdPattern.MatchString(cscId) || len(csc) < 16 || len(csc) > 36 {
+		return errors.New("nfce_not_configured: csc id (up to 6 digits) and csc (16 to 36 characters) are required")
+	}
+	return nil
+}
+
+// ValidateNfceSchema valida o XML com o schema oficial (nfe_v4.00.xsd) via xmllint.
+// Fora de dev o schema é obrigatório; em dev, sem NFCE_SCHEMA_PATH valem apenas as validações internas.
+func ValidateNfceSchema(document []byte) error {
+	if config.NFCE_SCHEMA_PATH == "" && config.IsDev() {
+		return nil
+	}
+	if err := NfceSchemaAvailable(); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("xmllint", "--noout", "--nonet", "--schema", config.NFCE_SCHEMA_PATH, "-")
+	cmd.Stdin = bytes.NewReader(document)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return fmt.Errorf("nfce_not_configured: schema validation unavailable: %w", err)
+		}
+		var problems []string
+		for _, line := range strings.Split(strings.TrimSpace(stderr.String()), "\n") {
+			if line != "" && !strings.HasSuffix(line, "fails to validate") {
+				problems = append(problems, strings.TrimPrefix(line, "-:"))
+			}
+		}
+		return errors.New("invalid_nfce: schema: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// NfceSchemaAvailable confere se o schema configurado e o xmllint estão presentes para validar a emissão
+func NfceSchemaAvailable() error {
+	if config.NFCE_SCHEMA_PATH == "" {
+		return errors.New("nfce_not_configured: NFCE_SCHEMA_PATH must point to the official nfe_v4.00.xsd")
+	}
+	if _, err := os.Stat(config.NFCE_SCHEMA_PATH); err != nil {
+		return fmt.Errorf("nfce_not_configured: schema %s not found", config.NFCE_SCHEMA_PATH)
+	}
+	if _, err := exec.LookPath("xmllint"); err != nil {
+		return errors.New("nfce_not_configured: xmllint is not installed")
+	}
+	return nil
+}
+
+// ValidCNPJ confere os dígitos verificadores do CNPJ
+func ValidCNPJ(cnpj string) bool {
+	if len(cnpj) != 14 || strings.Count(cnpj, cnpj[:1]) == 14 {
+		return false
+	}
+	digit := func(base string, weights []int) byte {
+		sum := 0
+		for i, w := range weights {
+			sum += int(base[i]-'0') * w
+		}
+		rest := sum % 11
+		if rest < 2 {
+			return '0'
+		}
+		return byte('0' + 11 - rest)
+	}
+	first := digit(cnpj, []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2})
+	second := digit(cnpj, []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2})
+	return cnpj[12] == first && cnpj[13] == second
+}
+
+// ValidCPF confere os dígitos verificadores do CPF
+func ValidCPF(cpf string) bool {
+	if len(cpf) != 11 || strings.Count(cpf, cpf[:1]) == 11 {
+		return false
+	}
+	for _, size := range []int{9, 10} {
+		sum := 0
+		for i := 0; i < size; i++ {
+			sum += int(cpf[i]-'0') * (size + 1 - i)
+		}
+		digit := sum * 10 % 11
+		if digit == 10 {
+			digit = 0
+		}
+		if cpf[size] != byte('0'+digit) {
+			return false
+		}
+	}
+	return true
+}
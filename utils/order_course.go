@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"fmt"
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderCourseSequence ordem em que os tempos saem da cozinha
+var OrderCourseSequence = []string{models.OrderCourseStarter, models.OrderCourseMain, models.OrderCourseDessert}
+
+// courseRank posição do tempo na sequência (-1 para item sem tempo)
+func courseRank(course string) int {
+	for i, c := range OrderCourseSequence {
+		if c == course {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsValidOrderCourse verifica se o tempo existe (vazio = item sai direto para a cozinha)
+func IsValidOrderCourse(course string) bool {
+	return course == "" || courseRank(course) >= 0
+}
+
+// ValidateOrderCourses rejeita tempos desconhecidos com prefixo "invalid_items:"
+func ValidateOrderCourses(items models.OrderItems) error {
+	for i, item := range items {
+		if !IsValidOrderCourse(item.Course) {
+			return fmt.Errorf("invalid_items: item %d has unknown course %q (allowed: entrada, principal, sobremesa)", i, item.Course)
+		}
+	}
+	return nil
+}
+
+// ApplyCourseHolds decide quais itens novos vão para a cozinha e quais ficam retidos.
+// Itens sem tempo e o primeiro tempo do pedido saem na hora; tempos seguintes aguardam o disparo.
+// Itens já gravados (snapshots) mantêm tempo e situação: só saem da retenção por FireCourse.
+func ApplyCourseHolds(order *models.Order, snapshots map[uuid.UUID]models.OrderItem, now time.Time) {
+	firedRank, firstRank := -1, -1
+	heldCourses := make(map[string]bool)
+	for i := range order.Items {
+		item := &order.Items[i]
+		if snapshot, ok := snapshots[item.Id]; ok && item.Id != uuid.Nil {
+			item.Course = snapshot.Course
+			item.FiredAt = snapshot.FiredAt
+			if snapshot.Status == models.OrderItemStatusHeld {
+				item.Status = models.OrderItemStatusHeld
+			}
+		}
+
+		rank := courseRank(item.Course)
+		if rank < 0 {
+			continue
+		}
+		if item.Status == models.OrderItemStatusHeld {
+			heldCourses[item.Course] = true
+		} else if item.FiredAt != nil && rank > firedRank {
+			firedRank = rank
+		}
+		if firstRank < 0 || rank < firstRank {
+			firstRank = rank
+		}
+	}
+	if firedRank < 0 {
+		firedRank = firstRank
+	}
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		if _, ok := snapshots[item.Id]; ok && item.Id != uuid.Nil {
+			continue
+		}
+
+		rank := courseRank(item.Course)
+		if rank >= 0 && (heldCourses[item.Course] || rank > firedRank) {
+			item.Status = models.OrderItemStatusHeld
+			item.FiredAt = nil
+			continue
+		}
+		if item.Status == "" || item.Status == models.OrderItemStatusHeld {
+			item.Status = models.OrderItemStatusQueued
+		}
+		item.FiredAt = &now
+	}
+}
+
+// HasHeldItems indica se ainda há tempos aguardando disparo
+func HasHeldItems(items models.OrderItems) bool {
+	for _, item := range items {
+		if item.Status == models.OrderItemStatusHeld {
+			return true
+		}
+	}
+	return false
+}
+
+// KitchenItems itens liberados para a cozinha (sem os tempos retidos)
+func KitchenItems(items models.OrderItems) []models.OrderItem {
+	result := make([]models.OrderItem, 0, len(items))
+	for _, item := range items {
+		if item.Status != models.OrderItemStatusHeld {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// NextHeldCourse primeiro tempo retido na sequência ("" se não houver)
+func NextHeldCourse(items models.OrderItems) string {
+	next := ""
+	for _, item := range items {
+		if item.Status != models.OrderItemStatusHeld {
+			continue
+		}
+		if next == "" || courseRank(item.Course) < courseRank(next) {
+			next = item.Course
+		}
+	}
+	return next
+}
+
+// FireCourse libera para a cozinha os itens retidos do tempo e retorna os itens disparados
+func FireCourse(order *models.Order, course string, firedBy *uuid.UUID, now time.Time) []models.OrderItem {
+	var fired []models.OrderItem
+	for i := range order.Items {
+		item := &order.Items[i]
+		if item.Course != course || item.Status != models.OrderItemStatusHeld {
+			continue
+		}
+		item.Status = models.OrderItemStatusQueued
+		item.FiredAt = &now
+		fired = append(fired, *item)
+	}
+	if len(fired) == 0 {
+		return nil
+	}
+
+	order.UpdatedAt = now
+	SyncOrderCourses(order)
+	for i := range order.Courses {
+		if order.Courses[i].Course == course {
+			order.Courses[i].FiredBy = firedBy
+		}
+	}
+	return fired
+}
+
+// SyncOrderCourses recalcula situação e horários de cada tempo a partir dos itens
+// (o disparo, o início do primeiro item e quando o último item ficou pronto)
+func SyncOrderCourses(order *models.Order) {
+	firedBy := make(map[string]*uuid.UUID)
+	for _, course := range order.Courses {
+		firedBy[course.Course] = course.FiredBy
+	}
+
+	courses := make(models.OrderCourses, 0, len(OrderCourseSequence))
+	for _, name := range OrderCourseSequence {
+		course := models.OrderCourse{Course: name, FiredBy: firedBy[name]}
+		held, ready, started := 0, 0, false
+		var lastReady *time.Time
+		for _, item := range order.Items {
+			if item.Course != name {
+				continue
+			}
+			course.Items++
+			switch item.Status {
+			case models.OrderItemStatusHeld:
+				held++
+			case models.OrderItemStatusReady:
+				ready++
+				started = true
+			case models.OrderItemStatusPreparing:
+				started = true
+			}
+			course.FiredAt = earliest(course.FiredAt, item.FiredAt)
+			course.StartedAt = earliest(course.StartedAt, item.StartedAt)
+			if item.ReadyAt != nil && (lastReady == nil || item.ReadyAt.After(*lastReady)) {
+				lastReady = item.ReadyAt
+			}
+		}
+		if course.Items == 0 {
+			continue
+		}
+
+		switch {
+		case held > 0:
+			course.Status = models.OrderItemStatusHeld
+		case ready == course.Items:
+			course.Status = models.OrderItemStatusReady
+			course.ReadyAt = lastReady
+		case started:
+			course.Status = models.OrderItemStatusPreparing
+		default:
+			course.Status = models.OrderItemStatusQueued
+		}
+		courses = append(courses, course)
+	}
+	order.Courses = courses
+}
+
+func earliest(current, candidate *time.Time) *time.Time {
+	if candidate == nil {
+		return current
+	}
+	if current == nil || candidate.Before(*current) {
+		value := *candidate
+		return &value
+	}
+	return current
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"fmt"
+	"lep/repositories/models"
+
+	"github.com/google/uuid"
+)
+
+// ApplyItemModifiers valida as opções escolhidas em cada item contra os grupos do produto
+// e preenche nome e preço de cada modificador a partir do cadastro (o valor enviado pelo cliente é ignorado).
+// Itens presentes em snapshots mantêm os modificadores já gravados, como o preço unitário.
+// Erros retornam com prefixo "invalid_modifiers:".
+func ApplyItemModifiers(items models.OrderItems, products []models.Product, snapshots map[uuid.UUID]models.OrderItem) error {
+	productMap := make(map[uuid.UUID]models.Product)
+	for _, product := range products {
+		productMap[product.Id] = product
+	}
+
+	for i := range items {
+		frozen := frozenModifiers(items[i], snapshots)
+		if keepsModifiers(items[i].Modifiers, frozen) {
+			// Item já gravado sem mudança nas opções: nada é revalidado nem reprecificado
+			for j := range items[i].Modifiers {
+				items[i].Modifiers[j] = frozen[items[i].Modifiers[j].OptionId]
+			}
+			continue
+		}
+
+		product, exists := productMap[items[i].ProductId]
+		if !exists {
+			if len(items[i].Modifiers) > 0 {
+				return fmt.Errorf("invalid_modifiers: product %s not found", items[i].ProductId)
+			}
+			continue
+		}
+
+		if err := applyProductModifiers(&items[i], product, frozen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// frozenModifiers modificadores gravados do item já existente, por opção (nil para item novo)
+func frozenModifiers(item models.OrderItem, snapshots map[uuid.UUID]models.OrderItem) map[uuid.UUID]models.OrderItemModifier {
+	snapshot, exists := snapshotItem(item, snapshots)
+	if !exists {
+		return nil
+	}
+	frozen := make(map[uuid.UUID]models.OrderItemModifier, len(snapshot.Modifiers))
+	for _, modifier := range snapshot.Modifiers {
+		frozen[modifier.OptionId] = modifier
+	}
+	return frozen
+}
+
+// keepsModifiers indica se o item manteve exatamente as opções gravadas
+func keepsModifiers(modifiers []models.OrderItemModifier, frozen map[uuid.UUID]models.OrderItemModifier) bool {
+	if frozen == nil || len(modifiers) != len(frozen) {
+		return false
+	}
+	seen := make(map[uuid.UUID]bool, len(modifiers))
+	for _, modifier := range modifiers {
+		kept, exists := frozen[modifier.OptionId]
+		if !exists || kept.GroupId != modifier.GroupId || seen[modifier.OptionId] {
+			return false
+		}
+		seen[modifier.OptionId] = true
+	}
+	return true
+}
+
+func applyProductModifiers(item *models.OrderItem, product models.Product, frozen map[uuid.UUID]models.OrderItemModifier) error {
+	groupMap := make(map[uuid.UUID]models.ProductOptionGroup)
+	for _, group := range product.OptionGroups {
+		groupMap[group.Id] = group
+	}
+
+	counts := make(map[uuid.UUID]int)
+	chosen := make(map[uuid.UUID]bool)
+	for j := range item.Modifiers {
+		modifier := &item.Modifiers[j]
+
+		// Opção já aceita no pedido mantém nome e preço gravados, mesmo que o cadastro tenha mudado
+		if kept, exists := frozen[modifier.OptionId]; exists && kept.GroupId == modifier.GroupId {
+			if chosen[kept.OptionId] {
+				return fmt.Errorf("invalid_modifiers: option %s selected more than once", kept.OptionName)
+			}
+			chosen[kept.OptionId] = true
+			*modifier = kept
+			counts[kept.GroupId]++
+			continue
+		}
+
+		group, exists := groupMap[modifier.GroupId]
+		if !exists {
+			return fmt.Errorf("invalid_modifiers: option group %s does not belong to product %s", modifier.GroupId, product.Name)
+		}
+
+		var option *models.ProductOption
+		for k := range group.Options {
+			if group.Options[k].Id == modifier.OptionId {
+				option = &group.Options[k]
+				break
+			}
+		}
+		if option == nil || !option.Active {
+			return fmt.Errorf("invalid_modifiers: option %s is not available in group %s", modifier.OptionId, group.Name)
+		}
+		if chosen[option.Id] {
+			return fmt.Errorf("invalid_modifiers: option %s selected more than once", option.Name)
+		}
+		chosen[option.Id] = true
+
+		modifier.GroupName = group.Name
+		modifier.OptionName = option.Name
+		modifier.PriceDelta = option.PriceDelta
+		counts[group.Id]++
+	}
+
+	for _, group := range product.OptionGroups {
+		min := group.MinSelections
+		if group.Required && min < 1 {
+			min = 1
+		}
+		if counts[group.Id] < min {
+			return fmt.Errorf("invalid_modifiers: %s requires at least %d selection(s) in %s", product.Name, min, group.Name)
+		}
+		if group.MaxSelections > 0 && counts[group.Id] > group.MaxSelections {
+			return fmt.Errorf("invalid_modifiers: %s allows at most %d selection(s) in %s", product.Name, group.MaxSelections, group.Name)
+		}
+	}
+
+	return nil
+}
+
+// ModifiersDelta soma os acréscimos dos modificadores de um item (por unidade)
+func ModifiersDelta(item models.OrderItem) float64 {
+	delta := 0.0
+	for _, modifier := range item.Modifiers {
+		delta += modifier.PriceDelta
+	}
+	return delta
+}
+
+// CalculateOrderItemsTotal calcula o total do pedido incluindo modificadores
+func CalculateOrderItemsTotal(items models.OrderItems) float64 {
+	simplified := make([]OrderItem, 0, len(items))
+	for _, item := range items {
+		simplified = append(simplified, OrderItem{
+			Quantity:       item.Quantity,
+			Price:          item.Price,
+			ModifiersDelta: ModifiersDelta(item),
+		})
+	}
+	return CalculateOrderTotal(simplified)
+}
@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"fmt"
+	"lep/repositories/models"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// Variantes de preço de vinho
+const (
+	PriceVariantBottle     = "bottle"
+	PriceVariantHalfBottle = "half_bottle"
+	PriceVariantGlass      = "glass"
+)
+
+// priceTolerance tolerância de arredondamento na comparação de valores (centavos)
+const priceTolerance = 0.009
+
+// PriceMismatch item cujo preço enviado pelo cliente difere do preço atual do cadastro
+type PriceMismatch struct {
+	ItemIndex   int       `json:"item_index"`
+	ItemId      uuid.UUID `json:"item_id"`
+	ProductId   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Variant     string    `json:"variant,omitempty"`
+	ClientPrice float64   `json:"client_price"`
+	ServerPrice float64   `json:"server_price"`
+}
+
+// PriceMismatchError retornado quando o total enviado pelo cliente não confere com o total calculado
+type PriceMismatchError struct {
+	ClientTotal float64         `json:"client_total"`
+	ServerTotal float64         `json:"server_total"`
+	Items       []PriceMismatch `json:"items"`
+}
+
+func (e *PriceMismatchError) Error() string {
+	return fmt.Sprintf("price_mismatch: client total %.2f differs from server total %.2f", e.ClientTotal, e.ServerTotal)
+}
+
+// ResolveProductPrice retorna o preço unitário vigente do produto.
+// Vinhos aceitam as variantes bottle, half_bottle e glass; sem variante vale o preço normal/promocional.
+func ResolveProductPrice(product models.Product, variant string) (float64, error) {
+	if variant != "" {
+		if product.Type != "vinho" {
+			return 0, fmt.Errorf("invalid_items: variant %s is only allowed for wines (%s)", variant, product.Name)
+		}
+
+		var price *float64
+		switch variant {
+		case PriceVariantBottle:
+			price = product.PriceBottle
+		case PriceVariantHalfBottle:
+			price = product.PriceHalfBottle
+		case PriceVariantGlass:
+			price = product.PriceGlass
+		default:
+			return 0, fmt.Errorf("invalid_items: unknown variant %s", variant)
+		}
+		if price == nil || *price <= 0 {
+			return 0, fmt.Errorf("invalid_items: %s is not sold as %s", product.Name, variant)
+		}
+		return *price, nil
+	}
+
+	if product.UsePromo && product.PricePromo != nil && *product.PricePromo > 0 {
+		return *product.PricePromo, nil
+	}
+	if product.PriceNormal <= 0 {
+		return 0, fmt.Errorf("invalid_items: %s requires a variant (bottle, half_bottle or glass)", product.Name)
+	}
+	return product.PriceNormal, nil
+}
+
+// PriceOrderItems redefine preço unitário e nome de cada item a partir do cadastro atual.
+// Itens presentes em snapshots (já gravados no pedido) mantêm o preço e nome originais.
+// Produtos inexistentes, excluídos, inativos ou de outro projeto são rejeitados com prefixo "invalid_items:".
+// Retorna os itens cujo preço enviado pelo cliente diverge do preço calculado.
+func PriceOrderItems(order *models.Order, products []models.Product, snapshots map[uuid.UUID]models.OrderItem) ([]PriceMismatch, error) {
+	productMap := make(map[uuid.UUID]models.Product)
+	for _, product := range products {
+		if product.OrganizationId == order.OrganizationId && product.ProjectId == order.ProjectId {
+			productMap[product.Id] = product
+		}
+	}
+
+	mismatches := make([]PriceMismatch, 0)
+	for i := range order.Items {
+		item := &order.Items[i]
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("invalid_items: item %d must have a positive quantity", i)
+		}
+
+		if snapshot, isSnapshot := snapshotItem(*item, snapshots); isSnapshot {
+			// Item já existente: preço congelado no momento do pedido
+			clientPrice := item.Price
+			item.Price = snapshot.Price
+			item.ProductName = snapshot.ProductName
+			if clientPrice > 0 && math.Abs(clientPrice-item.Price) > priceTolerance {
+				mismatches = append(mismatches, PriceMismatch{
+					ItemIndex: i, ItemId: item.Id, ProductId: item.ProductId, ProductName: item.ProductName,
+					Variant: item.Variant, ClientPrice: clientPrice, ServerPrice: item.Price,
+				})
+			}
+			continue
+		}
+
+		product, exists := productMap[item.ProductId]
+		if !exists {
+			return nil, fmt.Errorf("invalid_items: product %s not found or deleted", item.ProductId)
+		}
+		if !product.Active {
+			return nil, fmt.Errorf("invalid_items: product %s is inactive", product.Name)
+		}
+
+		price, err := ResolveProductPrice(product, item.Variant)
+		if err != nil {
+			return nil, err
+		}
+
+		clientPrice := item.Price
+		item.Price = price
+		item.ProductName = product.Name
+		if clientPrice > 0 && math.Abs(clientPrice-price) > priceTolerance {
+			mismatches = append(mismatches, PriceMismatch{
+				ItemIndex: i, ItemId: item.Id, ProductId: item.ProductId, ProductName: product.Name,
+				Variant: item.Variant, ClientPrice: clientPrice, ServerPrice: price,
+			})
+		}
+	}
+
+	return mismatches, nil
+}
+
+// snapshotItem versão já gravada do item, quando ele continua com o mesmo produto e variante
+func snapshotItem(item models.OrderItem, snapshots map[uuid.UUID]models.OrderItem) (models.OrderItem, bool) {
+	snapshot, exists := snapshots[item.Id]
+	if !exists || item.Id == uuid.Nil || snapshot.ProductId != item.ProductId || snapshot.Variant != item.Variant {
+		return models.OrderItem{}, false
+	}
+	return snapshot, true
+}
+
+// TotalsMatch compara dois valores monetários com tolerância de arredondamento
+func TotalsMatch(a, b float64) bool {
+	return math.Abs(a-b) <= priceTolerance
+}
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"fmt"
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// orderStatusTransitions transições permitidas por status de origem.
+// Cancelamento só é possível antes do pedido ficar pronto.
+var orderStatusTransitions = map[string][]string{
+	models.OrderStatusAwaitingApproval: {models.OrderStatusPending, models.OrderStatusCancelled},
+	models.OrderStatusPending:          {models.OrderStatusPreparing, models.OrderStatusCancelled},
+	models.OrderStatusPreparing:        {models.OrderStatusReady, models.OrderStatusCancelled},
+	models.OrderStatusReady:            {models.OrderStatusDelivered},
+	models.OrderStatusDelivered:        {},
+	models.OrderStatusCancelled:        {},
+}
+
+// OrderTransitionError transição de status não permitida pela máquina de estados
+type OrderTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *OrderTransitionError) Error() string {
+	return fmt.Sprintf("invalid_transition: cannot change order status from %s to %s", e.From, e.To)
+}
+
+// IsValidOrderStatus verifica se o status existe
+func IsValidOrderStatus(status string) bool {
+	_, exists := orderStatusTransitions[status]
+	return exists
+}
+
+// CanTransitionOrderStatus verifica se a transição direta é permitida
+func CanTransitionOrderStatus(from, to string) bool {
+	for _, allowed := range orderStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateOrderStatusTransition valida status de destino, transição e motivo (obrigatório no cancelamento)
+func ValidateOrderStatusTransition(from, to, reason string) error {
+	if !IsValidOrderStatus(to) {
+		return fmt.Errorf("invalid_status: unknown order status %q", to)
+	}
+	if !CanTransitionOrderStatus(from, to) {
+		return &OrderTransitionError{From: from, To: to}
+	}
+	if to == models.OrderStatusCancelled && reason == "" {
+		return fmt.Errorf("reason_required: a reason is required to cancel an order")
+	}
+	return nil
+}
+
+// OrderStatusPath retorna os passos para avançar de from até to seguindo o fluxo principal
+// (ex.: pending -> ready passa por preparing). Retorna nil se não houver caminho à frente.
+func OrderStatusPath(from, to string) []string {
+	flow := []string{models.OrderStatusPending, models.OrderStatusPreparing, models.OrderStatusReady, models.OrderStatusDelivered}
+
+	start, end := -1, -1
+	for i, status := range flow {
+		if status == from {
+			start = i
+		}
+		if status == to {
+			end = i
+		}
+	}
+	if start < 0 || end <= start {
+		return nil
+	}
+	return flow[start+1 : end+1]
+}
+
+// NewOrderStatusEntry monta o registro de histórico de uma transição
+func NewOrderStatusEntry(order *models.Order, from, to, reason string, changedBy *uuid.UUID) models.OrderStatusHistory {
+	return models.OrderStatusHistory{
+		Id:             NewUUId(),
+		OrganizationId: order.OrganizationId,
+		ProjectId:      order.ProjectId,
+		OrderId:        order.Id,
+		FromStatus:     from,
+		ToStatus:       to,
+		Reason:         reason,
+		ChangedBy:      changedBy,
+		ChangedAt:      time.Now(),
+	}
+}
+
+// StatusEnteredAt retorna quando o pedido entrou no status pela última vez (nil se nunca entrou)
+func StatusEnteredAt(history []models.OrderStatusHistory, status string) *time.Time {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].ToStatus == status {
+			changedAt := history[i].ChangedAt
+			return &changedAt
+		}
+	}
+	return nil
+}
+
+// GetOrderProgressFromHistory calcula o progresso usando o momento real em que o preparo começou.
+// Sem histórico, usa GetOrderProgress.
+func GetOrderProgressFromHistory(order models.Order, history []models.OrderStatusHistory) (float64, int) {
+	if order.Status != models.OrderStatusPreparing || len(history) == 0 {
+		return GetOrderProgress(order), GetRemainingTime(order)
+	}
+
+	startedAt := StatusEnteredAt(history, models.OrderStatusPreparing)
+	if startedAt == nil || order.EstimatedPrepTime <= 0 {
+		return GetOrderProgress(order), GetRemainingTime(order)
+	}
+
+	total := float64(order.EstimatedPrepTime)
+	elapsed := time.Since(*startedAt).Minutes()
+	progress := elapsed / total * 100
+	if progress > 99 {
+		progress = 99.0 // Nunca 100% até estar ready
+	}
+
+	remaining := int(total - elapsed)
+	if remaining < 0 {
+		remaining = 0 // Pedido atrasado
+	}
+	return progress, remaining
+}
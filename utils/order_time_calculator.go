@@ -98,8 +98,23 @@ func UpdateOrderStatus(order *models.Order, newStatus string) {
 		order.StartedAt = &now
 	case "ready":
 		order.ReadyAt = &now
+		markItemsReady(order.Items, now)
 	case "delivered":
 		order.DeliveredAt = &now
+		markItemsReady(order.Items, now)
+	}
+	SyncOrderCourses(order)
+}
+
+// markItemsReady marca como prontos os itens que ainda não estavam (status do pedido definido manualmente).
+// Tempos retidos não foram para a cozinha e continuam aguardando o disparo.
+func markItemsReady(items models.OrderItems, now time.Time) {
+	for i := range items {
+		if items[i].Status == models.OrderItemStatusReady || items[i].Status == models.OrderItemStatusHeld {
+			continue
+		}
+		items[i].Status = models.OrderItemStatusReady
+		items[i].ReadyAt = &now
 	}
 }
 
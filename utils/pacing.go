@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PacingChecker confere os limites de ritmo com as reservas já carregadas.
+// Reservations são as reservas ativas (confirmed/pending) em torno do horário conferido.
+type PacingChecker struct {
+	Rules        []models.PacingRule
+	Reservations []models.Reservation
+	Settings     *models.Settings
+	Location     *time.Location
+}
+
+// ServiceAt serviço do horário local pelas configurações: "lunch", "dinner" ou "" fora dos dois.
+// O serviço vai do início até o último horário de reserva mais um intervalo.
+func ServiceAt(settings *models.Settings, at time.Time, loc *time.Location) string {
+	clock := NewClock(loc)
+	day := clock.StartOfDay(at)
+	interval := time.Duration(slotIntervalMinutes(settings)) * time.Minute
+	for _, service := range []string{models.PacingServiceLunch, models.PacingServiceDinner} {
+		from, to := serviceHours(settings, service)
+		start, errStart := clock.At(day, from)
+		end, errEnd := clock.At(day, to)
+		if errStart == nil && errEnd == nil && !at.Before(start) && at.Before(end.Add(interval)) {
+			return service
+		}
+	}
+	return ""
+}
+
+// SlotWindow janela de SlotIntervalMinutes que contém at, alinhada ao início do serviço
+// (fora dos serviços, alinhada à meia-noite local)
+func (p *PacingChecker) SlotWindow(at time.Time) (time.Time, time.Time, string) {
+	clock := NewClock(p.Location)
+	day := clock.StartOfDay(at)
+	interval := time.Duration(slotIntervalMinutes(p.Settings)) * time.Minute
+	service := ServiceAt(p.Settings, at, p.Location)
+	origin := day
+	if service != "" {
+		from, _ := serviceHours(p.Settings, service)
+		if start, err := clock.At(day, from); err == nil {
+			origin = start
+		}
+	}
+	start := origin.Add(at.Sub(origin) / interval * interval)
+	return start, start.Add(interval), service
+}
+
+// RulesAt regras que valem para o horário (serviço e dia da semana locais)
+func (p *PacingChecker) RulesAt(at time.Time) []models.PacingRule {
+	service := ServiceAt(p.Settings, at, p.Location)
+	weekday := at.In(p.Location).Weekday()
+	var rules []models.PacingRule
+	for _, rule := range p.Rules {
+		if rule.AppliesTo(service, weekday) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Usage pessoas e reservas que chegam em [start, end), ignorando skip
+func (p *PacingChecker) Usage(start, end time.Time, skip uuid.UUID) (covers int, reservations int) {
+	for _, reservation := range p.Reservations {
+		if reservation.Id == skip || reservation.StartsAt == nil {
+			continue
+		}
+		if !reservation.StartsAt.Before(start) && reservation.StartsAt.Before(end) {
+			covers += reservation.PartySize
+			reservations++
+		}
+	}
+	return covers, reservations
+}
+
+// Check primeiro limite que um grupo de partySize chegando em at ultrapassaria (nil = dentro dos limites).
+// skip ignora a própria reserva numa atualização.
+func (p *PacingChecker) Check(at time.Time, partySize int, skip uuid.UUID) *models.PacingViolation {
+	rules := p.RulesAt(at)
+	if len(rules) == 0 {
+		return nil
+	}
+	clock := NewClock(p.Location)
+	slotStart, slotEnd, _ := p.SlotWindow(at)
+	slotCovers, slotReservations := p.Usage(slotStart, slotEnd, skip)
+
+	violation := func(rule models.PacingRule, limit string, max, current, requested int, start, end time.Time) *models.PacingViolation {
+		return &models.PacingViolation{
+			RuleId:      rule.Id,
+			RuleName:    rule.Name,
+			Limit:       limit,
+			Max:         max,
+			Current:     current,
+			Requested:   requested,
+			WindowStart: clock.Format(start),
+			WindowEnd:   clock.Format(end),
+		}
+	}
+
+	for _, rule := range rules {
+		if rule.MaxCoversPerSlot > 0 && slotCovers+partySize > rule.MaxCoversPerSlot {
+			return violation(rule, "covers_per_slot", rule.MaxCoversPerSlot, slotCovers, partySize, slotStart, slotEnd)
+		}
+		if rule.MaxReservationsPerSlot > 0 && slotReservations+1 > rule.MaxReservationsPerSlot {
+			return violation(rule, "reservations_per_slot", rule.MaxReservationsPerSlot, slotReservations, 1, slotStart, slotEnd)
+		}
+		if rule.RollingWindowMinutes <= 0 || (rule.RollingMaxCovers <= 0 && rule.RollingMaxReservations <= 0) {
+			continue
+		}
+
+		// A janela móvel mais cheia que contém at começa em at ou na chegada de alguma reserva anterior
+		window := time.Duration(rule.RollingWindowMinutes) * time.Minute
+		starts := []time.Time{at}
+		for _, reservation := range p.Reservations {
+			if reservation.Id != skip && reservation.StartsAt != nil &&
+				reservation.StartsAt.After(at.Add(-window)) && !reservation.StartsAt.After(at) {
+				starts = append(starts, *reservation.StartsAt)
+			}
+		}
+		for _, start := range starts {
+			covers, reservations := p.Usage(start, start.Add(window), skip)
+			if rule.RollingMaxCovers > 0 && covers+partySize > rule.RollingMaxCovers {
+				return violation(rule, "rolling_covers", rule.RollingMaxCovers, covers, partySize, start, start.Add(window))
+			}
+			if rule.RollingMaxReservations > 0 && reservations+1 > rule.RollingMaxReservations {
+				return violation(rule, "rolling_reservations", rule.RollingMaxReservations, reservations, 1, start, start.Add(window))
+			}
+		}
+	}
+	return nil
+}
+
+// serviceHours horário de início e do último horário de reserva do serviço (padrão 12:00-14:30 e 19:00-22:00)
+func serviceHours(settings *models.Settings, service string) (string, string) {
+	if service == models.PacingServiceLunch {
+		if settings != nil && settings.LunchStart != "" && settings.LunchEnd != "" {
+			return settings.LunchStart, settings.LunchEnd
+		}
+		return "12:00", "14:30"
+	}
+	if settings != nil && settings.DinnerStart != "" && settings.DinnerEnd != "" {
+		return settings.DinnerStart, settings.DinnerEnd
+	}
+	return "19:00", "22:00"
+}
+
+func slotIntervalMinutes(settings *models.Settings) int {
+	if settings != nil && settings.SlotIntervalMinutes > 0 {
+		return settings.SlotIntervalMinutes
+	}
+	return 30
+}
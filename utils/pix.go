@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/text/unicode/norm"
+)
+
+// PixPayloadParams dados para montar o BR Code (padrão EMV do Banco Central)
+type PixPayloadParams struct {
+	Key          string  // chave Pix (estático)
+	Location     string  // URL do PSP sem "https://" (dinâmico)
+	MerchantName string  // até 25 caracteres
+	MerchantCity string  // até 15 caracteres
+	Amount       float64 // 0 = valor livre
+	TxId         string  // até 25 caracteres alfanuméricos ("***" quando vazio)
+	Description  string  // informação adicional (apenas estático)
+}
+
+// BuildPixPayload gera o "copia e cola" Pix (BR Code EMV com CRC16).
+// Com Location o código é dinâmico (uso único); caso contrário é estático com a chave.
+func BuildPixPayload(params PixPayloadParams) (string, error) {
+	if params.Key == "" && params.Location == "" {
+		return "", fmt.Errorf("pix key or location is required")
+	}
+
+	name := sanitizePixText(params.MerchantName, 25)
+	city := sanitizePixText(params.MerchantCity, 15)
+	if name == "" || city == "" {
+		return "", fmt.Errorf("merchant name and city are required")
+	}
+
+	dynamic := params.Location != ""
+
+	var account strings.Builder
+	account.WriteString(emvField("00", "br.gov.bcb.pix"))
+	if dynamic {
+		account.WriteString(emvField("25", strings.TrimPrefix(params.Location, "https://")))
+	} else {
+		account.WriteString(emvField("01", params.Key))
+		if description := sanitizePixText(params.Description, 40); description != "" {
+			account.WriteString(emvField("02", description))
+		}
+	}
+	if account.Len() > 99 {
+		return "", fmt.Errorf("merchant account information exceeds 99 characters")
+	}
+
+	txId := params.TxId
+	if txId == "" {
+		txId = "***"
+	}
+
+	var payload strings.Builder
+	payload.WriteString(emvField("00", "01"))
+	if dynamic {
+		payload.WriteString(emvField("01", "12")) // uso único
+	} else {
+		payload.WriteString(emvField("01", "11")) // reutilizável
+	}
+	payload.WriteString(emvField("26", account.String()))
+	payload.WriteString(emvField("52", "0000"))
+	payload.WriteString(emvField("53", "986")) // BRL
+	if params.Amount > 0 {
+		payload.WriteString(emvField("54", fmt.Sprintf("%.2f", RoundMoney(params.Amount))))
+	}
+	payload.WriteString(emvField("58", "BR"))
+	payload.WriteString(emvField("59", name))
+	payload.WriteString(emvField("60", city))
+	payload.WriteString(emvField("62", emvField("05", txId)))
+	payload.WriteString("6304")
+
+	crc := CRC16CCITT([]byte(payload.String()))
+	return payload.String() + fmt.Sprintf("%04X", crc), nil
+}
+
+// GeneratePixQRCode gera o PNG do QR Code para o payload Pix
+func GeneratePixQRCode(payload string, size int) ([]byte, error) {
+	if size <= 0 {
+		size = 256
+	}
+	return qrcode.Encode(payload, qrcode.Medium, size)
+}
+
+// GeneratePixTxId gera identificador de transação alfanumérico (25 caracteres)
+func GeneratePixTxId() string {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	bytes := make([]byte, 25)
+	if _, err := rand.Read(bytes); err != nil {
+		return strings.ReplaceAll(NewUUId().String(), "-", "")[:25]
+	}
+	for i := range bytes {
+		bytes[i] = alphabet[int(bytes[i])%len(alphabet)]
+	}
+	return string(bytes)
+}
+
+// CRC16CCITT calcula o CRC16-CCITT (polinômio 0x1021, valor inicial 0xFFFF) exigido pelo BR Code
+func CRC16CCITT(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// emvField monta um campo EMV (id + tamanho com 2 dígitos + valor)
+func emvField(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// sanitizePixText remove acentos e caracteres fora do ASCII e limita o tamanho
+func sanitizePixText(value string, maxLen int) string {
+	var builder strings.Builder
+	for _, r := range norm.NFD.String(strings.TrimSpace(value)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if r < 32 || r > 126 {
+			continue
+		}
+		builder.WriteRune(r)
+	}
+
+	result := builder.String()
+	if len(result) > maxLen {
+		result = strings.TrimSpace(result[:maxLen])
+	}
+	return result
+}
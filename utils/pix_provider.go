@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"lep/config"
+	"lep/repositories/models"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// PixSignatureHeader cabeçalho com a assinatura HMAC-SHA256 do corpo do webhook ("sha256=<hex>")
+const PixSignatureHeader = "X-Pix-Signature"
+
+// ErrInvalidPixSignature webhook com assinatura ausente ou inválida
+var ErrInvalidPixSignature = errors.New("invalid_signature: pix webhook signature mismatch")
+
+// PixConfirmation confirmação de recebimento informada pelo provedor
+type PixConfirmation struct {
+	TxId       string  `json:"txid"`
+	EndToEndId string  `json:"end_to_end_id"`
+	Amount     float64 `json:"amount"`
+}
+
+// PixProvider integração com o PSP que gera cobranças dinâmicas e confirma recebimentos
+type PixProvider interface {
+	Name() string
+	// CreateCharge registra a cobrança no PSP e retorna a URL (location) do payload dinâmico
+	CreateCharge(charge *models.PixCharge) (string, error)
+	// ParseWebhook valida a autenticidade da requisição do PSP (assinatura, headers) e converte o corpo em confirmações
+	ParseWebhook(req *http.Request) ([]PixConfirmation, error)
+}
+
+var (
+	pixProviders   = make(map[string]PixProvider)
+	pixProvidersMu sync.RWMutex
+)
+
+// RegisterPixProvider registra um provedor Pix pelo nome
+func RegisterPixProvider(provider PixProvider) {
+	pixProvidersMu.Lock()
+	defer pixProvidersMu.Unlock()
+	pixProviders[provider.Name()] = provider
+}
+
+// GetPixProvider busca provedor registrado
+func GetPixProvider(name string) (PixProvider, error) {
+	pixProvidersMu.RLock()
+	defer pixProvidersMu.RUnlock()
+	provider, exists := pixProviders[name]
+	if !exists {
+		return nil, fmt.Errorf("pix provider %s not registered", name)
+	}
+	return provider, nil
+}
+
+func init() {
+	// Provedor fake fica disponível apenas fora de produção (webhook só em desenvolvimento local)
+	if config.ENV != "prod" {
+		RegisterPixProvider(&FakePixProvider{})
+	}
+}
+
+// SignPixPayload assinatura HMAC-SHA256 do corpo no formato "sha256=<hex>"
+func SignPixPayload(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyPixSignature compara a assinatura recebida em tempo constante
+func VerifyPixSignature(body []byte, signature, secret string) error {
+	if secret == "" || signature == "" {
+		return ErrInvalidPixSignature
+	}
+	if !hmac.Equal([]byte(strings.TrimSpace(signature)), []byte(SignPixPayload(body, secret))) {
+		return ErrInvalidPixSignature
+	}
+	return nil
+}
+
+// FakePixProvider provedor local para testes: não chama PSP e aceita confirmações simuladas.
+// O webhook só é aceito em desenvolvimento local e assinado com PIX_WEBHOOK_SECRET (X-Pix-Signature).
+type FakePixProvider struct{}
+
+func (p *FakePixProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakePixProvider) CreateCharge(charge *models.PixCharge) (string, error) {
+	return "pix.fake.local/qr/v2/" + charge.TxId, nil
+}
+
+// ParseWebhook aceita {"txid": "...", "end_to_end_id": "...", "amount": 10.5} ou um array desses objetos
+func (p *FakePixProvider) ParseWebhook(req *http.Request) ([]PixConfirmation, error) {
+	if !config.IsDev() {
+		return nil, errors.New("fake provider webhook is only available in local development")
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	if err := VerifyPixSignature(body, req.Header.Get(PixSignatureHeader), config.PIX_WEBHOOK_SECRET); err != nil {
+		return nil, err
+	}
+
+	var confirmations []PixConfirmation
+	if err := json.Unmarshal(body, &confirmations); err == nil {
+		return confirmations, nil
+	}
+
+	var single PixConfirmation
+	if err := json.Unmarshal(body, &single); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	return []PixConfirmation{single}, nil
+}
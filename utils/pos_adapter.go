@@ -0,0 +1,260 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"lep/repositories/models"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PosAdapter formato de arquivo trocado com o PDV: lê o cardápio e gera o arquivo de vendas.
+// Novos formatos de fornecedor entram registrando outro adaptador.
+type PosAdapter interface {
+	Name() string
+	ContentType() string
+	FileExtension() string
+	// ParseCatalog lê o cardápio; linhas inválidas voltam como itens "failed" sem interromper a leitura
+	ParseCatalog(data []byte) ([]models.PosCatalogItem, []models.PosSyncItem, error)
+	// WriteSales gera o arquivo de vendas
+	WriteSales(sales []models.PosSaleRecord) ([]byte, error)
+}
+
+var (
+	posAdapters   = make(map[string]PosAdapter)
+	posAdaptersMu sync.RWMutex
+)
+
+// RegisterPosAdapter registra um adaptador de PDV pelo nome
+func RegisterPosAdapter(adapter PosAdapter) {
+	posAdaptersMu.Lock()
+	defer posAdaptersMu.Unlock()
+	posAdapters[adapter.Name()] = adapter
+}
+
+// GetPosAdapter busca adaptador registrado
+func GetPosAdapter(name string) (PosAdapter, error) {
+	posAdaptersMu.RLock()
+	defer posAdaptersMu.RUnlock()
+	adapter, exists := posAdapters[name]
+	if !exists {
+		return nil, fmt.Errorf("pos format %s not registered", name)
+	}
+	return adapter, nil
+}
+
+func init() {
+	RegisterPosAdapter(&CSVPosAdapter{})
+	RegisterPosAdapter(&JSONPosAdapter{})
+}
+
+// posCatalogColumns nomes de coluna aceitos no cardápio (cabeçalho em português ou inglês)
+var posCatalogColumns = map[string]string{
+	"code": "code", "pdv_code": "code", "codigo": "code", "código": "code", "sku": "code",
+	"name": "name", "nome": "name", "produto": "name",
+	"description": "description", "descricao": "description", "descrição": "description",
+	"price": "price", "preco": "price", "preço": "price", "valor": "price",
+	"category": "category", "categoria": "category",
+	"type": "type", "tipo": "type",
+	"active": "active", "ativo": "active",
+}
+
+// posSalesColumns cabeçalho do arquivo de vendas
+var posSalesColumns = []string{"order_id", "delivered_at", "code", "name", "variant", "quantity", "unit_price", "total", "source", "type"}
+
+// CSVPosAdapter cardápio e vendas em CSV com cabeçalho.
+// Importação aceita "," ou ";" como separador e preço com vírgula decimal ("12,50").
+type CSVPosAdapter struct{}
+
+func (a *CSVPosAdapter) Name() string          { return "csv" }
+func (a *CSVPosAdapter) ContentType() string   { return "text/csv; charset=utf-8" }
+func (a *CSVPosAdapter) FileExtension() string { return "csv" }
+
+func (a *CSVPosAdapter) ParseCatalog(data []byte) ([]models.PosCatalogItem, []models.PosSyncItem, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // BOM de planilhas exportadas
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectCSVDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid_pos_file: missing header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		if column, ok := posCatalogColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[column] = i
+		}
+	}
+	if _, ok := columns["code"]; !ok {
+		return nil, nil, errors.New("invalid_pos_file: header must have a code column")
+	}
+
+	var items []models.PosCatalogItem
+	var failures []models.PosSyncItem
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			line := 0
+			if errors.As(err, &parseErr) {
+				line = parseErr.StartLine
+			}
+			failures = append(failures, models.PosSyncItem{Line: line, Action: models.PosSyncItemFailed, Message: err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		item := models.PosCatalogItem{
+			Line:        line,
+			Code:        field("code"),
+			Name:        field("name"),
+			Description: field("description"),
+			Category:    field("category"),
+			Type:        field("type"),
+		}
+		if value := field("price"); value != "" {
+			price, err := parsePosPrice(value)
+			if err != nil {
+				failures = append(failures, models.PosSyncItem{Line: line, Code: item.Code, Name: item.Name, Action: models.PosSyncItemFailed, Message: err.Error()})
+				continue
+			}
+			item.Price = &price
+		}
+		if value := field("active"); value != "" {
+			active, err := parsePosBool(value)
+			if err != nil {
+				failures = append(failures, models.PosSyncItem{Line: line, Code: item.Code, Name: item.Name, Action: models.PosSyncItemFailed, Message: err.Error()})
+				continue
+			}
+			item.Active = &active
+		}
+		items = append(items, item)
+	}
+	return items, failures, nil
+}
+
+func (a *CSVPosAdapter) WriteSales(sales []models.PosSaleRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(posSalesColumns); err != nil {
+		return nil, err
+	}
+	for _, sale := range sales {
+		record := []string{
+			sale.OrderId.String(),
+			sale.DeliveredAt.UTC().Format(time.RFC3339),
+			sale.Code,
+			sale.Name,
+			sale.Variant,
+			strconv.Itoa(sale.Quantity),
+			strconv.FormatFloat(sale.UnitPrice, 'f', 2, 64),
+			strconv.FormatFloat(sale.Total, 'f', 2, 64),
+			sale.Source,
+			sale.Type,
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// JSONPosAdapter cardápio como lista de itens (ou {"items": [...]}) e vendas como lista de registros
+type JSONPosAdapter struct{}
+
+func (a *JSONPosAdapter) Name() string          { return "json" }
+func (a *JSONPosAdapter) ContentType() string   { return "application/json" }
+func (a *JSONPosAdapter) FileExtension() string { return "json" }
+
+func (a *JSONPosAdapter) ParseCatalog(data []byte) ([]models.PosCatalogItem, []models.PosSyncItem, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		var envelope struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		if err := json.Unmarshal(data, &envelope); err != nil || envelope.Items == nil {
+			return nil, nil, errors.New("invalid_pos_file: expected a list of items or {\"items\": [...]}")
+		}
+		raw = envelope.Items
+	}
+
+	items := make([]models.PosCatalogItem, 0, len(raw))
+	var failures []models.PosSyncItem
+	for i, entry := range raw {
+		var item models.PosCatalogItem
+		if err := json.Unmarshal(entry, &item); err != nil {
+			failures = append(failures, models.PosSyncItem{Line: i + 1, Action: models.PosSyncItemFailed, Message: err.Error()})
+			continue
+		}
+		item.Line = i + 1
+		item.Code = strings.TrimSpace(item.Code)
+		item.Name = strings.TrimSpace(item.Name)
+		items = append(items, item)
+	}
+	return items, failures, nil
+}
+
+func (a *JSONPosAdapter) WriteSales(sales []models.PosSaleRecord) ([]byte, error) {
+	if sales == nil {
+		sales = []models.PosSaleRecord{}
+	}
+	return json.MarshalIndent(sales, "", "  ")
+}
+
+// detectCSVDelimiter escolhe entre ";" e "," pela primeira linha
+func detectCSVDelimiter(data []byte) rune {
+	firstLine := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		firstLine = data[:i]
+	}
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+// parsePosPrice aceita "12.50", "12,50" e "1.234,50" (com ou sem "R$")
+func parsePosPrice(value string) (float64, error) {
+	normalized := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "R$"))
+	if strings.Contains(normalized, ",") {
+		normalized = strings.ReplaceAll(normalized, ".", "")
+		normalized = strings.ReplaceAll(normalized, ",", ".")
+	}
+	price, err := strconv.ParseFloat(normalized, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid price %q", value)
+	}
+	return price, nil
+}
+
+// parsePosBool aceita true/false, 1/0 e sim/não
+func parsePosBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "1", "sim", "s", "yes", "y":
+		return true, nil
+	case "false", "0", "nao", "não", "n", "no":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid active value %q", value)
+}
@@ -0,0 +1,447 @@
+package utils
+
+import (
+	"lep/repositories"
+	"lep/repositories/models"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PrepTimeMinSamples   = 10 // amostras mínimas para confiar no tempo aprendido
+	PrepTimeLookbackDays = 30 // janela de histórico usada no cálculo
+
+	prepTimeMaxMinutes     = 240 // medições maiores são pedidos esquecidos em "preparing"
+	prepTimeQueueDepthCap  = 10  // filas maiores que isso são agrupadas
+	prepTimeParallelFactor = 0.2 // mesma sobrecarga sequencial de CalculateOrderPrepTime
+)
+
+// ProjectLocation fuso horário do projeto (padrão: America/Sao_Paulo)
+func ProjectLocation(timezone string) *time.Location {
+	if timezone == "" {
+		timezone = "America/Sao_Paulo"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Printf("Invalid timezone %s, using UTC: %v", timezone, err)
+		return time.UTC
+	}
+	return loc
+}
+
+// prepQuantityFactor converte o tempo de uma unidade no tempo da linha com a quantidade pedida
+func prepQuantityFactor(quantity int) float64 {
+	if quantity < 1 {
+		quantity = 1
+	}
+	return 1 + float64(quantity-1)*prepTimeParallelFactor
+}
+
+type prepStatKey struct {
+	productId uuid.UUID
+	hour      int
+}
+
+// prepSample tempo real de uma linha do pedido, normalizado para uma unidade
+type prepSample struct {
+	productId uuid.UUID
+	hour      int
+	minutes   float64
+}
+
+// itemPrepSamples mede cada item do pedido: do início do preparo (do item ou do pedido) até ficar pronto
+func itemPrepSamples(order models.Order, loc *time.Location) []prepSample {
+	if order.StartedAt == nil || order.ReadyAt == nil {
+		return nil
+	}
+
+	var samples []prepSample
+	for _, item := range order.Items {
+		start, end := *order.StartedAt, *order.ReadyAt
+		if item.FiredAt != nil && item.FiredAt.After(start) {
+			start = *item.FiredAt // tempo disparado depois do início do pedido
+		}
+		if item.StartedAt != nil {
+			start = *item.StartedAt
+		}
+		if item.ReadyAt != nil {
+			end = *item.ReadyAt
+		}
+
+		minutes := end.Sub(start).Minutes()
+		if minutes <= 0 || minutes > prepTimeMaxMinutes {
+			continue
+		}
+		samples = append(samples, prepSample{
+			productId: item.ProductId,
+			hour:      start.In(loc).Hour(),
+			minutes:   minutes / prepQuantityFactor(item.Quantity),
+		})
+	}
+	return samples
+}
+
+// queueDepthAt conta os pedidos em preparo no momento em que o pedido chegou
+func queueDepthAt(order models.Order, orders []models.Order) int {
+	depth := 0
+	for _, other := range orders {
+		if other.Id == order.Id || other.StartedAt == nil || other.StartedAt.After(order.CreatedAt) {
+			continue
+		}
+		if other.ReadyAt == nil || other.ReadyAt.After(order.CreatedAt) {
+			depth++
+		}
+	}
+	if depth > prepTimeQueueDepthCap {
+		depth = prepTimeQueueDepthCap
+	}
+	return depth
+}
+
+// BuildPrepTimeStats calcula tempos reais por produto (geral e por hora do dia) e a espera por tamanho da fila
+func BuildPrepTimeStats(orders []models.Order, loc *time.Location, now time.Time) ([]models.PrepTimeStat, []models.QueueTimeStat) {
+	if len(orders) == 0 {
+		return nil, nil
+	}
+	orgId, projectId := orders[0].OrganizationId, orders[0].ProjectId
+
+	productSamples := make(map[prepStatKey][]float64)
+	queueSamples := make(map[int][]float64)
+	for _, order := range orders {
+		for _, sample := range itemPrepSamples(order, loc) {
+			byHour := prepStatKey{sample.productId, sample.hour}
+			overall := prepStatKey{sample.productId, -1}
+			productSamples[byHour] = append(productSamples[byHour], sample.minutes)
+			productSamples[overall] = append(productSamples[overall], sample.minutes)
+		}
+
+		if order.StartedAt != nil {
+			wait := order.StartedAt.Sub(order.CreatedAt).Minutes()
+			if wait >= 0 && wait <= prepTimeMaxMinutes {
+				depth := queueDepthAt(order, orders)
+				queueSamples[depth] = append(queueSamples[depth], wait)
+			}
+		}
+	}
+
+	prepStats := make([]models.PrepTimeStat, 0, len(productSamples))
+	for key, values := range productSamples {
+		avg, median, p90 := summarizeMinutes(values)
+		prepStats = append(prepStats, models.PrepTimeStat{
+			Id:             uuid.New(),
+			OrganizationId: orgId,
+			ProjectId:      projectId,
+			ProductId:      key.productId,
+			HourOfDay:      key.hour,
+			SampleCount:    len(values),
+			AvgMinutes:     avg,
+			MedianMinutes:  median,
+			P90Minutes:     p90,
+			ComputedAt:     now,
+		})
+	}
+	sort.Slice(prepStats, func(i, j int) bool {
+		if prepStats[i].ProductId != prepStats[j].ProductId {
+			return prepStats[i].ProductId.String() < prepStats[j].ProductId.String()
+		}
+		return prepStats[i].HourOfDay < prepStats[j].HourOfDay
+	})
+
+	queueStats := make([]models.QueueTimeStat, 0, len(queueSamples))
+	for depth, values := range queueSamples {
+		avg, median, _ := summarizeMinutes(values)
+		queueStats = append(queueStats, models.QueueTimeStat{
+			Id:                uuid.New(),
+			OrganizationId:    orgId,
+			ProjectId:         projectId,
+			QueueDepth:        depth,
+			SampleCount:       len(values),
+			AvgWaitMinutes:    avg,
+			MedianWaitMinutes: median,
+			ComputedAt:        now,
+		})
+	}
+	sort.Slice(queueStats, func(i, j int) bool { return queueStats[i].QueueDepth < queueStats[j].QueueDepth })
+
+	return prepStats, queueStats
+}
+
+// summarizeMinutes média, mediana e percentil 90 arredondados em 0,1 minuto
+func summarizeMinutes(values []float64) (float64, float64, float64) {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	total := 0.0
+	for _, v := range sorted {
+		total += v
+	}
+	return roundTenth(total / float64(len(sorted))), roundTenth(percentile(sorted, 0.5)), roundTenth(percentile(sorted, 0.9))
+}
+
+// percentile pelo método nearest-rank (valores ordenados)
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func roundTenth(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+// PrepTimeModel estatísticas aprendidas indexadas para a estimativa
+type PrepTimeModel struct {
+	products map[prepStatKey]models.PrepTimeStat
+	queue    map[int]models.QueueTimeStat
+}
+
+func NewPrepTimeModel(prepStats []models.PrepTimeStat, queueStats []models.QueueTimeStat) *PrepTimeModel {
+	model := &PrepTimeModel{
+		products: make(map[prepStatKey]models.PrepTimeStat, len(prepStats)),
+		queue:    make(map[int]models.QueueTimeStat, len(queueStats)),
+	}
+	for _, stat := range prepStats {
+		model.products[prepStatKey{stat.ProductId, stat.HourOfDay}] = stat
+	}
+	for _, stat := range queueStats {
+		model.queue[stat.QueueDepth] = stat
+	}
+	return model
+}
+
+// ProductMinutes tempo por unidade aprendido para o produto: da hora do dia se houver dados, senão o geral
+func (m *PrepTimeModel) ProductMinutes(productId uuid.UUID, hour int) (float64, bool) {
+	if m == nil {
+		return 0, false
+	}
+	for _, key := range []prepStatKey{{productId, hour}, {productId, -1}} {
+		if stat, ok := m.products[key]; ok && stat.SampleCount >= PrepTimeMinSamples {
+			return stat.MedianMinutes, true
+		}
+	}
+	return 0, false
+}
+
+// QueueMinutes espera aprendida para a fila com o tamanho informado
+func (m *PrepTimeModel) QueueMinutes(depth int) (float64, bool) {
+	if m == nil {
+		return 0, false
+	}
+	if depth > prepTimeQueueDepthCap {
+		depth = prepTimeQueueDepthCap
+	}
+	if stat, ok := m.queue[depth]; ok && stat.SampleCount >= PrepTimeMinSamples {
+		return stat.MedianWaitMinutes, true
+	}
+	return 0, false
+}
+
+// EstimateOrderPrepTime igual a CalculateOrderPrepTime, mas usa o tempo aprendido
+// dos produtos com histórico suficiente. Retorna também a origem da estimativa.
+func EstimateOrderPrepTime(items []models.OrderItem, products []models.Product, model *PrepTimeModel, hour int) (int, string) {
+	productMap := make(map[uuid.UUID]models.Product)
+	for _, product := range products {
+		productMap[product.Id] = product
+	}
+
+	maxPrepTime := 0.0
+	totalSequentialTime := 0.0
+	counted, learned := 0, 0
+	for _, item := range items {
+		product, exists := productMap[item.ProductId]
+		if !exists {
+			continue
+		}
+		counted++
+
+		var itemTime float64
+		if minutes, ok := model.ProductMinutes(item.ProductId, hour); ok {
+			itemTime = minutes * prepQuantityFactor(item.Quantity)
+			learned++
+		} else if product.PrepTimeMinutes != nil {
+			itemTime = float64(*product.PrepTimeMinutes * item.Quantity)
+		}
+
+		totalSequentialTime += itemTime
+		if itemTime > maxPrepTime {
+			maxPrepTime = itemTime
+		}
+	}
+
+	estimated := int(math.Round(maxPrepTime + (totalSequentialTime-maxPrepTime)*prepTimeParallelFactor))
+	return estimated, estimateSource(counted, learned)
+}
+
+func estimateSource(total, learned int) string {
+	switch {
+	case learned == 0:
+		return models.EstimateSourceConfigured
+	case learned == total:
+		return models.EstimateSourceLearned
+	default:
+		return models.EstimateSourceMixed
+	}
+}
+
+// CombineEstimateSource junta a origem do tempo de preparo com a da espera na fila
+func CombineEstimateSource(prepSource string, queueLearned bool) string {
+	switch {
+	case prepSource == models.EstimateSourceLearned && !queueLearned,
+		prepSource == models.EstimateSourceConfigured && queueLearned:
+		return models.EstimateSourceMixed
+	default:
+		return prepSource
+	}
+}
+
+// BuildPrepTimeAccuracy compara o tempo prometido com o real dos pedidos prontos no período
+func BuildPrepTimeAccuracy(orders []models.Order, products []models.Product, model *PrepTimeModel, loc *time.Location) ([]models.PrepTimeAccuracy, models.PrepTimeAccuracy, []models.ProductPrepAccuracy) {
+	type accumulator struct {
+		orders, readyOrders, withinFive                int
+		estimated, actual, errSum, absErr, readyAbsErr float64
+	}
+	bySource := make(map[string]*accumulator)
+	overall := &accumulator{}
+
+	productMinutes := make(map[uuid.UUID][]float64)
+	for _, order := range orders {
+		for _, sample := range itemPrepSamples(order, loc) {
+			productMinutes[sample.productId] = append(productMinutes[sample.productId], sample.minutes)
+		}
+
+		if order.StartedAt == nil || order.ReadyAt == nil || order.EstimatedPrepTime <= 0 {
+			continue
+		}
+		source := order.EstimateSource
+		if source == "" {
+			source = models.EstimateSourceConfigured
+		}
+		acc, ok := bySource[source]
+		if !ok {
+			acc = &accumulator{}
+			bySource[source] = acc
+		}
+
+		actual := order.ReadyAt.Sub(*order.StartedAt).Minutes()
+		diff := actual - float64(order.EstimatedPrepTime)
+		for _, a := range []*accumulator{acc, overall} {
+			a.orders++
+			a.estimated += float64(order.EstimatedPrepTime)
+			a.actual += actual
+			a.errSum += diff
+			a.absErr += math.Abs(diff)
+			if order.EstimatedDeliveryTime != nil {
+				readyDiff := math.Abs(order.ReadyAt.Sub(*order.EstimatedDeliveryTime).Minutes())
+				a.readyOrders++
+				a.readyAbsErr += readyDiff
+				if readyDiff <= 5 {
+					a.withinFive++
+				}
+			}
+		}
+	}
+
+	summarize := func(source string, a *accumulator) models.PrepTimeAccuracy {
+		result := models.PrepTimeAccuracy{Source: source, Orders: a.orders}
+		if a.orders == 0 {
+			return result
+		}
+		n := float64(a.orders)
+		result.AvgEstimatedMinutes = roundTenth(a.estimated / n)
+		result.AvgActualMinutes = roundTenth(a.actual / n)
+		result.MeanErrorMinutes = roundTenth(a.errSum / n)
+		result.MeanAbsErrorMinutes = roundTenth(a.absErr / n)
+		if a.readyOrders > 0 {
+			result.ReadyMeanAbsMinutes = roundTenth(a.readyAbsErr / float64(a.readyOrders))
+			result.WithinFiveMinutesPct = roundTenth(float64(a.withinFive) / float64(a.readyOrders) * 100)
+		}
+		return result
+	}
+
+	sources := make([]models.PrepTimeAccuracy, 0, len(bySource))
+	for _, source := range []string{models.EstimateSourceConfigured, models.EstimateSourceMixed, models.EstimateSourceLearned} {
+		if acc, ok := bySource[source]; ok {
+			sources = append(sources, summarize(source, acc))
+		}
+	}
+
+	productAccuracy := make([]models.ProductPrepAccuracy, 0, len(products))
+	for _, product := range products {
+		values := productMinutes[product.Id]
+		if len(values) == 0 {
+			continue
+		}
+		avg, _, _ := summarizeMinutes(values)
+		entry := models.ProductPrepAccuracy{
+			ProductId:         product.Id,
+			Name:              product.Name,
+			Samples:           len(values),
+			ConfiguredMinutes: product.PrepTimeMinutes,
+			AvgActualMinutes:  avg,
+		}
+		if minutes, ok := model.ProductMinutes(product.Id, -1); ok {
+			entry.LearnedMinutes = &minutes
+		}
+		productAccuracy = append(productAccuracy, entry)
+	}
+	sort.Slice(productAccuracy, func(i, j int) bool { return productAccuracy[i].Name < productAccuracy[j].Name })
+
+	return sources, summarize("all", overall), productAccuracy
+}
+
+// PrepTimeService recalcula periodicamente os tempos aprendidos a partir do histórico de pedidos
+type PrepTimeService struct {
+	repo     repositories.IPrepTimeRepository
+	projects repositories.IProjectRepository
+}
+
+func NewPrepTimeService(repo repositories.IPrepTimeRepository, projects repositories.IProjectRepository) *PrepTimeService {
+	return &PrepTimeService{repo: repo, projects: projects}
+}
+
+// Location fuso horário do projeto usado para a hora do dia
+func (s *PrepTimeService) Location(projectId uuid.UUID) *time.Location {
+	return ProjectClock(s.projects, projectId).Location()
+}
+
+// RecomputeProject recalcula as estatísticas do projeto com os pedidos da janela de histórico
+func (s *PrepTimeService) RecomputeProject(orgId, projectId uuid.UUID) (*models.PrepTimeStats, error) {
+	now := time.Now()
+	orders, err := s.repo.ListFinishedOrders(orgId, projectId, now.AddDate(0, 0, -PrepTimeLookbackDays), now)
+	if err != nil {
+		return nil, err
+	}
+
+	prepStats, queueStats := BuildPrepTimeStats(orders, s.Location(projectId), now)
+	if err := s.repo.ReplaceStats(orgId, projectId, prepStats, queueStats); err != nil {
+		return nil, err
+	}
+
+	return &models.PrepTimeStats{
+		MinSamples: PrepTimeMinSamples,
+		ComputedAt: &now,
+		Products:   prepStats,
+		Queue:      queueStats,
+	}, nil
+}
+
+// RecomputeAll recalcula os projetos que tiveram pedidos prontos na janela de histórico
+func (s *PrepTimeService) RecomputeAll() error {
+	refs, err := s.repo.ListProjectsWithFinishedOrders(time.Now().AddDate(0, 0, -PrepTimeLookbackDays))
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range refs {
+		if _, err := s.RecomputeProject(ref.OrganizationId, ref.ProjectId); err != nil {
+			log.Printf("Error computing prep times for project %s: %v", ref.ProjectId, err)
+		}
+	}
+	return nil
+}
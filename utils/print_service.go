@@ -0,0 +1,282 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"log"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	printJobMaxAttempts = 5
+	printJobRetryDelay  = 30 * time.Second // multiplicado pelo número de tentativas
+	printJobStuckAfter  = 2 * time.Minute  // "printing" há mais tempo volta para a fila (processo caiu no meio)
+)
+
+// PrinterTransport envia bytes ESC/POS para a impressora
+type PrinterTransport interface {
+	Send(host string, port int, payload []byte) error
+}
+
+// privatePrinterNetworks faixas privadas (RFC 1918) aceitas como endereço de impressora
+var privatePrinterNetworks = []*net.IPNet{
+	mustParseCIDR("10.0.0.0/8"),
+	mustParseCIDR("172.16.0.0/12"),
+	mustParseCIDR("192.168.0.0/16"),
+}
+
+// ErrInvalidPrinterHost endereço fora das redes privadas (loopback, link-local/metadados, público ou nome DNS)
+var ErrInvalidPrinterHost = errors.New("invalid_printer_host: host must be a private IPv4 address (10/8, 172.16/12, 192.168/16)")
+
+// ValidatePrinterHost aceita só IPv4 literal em rede privada. Nomes DNS são recusados para
+// que o endereço conectado seja o validado.
+func ValidatePrinterHost(host string) error {
+	ip := net.ParseIP(host)
+	if ip == nil || ip.To4() == nil {
+		return ErrInvalidPrinterHost
+	}
+	for _, network := range privatePrinterNetworks {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	return ErrInvalidPrinterHost
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// TCPPrinterTransport envio RAW (porta 9100) pela rede local
+type TCPPrinterTransport struct {
+	Timeout time.Duration
+}
+
+// Send revalida o host na hora do envio (cadastros antigos não passaram pela validação atual)
+func (t *TCPPrinterTransport) Send(host string, port int, payload []byte) error {
+	if err := ValidatePrinterHost(host); err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), t.Timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetWriteDeadline(time.Now().Add(t.Timeout)); err != nil {
+		return err
+	}
+	_, err = conn.Write(payload)
+	return err
+}
+
+// PrintService fila de impressão: grava o trabalho, tenta imprimir na hora e
+// reagenda com espera crescente quando a impressora não responde.
+// Impressoras "agent" não são chamadas pela API: o agente local busca os trabalhos e devolve o resultado.
+type PrintService struct {
+	repo      repositories.IPrinterRepository
+	transport PrinterTransport
+}
+
+func NewPrintService(repo repositories.IPrinterRepository) *PrintService {
+	return &PrintService{repo: repo, transport: &TCPPrinterTransport{Timeout: 5 * time.Second}}
+}
+
+// Enqueue grava o trabalho na fila e dispara a primeira tentativa em segundo plano
+func (s *PrintService) Enqueue(printer models.Printer, jobType string, payload []byte, orderId, tabId, createdBy *uuid.UUID) (*models.PrintJob, error) {
+	now := time.Now()
+	job := &models.PrintJob{
+		Id:             uuid.New(),
+		OrganizationId: printer.OrganizationId,
+		ProjectId:      printer.ProjectId,
+		PrinterId:      printer.Id,
+		Type:           jobType,
+		OrderId:        orderId,
+		TabId:          tabId,
+		Payload:        payload,
+		Status:         models.PrintJobStatusPending,
+		MaxAttempts:    printJobMaxAttempts,
+		NextAttemptAt:  now,
+		CreatedBy:      createdBy,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.repo.CreateJob(job); err != nil {
+		return nil, err
+	}
+
+	if printer.Delivery == models.PrinterDeliveryDirect {
+		go s.dispatch(job.Id)
+	}
+	return job, nil
+}
+
+// Retry devolve trabalho (falho ou já impresso) para a fila com tentativas zeradas
+func (s *PrintService) Retry(job *models.PrintJob) error {
+	if job.Status == models.PrintJobStatusPending || job.Status == models.PrintJobStatusPrinting {
+		return fmt.Errorf("invalid_print_job: job is already %s", job.Status)
+	}
+
+	job.Status = models.PrintJobStatusPending
+	job.Attempts = 0
+	job.LastError = ""
+	job.NextAttemptAt = time.Now()
+	job.UpdatedAt = time.Now()
+	if err := s.repo.UpdateJob(job); err != nil {
+		return err
+	}
+
+	if printer, err := s.repo.GetPrinterById(job.PrinterId); err != nil || printer.Delivery == models.PrinterDeliveryDirect {
+		go s.dispatch(job.Id)
+	}
+	return nil
+}
+
+// ProcessDueJobs tenta novamente os trabalhos pendentes das impressoras diretas cujo horário chegou
+func (s *PrintService) ProcessDueJobs() error {
+	now := time.Now()
+	jobs, err := s.repo.ListDueJobs(now, now.Add(-printJobStuckAfter), 100)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		s.dispatch(job.Id)
+	}
+	return nil
+}
+
+// dispatch faz uma tentativa de impressão do trabalho, se ninguém mais o pegou
+func (s *PrintService) dispatch(jobId uuid.UUID) {
+	now := time.Now()
+	claimed, err := s.repo.ClaimJob(jobId, now, now.Add(-printJobStuckAfter))
+	if err != nil || !claimed {
+		return
+	}
+
+	job, err := s.repo.GetJobById(jobId)
+	if err != nil {
+		log.Printf("Print job %s not found: %v", jobId, err)
+		return
+	}
+
+	job.Attempts++
+	job.UpdatedAt = time.Now()
+
+	printer, err := s.repo.GetPrinterById(job.PrinterId)
+	switch {
+	case err != nil:
+		// Impressora excluída: não adianta tentar de novo
+		job.Attempts = job.MaxAttempts
+		err = fmt.Errorf("printer not found: %w", err)
+	case !printer.Active:
+		job.Attempts = job.MaxAttempts
+		err = fmt.Errorf("printer %s is inactive", printer.Name)
+	default:
+		err = s.transport.Send(printer.Host, printer.Port, job.Payload)
+	}
+
+	s.finish(job, err)
+}
+
+// ClaimAgentJobs entrega ao agente os trabalhos prontos das impressoras "agent" do projeto,
+// marcando-os como "printing"; trabalhos de impressoras desativadas falham aqui mesmo
+func (s *PrintService) ClaimAgentJobs(orgId, projectId uuid.UUID, limit int) ([]models.PrintAgentJob, error) {
+	now := time.Now()
+	jobs, err := s.repo.ListAgentDueJobs(orgId, projectId, now, now.Add(-printJobStuckAfter), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.PrintAgentJob{}
+	for i := range jobs {
+		job := &jobs[i]
+		claimed, err := s.repo.ClaimJob(job.Id, now, now.Add(-printJobStuckAfter))
+		if err != nil {
+			return result, err
+		}
+		if !claimed {
+			continue
+		}
+
+		job.Status = models.PrintJobStatusPrinting
+		job.Attempts++
+		job.UpdatedAt = time.Now()
+
+		printer, err := s.repo.GetPrinterById(job.PrinterId)
+		switch {
+		case err != nil:
+			job.Attempts = job.MaxAttempts
+			s.finish(job, fmt.Errorf("printer not found: %w", err))
+			continue
+		case !printer.Active:
+			job.Attempts = job.MaxAttempts
+			s.finish(job, fmt.Errorf("printer %s is inactive", printer.Name))
+			continue
+		}
+
+		if err := s.repo.UpdateJob(job); err != nil {
+			return result, err
+		}
+		result = append(result, models.PrintAgentJob{
+			Id:        job.Id,
+			PrinterId: printer.Id,
+			Host:      printer.Host,
+			Port:      printer.Port,
+			Payload:   job.Payload,
+		})
+	}
+	return result, nil
+}
+
+// CompleteAgentJob grava o resultado informado pelo agente para um trabalho que ele pegou
+func (s *PrintService) CompleteAgentJob(job *models.PrintJob, result models.PrintAgentResult) error {
+	if job.Status != models.PrintJobStatusPrinting {
+		return fmt.Errorf("invalid_print_job: job is %s", job.Status)
+	}
+
+	job.UpdatedAt = time.Now()
+	var err error
+	if !result.Success {
+		err = errors.New(result.Error)
+		if result.Error == "" {
+			err = errors.New("print agent reported a failure")
+		}
+	}
+	s.finish(job, err)
+	return nil
+}
+
+// finish grava o resultado da tentativa: impresso, nova tentativa com espera crescente ou falha final
+func (s *PrintService) finish(job *models.PrintJob, err error) {
+	if err == nil {
+		printedAt := time.Now()
+		job.Status = models.PrintJobStatusPrinted
+		job.PrintedAt = &printedAt
+		job.LastError = ""
+	} else {
+		job.LastError = err.Error()
+		if job.Attempts >= job.MaxAttempts {
+			job.Status = models.PrintJobStatusFailed
+			GetRealtimeBus().Publish(job.OrganizationId, job.ProjectId, RealtimeTopicKitchen, "print.failed", job)
+		} else {
+			job.Status = models.PrintJobStatusPending
+			job.NextAttemptAt = time.Now().Add(time.Duration(job.Attempts) * printJobRetryDelay)
+		}
+	}
+
+	if err := s.repo.UpdateJob(job); err != nil {
+		log.Printf("Error saving print job %s: %v", job.Id, err)
+	}
+}
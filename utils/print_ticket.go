@@ -0,0 +1,193 @@
+package utils
+
+import (
+	"fmt"
+	"lep/repositories/models"
+	"strings"
+	"time"
+)
+
+// TicketOptions dados de impressão que não vêm do pedido. PrintedAt é informado por quem chama
+// para que a mesma entrada gere sempre os mesmos bytes.
+type TicketOptions struct {
+	PaperWidth int
+	Title      string // estação da cozinha ou nome do estabelecimento
+	PrintedAt  time.Time
+	Reprint    bool
+}
+
+var variantLabels = map[string]string{
+	PriceVariantBottle:     "garrafa",
+	PriceVariantHalfBottle: "meia garrafa",
+	PriceVariantGlass:      "taca",
+}
+
+// RenderKitchenTicket gera a comanda de cozinha (ESC/POS) com os itens informados do pedido
+func RenderKitchenTicket(order models.Order, items []models.OrderItem, opts TicketOptions) []byte {
+	b := NewEscPosBuilder(opts.PaperWidth)
+
+	b.Align(EscPosAlignCenter).Bold(true).DoubleSize(true).Line(ticketOrderLabel(order)).DoubleSize(false)
+	if opts.Title != "" {
+		b.Line(opts.Title)
+	}
+	if opts.Reprint {
+		b.Line("*** REIMPRESSAO ***")
+	}
+	b.Bold(false).Align(EscPosAlignLeft)
+
+	b.Line("Pedido: #" + shortId(order.Id.String()))
+	b.Line("Hora: " + order.CreatedAt.Format("02/01 15:04"))
+	if order.Source == "public" && order.Type != models.OrderTypeTakeout && order.Type != models.OrderTypeDelivery {
+		b.Line("Origem: QR Code da mesa")
+	}
+	if order.ScheduledFor != nil {
+		b.Bold(true).Line("Agendado: " + order.ScheduledFor.Format("02/01 15:04")).Bold(false)
+	}
+	b.Separator()
+
+	course := ""
+	for _, item := range items {
+		if item.Course != "" && item.Course != course {
+			course = item.Course
+			b.Bold(true).Line("-- " + strings.ToUpper(course) + " --").Bold(false)
+		}
+		b.Bold(true).Line(fmt.Sprintf("%dx %s", item.Quantity, orderItemLabel(item))).Bold(false)
+		for _, modifier := range item.Modifiers {
+			b.Indented(modifier.OptionName, "   + ")
+		}
+		if item.Seat > 0 {
+			b.Indented(fmt.Sprintf("Lugar %d", item.Seat), "   ")
+		}
+		if item.Notes != "" {
+			b.Bold(true).Indented("OBS: "+item.Notes, "   ").Bold(false)
+		}
+	}
+
+	b.Separator()
+	if order.Note != "" {
+		b.Bold(true).Line("OBS PEDIDO: " + order.Note).Bold(false).Separator()
+	}
+
+	b.Align(EscPosAlignCenter).QRCode(order.Id.String(), 6)
+	b.Line("Impresso em " + opts.PrintedAt.Format("02/01/2006 15:04"))
+	return b.Feed(3).Cut().Bytes()
+}
+
+// RenderReceipt gera a conferência de conta do cliente com todos os pedidos da comanda
+func RenderReceipt(summary models.TabSummary, tableNumber *int, opts TicketOptions) []byte {
+	b := NewEscPosBuilder(opts.PaperWidth)
+
+	b.Align(EscPosAlignCenter).Bold(true)
+	if opts.Title != "" {
+		b.Line(opts.Title)
+	}
+	b.Line("CONFERENCIA DE CONTA")
+	if opts.Reprint {
+		b.Line("*** REIMPRESSAO ***")
+	}
+	b.DoubleSize(true).Line(ticketTableLabel(tableNumber)).DoubleSize(false).Bold(false)
+	b.Align(EscPosAlignLeft)
+	b.Line("Comanda: #" + shortId(summary.Tab.Id.String()))
+	b.Line("Abertura: " + summary.Tab.OpenedAt.Format("02/01/2006 15:04"))
+	b.Separator()
+
+	for _, order := range summary.Orders {
+		if order.Status == models.OrderStatusCancelled {
+			continue
+		}
+		for _, item := range order.Items {
+			unit := item.Price + ModifiersDelta(item)
+			b.Columns(fmt.Sprintf("%dx %s", item.Quantity, orderItemLabel(item)), FormatBRL(unit*float64(item.Quantity)))
+			for _, modifier := range item.Modifiers {
+				b.Indented(modifier.OptionName, "   + ")
+			}
+		}
+	}
+
+	b.Separator()
+	b.Columns("Subtotal", FormatBRL(summary.Tab.Subtotal))
+	if summary.Tab.ServiceCharge > 0 {
+		b.Columns(fmt.Sprintf("Servico (%g%%)", summary.Tab.ServiceChargePercent), FormatBRL(summary.Tab.ServiceCharge))
+	}
+	b.Bold(true).Columns("TOTAL", FormatBRL(summary.Tab.Total)).Bold(false)
+	if summary.Tab.PaidTotal > 0 {
+		b.Columns("Pago", FormatBRL(summary.Tab.PaidTotal))
+		b.Bold(true).Columns("A PAGAR", FormatBRL(summary.Balance)).Bold(false)
+	}
+	if summary.Tab.Guests > 1 {
+		b.Columns(fmt.Sprintf("Por pessoa (%d)", summary.Tab.Guests), FormatBRL(summary.Balance/float64(summary.Tab.Guests)))
+	}
+
+	b.Separator()
+	b.Align(EscPosAlignCenter)
+	b.Line("Nao e documento fiscal")
+	b.Line("Impresso em " + opts.PrintedAt.Format("02/01/2006 15:04"))
+	return b.Feed(3).Cut().Bytes()
+}
+
+// RenderTestPage cupom de teste para validar a configuração da impressora
+func RenderTestPage(printer models.Printer, printedAt time.Time) []byte {
+	b := NewEscPosBuilder(printer.PaperWidth)
+	b.Align(EscPosAlignCenter).Bold(true).DoubleSize(true).Line("TESTE").DoubleSize(false).Bold(false)
+	b.Line(printer.Name)
+	b.Line(fmt.Sprintf("%s:%d - %dmm", printer.Host, printer.Port, printer.PaperWidth))
+	b.Separator()
+	b.Line("Impresso em " + printedAt.Format("02/01/2006 15:04"))
+	return b.Feed(3).Cut().Bytes()
+}
+
+// FormatBRL formata valor em reais (ex.: R$ 1.234,56)
+func FormatBRL(value float64) string {
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	raw := fmt.Sprintf("%.2f", RoundMoney(value))
+	integer, cents := raw[:len(raw)-3], raw[len(raw)-2:]
+	var groups []string
+	for len(integer) > 3 {
+		groups = append([]string{integer[len(integer)-3:]}, groups...)
+		integer = integer[:len(integer)-3]
+	}
+	groups = append([]string{integer}, groups...)
+
+	return sign + "R$ " + strings.Join(groups, ".") + "," + cents
+}
+
+// ticketOrderLabel cabeçalho da comanda: retirada e entrega em destaque no lugar da mesa
+func ticketOrderLabel(order models.Order) string {
+	switch order.Type {
+	case models.OrderTypeTakeout:
+		return "VIAGEM"
+	case models.OrderTypeDelivery:
+		return "DELIVERY"
+	}
+	return ticketTableLabel(order.TableNumber)
+}
+
+func ticketTableLabel(tableNumber *int) string {
+	if tableNumber == nil {
+		return "BALCAO"
+	}
+	return fmt.Sprintf("MESA %d", *tableNumber)
+}
+
+func orderItemLabel(item models.OrderItem) string {
+	name := item.ProductName
+	if name == "" {
+		name = "Item " + shortId(item.ProductId.String())
+	}
+	if label, ok := variantLabels[item.Variant]; ok {
+		name += " (" + label + ")"
+	}
+	return name
+}
+
+func shortId(id string) string {
+	if len(id) < 8 {
+		return id
+	}
+	return strings.ToUpper(id[:8])
+}
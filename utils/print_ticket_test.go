@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"bytes"
+	"flag"
+	"lep/repositories/models"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// go test ./utils -run TestTicketGolden -update regrava os arquivos esperados em testdata/
+var updateGolden = flag.Bool("update", false, "regrava os arquivos golden")
+
+func TestTicketGolden(t *testing.T) {
+	createdAt := time.Date(2026, 3, 14, 19, 42, 0, 0, time.UTC)
+	printedAt := time.Date(2026, 3, 14, 19, 45, 0, 0, time.UTC)
+	scheduledFor := time.Date(2026, 3, 14, 20, 30, 0, 0, time.UTC)
+	tableNumber := 12
+
+	dineIn := models.Order{
+		Id:          uuid.MustParse("3f2a9c1e-5b7d-4e8f-9a0b-1c2d3e4f5a6b"),
+		TableNumber: &tableNumber,
+		Source:      "public",
+		Type:        models.OrderTypeDineIn,
+		Note:        "Cliente alérgico a amendoim",
+		CreatedAt:   createdAt,
+	}
+	dineInItems := []models.OrderItem{
+		{
+			ProductId:   uuid.MustParse("a1b2c3d4-0000-4000-8000-000000000001"),
+			ProductName: "Bruschetta de tomate",
+			Quantity:    2,
+			Price:       32.5,
+			Course:      models.OrderCourseStarter,
+		},
+		{
+			ProductId:   uuid.MustParse("a1b2c3d4-0000-4000-8000-000000000002"),
+			ProductName: "Filé ao molho madeira com arroz, batatas rústicas e legumes salteados",
+			Quantity:    1,
+			Price:       89.9,
+			Course:      models.OrderCourseMain,
+			Seat:        3,
+			Notes:       "ponto para mal passado",
+			Modifiers: []models.OrderItemModifier{
+				{OptionName: "Bacon extra", PriceDelta: 8},
+				{OptionName: "Sem cebola"},
+			},
+		},
+		{
+			ProductId: uuid.MustParse("a1b2c3d4-0000-4000-8000-000000000003"),
+			Quantity:  1,
+			Price:     120,
+			Variant:   PriceVariantHalfBottle,
+			Course:    models.OrderCourseMain,
+		},
+	}
+
+	takeout := models.Order{
+		Id:           uuid.MustParse("7c6b5a49-3827-4165-9f8e-7d6c5b4a3928"),
+		Source:       "public",
+		Type:         models.OrderTypeTakeout,
+		ScheduledFor: &scheduledFor,
+		CreatedAt:    createdAt,
+	}
+	takeoutItems := []models.OrderItem{
+		{ProductName: "Pão de queijo", Quantity: 6, Price: 4.5},
+	}
+
+	cancelled := dineIn
+	cancelled.Status = models.OrderStatusCancelled
+	cancelled.Items = []models.OrderItem{{ProductName: "Não deve aparecer", Quantity: 1, Price: 999}}
+	delivered := dineIn
+	delivered.Status = models.OrderStatusDelivered
+	delivered.Items = dineInItems
+	summary := models.TabSummary{
+		Tab: models.Tab{
+			Id:                   uuid.MustParse("0d1e2f3a-4b5c-4d6e-8f70-8192a3b4c5d6"),
+			Guests:               4,
+			ServiceChargePercent: 10,
+			Subtotal:             1282.9,
+			ServiceCharge:        128.29,
+			Total:                1411.19,
+			PaidTotal:            400,
+			OpenedAt:             createdAt.Add(-time.Hour),
+		},
+		Orders:  []models.Order{cancelled, delivered},
+		Balance: 1011.19,
+	}
+
+	cases := []struct {
+		name    string
+		payload []byte
+	}{
+		{"kitchen_dine_in_80", RenderKitchenTicket(dineIn, dineInItems, TicketOptions{
+			PaperWidth: PaperWidth80, Title: "Grelha", PrintedAt: printedAt, Reprint: true,
+		})},
+		{"kitchen_takeout_58", RenderKitchenTicket(takeout, takeoutItems, TicketOptions{
+			PaperWidth: PaperWidth58, PrintedAt: printedAt,
+		})},
+		{"receipt_80", RenderReceipt(summary, &tableNumber, TicketOptions{
+			PaperWidth: PaperWidth80, Title: "Cantina São João", PrintedAt: printedAt,
+		})},
+		{"receipt_counter_58", RenderReceipt(summary, nil, TicketOptions{
+			PaperWidth: PaperWidth58, PrintedAt: printedAt, Reprint: true,
+		})},
+		{"test_page_58", RenderTestPage(models.Printer{
+			Name: "Cozinha quente", Host: "192.168.0.50", Port: 9100, PaperWidth: PaperWidth58,
+		}, printedAt)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join("testdata", tc.name+".golden")
+			if *updateGolden {
+				if err := os.WriteFile(path, tc.payload, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("reading %s (run with -update to create it): %v", path, err)
+			}
+			if !bytes.Equal(tc.payload, want) {
+				t.Errorf("%s differs from golden file (%d bytes, want %d)\ngot:  %q\nwant: %q", tc.name, len(tc.payload), len(want), tc.payload, want)
+			}
+		})
+	}
+}
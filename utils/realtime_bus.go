@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tópicos do stream em tempo real
+const (
+	RealtimeTopicKitchen  = "kitchen"  // pedidos e fila da cozinha
+	RealtimeTopicFloor    = "floor"    // reservas e salão
+	RealtimeTopicWaitlist = "waitlist" // fila de espera
+)
+
+// RealtimeTopics lista os tópicos aceitos na assinatura
+var RealtimeTopics = []string{RealtimeTopicKitchen, RealtimeTopicFloor, RealtimeTopicWaitlist}
+
+const (
+	realtimeHistorySize    = 200 // eventos mantidos por tenant para retomada via last-event id
+	realtimeSubscriberSize = 64  // buffer do canal de cada assinante
+)
+
+// RealtimeEvent evento publicado no barramento em tempo real
+type RealtimeEvent struct {
+	Id             uint64      `json:"id"`
+	Topic          string      `json:"topic"`
+	Type           string      `json:"type"`
+	OrganizationId string      `json:"organization_id"`
+	ProjectId      string      `json:"project_id"`
+	Data           interface{} `json:"data,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// RealtimeSubscription assinatura de um cliente conectado
+type RealtimeSubscription struct {
+	Events chan RealtimeEvent
+	tenant string
+	topics map[string]bool
+}
+
+// RealtimeBus barramento de eventos em memória, isolado por organização/projeto
+type RealtimeBus struct {
+	mu          sync.RWMutex
+	lastId      uint64
+	history     map[string][]RealtimeEvent
+	subscribers map[string]map[*RealtimeSubscription]bool
+}
+
+var (
+	realtimeBus     *RealtimeBus
+	realtimeBusOnce sync.Once
+)
+
+// GetRealtimeBus retorna o barramento compartilhado pelo processo
+func GetRealtimeBus() *RealtimeBus {
+	realtimeBusOnce.Do(func() {
+		realtimeBus = NewRealtimeBus()
+	})
+	return realtimeBus
+}
+
+func NewRealtimeBus() *RealtimeBus {
+	return &RealtimeBus{
+		history:     make(map[string][]RealtimeEvent),
+		subscribers: make(map[string]map[*RealtimeSubscription]bool),
+	}
+}
+
+func realtimeTenantKey(orgId, projectId string) string {
+	return orgId + ":" + projectId
+}
+
+// Publish publica um evento para os assinantes do tenant e guarda no histórico
+func (b *RealtimeBus) Publish(orgId, projectId uuid.UUID, topic, eventType string, data interface{}) RealtimeEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastId++
+	event := RealtimeEvent{
+		Id:             b.lastId,
+		Topic:          topic,
+		Type:           eventType,
+		OrganizationId: orgId.String(),
+		ProjectId:      projectId.String(),
+		Data:           data,
+		CreatedAt:      time.Now(),
+	}
+
+	tenant := realtimeTenantKey(event.OrganizationId, event.ProjectId)
+	history := append(b.history[tenant], event)
+	if len(history) > realtimeHistorySize {
+		history = history[len(history)-realtimeHistorySize:]
+	}
+	b.history[tenant] = history
+
+	for sub := range b.subscribers[tenant] {
+		if !sub.topics[topic] {
+			continue
+		}
+		select {
+		case sub.Events <- event:
+		default:
+			// Assinante lento: encerra a conexão para que o cliente reconecte com o last-event id
+			delete(b.subscribers[tenant], sub)
+			close(sub.Events)
+		}
+	}
+
+	return event
+}
+
+// Subscribe registra um assinante nos tópicos informados.
+// Retorna os eventos posteriores a lastEventId que ainda estão no histórico e
+// complete=false quando parte deles já foi descartada (cliente deve recarregar o estado).
+func (b *RealtimeBus) Subscribe(orgId, projectId string, topics []string, lastEventId uint64) (*RealtimeSubscription, []RealtimeEvent, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tenant := realtimeTenantKey(orgId, projectId)
+	sub := &RealtimeSubscription{
+		Events: make(chan RealtimeEvent, realtimeSubscriberSize),
+		tenant: tenant,
+		topics: make(map[string]bool),
+	}
+	for _, topic := range topics {
+		sub.topics[topic] = true
+	}
+
+	if b.subscribers[tenant] == nil {
+		b.subscribers[tenant] = make(map[*RealtimeSubscription]bool)
+	}
+	b.subscribers[tenant][sub] = true
+
+	if lastEventId == 0 {
+		return sub, nil, true
+	}
+
+	// Ids maiores que o último publicado indicam reinício do servidor
+	if lastEventId > b.lastId {
+		return sub, nil, false
+	}
+
+	history := b.history[tenant]
+	// Ids são globais (intercalados entre tenants); só há perda se o histórico do tenant já foi truncado
+	complete := len(history) < realtimeHistorySize || history[0].Id <= lastEventId
+	missed := make([]RealtimeEvent, 0)
+	for _, event := range history {
+		if event.Id > lastEventId && sub.topics[event.Topic] {
+			missed = append(missed, event)
+		}
+	}
+
+	return sub, missed, complete
+}
+
+// Unsubscribe remove o assinante do barramento
+func (b *RealtimeBus) Unsubscribe(sub *RealtimeSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if subs, ok := b.subscribers[sub.tenant]; ok && subs[sub] {
+		delete(subs, sub)
+		close(sub.Events)
+		if len(subs) == 0 {
+			delete(b.subscribers, sub.tenant)
+		}
+	}
+}
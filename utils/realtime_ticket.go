@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"errors"
+	"lep/config"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// RealtimeTicketTTL validade do ticket dos streams (EventSource/WebSocket não enviam headers)
+const RealtimeTicketTTL = 60 * time.Second
+
+const realtimeTicketPurpose = "realtime"
+
+// RealtimeTicket escopo do ticket de conexão em tempo real
+type RealtimeTicket struct {
+	UserId         string
+	UserType       string
+	OrganizationId string
+	ProjectId      string
+}
+
+// NewRealtimeTicket assina um ticket curto para abrir /realtime/stream ou /realtime/ws via query
+func NewRealtimeTicket(ticket RealtimeTicket) (string, time.Time, error) {
+	expiresAt := time.Now().Add(RealtimeTicketTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"purpose":         realtimeTicketPurpose,
+		"user_id":         ticket.UserId,
+		"user_type":       ticket.UserType,
+		"organization_id": ticket.OrganizationId,
+		"project_id":      ticket.ProjectId,
+		"exp":             expiresAt.Unix(),
+	})
+	signed, err := token.SignedString([]byte(config.JWT_SECRET_PRIVATE_KEY))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ParseRealtimeTicket valida assinatura, validade e finalidade do ticket.
+// Tokens de login não são aceitos (sem purpose=realtime).
+func ParseRealtimeTicket(raw string) (*RealtimeTicket, error) {
+	token, err := jwt.Parse(raw, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(config.JWT_SECRET_PRIVATE_KEY), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrSignatureInvalid
+	}
+	if purpose, _ := claims["purpose"].(string); purpose != realtimeTicketPurpose {
+		return nil, errors.New("not a realtime ticket")
+	}
+
+	ticket := &RealtimeTicket{}
+	ticket.UserId, _ = claims["user_id"].(string)
+	ticket.UserType, _ = claims["user_type"].(string)
+	ticket.OrganizationId, _ = claims["organization_id"].(string)
+	ticket.ProjectId, _ = claims["project_id"].(string)
+	if ticket.OrganizationId == "" || ticket.ProjectId == "" {
+		return nil, errors.New("ticket without organization or project")
+	}
+	return ticket, nil
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"lep/repositories/models"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+type serviceRequestAccumulator struct {
+	environmentId *uuid.UUID
+	label         string
+	total         int
+	open          int
+	acknowledge   []float64 // segundos até o chamado ser visto
+	resolve       []float64 // segundos até o chamado ser resolvido
+	byType        map[string]int
+}
+
+func (a *serviceRequestAccumulator) add(request models.ServiceRequest) {
+	a.total++
+	a.byType[request.Type]++
+	if request.AcknowledgedAt != nil {
+		a.acknowledge = append(a.acknowledge, request.AcknowledgedAt.Sub(request.CreatedAt).Seconds())
+	}
+	if request.ResolvedAt != nil {
+		a.resolve = append(a.resolve, request.ResolvedAt.Sub(request.CreatedAt).Seconds())
+	} else {
+		a.open++
+	}
+}
+
+func (a *serviceRequestAccumulator) result() models.ServiceRequestMetrics {
+	result := models.ServiceRequestMetrics{
+		EnvironmentId: a.environmentId,
+		Label:         a.label,
+		Total:         a.total,
+		Open:          a.open,
+		ByType:        a.byType,
+	}
+	if len(a.acknowledge) > 0 {
+		result.AvgAcknowledgeSeconds, _, result.P90AcknowledgeSeconds = summarizeMinutes(a.acknowledge)
+	}
+	if len(a.resolve) > 0 {
+		result.AvgResolveSeconds, _, _ = summarizeMinutes(a.resolve)
+	}
+	return result
+}
+
+// BuildServiceRequestReport tempos até o chamado ser visto e resolvido, no total e por setor (ambiente da mesa)
+func BuildServiceRequestReport(requests []models.ServiceRequest, environments []models.Environment) (models.ServiceRequestMetrics, []models.ServiceRequestMetrics) {
+	names := make(map[uuid.UUID]string, len(environments))
+	for _, environment := range environments {
+		names[environment.Id] = environment.Name
+	}
+
+	overall := &serviceRequestAccumulator{label: "Todos", byType: make(map[string]int)}
+	sections := make(map[string]*serviceRequestAccumulator)
+	for _, request := range requests {
+		overall.add(request)
+
+		key, label := "none", "Sem setor"
+		if request.EnvironmentId != nil {
+			key = request.EnvironmentId.String()
+			label = names[*request.EnvironmentId]
+		}
+		acc, ok := sections[key]
+		if !ok {
+			acc = &serviceRequestAccumulator{environmentId: request.EnvironmentId, label: label, byType: make(map[string]int)}
+			sections[key] = acc
+		}
+		acc.add(request)
+	}
+
+	list := make([]models.ServiceRequestMetrics, 0, len(sections))
+	for _, acc := range sections {
+		list = append(list, acc.result())
+	}
+	// Mais lentos primeiro
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].AvgAcknowledgeSeconds != list[j].AvgAcknowledgeSeconds {
+			return list[i].AvgAcknowledgeSeconds > list[j].AvgAcknowledgeSeconds
+		}
+		return list[i].Label < list[j].Label
+	})
+	return overall.result(), list
+}
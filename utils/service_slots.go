@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"encoding/json"
+	"lep/repositories/models"
+	"strconv"
+	"time"
+)
+
+// ServiceTimeSlots horários (HH:MM) de funcionamento do dia a partir das configurações do projeto:
+// almoço e jantar, respeitando a agenda semanal do dia da data civil de date (local do projeto). intervalMinutes <= 0 usa o intervalo das reservas.
+// Sem configurações, usa os horários padrão (12:00-14:30 e 19:00-22:00).
+func ServiceTimeSlots(date time.Time, settings *models.Settings, intervalMinutes int) []string {
+	lunchStart, lunchEnd := "12:00", "14:30"
+	dinnerStart, dinnerEnd := "19:00", "22:00"
+	slotInterval := 30
+	enableLunch, enableDinner := true, true
+
+	if settings != nil {
+		if settings.LunchStart != "" {
+			lunchStart = settings.LunchStart
+		}
+		if settings.LunchEnd != "" {
+			lunchEnd = settings.LunchEnd
+		}
+		if settings.DinnerStart != "" {
+			dinnerStart = settings.DinnerStart
+		}
+		if settings.DinnerEnd != "" {
+			dinnerEnd = settings.DinnerEnd
+		}
+		if settings.SlotIntervalMinutes > 0 {
+			slotInterval = settings.SlotIntervalMinutes
+		}
+		enableLunch = settings.EnableLunch
+		enableDinner = settings.EnableDinner
+
+		// Agenda semanal: sobrescreve enableLunch/enableDinner para o dia da semana solicitado
+		if settings.OperatingScheduleJson != "" {
+			type dayConfig struct {
+				Enabled      bool `json:"enabled"`
+				EnableLunch  bool `json:"enable_lunch"`
+				EnableDinner bool `json:"enable_dinner"`
+			}
+			var schedule map[string]dayConfig
+			if jsonErr := json.Unmarshal([]byte(settings.OperatingScheduleJson), &schedule); jsonErr == nil {
+				dayKey := strconv.Itoa(int(date.Weekday())) // 0=Domingo ... 6=Sábado
+				if dc, ok := schedule[dayKey]; ok {
+					if !dc.Enabled {
+						return nil // restaurante fechado neste dia
+					}
+					enableLunch = dc.EnableLunch
+					enableDinner = dc.EnableDinner
+				}
+			}
+		}
+	}
+	if intervalMinutes > 0 {
+		slotInterval = intervalMinutes
+	}
+
+	var timeSlots []string
+	if enableLunch {
+		timeSlots = append(timeSlots, BuildTimeSlots(lunchStart, lunchEnd, slotInterval)...)
+	}
+	if enableDinner {
+		timeSlots = append(timeSlots, BuildTimeSlots(dinnerStart, dinnerEnd, slotInterval)...)
+	}
+	return timeSlots
+}
+
+// BuildTimeSlots gera uma lista de horários (HH:MM) entre start e end com o intervalo dado em minutos.
+func BuildTimeSlots(start, end string, intervalMinutes int) []string {
+	startTime, err := time.Parse("15:04", start)
+	if err != nil {
+		return nil
+	}
+	endTime, err := time.Parse("15:04", end)
+	if err != nil {
+		return nil
+	}
+	var slots []string
+	cur := startTime
+	for !cur.After(endTime) {
+		slots = append(slots, cur.Format("15:04"))
+		cur = cur.Add(time.Duration(intervalMinutes) * time.Minute)
+	}
+	return slots
+}
+
+// SlotDateTime combina a data civil com o horário HH:MM do slot no fuso do projeto
+func SlotDateTime(date time.Time, slot string, loc *time.Location) (time.Time, error) {
+	return NewClock(loc).At(date, slot)
+}
@@ -0,0 +1,339 @@
+package utils
+
+import (
+	"fmt"
+	"lep/repositories"
+	"lep/repositories/models"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SLAMonitorService verifica os pedidos da cozinha que passaram do horário estimado
+// ou estão parados em um status, registra a violação e alerta a equipe
+type SLAMonitorService struct {
+	repo         *repositories.DBconn
+	eventService *EventService
+}
+
+func NewSLAMonitorService(repo *repositories.DBconn, eventService *EventService) *SLAMonitorService {
+	return &SLAMonitorService{repo: repo, eventService: eventService}
+}
+
+// defaultSLASettings limites usados quando o projeto não tem configurações
+func defaultSLASettings() *models.Settings {
+	return &models.Settings{
+		SlaGraceMinutes:        5,
+		SlaPendingMaxMinutes:   15,
+		SlaPreparingMaxMinutes: 45,
+		SlaReadyMaxMinutes:     10,
+		LunchStart:             "12:00",
+		LunchEnd:               "14:30",
+		DinnerStart:            "19:00",
+		DinnerEnd:              "22:00",
+		DiningDurationMinutes:  120,
+	}
+}
+
+// slaBreachKey identifica a violação: atraso uma vez por pedido, parado uma vez por status
+func slaBreachKey(orderId uuid.UUID, breachType, status string) string {
+	if breachType == models.SLABreachTypeLate {
+		status = ""
+	}
+	return orderId.String() + "|" + breachType + "|" + status
+}
+
+// slaBreachOpen indica se a violação continua valendo para o estado atual do pedido
+func slaBreachOpen(breach models.OrderSLABreach, order models.Order) bool {
+	if breach.Type == models.SLABreachTypeLate {
+		return order.Status == models.OrderStatusPending || order.Status == models.OrderStatusPreparing
+	}
+	return order.Status == breach.OrderStatus
+}
+
+// slaStatusEnteredAt momento em que o pedido entrou no status atual
+func slaStatusEnteredAt(order models.Order, latest *models.OrderStatusHistory) *time.Time {
+	if latest != nil && latest.ToStatus == order.Status {
+		return &latest.ChangedAt
+	}
+	switch order.Status {
+	case models.OrderStatusPending:
+		return &order.CreatedAt
+	case models.OrderStatusPreparing:
+		return order.StartedAt
+	case models.OrderStatusReady:
+		return order.ReadyAt
+	}
+	return nil
+}
+
+// DetectSLABreaches retorna as violações atuais do pedido (sem considerar as já registradas)
+func DetectSLABreaches(order models.Order, enteredAt *time.Time, settings *models.Settings, now time.Time) []models.OrderSLABreach {
+	var breaches []models.OrderSLABreach
+	newBreach := func(breachType string, expectedAt time.Time) models.OrderSLABreach {
+		return models.OrderSLABreach{
+			Id:             uuid.New(),
+			OrganizationId: order.OrganizationId,
+			ProjectId:      order.ProjectId,
+			OrderId:        order.Id,
+			Type:           breachType,
+			OrderStatus:    order.Status,
+			ExpectedAt:     expectedAt,
+			DetectedAt:     now,
+			DelayMinutes:   int(now.Sub(expectedAt).Minutes()),
+			CreatedAt:      now,
+		}
+	}
+
+	inKitchen := order.Status == models.OrderStatusPending || order.Status == models.OrderStatusPreparing
+	if inKitchen && order.EstimatedDeliveryTime != nil {
+		grace := time.Duration(settings.SlaGraceMinutes) * time.Minute
+		if now.After(order.EstimatedDeliveryTime.Add(grace)) {
+			breaches = append(breaches, newBreach(models.SLABreachTypeLate, *order.EstimatedDeliveryTime))
+		}
+	}
+
+	maxMinutes := 0
+	switch order.Status {
+	case models.OrderStatusPending:
+		maxMinutes = settings.SlaPendingMaxMinutes
+	case models.OrderStatusPreparing:
+		maxMinutes = settings.SlaPreparingMaxMinutes
+	case models.OrderStatusReady:
+		maxMinutes = settings.SlaReadyMaxMinutes
+	}
+	if maxMinutes > 0 && enteredAt != nil {
+		limit := enteredAt.Add(time.Duration(maxMinutes) * time.Minute)
+		if now.After(limit) {
+			breaches = append(breaches, newBreach(models.SLABreachTypeStuck, limit))
+		}
+	}
+
+	return breaches
+}
+
+// CheckOrders resolve as violações que deixaram de valer e registra/alerta as novas
+func (s *SLAMonitorService) CheckOrders(now time.Time) error {
+	orders, err := s.repo.SLA.ListActiveOrders()
+	if err != nil {
+		return err
+	}
+	openBreaches, err := s.repo.SLA.ListOpenBreaches()
+	if err != nil {
+		return err
+	}
+
+	active := make(map[uuid.UUID]models.Order, len(orders))
+	orderIds := make([]uuid.UUID, 0, len(orders))
+	for _, order := range orders {
+		active[order.Id] = order
+		orderIds = append(orderIds, order.Id)
+	}
+
+	registered := make(map[string]bool)
+	var resolved []uuid.UUID
+	for _, breach := range openBreaches {
+		order, ok := active[breach.OrderId]
+		if !ok || !slaBreachOpen(breach, order) {
+			resolved = append(resolved, breach.Id)
+			continue
+		}
+		registered[slaBreachKey(breach.OrderId, breach.Type, breach.OrderStatus)] = true
+	}
+	if err := s.repo.SLA.ResolveBreaches(resolved, now); err != nil {
+		log.Printf("Error resolving SLA breaches: %v", err)
+	}
+
+	latest := make(map[uuid.UUID]*models.OrderStatusHistory)
+	if entries, err := s.repo.OrderStatusHistory.ListLatestByOrders(orderIds); err == nil {
+		for i := range entries {
+			latest[entries[i].OrderId] = &entries[i]
+		}
+	}
+
+	settingsByProject := make(map[uuid.UUID]*models.Settings)
+	for _, order := range orders {
+		settings, ok := settingsByProject[order.ProjectId]
+		if !ok {
+			settings, err = s.repo.Settings.GetSettingsByProject(order.OrganizationId, order.ProjectId)
+			if err != nil {
+				settings = defaultSLASettings()
+			}
+			settingsByProject[order.ProjectId] = settings
+		}
+
+		for _, breach := range DetectSLABreaches(order, slaStatusEnteredAt(order, latest[order.Id]), settings, now) {
+			key := slaBreachKey(breach.OrderId, breach.Type, breach.OrderStatus)
+			if registered[key] {
+				continue
+			}
+			registered[key] = true
+
+			created, err := s.repo.SLA.CreateBreach(&breach)
+			if err != nil {
+				log.Printf("Error registering SLA breach for order %s: %v", order.Id, err)
+				continue
+			}
+			if !created {
+				continue
+			}
+			s.alert(order, &breach)
+		}
+	}
+	return nil
+}
+
+// alert avisa a cozinha em tempo real e o telefone responsável do projeto
+func (s *SLAMonitorService) alert(order models.Order, breach *models.OrderSLABreach) {
+	GetRealtimeBus().Publish(order.OrganizationId, order.ProjectId, RealtimeTopicKitchen, "order.sla_breach", breach)
+
+	tableNumber := order.TableNumber
+	if tableNumber == nil && order.TableId != nil {
+		if table, err := s.repo.Tables.GetTableById(*order.TableId); err == nil {
+			tableNumber = &table.Number
+		}
+	}
+
+	if err := s.eventService.TriggerOrderSLABreach(&order, breach, tableNumber); err != nil {
+		log.Printf("Error alerting SLA breach for order %s: %v", order.Id, err)
+		return
+	}
+
+	breach.Alerted = true
+	if err := s.repo.SLA.UpdateBreach(breach); err != nil {
+		log.Printf("Error updating SLA breach %s: %v", breach.Id, err)
+	}
+}
+
+// clockMinutes converte "HH:MM" em minutos do dia
+func clockMinutes(value string) (int, bool) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hour, &minute); err != nil {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
+
+// SLAShift classifica o horário no turno de almoço ou jantar das configurações.
+// O turno vai até o fim das reservas mais a permanência média, quando a cozinha ainda atende.
+func SLAShift(at time.Time, settings *models.Settings) string {
+	minutes := at.Hour()*60 + at.Minute()
+	inWindow := func(start, end string) bool {
+		from, okFrom := clockMinutes(start)
+		to, okTo := clockMinutes(end)
+		return okFrom && okTo && minutes >= from && minutes < to+settings.DiningDurationMinutes
+	}
+
+	switch {
+	case inWindow(settings.LunchStart, settings.LunchEnd):
+		return models.SLAShiftLunch
+	case inWindow(settings.DinnerStart, settings.DinnerEnd):
+		return models.SLAShiftDinner
+	default:
+		return models.SLAShiftOther
+	}
+}
+
+type slaAccumulator struct {
+	label            string
+	total, onTime    int
+	lateMinutesTotal float64
+}
+
+func (a *slaAccumulator) add(readyAt, promisedAt time.Time, grace time.Duration) {
+	a.total++
+	if !readyAt.After(promisedAt.Add(grace)) {
+		a.onTime++
+		return
+	}
+	a.lateMinutesTotal += readyAt.Sub(promisedAt).Minutes()
+}
+
+func (a *slaAccumulator) result(key string) models.SLAOnTime {
+	result := models.SLAOnTime{Key: key, Label: a.label, Total: a.total, OnTime: a.onTime}
+	if a.total > 0 {
+		result.OnTimePct = roundTenth(float64(a.onTime) / float64(a.total) * 100)
+	}
+	if late := a.total - a.onTime; late > 0 {
+		result.AvgDelayMinutes = roundTenth(a.lateMinutesTotal / float64(late))
+	}
+	return result
+}
+
+// BuildSLAReport calcula o percentual de pedidos prontos no horário estimado (mais a tolerância)
+// por estação e produto (pelo horário em que cada item ficou pronto) e por turno
+func BuildSLAReport(orders []models.Order, stations []models.KitchenStation, products []models.Product, settings *models.Settings, loc *time.Location) (models.SLAOnTime, []models.SLAOnTime, []models.SLAOnTime, []models.SLAOnTime) {
+	grace := time.Duration(settings.SlaGraceMinutes) * time.Minute
+
+	overall := &slaAccumulator{label: "Todos"}
+	byStation := make(map[string]*slaAccumulator)
+	byShift := make(map[string]*slaAccumulator)
+	byProduct := make(map[string]*slaAccumulator)
+	group := func(groups map[string]*slaAccumulator, key, label string) *slaAccumulator {
+		acc, ok := groups[key]
+		if !ok {
+			acc = &slaAccumulator{label: label}
+			groups[key] = acc
+		}
+		return acc
+	}
+
+	stationNames := make(map[uuid.UUID]string, len(stations))
+	for _, station := range stations {
+		stationNames[station.Id] = station.Name
+	}
+	productNames := make(map[uuid.UUID]string, len(products))
+	for _, product := range products {
+		productNames[product.Id] = product.Name
+	}
+
+	for _, order := range orders {
+		if order.ReadyAt == nil || order.EstimatedDeliveryTime == nil {
+			continue
+		}
+		promised := *order.EstimatedDeliveryTime
+
+		overall.add(*order.ReadyAt, promised, grace)
+		shift := SLAShift(order.CreatedAt.In(loc), settings)
+		group(byShift, shift, shift).add(*order.ReadyAt, promised, grace)
+
+		for _, item := range order.Items {
+			readyAt := *order.ReadyAt
+			if item.ReadyAt != nil {
+				readyAt = *item.ReadyAt
+			}
+
+			stationKey, stationLabel := "unrouted", "Sem estação"
+			if item.StationId != nil {
+				stationKey = item.StationId.String()
+				stationLabel = stationNames[*item.StationId]
+			}
+			group(byStation, stationKey, stationLabel).add(readyAt, promised, grace)
+
+			productLabel := productNames[item.ProductId]
+			if productLabel == "" {
+				productLabel = item.ProductName
+			}
+			group(byProduct, item.ProductId.String(), productLabel).add(readyAt, promised, grace)
+		}
+	}
+
+	results := func(groups map[string]*slaAccumulator) []models.SLAOnTime {
+		list := make([]models.SLAOnTime, 0, len(groups))
+		for key, acc := range groups {
+			list = append(list, acc.result(key))
+		}
+		// Piores primeiro
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].OnTimePct != list[j].OnTimePct {
+				return list[i].OnTimePct < list[j].OnTimePct
+			}
+			return list[i].Label < list[j].Label
+		})
+		return list
+	}
+
+	return overall.result("all"), results(byStation), results(byShift), results(byProduct)
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"fmt"
+	"lep/repositories/models"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// RoundMoney arredonda para centavos
+func RoundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// CalculateServiceCharge calcula a taxa de serviço sobre o subtotal
+func CalculateServiceCharge(subtotal, percent float64) float64 {
+	if percent <= 0 {
+		return 0
+	}
+	return RoundMoney(subtotal * percent / 100)
+}
+
+// OrderItemSubtotal valor do item (quantidade x preço unitário com modificadores)
+func OrderItemSubtotal(item models.OrderItem) float64 {
+	return float64(item.Quantity) * (item.Price + ModifiersDelta(item))
+}
+
+// SplitTabEvenly divide o total da comanda em partes iguais; centavos restantes vão para as primeiras partes
+func SplitTabEvenly(tab models.Tab, parts int) ([]models.TabSplitShare, error) {
+	if parts <= 0 {
+		return nil, fmt.Errorf("invalid_tab: parts must be greater than zero")
+	}
+
+	subtotals := distributeCents(tab.Subtotal, parts)
+	services := distributeCents(tab.ServiceCharge, parts)
+
+	shares := make([]models.TabSplitShare, 0, parts)
+	for i := 0; i < parts; i++ {
+		shares = append(shares, models.TabSplitShare{
+			Label:         fmt.Sprintf("%d/%d", i+1, parts),
+			Subtotal:      subtotals[i],
+			ServiceCharge: services[i],
+			Total:         RoundMoney(subtotals[i] + services[i]),
+		})
+	}
+	return shares, nil
+}
+
+// SplitTabBySeat divide a conta pelo lugar de cada item; itens sem lugar formam a parte "shared"
+func SplitTabBySeat(tab models.Tab, orders []models.Order) []models.TabSplitShare {
+	subtotals := make(map[int]float64)
+	for _, order := range orders {
+		if order.Status == "cancelled" {
+			continue
+		}
+		for _, item := range order.Items {
+			subtotals[item.Seat] += OrderItemSubtotal(item)
+		}
+	}
+
+	seats := make([]int, 0, len(subtotals))
+	for seat := range subtotals {
+		seats = append(seats, seat)
+	}
+	sort.Ints(seats)
+
+	shares := make([]models.TabSplitShare, 0, len(seats))
+	for _, seat := range seats {
+		label := fmt.Sprintf("seat %d", seat)
+		if seat == 0 {
+			label = "shared"
+		}
+		shares = append(shares, buildShare(label, subtotals[seat], tab.ServiceChargePercent))
+	}
+	return shares
+}
+
+// SplitTabByItems divide a conta conforme os itens atribuídos a cada grupo.
+// Itens não atribuídos formam a parte "unassigned".
+func SplitTabByItems(tab models.Tab, orders []models.Order, groups []models.TabSplitGroup) ([]models.TabSplitShare, error) {
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("invalid_tab: at least one group is required")
+	}
+
+	itemSubtotals := make(map[uuid.UUID]float64)
+	for _, order := range orders {
+		if order.Status == "cancelled" {
+			continue
+		}
+		for _, item := range order.Items {
+			itemSubtotals[item.Id] = OrderItemSubtotal(item)
+		}
+	}
+
+	assigned := make(map[uuid.UUID]bool)
+	shares := make([]models.TabSplitShare, 0, len(groups)+1)
+	for i, group := range groups {
+		label := group.Label
+		if label == "" {
+			label = fmt.Sprintf("group %d", i+1)
+		}
+
+		subtotal := 0.0
+		for _, itemId := range group.ItemIds {
+			value, exists := itemSubtotals[itemId]
+			if !exists {
+				return nil, fmt.Errorf("invalid_tab: item %s does not belong to this tab", itemId)
+			}
+			if assigned[itemId] {
+				return nil, fmt.Errorf("invalid_tab: item %s assigned to more than one group", itemId)
+			}
+			assigned[itemId] = true
+			subtotal += value
+		}
+		shares = append(shares, buildShare(label, subtotal, tab.ServiceChargePercent))
+	}
+
+	unassigned := 0.0
+	for itemId, value := range itemSubtotals {
+		if !assigned[itemId] {
+			unassigned += value
+		}
+	}
+	if unassigned > 0 {
+		shares = append(shares, buildShare("unassigned", unassigned, tab.ServiceChargePercent))
+	}
+
+	return shares, nil
+}
+
+func buildShare(label string, subtotal, servicePercent float64) models.TabSplitShare {
+	subtotal = RoundMoney(subtotal)
+	service := CalculateServiceCharge(subtotal, servicePercent)
+	return models.TabSplitShare{
+		Label:         label,
+		Subtotal:      subtotal,
+		ServiceCharge: service,
+		Total:         RoundMoney(subtotal + service),
+	}
+}
+
+// distributeCents divide um valor em partes iguais sem perder centavos
+func distributeCents(value float64, parts int) []float64 {
+	cents := int64(math.Round(value * 100))
+	base := cents / int64(parts)
+	remainder := cents % int64(parts)
+
+	result := make([]float64, parts)
+	for i := 0; i < parts; i++ {
+		share := base
+		if int64(i) < remainder {
+			share++
+		}
+		result[i] = float64(share) / 100
+	}
+	return result
+}
@@ -0,0 +1,254 @@
+package utils
+
+import (
+	"lep/repositories/models"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// TableAllocator escolhe mesas para reservas com os dados do período já carregados.
+// Reservations são as reservas ativas (confirmed/pending) que podem ocupar mesas no período;
+// Settings define a permanência de cada grupo.
+type TableAllocator struct {
+	Tables         []models.Table
+	Combinations   []models.TableCombination
+	Reservations   []models.Reservation
+	BlockedPeriods []models.BlockedPeriod
+	Location       *time.Location
+	Settings       *models.Settings
+}
+
+// tableCandidate mesa avulsa ou montagem que pode receber o grupo
+type tableCandidate struct {
+	tables        []models.Table
+	combinationId *uuid.UUID
+	capacity      int
+}
+
+// ReservationTableIds mesas ocupadas pela reserva (todas as da montagem ou a mesa principal)
+func ReservationTableIds(reservation models.Reservation) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(reservation.TableIds)+1)
+	for _, value := range reservation.TableIds {
+		if id, err := uuid.Parse(value); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 && reservation.TableId != nil {
+		ids = append(ids, *reservation.TableId)
+	}
+	return ids
+}
+
+// ApplyTableAllocation grava as mesas escolhidas pelo alocador na reserva
+func ApplyTableAllocation(reservation *models.Reservation, allocation *models.TableAllocation) {
+	if allocation == nil || len(allocation.TableIds) == 0 {
+		return
+	}
+	tableId := allocation.TableIds[0]
+	reservation.TableId = &tableId
+	reservation.TableIds = nil
+	if len(allocation.TableIds) > 1 {
+		reservation.TableIds = make(pq.StringArray, 0, len(allocation.TableIds))
+		for _, id := range allocation.TableIds {
+			reservation.TableIds = append(reservation.TableIds, id.String())
+		}
+	}
+	reservation.AutoAssigned = true
+}
+
+// ProjectBlocked indica bloqueio do projeto inteiro no horário
+func (a *TableAllocator) ProjectBlocked(at time.Time) bool {
+	return models.FindBlockingPeriod(a.BlockedPeriods, at, a.Location, nil) != nil
+}
+
+// BusyTables mesas indisponíveis para um grupo que chega em at: ocupadas por reservas cuja permanência
+// se sobrepõe à do grupo ou em período bloqueado. skip ignora a própria reserva.
+func (a *TableAllocator) BusyTables(at time.Time, partySize int, skip uuid.UUID) map[uuid.UUID]bool {
+	busy := make(map[uuid.UUID]bool)
+	end := at.Add(DiningDuration(a.Settings, partySize))
+	for _, reservation := range a.Reservations {
+		if reservation.Id == skip || reservation.StartsAt == nil {
+			continue
+		}
+		start := *reservation.StartsAt
+		if start.Before(end) && at.Before(start.Add(ReservationDuration(a.Settings, reservation))) {
+			for _, id := range ReservationTableIds(reservation) {
+				busy[id] = true
+			}
+		}
+	}
+	for i := range a.Tables {
+		if models.FindBlockingPeriod(a.BlockedPeriods, at, a.Location, &a.Tables[i]) != nil {
+			busy[a.Tables[i].Id] = true
+		}
+	}
+	return busy
+}
+
+// Allocate melhor mesa (ou montagem) livre para o grupo em at, nesta ordem:
+// 1. no ambiente preferido, quando houver opção lá;
+// 2. menor sobra de lugares;
+// 3. menos mesas juntadas;
+// 4. menor número de mesa (resultado estável).
+func (a *TableAllocator) Allocate(at time.Time, partySize int, environmentId *uuid.UUID, skip uuid.UUID) *models.TableAllocation {
+	candidates := a.candidates(a.BusyTables(at, partySize, skip), partySize)
+	if len(candidates) == 0 {
+		return nil
+	}
+	preferred := func(c tableCandidate) bool {
+		env := candidateEnvironment(c)
+		return environmentId != nil && env != nil && *env == *environmentId
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ci, cj := candidates[i], candidates[j]
+		if pi, pj := preferred(ci), preferred(cj); pi != pj {
+			return pi
+		}
+		if ci.capacity != cj.capacity {
+			return ci.capacity < cj.capacity
+		}
+		if len(ci.tables) != len(cj.tables) {
+			return len(ci.tables) < len(cj.tables)
+		}
+		return ci.tables[0].Number < cj.tables[0].Number
+	})
+	return candidateAllocation(candidates[0], partySize)
+}
+
+// Placeholder maior mesa ou montagem livre em at, para grupos que nenhuma opção comporta
+// (a reserva fica pendente para a equipe ajustar)
+func (a *TableAllocator) Placeholder(at time.Time, partySize int, skip uuid.UUID) *models.TableAllocation {
+	candidates := a.candidates(a.BusyTables(at, partySize, skip), 0)
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].capacity != candidates[j].capacity {
+			return candidates[i].capacity > candidates[j].capacity
+		}
+		return candidates[i].tables[0].Number < candidates[j].tables[0].Number
+	})
+	return candidateAllocation(candidates[0], partySize)
+}
+
+// Table mesa carregada pelo alocador (nil quando não é do projeto)
+func (a *TableAllocator) Table(id uuid.UUID) *models.Table {
+	for i := range a.Tables {
+		if a.Tables[i].Id == id {
+			return &a.Tables[i]
+		}
+	}
+	return nil
+}
+
+// FitsParty indica se alguma mesa ou montagem comporta o grupo (sem olhar a ocupação)
+func (a *TableAllocator) FitsParty(partySize int) bool {
+	return len(a.candidates(nil, partySize)) > 0
+}
+
+// CapacityOf capacidade das mesas de uma reserva (montagem cadastrada ou soma das mesas)
+func (a *TableAllocator) CapacityOf(tableIds []uuid.UUID) int {
+	if len(tableIds) > 1 {
+		for _, combination := range a.Combinations {
+			if combination.Active && combination.DeletedAt == nil && sameTableSet(combination.TableIds, tableIds) {
+				return combination.Capacity
+			}
+		}
+	}
+	capacity := 0
+	for _, id := range tableIds {
+		for _, table := range a.Tables {
+			if table.Id == id {
+				capacity += table.Capacity
+			}
+		}
+	}
+	return capacity
+}
+
+// candidates mesas e montagens livres com capacidade para partySize (0 = qualquer capacidade)
+func (a *TableAllocator) candidates(busy map[uuid.UUID]bool, partySize int) []tableCandidate {
+	byId := make(map[uuid.UUID]models.Table, len(a.Tables))
+	var candidates []tableCandidate
+	for _, table := range a.Tables {
+		if table.DeletedAt != nil {
+			continue
+		}
+		byId[table.Id] = table
+		if !busy[table.Id] && table.Capacity > 0 && table.Capacity >= partySize {
+			candidates = append(candidates, tableCandidate{tables: []models.Table{table}, capacity: table.Capacity})
+		}
+	}
+
+	for i := range a.Combinations {
+		combination := a.Combinations[i]
+		if !combination.Active || combination.DeletedAt != nil || combination.Capacity < partySize || len(combination.TableIds) < 2 {
+			continue
+		}
+		if partySize > 0 && partySize < combination.MinPartySize {
+			continue
+		}
+		tables := make([]models.Table, 0, len(combination.TableIds))
+		for _, value := range combination.TableIds {
+			id, err := uuid.Parse(value)
+			table, ok := byId[id]
+			if err != nil || !ok || busy[id] {
+				tables = nil
+				break
+			}
+			tables = append(tables, table)
+		}
+		if len(tables) == 0 {
+			continue
+		}
+		sort.Slice(tables, func(i, j int) bool { return tables[i].Number < tables[j].Number })
+		candidates = append(candidates, tableCandidate{tables: tables, combinationId: &combination.Id, capacity: combination.Capacity})
+	}
+	return candidates
+}
+
+// candidateEnvironment ambiente da opção (nil quando as mesas estão em ambientes diferentes)
+func candidateEnvironment(candidate tableCandidate) *uuid.UUID {
+	env := candidate.tables[0].EnvironmentId
+	for _, table := range candidate.tables[1:] {
+		if env == nil || table.EnvironmentId == nil || *table.EnvironmentId != *env {
+			return nil
+		}
+	}
+	return env
+}
+
+func candidateAllocation(candidate tableCandidate, partySize int) *models.TableAllocation {
+	allocation := &models.TableAllocation{
+		EnvironmentId: candidateEnvironment(candidate),
+		CombinationId: candidate.combinationId,
+		Capacity:      candidate.capacity,
+	}
+	if candidate.capacity > partySize {
+		allocation.WastedSeats = candidate.capacity - partySize
+	}
+	for _, table := range candidate.tables {
+		allocation.TableIds = append(allocation.TableIds, table.Id)
+		allocation.TableNumbers = append(allocation.TableNumbers, table.Number)
+	}
+	return allocation
+}
+
+func sameTableSet(values []string, ids []uuid.UUID) bool {
+	if len(values) != len(ids) {
+		return false
+	}
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	for _, id := range ids {
+		if !set[id.String()] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"fmt"
+	"lep/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TableStatusAfterService mesa com atendimento fica ocupada; sem atendimento volta a livre
+// (mesa reservada continua reservada)
+func TableStatusAfterService(current string, inService bool) string {
+	if inService {
+		return "ocupada"
+	}
+	if current == "ocupada" {
+		return "livre"
+	}
+	return current
+}
+
+// SplitOrderItems separa os itens informados em um novo pedido com o mesmo status, horários e comanda.
+// Pedido de origem e novo pedido têm o total recalculado; retorna erro se algum item não pertence ao pedido
+// ou se todos os itens foram informados (nesse caso o pedido inteiro deve ser movido).
+func SplitOrderItems(order *models.Order, itemIds []uuid.UUID, now time.Time) (*models.Order, error) {
+	selected := make(map[uuid.UUID]bool, len(itemIds))
+	for _, id := range itemIds {
+		selected[id] = true
+	}
+
+	var kept, moved models.OrderItems
+	for _, item := range order.Items {
+		if selected[item.Id] {
+			moved = append(moved, item)
+			delete(selected, item.Id)
+		} else {
+			kept = append(kept, item)
+		}
+	}
+	for _, id := range itemIds {
+		if selected[id] {
+			return nil, fmt.Errorf("invalid_transfer: item %s not found in order %s", id, order.Id)
+		}
+	}
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("invalid_transfer: all items of order %s selected; move the whole order instead", order.Id)
+	}
+
+	split := *order
+	split.Id = uuid.New()
+	split.Items = moved
+	split.TrackingToken = ""
+	split.TotalAmount = CalculateOrderItemsTotal(moved)
+	split.UpdatedAt = now
+	SyncOrderCourses(&split)
+
+	order.Items = kept
+	order.TotalAmount = CalculateOrderItemsTotal(kept)
+	order.UpdatedAt = now
+	SyncOrderCourses(order)
+
+	return &split, nil
+}
@@ -30,17 +30,18 @@ func NewUUId() uuid.UUID {
 }
 
 // Função para calcular total de um pedido
-// Recebe slice de OrderItem, retorna soma dos subtotais
+// Recebe slice de OrderItem, retorna soma dos subtotais (preço unitário + modificadores)
 func CalculateOrderTotal(items []OrderItem) float64 {
 	total := 0.0
 	for _, item := range items {
-		total += float64(item.Quantity) * item.Price
+		total += float64(item.Quantity) * (item.Price + item.ModifiersDelta)
 	}
 	return total
 }
 
 // OrderItem model simplificado para utils
 type OrderItem struct {
-	Quantity int
-	Price    float64
+	Quantity       int
+	Price          float64
+	ModifiersDelta float64 // soma dos acréscimos dos modificadores por unidade
 }